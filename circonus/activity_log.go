@@ -0,0 +1,173 @@
+package circonus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// When circonus.activity_log_url is set, an event describing each resource
+// that was created, updated or deleted is POSTed to it so change management
+// systems are notified of monitoring changes as they are applied.  Delivery is
+// best effort: a failure is logged and never fails the operation.
+
+const (
+	activityLogActionCreate = "create"
+	activityLogActionUpdate = "update"
+	activityLogActionDelete = "delete"
+)
+
+// activityLogTimeout bounds each POST to the activity log endpoint.
+const activityLogTimeout = 10 * time.Second
+
+// activityLog is the activity log configuration of the provider.
+type activityLog struct {
+	url       string
+	token     string
+	actor     string
+	workspace string
+	client    *http.Client
+}
+
+// activityLogEvent is the body POSTed to the activity log endpoint.
+type activityLogEvent struct {
+	Action       string `json:"action"`
+	Actor        string `json:"actor,omitempty"`
+	CID          string `json:"cid"`
+	RequestID    string `json:"request_id,omitempty"`
+	ResourceType string `json:"resource_type"`
+	RunID        string `json:"run_id,omitempty"`
+	Timestamp    string `json:"timestamp"`
+	Workspace    string `json:"workspace,omitempty"`
+}
+
+func newActivityLog(url, token, actor, workspace string) *activityLog {
+	if url == "" {
+		return nil
+	}
+
+	return &activityLog{
+		url:       url,
+		token:     token,
+		actor:     actor,
+		workspace: workspace,
+		client:    &http.Client{Timeout: activityLogTimeout},
+	}
+}
+
+// Send POSTs an event for the given change.  A nil activityLog is disabled.
+func (a *activityLog) Send(ctx context.Context, resourceType, action, cid string) error {
+	if a == nil {
+		return nil
+	}
+
+	annotation, _ := requestAnnotationFromContext(ctx)
+	body, err := json.Marshal(activityLogEvent{
+		Action:       action,
+		Actor:        a.actor,
+		CID:          cid,
+		RequestID:    annotation.ID,
+		ResourceType: resourceType,
+		RunID:        annotation.RunID,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		Workspace:    a.workspace,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode activity log event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create activity log request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setRequestAnnotationHeaders(ctx, req.Header)
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send activity log event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("activity log endpoint responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func sendActivityLog(ctx context.Context, meta interface{}, resourceType, action, cid string) {
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt == nil {
+		return
+	}
+
+	if err := ctxt.activityLog.Send(ctx, resourceType, action, cid); err != nil {
+		log.Printf("[WARN] %s %s %s: %v", resourceType, action, cid, err)
+	}
+}
+
+// withActivityLog wraps the create, update and delete functions of r so an
+// activity log event is sent after each one succeeds.
+func withActivityLog(resourceType string, r *schema.Resource) *schema.Resource {
+	r.Create = wrapActivityLogFunc(resourceType, activityLogActionCreate, r.Create)
+	r.Update = wrapActivityLogFunc(resourceType, activityLogActionUpdate, r.Update)
+	r.Delete = wrapActivityLogFunc(resourceType, activityLogActionDelete, r.Delete)
+	r.CreateContext = wrapActivityLogContextFunc(resourceType, activityLogActionCreate, r.CreateContext)
+	r.UpdateContext = wrapActivityLogContextFunc(resourceType, activityLogActionUpdate, r.UpdateContext)
+	r.DeleteContext = wrapActivityLogContextFunc(resourceType, activityLogActionDelete, r.DeleteContext)
+
+	return r
+}
+
+func wrapActivityLogFunc(resourceType, action string, fn func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if fn == nil {
+		return nil
+	}
+
+	return func(d *schema.ResourceData, meta interface{}) error {
+		// The ID is cleared by a successful delete, capture it first.
+		cid := d.Id()
+		if err := fn(d, meta); err != nil {
+			return err
+		}
+
+		if action != activityLogActionDelete {
+			cid = d.Id()
+		}
+		sendActivityLog(context.Background(), meta, resourceType, action, cid)
+
+		return nil
+	}
+}
+
+func wrapActivityLogContextFunc(resourceType, action string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		cid := d.Id()
+		diags := fn(ctx, d, meta)
+		if diags.HasError() {
+			return diags
+		}
+
+		if action != activityLogActionDelete {
+			cid = d.Id()
+		}
+		sendActivityLog(ctx, meta, resourceType, action, cid)
+
+		return diags
+	}
+}
@@ -0,0 +1,123 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestActivityLogSend(t *testing.T) {
+	var (
+		events []activityLogEvent
+		auth   string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+
+		var e activityLogEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("unable to decode event: %v", err)
+		}
+		events = append(events, e)
+	}))
+	defer srv.Close()
+
+	a := newActivityLog(srv.URL, "secret", "alice", "prod")
+	if err := a.Send(context.Background(), "circonus_check", activityLogActionCreate, "/check_bundle/1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", auth)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	e := events[0]
+	if e.Action != activityLogActionCreate || e.Actor != "alice" || e.CID != "/check_bundle/1" || e.ResourceType != "circonus_check" || e.Workspace != "prod" || e.Timestamp == "" {
+		t.Errorf("unexpected event: %#v", e)
+	}
+
+	var disabled *activityLog
+	if err := disabled.Send(context.Background(), "circonus_check", activityLogActionCreate, "/check_bundle/1"); err != nil {
+		t.Errorf("disabled activity log: unexpected error: %v", err)
+	}
+
+	if newActivityLog("", "secret", "alice", "prod") != nil {
+		t.Errorf("expected the activity log to be disabled without a URL")
+	}
+}
+
+func TestActivityLogSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	a := newActivityLog(srv.URL, "", "", "")
+	if err := a.Send(context.Background(), "circonus_check", activityLogActionCreate, "/check_bundle/1"); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestWithActivityLog(t *testing.T) {
+	var events []activityLogEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e activityLogEvent
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		events = append(events, e)
+	}))
+	defer srv.Close()
+
+	meta := &providerContext{activityLog: newActivityLog(srv.URL, "", "", "")}
+
+	var fail bool
+	r := withActivityLog("circonus_graph", &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if fail {
+				return diag.FromErr(fmt.Errorf("create failed"))
+			}
+			d.SetId("/graph/1")
+			return nil
+		},
+		DeleteContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			d.SetId("")
+			return nil
+		},
+	})
+
+	d := r.TestResourceData()
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := r.DeleteContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	fail = true
+	if diags := r.CreateContext(context.Background(), r.TestResourceData(), meta); !diags.HasError() {
+		t.Fatalf("expected an error")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d: %#v", len(events), events)
+	}
+
+	for i, expected := range []activityLogEvent{
+		{Action: activityLogActionCreate, CID: "/graph/1", ResourceType: "circonus_graph"},
+		{Action: activityLogActionDelete, CID: "/graph/1", ResourceType: "circonus_graph"},
+	} {
+		if events[i].Action != expected.Action || events[i].CID != expected.CID || events[i].ResourceType != expected.ResourceType {
+			t.Errorf("event %d: expected %#v, got %#v", i, expected, events[i])
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The Circonus API answers every request with a 503 while the API itself is in
+// a maintenance window.  When circonus.api_maintenance_timeout is set, resource
+// operations that fail with a maintenance response are retried until the window
+// ends or the timeout is exhausted.
+
+var (
+	// apiMaintenanceMinWait and apiMaintenanceMaxWait bound the delay between
+	// attempts while the API is in maintenance.  These are variables so tests
+	// are not forced to sleep.
+	apiMaintenanceMinWait = 5 * time.Second
+	apiMaintenanceMaxWait = 60 * time.Second
+)
+
+// isAPIMaintenanceError returns true when err is a 503 response from the API
+// whose body describes a maintenance window.
+func isAPIMaintenanceError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return isAPIMaintenanceMessage(err.Error())
+}
+
+func isAPIMaintenanceMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "503") && strings.Contains(msg, "maintenance")
+}
+
+// apiMaintenanceDiagsToError returns an error if any of the diagnostics is an
+// API maintenance response, otherwise nil.
+func apiMaintenanceDiagsToError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Error && isAPIMaintenanceMessage(d.Summary+" "+d.Detail) {
+			return fmt.Errorf("%s", d.Summary)
+		}
+	}
+
+	return nil
+}
+
+// apiMaintenanceRetry calls fn until it returns anything other than an API
+// maintenance error.  If the maintenance window outlasts timeout, an error
+// describing the situation is returned.  A timeout of zero disables retries.
+func apiMaintenanceRetry(ctx context.Context, timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	wait := apiMaintenanceMinWait
+
+	for {
+		err := fn()
+		if !isAPIMaintenanceError(err) || timeout <= 0 {
+			return err
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			return fmt.Errorf("the Circonus API maintenance window outlasted the %s of %s, re-run once the API is available: %w", providerAPIMaintenanceTimeoutAttr, timeout, err)
+		}
+
+		log.Printf("[WARN] Circonus API is in a maintenance window, retrying in %s", wait)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the Circonus API maintenance window to end: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		if wait > apiMaintenanceMaxWait {
+			wait = apiMaintenanceMaxWait
+		}
+	}
+}
+
+func apiMaintenanceTimeout(meta interface{}) time.Duration {
+	if ctxt, ok := meta.(*providerContext); ok && ctxt != nil {
+		return ctxt.apiMaintenanceTimeout
+	}
+
+	return 0
+}
+
+// withAPIMaintenanceRetry wraps the CRUD functions of r so they are retried
+// while the API is in a maintenance window.  A create that succeeded before the
+// window began is not repeated, only the read that follows it.
+func withAPIMaintenanceRetry(r *schema.Resource) *schema.Resource {
+	if fn := r.Create; fn != nil {
+		read := r.Read
+		r.Create = func(d *schema.ResourceData, meta interface{}) error {
+			return apiMaintenanceRetry(context.Background(), apiMaintenanceTimeout(meta), func() error {
+				if d.Id() != "" && read != nil {
+					return read(d, meta)
+				}
+				return fn(d, meta)
+			})
+		}
+	}
+
+	if fn := r.CreateContext; fn != nil {
+		read := r.ReadContext
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			var diags diag.Diagnostics
+			err := apiMaintenanceRetry(ctx, apiMaintenanceTimeout(meta), func() error {
+				if d.Id() != "" && read != nil {
+					diags = read(ctx, d, meta)
+				} else {
+					diags = fn(ctx, d, meta)
+				}
+				return apiMaintenanceDiagsToError(diags)
+			})
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return diags
+		}
+	}
+
+	if fn := r.Exists; fn != nil {
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			var exists bool
+			err := apiMaintenanceRetry(context.Background(), apiMaintenanceTimeout(meta), func() error {
+				var err error
+				exists, err = fn(d, meta)
+				return err
+			})
+			return exists, err
+		}
+	}
+
+	r.Read = wrapAPIMaintenanceFunc(r.Read)
+	r.Update = wrapAPIMaintenanceFunc(r.Update)
+	r.Delete = wrapAPIMaintenanceFunc(r.Delete)
+	r.ReadContext = wrapAPIMaintenanceContextFunc(r.ReadContext)
+	r.UpdateContext = wrapAPIMaintenanceContextFunc(r.UpdateContext)
+	r.DeleteContext = wrapAPIMaintenanceContextFunc(r.DeleteContext)
+
+	return r
+}
+
+func wrapAPIMaintenanceFunc(fn func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if fn == nil {
+		return nil
+	}
+
+	return func(d *schema.ResourceData, meta interface{}) error {
+		return apiMaintenanceRetry(context.Background(), apiMaintenanceTimeout(meta), func() error {
+			return fn(d, meta)
+		})
+	}
+}
+
+func wrapAPIMaintenanceContextFunc(fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		var diags diag.Diagnostics
+		err := apiMaintenanceRetry(ctx, apiMaintenanceTimeout(meta), func() error {
+			diags = fn(ctx, d, meta)
+			return apiMaintenanceDiagsToError(diags)
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		return diags
+	}
+}
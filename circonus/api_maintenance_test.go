@@ -0,0 +1,55 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIMaintenanceRetry(t *testing.T) {
+	minWait, maxWait := apiMaintenanceMinWait, apiMaintenanceMaxWait
+	apiMaintenanceMinWait, apiMaintenanceMaxWait = time.Millisecond, time.Millisecond
+	defer func() {
+		apiMaintenanceMinWait, apiMaintenanceMaxWait = minWait, maxWait
+	}()
+
+	maintenanceErr := errors.New(`API response code 503: {"code":503,"message":"The API is down for scheduled maintenance"}`)
+
+	var calls int
+	err := apiMaintenanceRetry(context.Background(), time.Second, func() error {
+		calls++
+		if calls < 3 {
+			return maintenanceErr
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success after 3 calls, got %d calls: %v", calls, err)
+	}
+
+	calls = 0
+	otherErr := errors.New("API response code 500: boom")
+	if err := apiMaintenanceRetry(context.Background(), time.Second, func() error {
+		calls++
+		return otherErr
+	}); err != otherErr || calls != 1 {
+		t.Fatalf("expected non-maintenance error to be returned immediately, got %d calls: %v", calls, err)
+	}
+
+	calls = 0
+	if err := apiMaintenanceRetry(context.Background(), 0, func() error {
+		calls++
+		return maintenanceErr
+	}); err != maintenanceErr || calls != 1 {
+		t.Fatalf("expected a zero timeout to disable retries, got %d calls: %v", calls, err)
+	}
+
+	err = apiMaintenanceRetry(context.Background(), 5*time.Millisecond, func() error {
+		return maintenanceErr
+	})
+	if err == nil || !strings.Contains(err.Error(), providerAPIMaintenanceTimeoutAttr) {
+		t.Fatalf("expected maintenance timeout diagnostic, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Some resources hit slow API paths (e.g. check bundles with thousands of
+// metrics) and need more patience than the provider's settings allow.  The
+// api_overrides block adjusts the provider's retry settings for a single
+// resource.
+
+const (
+	// *.api_overrides.* resource attribute names.
+	apiOverridesAttr           = "api_overrides"
+	apiOverridesMaxRetriesAttr = "max_retries"
+	apiOverridesTimeoutAttr    = "timeout"
+)
+
+var apiOverridesDescriptions = attrDescrs{
+	apiOverridesMaxRetriesAttr: "How many times a failed API request is retried, replacing the provider's unbounded backoff",
+	apiOverridesTimeoutAttr:    "How long to wait for an API maintenance window to end, overriding the provider's api_maintenance_timeout",
+}
+
+var schemaAPIOverrides = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(apiOverridesDescriptions, map[schemaAttr]*schema.Schema{
+			apiOverridesMaxRetriesAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateIntMin(apiOverridesMaxRetriesAttr, 1),
+			},
+			apiOverridesTimeoutAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDurationMin(apiOverridesTimeoutAttr, "0s"),
+			},
+		}),
+	},
+}
+
+// apiOverrides returns the provider context to use for the resource d: meta
+// itself when d has no api_overrides block, otherwise a copy of meta with the
+// overrides applied.
+func apiOverrides(d *schema.ResourceData, meta interface{}) (interface{}, error) {
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt == nil {
+		return meta, nil
+	}
+
+	l, ok := d.Get(apiOverridesAttr).([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return meta, nil
+	}
+
+	overrides := newInterfaceMap(l[0])
+
+	// The copy shares everything else with ctxt, the caches are created first
+	// so they are shared as well.
+	ctxt.lookupCaches()
+	o := *ctxt
+
+	if v, found := overrides[string(apiOverridesTimeoutAttr)]; found && v.(string) != "" {
+		timeout, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s.%s: %w", apiOverridesAttr, apiOverridesTimeoutAttr, err)
+		}
+		o.apiMaintenanceTimeout = timeout
+	}
+
+	if v, found := overrides[string(apiOverridesMaxRetriesAttr)]; found && v.(int) > 0 && ctxt.apiConfig != nil {
+		cfg := *ctxt.apiConfig
+		cfg.MaxRetries = uint(v.(int))
+
+		// The client's exponential backoff is not enabled: it retries 5xx
+		// responses without bound, ignoring MaxRetries.
+		client, err := api.NewAPI(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create an API client for %s: %w", apiOverridesAttr, err)
+		}
+		o.client = client
+	}
+
+	return &o, nil
+}
+
+// withAPIOverrides wraps the CRUD functions of r so they are called with the
+// provider context adjusted by the resource's api_overrides block.  Resources
+// without an api_overrides attribute are returned untouched.
+func withAPIOverrides(r *schema.Resource) *schema.Resource {
+	if _, found := r.Schema[apiOverridesAttr]; !found {
+		return r
+	}
+
+	r.CreateContext = wrapAPIOverridesContextFunc(r.CreateContext)
+	r.ReadContext = wrapAPIOverridesContextFunc(r.ReadContext)
+	r.UpdateContext = wrapAPIOverridesContextFunc(r.UpdateContext)
+	r.DeleteContext = wrapAPIOverridesContextFunc(r.DeleteContext)
+
+	return r
+}
+
+func wrapAPIOverridesContextFunc(fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		meta, err := apiOverrides(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		return fn(ctx, d, meta)
+	}
+}
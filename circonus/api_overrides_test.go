@@ -0,0 +1,79 @@
+package circonus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAPIOverrides(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cfg := &api.Config{
+		URL:           srv.URL,
+		TokenKey:      "test",
+		MinRetryDelay: "1ms",
+		MaxRetryDelay: "1ms",
+	}
+	client, err := api.NewAPI(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client, apiConfig: cfg, apiMaintenanceTimeout: time.Minute, runID: "run"}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{})
+	meta, err := apiOverrides(d, ctxt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta != ctxt {
+		t.Fatalf("expected the provider context to be used as is without %s", apiOverridesAttr)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(apiOverridesAttr): []interface{}{
+			map[string]interface{}{
+				string(apiOverridesMaxRetriesAttr): 2,
+				string(apiOverridesTimeoutAttr):    "30m",
+			},
+		},
+	})
+	meta, err = apiOverrides(d, ctxt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := meta.(*providerContext)
+	if o == ctxt || o.client == ctxt.client {
+		t.Fatalf("expected a new provider context and client")
+	}
+	if o.apiMaintenanceTimeout != 30*time.Minute || ctxt.apiMaintenanceTimeout != time.Minute {
+		t.Errorf("expected a timeout of 30m leaving the provider's untouched, got %s and %s", o.apiMaintenanceTimeout, ctxt.apiMaintenanceTimeout)
+	}
+	if o.runID != ctxt.runID {
+		t.Errorf("expected the settings without overrides to be kept, got run ID %q", o.runID)
+	}
+
+	// Lookups cached by either context are shared.
+	o.lookupCaches().userCIDs = map[string]string{"ops@example.org": "/user/1"}
+	if cid := ctxt.lookupCaches().userCIDs["ops@example.org"]; cid != "/user/1" {
+		t.Errorf("expected the caches to be shared, got %q", cid)
+	}
+
+	if _, err := o.client.Get("/check_bundle/1"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("expected 1 request and 2 retries, got %d requests", n)
+	}
+}
@@ -0,0 +1,33 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+)
+
+// The API client does not take a context: its calls block until the request,
+// and every retry of it, is done.  Resources with a timeouts block make their
+// API calls with apiCallContext so an operation gives up once the deadline
+// Terraform derived from the timeouts has passed.  The abandoned request runs
+// to completion in the background and its outcome is discarded, whatever it
+// changed is picked up by the next refresh.
+
+// apiCallContext calls fn and returns its error, or an error wrapping the
+// context's error when ctx is done first.
+func apiCallContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("not calling the Circonus API: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("gave up waiting for the Circonus API, the request may still complete, raise the resource's timeouts if it needs longer: %w", ctx.Err())
+	}
+}
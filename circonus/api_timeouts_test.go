@@ -0,0 +1,52 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAPICallContext(t *testing.T) {
+	errAPI := errors.New("API error")
+	if err := apiCallContext(context.Background(), func() error { return errAPI }); !errors.Is(err, errAPI) {
+		t.Errorf("expected the error of the call, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	err := apiCallContext(ctx, func() error {
+		<-release
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	called := false
+	if err := apiCallContext(ctx, func() error { called = true; return nil }); err == nil || called {
+		t.Errorf("expected no call once the deadline has passed, got called %t and %v", called, err)
+	}
+}
+
+func TestCheckTimeouts(t *testing.T) {
+	timeouts := resourceCheck().Timeouts
+	if timeouts == nil {
+		t.Fatal("expected circonus_check to support a timeouts block")
+	}
+
+	for name, v := range map[string]*time.Duration{
+		"create": timeouts.Create,
+		"read":   timeouts.Read,
+		"update": timeouts.Update,
+		"delete": timeouts.Delete,
+	} {
+		if v == nil || *v != defaultCheckTimeout {
+			t.Errorf("%s: expected a default timeout of %s, got %v", name, defaultCheckTimeout, v)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package circonus
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The provider speaks version 2 of the Circonus API.  api_url accepts the name
+// of a preset, a bare hostname or a URL; the API version path is added when it
+// is missing so switching deployments only takes changing api_url.
+
+const (
+	defaultAPIURL = "https://api.circonus.com/v2"
+
+	// apiVersionPath is the path segment of the API version the provider
+	// speaks.
+	apiVersionPath = "v2"
+)
+
+// apiURLPresets maps the names accepted by api_url to the URL of the API.
+var apiURLPresets = map[string]string{
+	"saas": defaultAPIURL,
+}
+
+var apiVersionPathRE = regexp.MustCompile(`^v[0-9]+$`)
+
+// normalizeAPIURL resolves raw, as given to api_url, to the URL of the API:
+// presets are expanded, a scheme of https is assumed and the version path is
+// appended when absent.
+func normalizeAPIURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultAPIURL, nil
+	}
+
+	if u, found := apiURLPresets[strings.ToLower(raw)]; found {
+		return u, nil
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %s %q: %w", providerAPIURLAttr, raw, err)
+	}
+
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return "", fmt.Errorf("%s %q must use http or https, not %q", providerAPIURLAttr, raw, u.Scheme)
+	case u.Host == "":
+		return "", fmt.Errorf("%s %q has no host, use a URL or one of the presets: %s", providerAPIURLAttr, raw, strings.Join(apiURLPresetNames(), ", "))
+	case u.RawQuery != "" || u.Fragment != "":
+		return "", fmt.Errorf("%s %q must not have a query or fragment", providerAPIURLAttr, raw)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	segments := strings.Split(path, "/")
+	last := segments[len(segments)-1]
+	switch {
+	case last == apiVersionPath:
+	case apiVersionPathRE.MatchString(last):
+		return "", fmt.Errorf("%s %q is an endpoint for API %s, the provider requires API %s", providerAPIURLAttr, raw, last, apiVersionPath)
+	default:
+		path += "/" + apiVersionPath
+	}
+	u.Path = path
+
+	return u.String(), nil
+}
+
+func apiURLPresetNames() []string {
+	names := make([]string, 0, len(apiURLPresets))
+	for name := range apiURLPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func validateAPIURL(v interface{}, key string) (warnings []string, errors []error) {
+	if _, err := normalizeAPIURL(v.(string)); err != nil {
+		errors = append(errors, err)
+	}
+
+	return warnings, errors
+}
@@ -0,0 +1,38 @@
+package circonus
+
+import "testing"
+
+func TestNormalizeAPIURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+		ok       bool
+	}{
+		{"", "https://api.circonus.com/v2", true},
+		{"saas", "https://api.circonus.com/v2", true},
+		{"SaaS", "https://api.circonus.com/v2", true},
+		{"https://api.circonus.com/v2", "https://api.circonus.com/v2", true},
+		{"https://api.circonus.com/v2/", "https://api.circonus.com/v2", true},
+		{"https://api.circonus.com", "https://api.circonus.com/v2", true},
+		{"circonus.example.com", "https://circonus.example.com/v2", true},
+		{"circonus.example.com:8443/api", "https://circonus.example.com:8443/api/v2", true},
+		{"http://10.0.0.1:8080/v2", "http://10.0.0.1:8080/v2", true},
+		{"https://api.circonus.com/v1", "", false},
+		{"https://api.circonus.com/v3/", "", false},
+		{"ftp://api.circonus.com/v2", "", false},
+		{"https://api.circonus.com/v2?account=1", "", false},
+		{"https:///v2", "", false},
+	}
+
+	for _, test := range tests {
+		got, err := normalizeAPIURL(test.raw)
+		switch {
+		case test.ok && err != nil:
+			t.Errorf("%q: unexpected error: %v", test.raw, err)
+		case !test.ok && err == nil:
+			t.Errorf("%q: expected an error, got %q", test.raw, got)
+		case got != test.expected:
+			t.Errorf("%q: expected %q, got %q", test.raw, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package circonus
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// The API reports who created and last modified each object and when, but the
+// API client only parses some of these fields, and only for some object types.
+// Objects whose audit fields are exposed in the statefile are fetched and
+// parsed with fetchObject so the fields come from the same response.
+
+// apiAuditMetadata holds the audit fields of an API object.  Times are epoch
+// seconds, users are user CIDs.
+type apiAuditMetadata struct {
+	Created        uint   `json:"_created,omitempty"`
+	CreatedBy      string `json:"_created_by,omitempty"`
+	LastModified   uint   `json:"_last_modified,omitempty"`
+	LastModifiedBy string `json:"_last_modified_by,omitempty"`
+
+	// LastModifedBy is the misspelled key the API client uses for check
+	// bundles, accepted when _last_modified_by is missing.
+	LastModifedBy string `json:"_last_modifed_by,omitempty"`
+}
+
+// parseObject parses the API response result into v and returns the audit
+// metadata of the object.
+func parseObject(result []byte, v interface{}) (apiAuditMetadata, error) {
+	var md apiAuditMetadata
+
+	if err := json.Unmarshal(result, v); err != nil {
+		return md, err
+	}
+
+	if err := json.Unmarshal(result, &md); err != nil {
+		return md, err
+	}
+
+	if md.LastModifiedBy == "" {
+		md.LastModifiedBy = md.LastModifedBy
+	}
+	md.LastModifedBy = ""
+
+	return md, nil
+}
+
+// fetchObject fetches the object with id, or CID, from the API endpoint
+// prefix (e.g. config.GraphPrefix) into v and returns its audit metadata.
+func fetchObject(ctxt *providerContext, prefix, id string, v interface{}) (apiAuditMetadata, error) {
+	if id == "" {
+		return apiAuditMetadata{}, fmt.Errorf("invalid %s CID (none)", prefix)
+	}
+	cid := makeCID(prefix, id)
+
+	result, err := ctxt.client.Get(cid)
+	if err != nil {
+		return apiAuditMetadata{}, err
+	}
+
+	md, err := parseObject(result, v)
+	if err != nil {
+		return apiAuditMetadata{}, fmt.Errorf("parsing %s: %w", cid, err)
+	}
+
+	return md, nil
+}
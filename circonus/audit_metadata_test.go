@@ -0,0 +1,77 @@
+package circonus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseObject(t *testing.T) {
+	var cb api.CheckBundle
+	md, err := parseObject([]byte(`{"_cid":"/check_bundle/1","_created":100,"_last_modified":200,"_last_modifed_by":"/user/2"}`), &cb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cb.CID != "/check_bundle/1" {
+		t.Fatalf("expected the check bundle to be parsed, got %#v", cb)
+	}
+	if md != (apiAuditMetadata{Created: 100, LastModified: 200, LastModifiedBy: "/user/2"}) {
+		t.Fatalf("expected the misspelled last modified by key to be accepted, got %#v", md)
+	}
+
+	md, err = parseObject([]byte(`{"_created_by":"/user/1","_last_modified_by":"/user/3","_last_modifed_by":"/user/2"}`), &cb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if md.CreatedBy != "/user/1" || md.LastModifiedBy != "/user/3" {
+		t.Fatalf("expected _last_modified_by to take precedence, got %#v", md)
+	}
+}
+
+func TestGraphAuditMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graph/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"_cid":"/graph/abc","title":"latency","_created":1600000000,"_created_by":"/user/1","_last_modified":1600000100,"_last_modified_by":"/user/2"}`)
+	}))
+	defer srv.Close()
+
+	client, err := api.NewAPI(&api.Config{URL: srv.URL, TokenKey: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	if _, err := fetchObject(ctxt, config.GraphPrefix, "", &api.Graph{}); err == nil {
+		t.Fatalf("expected an error without a CID")
+	}
+
+	id := "abc"
+	g, err := loadGraph(ctxt, api.CIDType(&id))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceGraph().Schema, map[string]interface{}{})
+	if err := graphToState(d, &g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for attr, expected := range map[string]interface{}{
+		graphOutCreatedAttr:        1600000000,
+		graphOutCreatedByAttr:      "/user/1",
+		graphOutLastModifiedAttr:   1600000100,
+		graphOutLastModifiedByAttr: "/user/2",
+	} {
+		if got := d.Get(attr); got != expected {
+			t.Errorf("expected %s to be %v, got %v", attr, expected, got)
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package circonus
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
+	"regexp"
+	"sort"
+	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
@@ -12,6 +18,14 @@ import (
 
 type circonusCheck struct {
 	api.CheckBundle
+
+	// deactivateDiscoveredMetrics sends a metric_limit of 0, which the API
+	// client leaves out of the check bundle it sends, so the metrics matched
+	// by the metric filters are recorded without being activated.
+	deactivateDiscoveredMetrics bool
+
+	// audit is who created and last modified the check bundle, and when.
+	audit apiAuditMetadata
 }
 
 type circonusCheckType string
@@ -23,24 +37,33 @@ const (
 )
 
 const (
-	apiCheckTypeCAQL       circonusCheckType = "caql"
-	apiCheckTypeConsul     circonusCheckType = "consul"
-	apiCheckTypeDNS        circonusCheckType = "dns"
-	apiCheckTypeICMPPing   circonusCheckType = "ping_icmp"
-	apiCheckTypeExternal   circonusCheckType = "external"
-	apiCheckTypeHTTP       circonusCheckType = "http"
-	apiCheckTypeJMX        circonusCheckType = "jmx"
-	apiCheckTypeMemcached  circonusCheckType = "memcached"
-	apiCheckTypeJSON       circonusCheckType = "json"
-	apiCheckTypeMySQL      circonusCheckType = "mysql"
-	apiCheckTypeNTP        circonusCheckType = "ntp"
-	apiCheckTypeRedis      circonusCheckType = "redis"
-	apiCheckTypeSMTP       circonusCheckType = "smtp"
-	apiCheckTypeSNMP       circonusCheckType = "snmp"
-	apiCheckTypeStatsd     circonusCheckType = "statsd"
-	apiCheckTypePostgreSQL circonusCheckType = "postgres"
-	apiCheckTypePromText   circonusCheckType = "promtext"
-	apiCheckTypeTCP        circonusCheckType = "tcp"
+	apiCheckTypeCAQL         circonusCheckType = "caql"
+	apiCheckTypeConsul       circonusCheckType = "consul"
+	apiCheckTypeDNS          circonusCheckType = "dns"
+	apiCheckTypeICMPPing     circonusCheckType = "ping_icmp"
+	apiCheckTypeIMAP         circonusCheckType = "imap"
+	apiCheckTypeExternal     circonusCheckType = "external"
+	apiCheckTypeHAProxy      circonusCheckType = "haproxy"
+	apiCheckTypeHTTP         circonusCheckType = "http"
+	apiCheckTypeHTTPSequence circonusCheckType = "http_sequence"
+	apiCheckTypeHistogram    circonusCheckType = "histogram_ingest"
+	apiCheckTypeJMX          circonusCheckType = "jmx"
+	apiCheckTypeMemcached    circonusCheckType = "memcached"
+	apiCheckTypeJSON         circonusCheckType = "json"
+	apiCheckTypeLDAP         circonusCheckType = "ldap"
+	apiCheckTypeMySQL        circonusCheckType = "mysql"
+	apiCheckTypeNTP          circonusCheckType = "ntp"
+	apiCheckTypeOTLP         circonusCheckType = "otlphttp"
+	apiCheckTypePOP3         circonusCheckType = "pop3"
+	apiCheckTypeRedis        circonusCheckType = "redis"
+	apiCheckTypeResmon       circonusCheckType = "resmon"
+	apiCheckTypeSelfcheck    circonusCheckType = "selfcheck"
+	apiCheckTypeSMTP         circonusCheckType = "smtp"
+	apiCheckTypeSNMP         circonusCheckType = "snmp"
+	apiCheckTypeStatsd       circonusCheckType = "statsd"
+	apiCheckTypePostgreSQL   circonusCheckType = "postgres"
+	apiCheckTypePromText     circonusCheckType = "promtext"
+	apiCheckTypeTCP          circonusCheckType = "tcp"
 )
 
 func newCheck() circonusCheck {
@@ -51,15 +74,88 @@ func newCheck() circonusCheck {
 
 func loadCheck(ctxt *providerContext, cid api.CIDType) (circonusCheck, error) {
 	var c circonusCheck
-	cb, err := ctxt.client.FetchCheckBundle(cid)
+	if cid == nil {
+		return circonusCheck{}, fmt.Errorf("invalid check bundle CID (none)")
+	}
+
+	audit, err := fetchObject(ctxt, config.CheckBundlePrefix, *cid, &c.CheckBundle)
 	if err != nil {
 		return circonusCheck{}, err
 	}
-	c.CheckBundle = *cb
+	c.audit = audit
 
 	return c, nil
 }
 
+const (
+	// API filters used when searching for check bundles.
+	checkSearchNameFilter = "f_name"
+	checkSearchTagFilter  = "f_tags_has"
+)
+
+// searchChecks returns the check bundles named name that carry all of tags.
+// The API does the filtering so lookups do not page through every check
+// bundle of large accounts; the results are matched exactly afterwards as the
+// API's filters are not guaranteed to be exact.
+func searchChecks(ctxt *providerContext, name string, tags []string) ([]circonusCheck, error) {
+	filter := api.SearchFilterType{}
+	if name != "" {
+		filter[checkSearchNameFilter] = []string{name}
+	}
+	if len(tags) > 0 {
+		filter[checkSearchTagFilter] = tags
+	}
+
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("a name or at least one tag is required to search for check bundles")
+	}
+
+	bundles, err := ctxt.client.SearchCheckBundles(nil, &filter)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search for check bundles: %w", err)
+	}
+
+	return matchCheckBundles(*bundles, name, tags), nil
+}
+
+// matchCheckBundles returns the check bundles named name (any name when empty)
+// that carry all of tags.
+func matchCheckBundles(bundles []api.CheckBundle, name string, tags []string) []circonusCheck {
+	matches := make([]circonusCheck, 0, 1)
+	for i := range bundles {
+		if name != "" && bundles[i].DisplayName != name {
+			continue
+		}
+
+		if !hasAllTags(bundles[i].Tags, tags) {
+			continue
+		}
+
+		matches = append(matches, circonusCheck{CheckBundle: bundles[i]})
+	}
+
+	return matches
+}
+
+func hasAllTags(have, want []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+
+	tagSet := make(map[string]struct{}, len(have))
+	for _, t := range have {
+		tagSet[t] = struct{}{}
+	}
+
+	for _, t := range want {
+		if _, ok := tagSet[t]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 func checkAPIStatusToBool(s string) bool {
 	var active bool
 	switch s {
@@ -82,7 +178,113 @@ func checkActiveToAPIStatus(active bool) string {
 	return checkStatusDisabled
 }
 
+// SortedByCollector returns the check IDs, check UUIDs and reverse connection
+// URLs of the check bundle ordered by collector CID.  The API returns these
+// lists in broker order, which is not stable between reads.
+func (c *circonusCheck) SortedByCollector() (checks, uuids, reverseConnectURLs []string) {
+	order := make([]int, len(c.Brokers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.Brokers[order[i]] < c.Brokers[order[j]]
+	})
+
+	reorder := func(l []string) []string {
+		out := make([]string, 0, len(l))
+		if len(l) != len(order) {
+			// Not indexed by broker, fall back to a lexical sort.
+			out = append(out, l...)
+			sort.Strings(out)
+			return out
+		}
+
+		for _, i := range order {
+			out = append(out, l[i])
+		}
+		return out
+	}
+
+	return reorder(c.Checks), reorder(c.CheckUUIDs), reorder(c.ReverseConnectURLs)
+}
+
+// MetricFilterMatches returns, for each of the check's metric filters, how
+// many of its metrics the filter decides on: filters are tried in order and a
+// metric is counted against the first one whose regex matches its name.  Tag
+// queries are not evaluated, so the count of a filter with a tag query is an
+// upper bound.  Filters whose regex is not supported by Go are reported as -1
+// and do not match any metric.
+func (c *circonusCheck) MetricFilterMatches() []int {
+	counts := make([]int, len(c.MetricFilters))
+	res := make([]*regexp.Regexp, len(c.MetricFilters))
+	for i, f := range c.MetricFilters {
+		if len(f) < 2 {
+			continue
+		}
+
+		re, err := regexp.Compile(f[1])
+		if err != nil {
+			counts[i] = -1
+			continue
+		}
+		res[i] = re
+	}
+
+	for _, m := range c.Metrics {
+		for i, re := range res {
+			if re != nil && re.MatchString(m.Name) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	return counts
+}
+
+// ActiveMetricCount returns the number of the check bundle's metrics that are
+// active, for filter based checks this includes the discovered metrics the
+// filters activated.
+func (c *circonusCheck) ActiveMetricCount() int {
+	var n int
+	for _, m := range c.Metrics {
+		if m.Status == metricStatusActive {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ConfigChecksum returns a stable checksum of the check bundle's config,
+// including keys that are not represented in the schema.
+func (c *circonusCheck) ConfigChecksum() string {
+	keys := make([]string, 0, len(c.Config))
+	for k := range c.Config {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%q=%q\n", k, c.Config[config.Key(k)])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (c *circonusCheck) Create(ctxt *providerContext) error {
+	if c.deactivateDiscoveredMetrics {
+		cb, err := c.send(ctxt.client.Post, config.CheckBundlePrefix)
+		if err != nil {
+			return fmt.Errorf("creating check bundle: %w", err)
+		}
+
+		c.CID = cb.CID
+
+		return nil
+	}
+
 	cb, err := ctxt.client.CreateCheckBundle(&c.CheckBundle)
 	if err != nil {
 		return err
@@ -93,8 +295,79 @@ func (c *circonusCheck) Create(ctxt *providerContext) error {
 	return nil
 }
 
+// apiCheckMetricLimitKey is the check bundle's metric_limit in the API.
+const apiCheckMetricLimitKey = "metric_limit"
+
+// send sends the check bundle to reqPath with an explicit metric_limit of 0,
+// see deactivateDiscoveredMetrics, and returns the check bundle the API
+// answers with.
+func (c *circonusCheck) send(method func(string, []byte) ([]byte, error), reqPath string) (*api.CheckBundle, error) {
+	payload, err := json.Marshal(&c.CheckBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	fields[apiCheckMetricLimitKey] = json.RawMessage("0")
+
+	payload, err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := method(reqPath, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &api.CheckBundle{}
+	if err := json.Unmarshal(result, cb); err != nil {
+		return nil, fmt.Errorf("parsing check bundle: %w", err)
+	}
+
+	return cb, nil
+}
+
+// checkQuiesceWindow is how long a check is kept in maintenance when it is
+// quiesced before being destroyed.
+const checkQuiesceWindow = 10 * time.Minute
+
+// defaultCheckTimeout is how long each check operation may take unless the
+// resource's timeouts block says otherwise.
+const defaultCheckTimeout = 20 * time.Minute
+
+// quiesceChecks places each of the checks of a check bundle in a maintenance
+// window covering all severities, so the alerts raised while the bundle is
+// removed are not sent.  The windows expire on their own.
+func quiesceChecks(ctxt *providerContext, bundleCID string, checkCIDs []string, window time.Duration) error {
+	start := time.Now()
+	for _, checkCID := range checkCIDs {
+		m := api.NewMaintenanceWindow()
+		m.Type = "check"
+		m.Item = checkCID
+		m.Notes = fmt.Sprintf("Quiesced by Terraform before destroying check bundle %s", bundleCID)
+		m.Severities = []string{"1", "2", "3", "4", "5"}
+		m.Start = uint(start.Unix())
+		m.Stop = uint(start.Add(window).Unix())
+
+		if _, err := ctxt.client.CreateMaintenanceWindow(m); err != nil {
+			return fmt.Errorf("unable to quiesce check %s before destroying check bundle %s: %w", checkCID, bundleCID, err)
+		}
+	}
+
+	return nil
+}
+
 func (c *circonusCheck) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateCheckBundle(&c.CheckBundle)
+	var err error
+	if c.deactivateDiscoveredMetrics {
+		_, err = c.send(ctxt.client.Put, c.CID)
+	} else {
+		_, err = ctxt.client.UpdateCheckBundle(&c.CheckBundle)
+	}
 	if err != nil {
 		return fmt.Errorf("Unable to update check bundle %s: %w", c.CID, err)
 	}
@@ -115,6 +388,21 @@ func (c *circonusCheck) Fixup() error {
 	return nil
 }
 
+// validateCheckPeriodTimeout verifies the timeout does not exceed the period
+// and that the period is supported by the check type.  It is used both at plan
+// time and before a check bundle is sent to the API.
+func validateCheckPeriodTimeout(checkType apiCheckType, period, timeout time.Duration) error {
+	if timeout > period {
+		return fmt.Errorf("Timeout (%s) can not exceed period (%s)", timeout, period)
+	}
+
+	if checkType == apiCheckTypeCloudWatchAttr && !(period == time.Minute || period == 5*time.Minute) {
+		return fmt.Errorf("Period must be either 1m or 5m for a %s check", apiCheckTypeCloudWatchAttr)
+	}
+
+	return nil
+}
+
 func (c *circonusCheck) Validate() error {
 	// there must be at least 1 metric or at least 1 metric_filter but only one of the lists can contain members.
 	if len(c.Metrics) > 0 && len(c.MetricFilters) > 0 {
@@ -125,20 +413,33 @@ func (c *circonusCheck) Validate() error {
 		return fmt.Errorf("You must supply one or more 'metric' blocks *or* one or more 'metric_filter' blocks")
 	}
 
-	if c.Timeout > float32(c.Period) {
-		return fmt.Errorf("Timeout (%f) can not exceed period (%d)", c.Timeout, c.Period)
+	if err := validateCheckPeriodTimeout(apiCheckType(c.Type), time.Duration(c.Period)*time.Second, time.Duration(float64(c.Timeout)*float64(time.Second))); err != nil {
+		return err
 	}
 
 	// Check-type specific validation
 	switch apiCheckType(c.Type) {
-	case apiCheckTypeCloudWatchAttr:
-		if !(c.Period == 60 || c.Period == 300) {
-			return fmt.Errorf("Period must be either 1m or 5m for a %s check", apiCheckTypeCloudWatchAttr)
-		}
 	case apiCheckTypeConsulAttr:
 		if v, found := c.Config[config.URL]; !found || v == "" {
 			return fmt.Errorf("%s must have at least one check mode set: %s, %s, or %s must be set", checkConsulAttr, checkConsulServiceAttr, checkConsulNodeAttr, checkConsulStateAttr)
 		}
+	case apiCheckTypePromTextAttr:
+		scrape, submission, err := checkPromTextIntervals(c)
+		if err != nil {
+			return err
+		}
+
+		if err := validatePromTextIntervals(time.Duration(c.Period)*time.Second, scrape, submission); err != nil {
+			return err
+		}
+	case apiCheckTypeSelfcheckAttr:
+		if c.Target == "" {
+			return fmt.Errorf("%s checks must set %s to the address of the broker", checkSelfcheckAttr, checkTargetAttr)
+		}
+
+		if len(c.Brokers) != 1 {
+			return fmt.Errorf("%s checks monitor a single broker, exactly one %s must be set", checkSelfcheckAttr, checkCollectorAttr)
+		}
 	}
 
 	return nil
@@ -0,0 +1,185 @@
+package circonus
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The database check types accept either a raw dsn or the connect options it
+// is assembled from, so the password can be a Sensitive attribute of its own
+// rather than part of a string shown in plans.  The DSN is written in the
+// keyword/value form (host=db port=5432 ...) the broker's modules parse.
+
+// checkDSNKey maps a connect option attribute of a check type block to its
+// DSN keyword.
+type checkDSNKey struct {
+	attr    schemaAttr
+	keyword string
+	numeric bool
+}
+
+// checkDSNInUse returns true if m sets any of the connect options of keys.
+func checkDSNInUse(m map[string]interface{}, keys []checkDSNKey) bool {
+	for _, key := range keys {
+		switch v := m[string(key.attr)].(type) {
+		case string:
+			if v != "" {
+				return true
+			}
+		case int:
+			if v != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkDSNInUseState returns true if the checkTypeAttr block of d sets any of
+// the connect options of keys.  The DSN of checks configured with a raw dsn,
+// or imported, is kept as is in the statefile.
+func checkDSNInUseState(d *schema.ResourceData, checkTypeAttr schemaAttr, keys []checkDSNKey) bool {
+	s, ok := d.Get(string(checkTypeAttr)).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return false
+	}
+
+	m, ok := s.List()[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	return checkDSNInUse(m, keys)
+}
+
+// checkDSNConfigToAPI returns the DSN of the checkTypeAttr block m: its
+// dsnAttr or the DSN assembled from its connect options, which conflict.
+func checkDSNConfigToAPI(checkTypeAttr, dsnAttr schemaAttr, m map[string]interface{}, keys []checkDSNKey) (string, error) {
+	dsn, _ := m[string(dsnAttr)].(string)
+	if !checkDSNInUse(m, keys) {
+		return dsn, nil
+	}
+
+	if dsn != "" {
+		attrs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			attrs = append(attrs, string(key.attr))
+		}
+		return "", fmt.Errorf("%s: %s conflicts with the connect options it would be assembled from (%s)", checkTypeAttr, dsnAttr, strings.Join(attrs, ", "))
+	}
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var v string
+		switch value := m[string(key.attr)].(type) {
+		case string:
+			v = value
+		case int:
+			if value != 0 {
+				v = strconv.Itoa(value)
+			}
+		}
+		if v == "" {
+			continue
+		}
+
+		parts = append(parts, key.keyword+"="+quoteDSNValue(v))
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// checkDSNAPIToState parses dsn into the connect options of keys in m.  The
+// DSN is stored in dsnAttr instead when it can not be parsed.
+func checkDSNAPIToState(dsnAttr schemaAttr, dsn string, keys []checkDSNKey, m map[string]interface{}) {
+	options, err := parseDSN(dsn)
+	if err != nil {
+		log.Printf("[ERROR]: Unable to parse DSN into %s: %v", dsnAttr, err)
+		m[string(dsnAttr)] = dsn
+		return
+	}
+
+	for _, key := range keys {
+		v, found := options[key.keyword]
+		if !found {
+			continue
+		}
+
+		if !key.numeric {
+			m[string(key.attr)] = v
+			continue
+		}
+
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("[ERROR]: Unable to convert %s to an integer: %v", key.keyword, err)
+			continue
+		}
+		m[string(key.attr)] = i
+	}
+}
+
+// quoteDSNValue quotes v when it is empty or contains spaces, quotes or
+// backslashes.
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(v) + "'"
+}
+
+// parseDSN parses a keyword/value DSN into its keywords.
+func parseDSN(dsn string) (map[string]string, error) {
+	options := make(map[string]string)
+	s := strings.TrimSpace(dsn)
+	for s != "" {
+		i := strings.IndexByte(s, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("missing value of %q", s)
+		}
+		keyword := strings.TrimSpace(s[:i])
+		s = strings.TrimLeft(s[i+1:], " ")
+
+		var v strings.Builder
+		if strings.HasPrefix(s, "'") {
+			s = s[1:]
+			closed := false
+			for len(s) > 0 && !closed {
+				switch s[0] {
+				case '\\':
+					if len(s) > 1 {
+						s = s[1:]
+					}
+					v.WriteByte(s[0])
+				case '\'':
+					closed = true
+				default:
+					v.WriteByte(s[0])
+				}
+				s = s[1:]
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted value of %q", keyword)
+			}
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			v.WriteString(s[:end])
+			s = s[end:]
+		}
+
+		options[keyword] = v.String()
+		s = strings.TrimLeft(s, " ")
+	}
+
+	return options, nil
+}
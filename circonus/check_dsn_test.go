@@ -0,0 +1,47 @@
+package circonus
+
+import (
+	"testing"
+)
+
+func TestParseDSN(t *testing.T) {
+	options, err := parseDSN(`host=/tmp  password='a b\'c' dbname = orders`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"host": "/tmp", "password": "a b'c", "dbname": "orders"}
+	for k, v := range expected {
+		if options[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, options[k])
+		}
+	}
+
+	for _, dsn := range []string{"host", "password='open"} {
+		if _, err := parseDSN(dsn); err == nil {
+			t.Errorf("%q: expected an error", dsn)
+		}
+	}
+}
+
+func TestQuoteDSNValue(t *testing.T) {
+	for v, expected := range map[string]string{
+		"monitor":    "monitor",
+		"":           "''",
+		"a b":        "'a b'",
+		`it's \here`: `'it\'s \\here'`,
+	} {
+		quoted := quoteDSNValue(v)
+		if quoted != expected {
+			t.Errorf("%q: expected %q, got %q", v, expected, quoted)
+		}
+
+		options, err := parseDSN("k=" + quoted)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", v, err)
+		}
+		if options["k"] != v {
+			t.Errorf("%q: expected to parse back, got %q", v, options["k"])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package circonus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Brokers and other tooling add keys to the config of some checks (e.g. a
+// header_X-Trace injected by a proxy).  The keys listed in ignore_config_keys
+// are left out of the statefile and the config checksum, so they cause no
+// diff even in strict_config mode, and their values are carried over when
+// Terraform updates the check.
+
+// circonus_check.ignore_config_keys resource attribute name.
+const checkIgnoreKeysAttr = "ignore_config_keys"
+
+var schemaCheckIgnoreKeys = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	Elem: &schema.Schema{
+		Type:         schema.TypeString,
+		ValidateFunc: validateRegexp(checkIgnoreKeysAttr, `^[^\s]+$`),
+	},
+}
+
+// checkIgnoredKeys returns the ignore_config_keys of d.
+func checkIgnoredKeys(d *schema.ResourceData) map[config.Key]struct{} {
+	keys := make(map[config.Key]struct{})
+	s, ok := d.Get(checkIgnoreKeysAttr).(*schema.Set)
+	if !ok {
+		return keys
+	}
+
+	for _, key := range flattenSet(s) {
+		if key != nil && *key != "" {
+			keys[config.Key(*key)] = struct{}{}
+		}
+	}
+
+	return keys
+}
+
+// checkIgnoredKeysFromAPI removes the ignored keys from the config of c read
+// from the API.
+func checkIgnoredKeysFromAPI(c *circonusCheck, ignored map[config.Key]struct{}) {
+	for key := range ignored {
+		delete(c.Config, key)
+	}
+}
+
+// checkIgnoredKeysToAPI copies the values of the ignored keys of the config
+// of current, the check as stored by the API, into the config of c.  An error
+// is returned if the configuration of the check sets an ignored key, which
+// would otherwise be sent but never read back.
+func checkIgnoredKeysToAPI(c, current *circonusCheck, ignored map[config.Key]struct{}) error {
+	set := make([]string, 0)
+	for key := range ignored {
+		if _, found := c.Config[key]; found {
+			set = append(set, string(key))
+		}
+	}
+	if len(set) > 0 {
+		sort.Strings(set)
+		return fmt.Errorf("%s: the check's configuration sets %s, remove them from its configuration or from %s", checkIgnoreKeysAttr, strings.Join(set, ", "), checkIgnoreKeysAttr)
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	for key := range ignored {
+		if v, found := current.Config[key]; found {
+			c.Config[key] = v
+		}
+	}
+
+	return nil
+}
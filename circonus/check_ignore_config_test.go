@@ -0,0 +1,61 @@
+package circonus
+
+import (
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckIgnoredKeys(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		checkIgnoreKeysAttr: []interface{}{"header_X-Trace", "reverse:secret_key"},
+	})
+
+	ignored := checkIgnoredKeys(d)
+	if len(ignored) != 2 {
+		t.Fatalf("expected 2 ignored keys, got %v", ignored)
+	}
+
+	current := newCheck()
+	current.Config = api.CheckBundleConfig{
+		config.URL:           "https://app1.example.org/",
+		"header_X-Trace":     "on",
+		"reverse:secret_key": "abc",
+	}
+
+	c := newCheck()
+	c.Config = api.CheckBundleConfig{
+		config.URL: "https://app2.example.org/",
+	}
+	if err := checkIgnoredKeysToAPI(&c, &current, ignored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := api.CheckBundleConfig{
+		config.URL:           "https://app2.example.org/",
+		"header_X-Trace":     "on",
+		"reverse:secret_key": "abc",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	checksum := func() string {
+		read := newCheck()
+		read.Config = api.CheckBundleConfig{config.URL: "https://app2.example.org/"}
+		return read.ConfigChecksum()
+	}()
+	checkIgnoredKeysFromAPI(&c, ignored)
+	if len(c.Config) != 1 || c.ConfigChecksum() != checksum {
+		t.Errorf("expected the ignored keys to be removed, got %v", c.Config)
+	}
+
+	c.Config["header_X-Trace"] = "off"
+	if err := checkIgnoredKeysToAPI(&c, nil, ignored); err == nil {
+		t.Errorf("expected an error for an ignored key set by the configuration")
+	}
+}
@@ -0,0 +1,100 @@
+package circonus
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.*.resolve_target resource attribute name.
+	checkResolveTargetAttr = "resolve_target"
+
+	// checkResolveTargetDescription is the description of resolve_target.
+	checkResolveTargetDescription = "How the collector resolves the target: ipv4 or ipv6 only use addresses of that family, none requires the target to be an IP address.  The collector's default is used when unset"
+
+	checkResolveTargetIPv4 = "ipv4"
+	checkResolveTargetIPv6 = "ipv6"
+	checkResolveTargetNone = "none"
+
+	// checkResolveRTypesKey is the config key of the record types the
+	// collector resolves the target to.  It is not known to the API client.
+	checkResolveRTypesKey config.Key = "resolve_rtypes"
+)
+
+var validCheckResolveTargets = validStringValues{
+	checkResolveTargetIPv4,
+	checkResolveTargetIPv6,
+	checkResolveTargetNone,
+}
+
+// checkResolveTargetRTypes maps resolve_target to the record types the
+// collector is restricted to.  none has no record types: the target is an IP
+// address and is never resolved.
+var checkResolveTargetRTypes = map[string]string{
+	checkResolveTargetIPv4: "force-ipv4",
+	checkResolveTargetIPv6: "force-ipv6",
+}
+
+// schemaCheckResolveTarget is the resolve_target attribute shared by the
+// check types that resolve their target before connecting to it.
+var schemaCheckResolveTarget = &schema.Schema{
+	Type:         schema.TypeString,
+	Optional:     true,
+	ValidateFunc: validateStringIn(checkResolveTargetAttr, validCheckResolveTargets),
+}
+
+// checkResolveTargetToAPI sets the record types the collectors resolve the
+// target of c to.  c.Target must already be set.
+func checkResolveTargetToAPI(c *circonusCheck, resolveTarget string) error {
+	switch resolveTarget {
+	case "":
+		return nil
+	case checkResolveTargetNone:
+		if net.ParseIP(c.Target) == nil {
+			return fmt.Errorf("%s %q requires %s to be an IP address, got %q", checkResolveTargetAttr, resolveTarget, checkTargetAttr, c.Target)
+		}
+		return nil
+	default:
+		rtypes, found := checkResolveTargetRTypes[resolveTarget]
+		if !found {
+			return fmt.Errorf("unsupported %s %q", checkResolveTargetAttr, resolveTarget)
+		}
+		c.Config[checkResolveRTypesKey] = rtypes
+		return nil
+	}
+}
+
+// checkResolveTargetToState returns the resolve_target of c.  The API has no
+// record of none, which is kept from prior, the value in the statefile, as
+// long as the target is still an IP address.
+func checkResolveTargetToState(c *circonusCheck, prior string) string {
+	if rtypes, found := c.Config[checkResolveRTypesKey]; found {
+		for resolveTarget, v := range checkResolveTargetRTypes {
+			if v == rtypes {
+				return resolveTarget
+			}
+		}
+		return rtypes
+	}
+
+	if prior == checkResolveTargetNone && net.ParseIP(c.Target) != nil {
+		return checkResolveTargetNone
+	}
+
+	return ""
+}
+
+// checkResolveTargetState returns the resolve_target of the attr block of d,
+// which holds at most one element.
+func checkResolveTargetState(d *schema.ResourceData, attr string) string {
+	s, ok := d.Get(attr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return ""
+	}
+
+	v, _ := newInterfaceMap(s.List()[0])[checkResolveTargetAttr].(string)
+	return v
+}
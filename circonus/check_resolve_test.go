@@ -0,0 +1,86 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckResolveTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		resolveTarget string
+		rtypes        string
+		wantErr       bool
+	}{
+		{name: "collector default", target: "ntp.example.org"},
+		{name: "ipv4", target: "ntp.example.org", resolveTarget: checkResolveTargetIPv4, rtypes: "force-ipv4"},
+		{name: "ipv6", target: "ntp.example.org", resolveTarget: checkResolveTargetIPv6, rtypes: "force-ipv6"},
+		{name: "none with an IPv4 address", target: "192.0.2.10", resolveTarget: checkResolveTargetNone},
+		{name: "none with an IPv6 address", target: "2001:db8::10", resolveTarget: checkResolveTargetNone},
+		{name: "none with a host name", target: "ntp.example.org", resolveTarget: checkResolveTargetNone, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			c.Target = test.target
+
+			err := checkResolveTargetToAPI(&c, test.resolveTarget)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.Config[checkResolveRTypesKey] != test.rtypes {
+				t.Errorf("expected %s %q, got %q", checkResolveRTypesKey, test.rtypes, c.Config[checkResolveRTypesKey])
+			}
+			if got := checkResolveTargetToState(&c, test.resolveTarget); got != test.resolveTarget {
+				t.Errorf("expected %s %q to read back, got %q", checkResolveTargetAttr, test.resolveTarget, got)
+			}
+		})
+	}
+
+	// none is only kept while the target is an IP address.
+	c := newCheck()
+	c.Target = "ntp.example.org"
+	if got := checkResolveTargetToState(&c, checkResolveTargetNone); got != "" {
+		t.Errorf("expected no %s for a host name, got %q", checkResolveTargetAttr, got)
+	}
+}
+
+func TestCheckNTPResolveTarget(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkTargetAttr): "ntp.example.org",
+		string(checkNTPAttr): []interface{}{
+			map[string]interface{}{
+				checkResolveTargetAttr: checkResolveTargetIPv6,
+			},
+		},
+	})
+
+	ntpConfig := d.Get(string(checkNTPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	c.Target = d.Get(string(checkTargetAttr)).(string)
+	if err := checkConfigToAPINTP(&c, ntpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Config[checkResolveRTypesKey] != "force-ipv6" {
+		t.Errorf("expected %s %q, got %q", checkResolveRTypesKey, "force-ipv6", c.Config[checkResolveRTypesKey])
+	}
+
+	if err := checkAPIToStateNTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkNTPAttr)).(*schema.Set).List()
+	if hashCheckNTP(state[0]) != hashCheckNTP(ntpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+}
@@ -0,0 +1,103 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.secret resource attribute name.
+	checkSecretAttr = "secret"
+
+	// circonus_check.secret.* resource attribute names.
+	checkSecretConfigKeyAttr = "config_key"
+	checkSecretRefAttr       = "secret_ref"
+)
+
+var checkSecretDescriptions = attrDescrs{
+	checkSecretConfigKeyAttr: "The API config key of the check set to the secret (e.g. dsn)",
+	checkSecretRefAttr:       "Reference to the secret, resolved by the provider when the check is applied (e.g. vault:secret/data/pg1#dsn)",
+}
+
+// checkSecretRequiredKeys are the config keys check types can not do without,
+// which may be set either by their block or by a secret.
+var checkSecretRequiredKeys = map[apiCheckType]struct {
+	attr schemaAttr
+	key  config.Key
+}{
+	apiCheckTypeMySQLAttr:      {checkMySQLDSNAttr, config.DSN},
+	apiCheckTypePostgreSQLAttr: {checkPostgreSQLDSNAttr, config.DSN},
+}
+
+var schemaCheckSecret = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkSecretDescriptions, map[schemaAttr]*schema.Schema{
+			checkSecretConfigKeyAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkSecretConfigKeyAttr, `^[^\s]+$`),
+			},
+			checkSecretRefAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(checkSecretRefAttr, `^[a-z]+:.+$`),
+			},
+		}),
+	},
+}
+
+// checkSecretKeys returns the config keys of the secrets of d.
+func checkSecretKeys(d *schema.ResourceData) []config.Key {
+	l, _ := d.Get(checkSecretAttr).([]interface{})
+	keys := make([]config.Key, 0, len(l))
+	for _, secretRaw := range l {
+		secret := newInterfaceMap(secretRaw)
+		if v, ok := secret[string(checkSecretConfigKeyAttr)].(string); ok && v != "" {
+			keys = append(keys, config.Key(v))
+		}
+	}
+
+	return keys
+}
+
+// checkSecretsToAPI resolves the secrets of d into the config of c.  A config
+// key can be set by a secret or by the check type block, not both.
+func checkSecretsToAPI(ctx context.Context, ctxt *providerContext, c *circonusCheck, d *schema.ResourceData) error {
+	l, _ := d.Get(checkSecretAttr).([]interface{})
+	for i, secretRaw := range l {
+		secret := newInterfaceMap(secretRaw)
+		key := config.Key(secret[string(checkSecretConfigKeyAttr)].(string))
+		ref := secret[string(checkSecretRefAttr)].(string)
+
+		if v, found := c.Config[key]; found && v != "" {
+			return fmt.Errorf("%s.%d: config key %q is already set by the %s check, remove it there to set it from %s", checkSecretAttr, i, key, c.Type, ref)
+		}
+
+		v, err := ctxt.secrets.Resolve(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("%s.%d: %w", checkSecretAttr, i, err)
+		}
+
+		c.Config[key] = v
+	}
+
+	if required, found := checkSecretRequiredKeys[apiCheckType(c.Type)]; found && c.Config[required.key] == "" {
+		return fmt.Errorf("%s: %s is required, set it or a %s with %s %q", c.Type, required.attr, checkSecretAttr, checkSecretConfigKeyAttr, required.key)
+	}
+
+	return nil
+}
+
+// checkSecretsFromAPI removes the config keys set from the secrets of d from
+// the config the API returned for c, so the resolved values are never read
+// into the statefile.
+func checkSecretsFromAPI(c *circonusCheck, d *schema.ResourceData) {
+	for _, key := range checkSecretKeys(d) {
+		delete(c.Config, key)
+	}
+}
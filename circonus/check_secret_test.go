@@ -0,0 +1,76 @@
+package circonus
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	os.Setenv("CIRCONUS_TEST_PG_DSN", "host=db1 user=monitor password=hunter2")
+	defer os.Unsetenv("CIRCONUS_TEST_PG_DSN")
+
+	ctxt := &providerContext{secrets: newSecretResolvers("", "")}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkPostgreSQLAttr): []interface{}{
+			map[string]interface{}{
+				string(checkPostgreSQLQueryAttr): "SELECT 1",
+			},
+		},
+		string(checkSecretAttr): []interface{}{
+			map[string]interface{}{
+				string(checkSecretConfigKeyAttr): string(config.DSN),
+				string(checkSecretRefAttr):       "env:CIRCONUS_TEST_PG_DSN",
+			},
+		},
+	})
+
+	c := newCheck()
+	if err := checkConfigToAPI(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkSecretsToAPI(context.Background(), ctxt, &c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := c.Config[config.DSN]; v != "host=db1 user=monitor password=hunter2" {
+		t.Errorf("expected the DSN to be resolved, got %q", v)
+	}
+
+	// The API echoes the DSN back, it must not be read into the statefile.
+	checkSecretsFromAPI(&c, d)
+	if err := checkAPIToStatePostgreSQL(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state := d.Get(string(checkPostgreSQLAttr)).(*schema.Set).List()
+	if v := newInterfaceMap(state[0])[string(checkPostgreSQLDSNAttr)]; v != "" {
+		t.Errorf("expected no DSN in the statefile, got %q", v)
+	}
+
+	// A key can not be set by both the check type block and a secret.
+	c = newCheck()
+	c.Type = string(apiCheckTypePostgreSQL)
+	c.Config[config.DSN] = "host=db3"
+	if err := checkSecretsToAPI(context.Background(), ctxt, &c, d); err == nil {
+		t.Errorf("expected a DSN set twice to be rejected")
+	}
+
+	// The DSN is required from one or the other.
+	d = schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkPostgreSQLAttr): []interface{}{
+			map[string]interface{}{
+				string(checkPostgreSQLQueryAttr): "SELECT 1",
+			},
+		},
+	})
+	c = newCheck()
+	if err := checkConfigToAPI(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkSecretsToAPI(context.Background(), ctxt, &c, d); err == nil {
+		t.Errorf("expected a missing DSN to be rejected")
+	}
+}
@@ -0,0 +1,789 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func Test_CheckSortedByCollector(t *testing.T) {
+	c := newCheck()
+	c.Brokers = []string{"/broker/35", "/broker/1", "/broker/2"}
+	c.Checks = []string{"/check/3", "/check/1", "/check/2"}
+	c.CheckUUIDs = []string{"uuid-3", "uuid-1", "uuid-2"}
+	c.ReverseConnectURLs = []string{"mtev_reverse://b", "mtev_reverse://a"}
+
+	checks, uuids, urls := c.SortedByCollector()
+
+	if expected := []string{"/check/1", "/check/2", "/check/3"}; !reflect.DeepEqual(checks, expected) {
+		t.Fatalf("checks: expected %v, got %v", expected, checks)
+	}
+
+	if expected := []string{"uuid-1", "uuid-2", "uuid-3"}; !reflect.DeepEqual(uuids, expected) {
+		t.Fatalf("uuids: expected %v, got %v", expected, uuids)
+	}
+
+	if expected := []string{"mtev_reverse://a", "mtev_reverse://b"}; !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("reverse_connect_urls: expected %v, got %v", expected, urls)
+	}
+}
+
+func Test_CheckConfigChecksum(t *testing.T) {
+	a := newCheck()
+	a.Config = map[config.Key]string{
+		config.URL:  "https://example.com/",
+		"unmanaged": "1",
+	}
+
+	b := newCheck()
+	b.Config = map[config.Key]string{
+		"unmanaged": "1",
+		config.URL:  "https://example.com/",
+	}
+
+	if a.ConfigChecksum() != b.ConfigChecksum() {
+		t.Fatal("expected checksum to be independent of key order")
+	}
+
+	b.Config["unmanaged"] = "2"
+	if a.ConfigChecksum() == b.ConfigChecksum() {
+		t.Fatal("expected checksum to change when an unmanaged key changes")
+	}
+}
+
+func Test_ValidateCheckPeriodTimeout(t *testing.T) {
+	tests := []struct {
+		checkType apiCheckType
+		period    time.Duration
+		timeout   time.Duration
+		ok        bool
+	}{
+		{apiCheckTypeHTTPAttr, time.Minute, 10 * time.Second, true},
+		{apiCheckTypeHTTPAttr, time.Minute, time.Minute, true},
+		{apiCheckTypeHTTPAttr, 30 * time.Second, time.Minute, false},
+		{apiCheckTypeCloudWatchAttr, 5 * time.Minute, 10 * time.Second, true},
+		{apiCheckTypeCloudWatchAttr, 2 * time.Minute, 10 * time.Second, false},
+	}
+
+	for _, test := range tests {
+		err := validateCheckPeriodTimeout(test.checkType, test.period, test.timeout)
+		if test.ok && err != nil {
+			t.Fatalf("%s period %s timeout %s: unexpected error: %v", test.checkType, test.period, test.timeout, err)
+		}
+		if !test.ok && err == nil {
+			t.Fatalf("%s period %s timeout %s: expected an error", test.checkType, test.period, test.timeout)
+		}
+	}
+}
+
+func Test_ValidateCheckMetricsUnique(t *testing.T) {
+	metric := func(name, metricType string) interface{} {
+		return map[string]interface{}{
+			string(metricNameAttr): name,
+			string(metricTypeAttr): metricType,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		metrics []interface{}
+		err     string
+	}{
+		{"unique", []interface{}{metric("a", "numeric"), metric("b", "numeric")}, ""},
+		{"same name, other type", []interface{}{metric("a", "numeric"), metric("a", "text")}, ""},
+		{"duplicate", []interface{}{metric("a", "numeric"), metric("b", "text"), metric("a", "numeric")}, "metric.2 duplicates metric.0"},
+		{"unknown", []interface{}{metric("a", "numeric"), nil, metric("", "numeric")}, ""},
+	}
+
+	for _, test := range tests {
+		err := validateCheckMetricsUnique(test.metrics)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%s: expected an error containing %q, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func Test_CheckMetricOrder(t *testing.T) {
+	metric := func(name, metricType string, active bool) interface{} {
+		return map[string]interface{}{
+			string(metricActiveAttr): active,
+			string(metricNameAttr):   name,
+			string(metricTypeAttr):   metricType,
+		}
+	}
+
+	a, b, c := metric("a", "numeric", true), metric("b", "text", true), metric("c", "numeric", true)
+
+	tests := []struct {
+		name      string
+		old, new  []interface{}
+		reordered bool
+	}{
+		{"reordered", []interface{}{a, b, c}, []interface{}{c, a, b}, true},
+		{"added", []interface{}{a, b}, []interface{}{b, a, c}, false},
+		{"removed", []interface{}{a, b, c}, []interface{}{c, a}, false},
+		{"replaced", []interface{}{a, b}, []interface{}{b, c}, false},
+		{"deactivated", []interface{}{a, b}, []interface{}{b, metric("a", "numeric", false)}, false},
+		{"retyped", []interface{}{a, b}, []interface{}{b, metric("a", "text", true)}, false},
+	}
+
+	for _, test := range tests {
+		if got := checkMetricsReordered(test.old, test.new); got != test.reordered {
+			t.Errorf("%s: expected %t, got %t", test.name, test.reordered, got)
+		}
+	}
+
+	// Metrics read from the API follow the order of the statefile, new ones
+	// are appended.
+	d := metric("d", "text", true)
+	metrics := []interface{}{a, d, b, c}
+	orderCheckMetrics(metrics, []interface{}{c, a, b})
+	if expected := []interface{}{c, a, b, d}; !reflect.DeepEqual(metrics, expected) {
+		t.Errorf("expected %v, got %v", expected, metrics)
+	}
+}
+
+func Test_CheckMetricOrderDiff(t *testing.T) {
+	metric := func(name string, active bool) interface{} {
+		return map[string]interface{}{
+			string(metricActiveAttr): active,
+			string(metricNameAttr):   name,
+			string(metricTypeAttr):   "numeric",
+		}
+	}
+
+	check := func(metrics ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			checkCollectorAttr: []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkJSONAttr:      []interface{}{map[string]interface{}{checkJSONURLAttr: "https://example.com/stats"}},
+			checkMetricAttr:    metrics,
+			checkNameAttr:      "stats",
+		}
+	}
+
+	r := resourceCheck()
+	d := schema.TestResourceDataRaw(t, r.Schema, check(metric("a", true), metric("b", true)))
+	d.SetId("/check_bundle/1")
+	state := d.State()
+
+	metricDiff := func(cfg map[string]interface{}) []string {
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(cfg), &providerContext{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys := make([]string, 0)
+		if diff != nil {
+			for k := range diff.Attributes {
+				if strings.HasPrefix(k, checkMetricAttr+".") {
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	if keys := metricDiff(check(metric("b", true), metric("a", true))); len(keys) != 0 {
+		t.Errorf("expected reordering the metrics to be a no-op, got a diff of %v", keys)
+	}
+
+	if keys := metricDiff(check(metric("b", true), metric("a", false))); len(keys) == 0 {
+		t.Error("expected deactivating a metric to be diffed")
+	}
+
+	withUnit := func(metricRaw interface{}, unit string, tags ...interface{}) interface{} {
+		m := metricRaw.(map[string]interface{})
+		m[string(metricUnitAttr)] = unit
+		m[string(metricTagsAttr)] = tags
+		return m
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, check(withUnit(metric("a", true), "seconds", "team:web", "env:prod"), metric("b", true)))
+	d.SetId("/check_bundle/1")
+	state = d.State()
+
+	if keys := metricDiff(check(metric("b", true), withUnit(metric("a", true), "seconds", "env:prod", "team:web"))); len(keys) != 0 {
+		t.Errorf("expected reordering metrics with units and tags to be a no-op, got a diff of %v", keys)
+	}
+
+	if keys := metricDiff(check(withUnit(metric("a", true), "milliseconds", "team:web", "env:prod"), metric("b", true))); len(keys) == 0 {
+		t.Error("expected changing the unit of a metric to be diffed")
+	}
+
+	if keys := metricDiff(check(withUnit(metric("a", true), "seconds", "team:web"), metric("b", true))); len(keys) == 0 {
+		t.Error("expected changing the tags of a metric to be diffed")
+	}
+}
+
+func Test_CheckActivateDiscoveredMetrics(t *testing.T) {
+	check := func(attrs map[string]interface{}) map[string]interface{} {
+		cfg := map[string]interface{}{
+			checkCollectorAttr:    []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkJSONAttr:         []interface{}{map[string]interface{}{checkJSONURLAttr: "https://example.com/stats"}},
+			checkMetricFilterAttr: []interface{}{map[string]interface{}{"type": "allow", "regex": ".*"}},
+			checkNameAttr:         "stats",
+		}
+		for k, v := range attrs {
+			cfg[k] = v
+		}
+		return cfg
+	}
+
+	r := resourceCheck()
+	d := schema.TestResourceDataRaw(t, r.Schema, check(map[string]interface{}{
+		checkActivateDiscoveredMetricsAttr: true,
+		checkMetricLimitAttr:               -1,
+	}))
+	d.SetId("/check_bundle/1")
+	state := d.State()
+
+	tests := []struct {
+		name     string
+		attrs    map[string]interface{}
+		activate string
+		limit    string
+		err      bool
+	}{
+		{"unchanged", nil, "", "", false},
+		{"deactivate", map[string]interface{}{checkActivateDiscoveredMetricsAttr: false}, "false", "0", false},
+		{"metric limit 0", map[string]interface{}{checkMetricLimitAttr: 0}, "false", "0", false},
+		{"metric limit 10", map[string]interface{}{checkMetricLimitAttr: 10}, "", "10", false},
+		{"conflict", map[string]interface{}{checkActivateDiscoveredMetricsAttr: false, checkMetricLimitAttr: 10}, "", "", true},
+	}
+
+	for _, test := range tests {
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(check(test.attrs)), &providerContext{})
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		for attr, expected := range map[string]string{
+			checkActivateDiscoveredMetricsAttr: test.activate,
+			checkMetricLimitAttr:               test.limit,
+		} {
+			var got string
+			if diff != nil && diff.Attributes[attr] != nil {
+				got = diff.Attributes[attr].New
+			}
+			if got != expected {
+				t.Errorf("%s: expected %s to be planned as %q, got %q", test.name, attr, expected, got)
+			}
+		}
+	}
+}
+
+func Test_CheckPreventCollectorChange(t *testing.T) {
+	check := func(prevent bool, collectors ...string) map[string]interface{} {
+		l := make([]interface{}, 0, len(collectors))
+		for _, id := range collectors {
+			l = append(l, map[string]interface{}{checkCollectorIDAttr: id})
+		}
+		return map[string]interface{}{
+			checkCollectorAttr:              l,
+			checkJSONAttr:                   []interface{}{map[string]interface{}{checkJSONURLAttr: "https://example.com/stats"}},
+			checkNameAttr:                   "stats",
+			checkPreventCollectorChangeAttr: prevent,
+		}
+	}
+
+	r := resourceCheck()
+	d := schema.TestResourceDataRaw(t, r.Schema, check(true, "/broker/1"))
+	d.SetId("/check_bundle/1")
+	state := d.State()
+
+	tests := []struct {
+		name string
+		cfg  map[string]interface{}
+		err  bool
+	}{
+		{"unchanged", check(true, "/broker/1"), false},
+		{"moved", check(true, "/broker/2"), true},
+		{"added", check(true, "/broker/1", "/broker/2"), true},
+		{"opted in", check(false, "/broker/2"), false},
+	}
+
+	for _, test := range tests {
+		_, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(test.cfg), &providerContext{})
+		switch {
+		case test.err && err == nil:
+			t.Errorf("%s: expected an error", test.name)
+		case test.err && !strings.Contains(err.Error(), "/broker/1 to "):
+			t.Errorf("%s: expected the collectors to be reported, got %v", test.name, err)
+		case !test.err && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(check(true, "/broker/2")), &providerContext{}); err != nil {
+		t.Errorf("expected new checks to be unaffected, got %v", err)
+	}
+}
+
+func Test_CheckDeactivateDiscoveredMetrics(t *testing.T) {
+	var bodies []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		bodies = append(bodies, body)
+		_ = json.NewEncoder(w).Encode(api.CheckBundle{CID: "/check_bundle/1"})
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	c := newCheck()
+	c.DisplayName = "stats"
+	c.MetricLimit = 0
+	c.deactivateDiscoveredMetrics = true
+	if err := c.Create(ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.CID != "/check_bundle/1" {
+		t.Errorf("expected the CID of the created check bundle, got %q", c.CID)
+	}
+	if err := c.Update(ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.deactivateDiscoveredMetrics = false
+	if err := c.Update(ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(bodies))
+	}
+	for i, expected := range []bool{true, true, false} {
+		limit, found := bodies[i][apiCheckMetricLimitKey]
+		if found != expected || (found && limit != float64(0)) {
+			t.Errorf("request %d: expected metric_limit 0 to be sent %t, got %v", i, expected, bodies[i])
+		}
+		if bodies[i]["display_name"] != "stats" {
+			t.Errorf("request %d: expected the rest of the check bundle to be sent, got %v", i, bodies[i])
+		}
+	}
+}
+
+func Test_CheckActiveMetricCount(t *testing.T) {
+	c := newCheck()
+	c.Metrics = []api.CheckBundleMetric{
+		{Name: "cpu", Status: metricStatusActive},
+		{Name: "mem", Status: metricStatusAvailable},
+		{Name: "disk", Status: metricStatusActive},
+	}
+
+	if n := c.ActiveMetricCount(); n != 2 {
+		t.Errorf("expected 2 active metrics, got %d", n)
+	}
+}
+
+func Test_CheckMetricFilterMatches(t *testing.T) {
+	c := newCheck()
+	c.MetricFilters = [][]string{
+		{"deny", "^debug`", ""},
+		{"allow", "^(cpu|mem)`", "tags", "env:prod", ""},
+		{"allow", "(?<=x)lookbehind", ""},
+		{"allow", ".*", ""},
+	}
+	c.Metrics = []api.CheckBundleMetric{
+		{Name: "debug`cpu`user"},
+		{Name: "cpu`user"},
+		{Name: "cpu`system"},
+		{Name: "mem`free"},
+		{Name: "disk`used"},
+	}
+
+	expected := []int{1, 3, -1, 1}
+	if got := c.MetricFilterMatches(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func Test_CheckValidateSelfcheck(t *testing.T) {
+	tests := []struct {
+		target  string
+		brokers []string
+		ok      bool
+	}{
+		{"10.0.0.1", []string{"/broker/1"}, true},
+		{"", []string{"/broker/1"}, false},
+		{"10.0.0.1", []string{"/broker/1", "/broker/2"}, false},
+	}
+
+	for _, test := range tests {
+		c := newCheck()
+		c.Type = string(apiCheckTypeSelfcheck)
+		c.Period = 60
+		c.Timeout = 10
+		c.Metrics = []api.CheckBundleMetric{{Name: "check_cnt", Type: "numeric"}}
+		c.Target = test.target
+		c.Brokers = test.brokers
+
+		err := c.Validate()
+		if test.ok && err != nil {
+			t.Fatalf("target %q brokers %v: unexpected error: %v", test.target, test.brokers, err)
+		}
+		if !test.ok && err == nil {
+			t.Fatalf("target %q brokers %v: expected an error", test.target, test.brokers)
+		}
+	}
+}
+
+// testCheckBundles returns n check bundles named "check-<i>", each tagged with
+// its parity.
+func testCheckBundles(n int) []api.CheckBundle {
+	bundles := make([]api.CheckBundle, n)
+	for i := range bundles {
+		bundles[i] = api.CheckBundle{
+			CID:         fmt.Sprintf("/check_bundle/%d", i),
+			DisplayName: fmt.Sprintf("check-%d", i),
+			Tags:        []string{fmt.Sprintf("parity:%d", i%2)},
+		}
+	}
+
+	return bundles
+}
+
+// testCheckSearchServer serves a check bundle search filtering on f_name the
+// way the API does, a prefix match rather than an exact one.  Unfiltered
+// listings are rejected.
+func testCheckSearchServer(tb testing.TB, bundles []api.CheckBundle) *providerContext {
+	tb.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get(checkSearchNameFilter) == "" && len(q[checkSearchTagFilter]) == 0 {
+			http.Error(w, "unfiltered check bundle listing", http.StatusBadRequest)
+			return
+		}
+
+		results := make([]api.CheckBundle, 0)
+		for _, b := range bundles {
+			if name := q.Get(checkSearchNameFilter); name != "" && !(len(b.DisplayName) >= len(name) && b.DisplayName[:len(name)] == name) {
+				continue
+			}
+			if !hasAllTags(b.Tags, q[checkSearchTagFilter]) {
+				continue
+			}
+			results = append(results, b)
+		}
+
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	tb.Cleanup(srv.Close)
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		tb.Fatalf("unexpected error: %v", err)
+	}
+
+	return &providerContext{client: client}
+}
+
+func TestSearchChecks(t *testing.T) {
+	ctxt := testCheckSearchServer(t, testCheckBundles(25))
+
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{"check-1", nil, []string{"/check_bundle/1"}},
+		{"check-1", []string{"parity:0"}, []string{}},
+		{"check-12", []string{"parity:0"}, []string{"/check_bundle/12"}},
+		{"", []string{"parity:1"}, []string{"/check_bundle/1", "/check_bundle/3", "/check_bundle/5", "/check_bundle/7", "/check_bundle/9", "/check_bundle/11", "/check_bundle/13", "/check_bundle/15", "/check_bundle/17", "/check_bundle/19", "/check_bundle/21", "/check_bundle/23"}},
+		{"missing", nil, []string{}},
+	}
+
+	for _, test := range tests {
+		checks, err := searchChecks(ctxt, test.name, test.tags)
+		if err != nil {
+			t.Fatalf("%q %v: unexpected error: %v", test.name, test.tags, err)
+		}
+
+		cids := make([]string, 0, len(checks))
+		for i := range checks {
+			cids = append(cids, checks[i].CID)
+		}
+
+		if !reflect.DeepEqual(cids, test.expected) {
+			t.Errorf("%q %v: expected %v, got %v", test.name, test.tags, test.expected, cids)
+		}
+	}
+
+	if _, err := searchChecks(ctxt, "", nil); err == nil {
+		t.Errorf("expected an error searching without a name or tags")
+	}
+}
+
+func BenchmarkSearchChecks(b *testing.B) {
+	ctxt := testCheckSearchServer(b, testCheckBundles(20000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checks, err := searchChecks(ctxt, "check-19999", nil)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+		if len(checks) != 1 {
+			b.Fatalf("expected 1 check, got %d", len(checks))
+		}
+	}
+}
+
+func BenchmarkMatchCheckBundles(b *testing.B) {
+	bundles := testCheckBundles(20000)
+	tags := []string{"parity:1"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if checks := matchCheckBundles(bundles, "check-19999", tags); len(checks) != 1 {
+			b.Fatalf("expected 1 check, got %d", len(checks))
+		}
+	}
+}
+
+func TestCheckImportState(t *testing.T) {
+	unit := "seconds"
+	bundles := map[string]api.CheckBundle{
+		"/check_bundle/1": {
+			CID:         "/check_bundle/1",
+			DisplayName: "Account usage",
+			Type:        string(apiCheckTypeJSONAttr),
+			Target:      "api.example.com",
+			Period:      60,
+			Timeout:     10,
+			Brokers:     []string{"/broker/1"},
+			Checks:      []string{"/check/11"},
+			Status:      "active",
+			Metrics: []api.CheckBundleMetric{
+				{Name: "duration", Type: "numeric", Status: metricStatusActive, Units: &unit, Tags: []string{"team:web"}},
+			},
+			Config: api.CheckBundleConfig{
+				config.URL:                            "https://api.example.com/usage",
+				config.AuthUser:                       "usage",
+				config.AuthPassword:                   "hunter2",
+				config.HeaderPrefix + "Accept":        "application/json",
+				config.HeaderPrefix + "X-Usage-Scope": "account",
+			},
+		},
+		"/check_bundle/2": {
+			CID:         "/check_bundle/2",
+			DisplayName: "Unsupported",
+			Type:        "bogus",
+			Brokers:     []string{"/broker/1"},
+			Checks:      []string{"/check/21"},
+		},
+		"/check_bundle/3": {},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/check_bundle" {
+			results := make([]api.CheckBundle, 0)
+			for _, b := range bundles {
+				if b.CID != "" && strings.HasPrefix(b.DisplayName, r.URL.Query().Get(checkSearchNameFilter)) {
+					results = append(results, b)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(results)
+			return
+		}
+
+		b, found := bundles[r.URL.Path]
+		if !found {
+			http.Error(w, `{"code":404,"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(b)
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	for _, id := range []string{"/check_bundle/1", "name=Account usage"} {
+		d := resourceCheck().Data(nil)
+		d.SetId(id)
+
+		imported, err := checkImportState(context.Background(), d, ctxt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+		if len(imported) != 1 || imported[0].Id() != "/check_bundle/1" {
+			t.Fatalf("%s: expected /check_bundle/1 to be imported, got %v", id, imported)
+		}
+
+		// The check type block is populated by the import itself.
+		d = imported[0]
+		for attr, expected := range map[string]string{
+			"name":                    "Account usage",
+			"json.#":                  "1",
+			"applied_config_checksum": d.Get(checkOutConfigChecksumAttr).(string),
+			"metric.0.unit":           "seconds",
+			"metric.0.tags.#":         "1",
+		} {
+			if got := fmt.Sprint(d.Get(attr)); got != expected {
+				t.Errorf("%s: expected %s to be %q, got %q", id, attr, expected, got)
+			}
+		}
+
+		jsonConfig := d.Get(checkJSONAttr).(*schema.Set).List()[0].(map[string]interface{})
+		for attr, expected := range map[schemaAttr]string{
+			checkJSONURLAttr:          "https://api.example.com/usage",
+			checkJSONAuthUserAttr:     "usage",
+			checkJSONAuthPasswordAttr: "hunter2",
+		} {
+			if got := fmt.Sprint(jsonConfig[string(attr)]); got != expected {
+				t.Errorf("%s: expected json %s to be %q, got %q", id, attr, expected, got)
+			}
+		}
+
+		headers := jsonConfig[string(checkJSONHeadersAttr)]
+		expectedHeaders := map[string]interface{}{"Accept": "application/json", "X-Usage-Scope": "account"}
+		if !reflect.DeepEqual(headers, expectedHeaders) {
+			t.Errorf("%s: expected headers %v, got %v", id, expectedHeaders, headers)
+		}
+	}
+
+	for _, id := range []string{"/check_bundle/2", "/check_bundle/3", "/check_bundle/4", "name=Missing", "name="} {
+		d := resourceCheck().Data(nil)
+		d.SetId(id)
+
+		if _, err := checkImportState(context.Background(), d, ctxt); err == nil {
+			t.Errorf("%s: expected an error", id)
+		}
+	}
+}
+
+func TestCheckQuiesceOnDestroy(t *testing.T) {
+	var failWindows bool
+	var requests []string
+	var windows []api.Maintenance
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/maintenance":
+			if failWindows {
+				http.Error(w, `{"code":400,"message":"invalid maintenance window"}`, http.StatusBadRequest)
+				return
+			}
+			var m api.Maintenance
+			if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+				t.Errorf("unable to decode maintenance window: %v", err)
+			}
+			windows = append(windows, m)
+			m.CID = fmt.Sprintf("/maintenance/%d", len(windows))
+			_ = json.NewEncoder(w).Encode(m)
+		case r.Method == http.MethodDelete && r.URL.Path == "/check_bundle/1":
+			_, _ = w.Write([]byte("{}"))
+		default:
+			http.Error(w, `{"code":404,"message":"not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	newCheck := func() *schema.ResourceData {
+		d := resourceCheck().Data(nil)
+		d.SetId("/check_bundle/1")
+		if err := d.Set(checkQuiesceAttr, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := d.Set(checkOutChecksAttr, []string{"/check/11", "/check/12"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return d
+	}
+
+	// Each check is placed in maintenance before the check bundle is deleted.
+	d := newCheck()
+	if diags := checkDelete(context.Background(), d, ctxt); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	expected := []string{"POST /maintenance", "POST /maintenance", "DELETE /check_bundle/1"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("expected requests %v, got %v", expected, requests)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the ID to be cleared, got %q", d.Id())
+	}
+	for i, item := range []string{"/check/11", "/check/12"} {
+		m := windows[i]
+		if m.Type != "check" || m.Item != item {
+			t.Errorf("expected a maintenance window for check %s, got %s %s", item, m.Type, m.Item)
+		}
+		if got := fmt.Sprint(m.Severities); got != "[1 2 3 4 5]" {
+			t.Errorf("expected all severities to be quiesced, got %s", got)
+		}
+		if window := time.Duration(m.Stop-m.Start) * time.Second; window != checkQuiesceWindow {
+			t.Errorf("expected a %s maintenance window, got %s", checkQuiesceWindow, window)
+		}
+	}
+
+	// A check bundle whose checks can not be quiesced is not deleted.
+	failWindows, requests = true, nil
+	d = newCheck()
+	diags := checkDelete(context.Background(), d, ctxt)
+	if !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+	if summary := diags[0].Summary; !strings.Contains(summary, "unable to quiesce check /check/11") {
+		t.Errorf("expected a quiesce error, got %q", summary)
+	}
+	expected = []string{"POST /maintenance"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+	if d.Id() != "/check_bundle/1" {
+		t.Errorf("expected the ID to be kept, got %q", d.Id())
+	}
+}
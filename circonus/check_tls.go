@@ -0,0 +1,202 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.*.tls_config.* resource attribute names.
+	checkTLSConfigAttr   = "tls_config"
+	checkTLSCAChainAttr  = "ca_chain"
+	checkTLSCertFileAttr = "certificate_file"
+	checkTLSCiphersAttr  = "ciphers"
+	checkTLSKeyFileAttr  = "key_file"
+)
+
+var checkTLSDescriptions = attrDescrs{
+	checkTLSCAChainAttr:  "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate",
+	checkTLSCertFileAttr: "A path to a file containing the client certificate that will be presented to the remote server",
+	checkTLSCiphersAttr:  "A list of ciphers to be used when establishing a TLS connection",
+	checkTLSKeyFileAttr:  "A path to a file containing key to be used in conjunction with the client certificate",
+}
+
+// checkTLSConfigDescription is the description of the tls_config block.
+const checkTLSConfigDescription = "The TLS settings used to connect to the target of the check"
+
+// checkTLSConfigKeys maps the tls_config attributes to their API config keys.
+var checkTLSConfigKeys = map[schemaAttr]config.Key{
+	checkTLSCAChainAttr:  config.CAChain,
+	checkTLSCertFileAttr: config.CertFile,
+	checkTLSCiphersAttr:  config.Ciphers,
+	checkTLSKeyFileAttr:  config.KeyFile,
+}
+
+// checkTLSConfigAttrs is the lexically sorted list of tls_config attributes.
+var checkTLSConfigAttrs = []schemaAttr{
+	checkTLSCAChainAttr,
+	checkTLSCertFileAttr,
+	checkTLSCiphersAttr,
+	checkTLSKeyFileAttr,
+}
+
+// schemaCheckTLS is the tls_config block shared by the check types that
+// connect to their target over TLS.
+var schemaCheckTLS = newSchemaCheckTLS(nil, nil)
+
+// newSchemaCheckTLS returns a tls_config block with the attributes of
+// extraAttrs in addition to the shared ones, for check types whose module
+// supports more TLS settings.
+func newSchemaCheckTLS(extraDescriptions attrDescrs, extraAttrs map[schemaAttr]*schema.Schema) *schema.Schema {
+	descriptions := make(attrDescrs, len(checkTLSDescriptions)+len(extraDescriptions))
+	for k, v := range checkTLSDescriptions {
+		descriptions[k] = v
+	}
+	for k, v := range extraDescriptions {
+		descriptions[k] = v
+	}
+
+	attrs := map[schemaAttr]*schema.Schema{
+		checkTLSCAChainAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegexp(checkTLSCAChainAttr, `.+`),
+		},
+		checkTLSCertFileAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegexp(checkTLSCertFileAttr, `.+`),
+		},
+		checkTLSCiphersAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegexp(checkTLSCiphersAttr, `.+`),
+		},
+		checkTLSKeyFileAttr: {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegexp(checkTLSKeyFileAttr, `.+`),
+		},
+	}
+	for k, v := range extraAttrs {
+		attrs[k] = v
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: convertToHelperSchema(descriptions, attrs),
+		},
+	}
+}
+
+// checkTLSDeprecation is the deprecation message of the top level TLS
+// attributes of the check types that predate tls_config.
+const checkTLSDeprecation = "use the " + string(checkTLSConfigAttr) + " block instead"
+
+// checkTLSAPIToState returns the tls_config state of the TLS settings found in
+// the check's config, removing them from swamp (which may be nil).  An empty
+// list is returned when no TLS setting is present.
+func checkTLSAPIToState(c *circonusCheck, swamp map[config.Key]string) []interface{} {
+	tlsConfig := make(map[string]interface{}, len(checkTLSConfigKeys))
+	for attr, apiKey := range checkTLSConfigKeys {
+		if v, ok := c.Config[apiKey]; ok && v != "" {
+			tlsConfig[string(attr)] = v
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	if len(tlsConfig) == 0 {
+		return []interface{}{}
+	}
+
+	return []interface{}{tlsConfig}
+}
+
+// checkTLSConfigToAPI copies the tls_config settings of a check type block
+// into the check's config.
+func checkTLSConfigToAPI(c *circonusCheck, m interfaceMap) {
+	for _, tlsConfig := range checkTLSConfigList(m) {
+		for attr, apiKey := range checkTLSConfigKeys {
+			if v, ok := tlsConfig[string(attr)].(string); ok && v != "" {
+				c.Config[apiKey] = v
+			}
+		}
+	}
+}
+
+// checkTLSConfigList returns the tls_config elements of a check type block.
+func checkTLSConfigList(m map[string]interface{}) []map[string]interface{} {
+	l, ok := m[string(checkTLSConfigAttr)].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	tlsConfigs := make([]map[string]interface{}, 0, len(l))
+	for _, v := range l {
+		if tlsConfig, ok := v.(map[string]interface{}); ok {
+			tlsConfigs = append(tlsConfigs, tlsConfig)
+		}
+	}
+
+	return tlsConfigs
+}
+
+// checkTLSLegacyInUse returns true if the check type block of d sets any of
+// the deprecated top level TLS attributes.  Those attributes are kept in the
+// statefile in place of a tls_config block so existing configs don't diff.
+func checkTLSLegacyInUse(d *schema.ResourceData, checkTypeAttr schemaAttr) bool {
+	s, ok := d.Get(string(checkTypeAttr)).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return false
+	}
+
+	m, ok := s.List()[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, attr := range checkTLSConfigAttrs {
+		if v, ok := m[string(attr)].(string); ok && v != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkTLSConflict returns an error if a check type block sets both the
+// deprecated top level TLS attributes and a tls_config block.
+func checkTLSConflict(checkTypeAttr schemaAttr, m interfaceMap) error {
+	if len(checkTLSConfigList(m)) == 0 {
+		return nil
+	}
+
+	for _, attr := range checkTLSConfigAttrs {
+		if v, ok := m[string(attr)].(string); ok && v != "" {
+			return fmt.Errorf("%s: %s conflicts with %s", checkTypeAttr, attr, checkTLSConfigAttr)
+		}
+	}
+
+	return nil
+}
+
+// writeCheckTLSHash writes the normalized tls_config of a check type block to
+// the hash buffer of the block.
+func writeCheckTLSHash(b *bytes.Buffer, m map[string]interface{}) {
+	for _, tlsConfig := range checkTLSConfigList(m) {
+		fmt.Fprint(b, checkTLSConfigAttr)
+		for _, attr := range checkTLSConfigAttrs {
+			if v, ok := tlsConfig[string(attr)].(string); ok && v != "" {
+				fmt.Fprint(b, strings.TrimSpace(v))
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckTLSConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkHTTPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHTTPURLAttr): "https://www.example.org/",
+				string(checkTLSConfigAttr): []interface{}{
+					map[string]interface{}{
+						string(checkTLSCAChainAttr): "/etc/ssl/ca.pem",
+						string(checkTLSCiphersAttr): "ECDHE-RSA-AES128-GCM-SHA256",
+					},
+				},
+			},
+		},
+	})
+
+	httpConfig := d.Get(string(checkHTTPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIHTTP(&c, httpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.Config[config.CAChain] != "/etc/ssl/ca.pem" {
+		t.Errorf("expected %s %q, got %q", config.CAChain, "/etc/ssl/ca.pem", c.Config[config.CAChain])
+	}
+	if c.Config[config.Ciphers] != "ECDHE-RSA-AES128-GCM-SHA256" {
+		t.Errorf("expected %s %q, got %q", config.Ciphers, "ECDHE-RSA-AES128-GCM-SHA256", c.Config[config.Ciphers])
+	}
+
+	if checkTLSLegacyInUse(d, checkHTTPAttr) {
+		t.Errorf("expected the tls_config block to be in use")
+	}
+
+	if err := checkAPIToStateHTTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkHTTPAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	tlsConfigs := checkTLSConfigList(state)
+	if len(tlsConfigs) != 1 || tlsConfigs[0][string(checkTLSCAChainAttr)] != "/etc/ssl/ca.pem" {
+		t.Errorf("expected the tls_config block in state, got %#v", state[string(checkTLSConfigAttr)])
+	}
+	if v := state[string(checkHTTPCAChainAttr)]; v != "" {
+		t.Errorf("expected the deprecated %s to be unset, got %q", checkHTTPCAChainAttr, v)
+	}
+
+	if hashCheckHTTP(state) != hashCheckHTTP(httpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config")
+	}
+}
+
+func TestCheckTLSConfigLegacy(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkTCPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkTCPHostAttr):    "127.0.0.1",
+				string(checkTCPPortAttr):    443,
+				string(checkTCPCAChainAttr): "/etc/ssl/ca.pem",
+			},
+		},
+	})
+
+	if !checkTLSLegacyInUse(d, checkTCPAttr) {
+		t.Fatalf("expected the deprecated attributes to be in use")
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPITCP(&c, d.Get(string(checkTCPAttr)).(*schema.Set).List()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := checkAPIToStateTCP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkTCPAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	if v := state[string(checkTCPCAChainAttr)]; v != "/etc/ssl/ca.pem" {
+		t.Errorf("expected %s %q, got %q", checkTCPCAChainAttr, "/etc/ssl/ca.pem", v)
+	}
+	if tlsConfigs := checkTLSConfigList(state); len(tlsConfigs) != 0 {
+		t.Errorf("expected no tls_config block, got %#v", tlsConfigs)
+	}
+}
+
+func TestCheckTLSConflict(t *testing.T) {
+	m := interfaceMap{
+		string(checkJSONCAChainAttr): "/etc/ssl/ca.pem",
+		string(checkTLSConfigAttr): []interface{}{
+			map[string]interface{}{
+				string(checkTLSCAChainAttr): "/etc/ssl/other.pem",
+			},
+		},
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIJSON(&c, interfaceList{map[string]interface{}(m)}); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	delete(m, string(checkJSONCAChainAttr))
+	if err := checkTLSConflict(checkJSONAttr, m); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckHTTPTLSConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkHTTPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHTTPURLAttr): "https://10.0.0.1/",
+				string(checkTLSConfigAttr): []interface{}{
+					map[string]interface{}{
+						string(checkHTTPTLSExpectedNamesAttr): []interface{}{"www.example.org", "*.example.org"},
+						string(checkHTTPTLSMinVersionAttr):    "TLSv1.2",
+						string(checkHTTPTLSServerNameAttr):    "www.example.org",
+					},
+				},
+			},
+		},
+	})
+
+	httpConfig := d.Get(string(checkHTTPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIHTTP(&c, httpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := c.Config[apiHTTPTLSExpectedNames]; v != "www.example.org,*.example.org" {
+		t.Errorf("expected %s %q, got %q", apiHTTPTLSExpectedNames, "www.example.org,*.example.org", v)
+	}
+	if v := c.Config[apiHTTPTLSMinVersion]; v != "TLSv1.2" {
+		t.Errorf("expected %s %q, got %q", apiHTTPTLSMinVersion, "TLSv1.2", v)
+	}
+	if _, ok := c.Config[apiHTTPTLSMaxVersion]; ok {
+		t.Errorf("expected %s to be unset", apiHTTPTLSMaxVersion)
+	}
+	if v := c.Config[apiHTTPTLSServerName]; v != "www.example.org" {
+		t.Errorf("expected %s %q, got %q", apiHTTPTLSServerName, "www.example.org", v)
+	}
+
+	if err := checkAPIToStateHTTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkHTTPAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	tlsConfigs := checkTLSConfigList(state)
+	if len(tlsConfigs) != 1 || tlsConfigs[0][string(checkHTTPTLSServerNameAttr)] != "www.example.org" {
+		t.Errorf("expected the tls_config block in state, got %#v", state[string(checkTLSConfigAttr)])
+	}
+
+	if hashCheckHTTP(state) != hashCheckHTTP(httpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config")
+	}
+
+	c = newCheck()
+	inverted := interfaceMap{
+		string(checkHTTPURLAttr): "https://10.0.0.1/",
+		string(checkTLSConfigAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHTTPTLSMaxVersionAttr): "TLSv1.1",
+				string(checkHTTPTLSMinVersionAttr): "TLSv1.3",
+			},
+		},
+	}
+	if err := checkConfigToAPIHTTP(&c, interfaceList{map[string]interface{}(inverted)}); err == nil {
+		t.Errorf("expected an error for a min_version newer than max_version")
+	}
+}
@@ -0,0 +1,37 @@
+package circonus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Circonus IDs (CIDs) are of the form /<type>/<id>, e.g. /check/1234 or
+// /graph/bd72aabc-90b9-4039-cc30-c9ab838c18f5.  These helpers are the single
+// place the provider splits and builds CIDs.
+//
+// They are not exposed as Terraform provider functions (e.g. circonus_cid_id).
+// Terraform 1.8 and later call provider functions over plugin protocol v5 as
+// well as v6, but the plugin SDK the provider is built on can not declare
+// them.  Exposing them means serving functions from a terraform-plugin-framework
+// provider muxed with this one by terraform-plugin-mux.
+
+// cidID returns the id portion of cid, e.g. 1234 for /check/1234.
+func cidID(cid string) (string, error) {
+	parts := strings.Split(cid, "/")
+	if len(parts) != 3 || parts[0] != "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid CID %q, expected /<type>/<id>", cid)
+	}
+
+	return parts[2], nil
+}
+
+// makeCID builds a CID from one of the API client's CID prefixes (e.g.
+// config.CheckPrefix) and an id.  An id that already is a CID of the same type
+// is returned unchanged.
+func makeCID(prefix, id string) string {
+	if strings.HasPrefix(id, prefix+"/") {
+		return id
+	}
+
+	return prefix + "/" + id
+}
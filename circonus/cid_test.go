@@ -0,0 +1,48 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+)
+
+func TestCIDID(t *testing.T) {
+	tests := []struct {
+		cid      string
+		id       string
+		hasError bool
+	}{
+		{"/check/1234", "1234", false},
+		{"/graph/bd72aabc-90b9-4039-cc30-c9ab838c18f5", "bd72aabc-90b9-4039-cc30-c9ab838c18f5", false},
+		{"1234", "", true},
+		{"/check/", "", true},
+		{"/check/1234/extra", "", true},
+		{"check/1234", "", true},
+	}
+
+	for _, test := range tests {
+		id, err := cidID(test.cid)
+		if test.hasError {
+			if err == nil {
+				t.Fatalf("%q: expected an error", test.cid)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.cid, err)
+		}
+		if id != test.id {
+			t.Fatalf("%q: expected id %q, got %q", test.cid, test.id, id)
+		}
+	}
+}
+
+func TestMakeCID(t *testing.T) {
+	if got := makeCID(config.CheckPrefix, "1234"); got != "/check/1234" {
+		t.Fatalf("expected /check/1234, got %q", got)
+	}
+
+	if got := makeCID(config.CheckPrefix, "/check/1234"); got != "/check/1234" {
+		t.Fatalf("expected /check/1234, got %q", got)
+	}
+}
@@ -13,9 +13,40 @@ const (
 	// When hashing a Set, default to a buffer this size.
 	defaultHashBufSize = 512
 
-	providerAPIURLAttr  = "api_url"
-	providerAutoTagAttr = "auto_tag"
-	providerKeyAttr     = "key"
+	// defaultValidateReferences determines the default behavior of
+	// circonus.validate_references.
+	defaultValidateReferences = false
+
+	// defaultValidateCAQL determines the default behavior of
+	// circonus.validate_caql.
+	defaultValidateCAQL = true
+
+	// defaultAPIMaintenanceTimeout determines how long to wait for an API
+	// maintenance window to end.  Zero disables waiting.
+	defaultAPIMaintenanceTimeout = "0s"
+
+	// defaultGraphCreateConcurrency and defaultGraphCreateRate determine how
+	// graph creates are paced.  Zero leaves them to Terraform's parallelism.
+	defaultGraphCreateConcurrency = 0
+	defaultGraphCreateRate        = 0
+
+	providerAccountIDAttr              = "account_id"
+	providerActivityLogActorAttr       = "activity_log_actor"
+	providerActivityLogTokenAttr       = "activity_log_token"
+	providerActivityLogURLAttr         = "activity_log_url"
+	providerActivityLogWorkspaceAttr   = "activity_log_workspace"
+	providerAPIMaintenanceTimeoutAttr  = "api_maintenance_timeout"
+	providerAPIURLAttr                 = "api_url"
+	providerAutoTagAttr                = "auto_tag"
+	providerGraphCreateConcurrencyAttr = "graph_create_concurrency"
+	providerGraphCreateRateAttr        = "graph_create_rate"
+	providerKeyAttr                    = "key"
+	providerLinkTemplateAttr           = "link_template"
+	providerRunIDAttr                  = "run_id"
+	providerValidateCAQLAttr           = "validate_caql"
+	providerValidateReferencesAttr     = "validate_references"
+	providerVaultAddressAttr           = "vault_address"
+	providerVaultTokenAttr             = "vault_token"
 
 	apiConsulCheckBlacklist    = "check_name_blacklist"
 	apiConsulDatacenterAttr    = "dc"
@@ -31,9 +62,11 @@ const (
 	defaultCheckConsulPort     = "8500"
 
 	defaultCheckJSONMethod  = "GET"
-	defaultCheckJSONPort    = "443"
 	defaultCheckJSONVersion = "1.1"
 
+	defaultCheckMemcachedPort = 11211
+	defaultCheckNTPPort       = 123
+
 	defaultCheckICMPPingAvailability = 100.0
 	defaultCheckICMPPingCount        = 5
 	defaultCheckICMPPingInterval     = "2s"
@@ -101,6 +134,12 @@ var validGraphStyles = validStringValues{
 	`line`,
 }
 
+// validGraphColorPalettes are the palettes of graphColorPalettes.
+var validGraphColorPalettes = validStringValues{
+	`okabe_ito`,
+	`tableau10`,
+}
+
 // validAxisAttrs: See `line_style`: https://login.circonus.com/resources/api/calls/graph
 var validAxisAttrs = validStringValues{
 	`left`,
@@ -0,0 +1,254 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	graphTemplateLayoutAttr  = "layout"
+	graphTemplateMetricsAttr = "metrics"
+	graphTemplateSearchAttr  = "search"
+
+	// circonus_graph_template.layout.* attribute names.
+	graphTemplateLayoutAlphaAttr    = "alpha"
+	graphTemplateLayoutAxisAttr     = "axis"
+	graphTemplateLayoutColorsAttr   = "colors"
+	graphTemplateLayoutFunctionAttr = "function"
+	graphTemplateLayoutLimitAttr    = "limit"
+	graphTemplateLayoutNameAttr     = "name"
+	graphTemplateLayoutStackAttr    = "stack"
+)
+
+const (
+	// Placeholders expanded in circonus_graph_template.layout.name.
+	graphTemplateCheckPlaceholder      = "{check}"
+	graphTemplateMetricNamePlaceholder = "{metric_name}"
+	graphTemplateMetricTypePlaceholder = "{metric_type}"
+
+	defaultGraphTemplateName = graphTemplateMetricNamePlaceholder
+)
+
+var graphTemplateDescription = map[schemaAttr]string{
+	graphTemplateLayoutAttr:  "How the metrics found are laid out on the graph",
+	graphTemplateMetricsAttr: "Graph metric objects, one per active metric found, ready to be used by a dynamic metric block of a circonus_graph",
+	graphTemplateSearchAttr:  "The metric search query selecting the metrics to graph",
+}
+
+var graphTemplateLayoutDescriptions = attrDescrs{
+	graphTemplateLayoutAlphaAttr:    "The opacity of every metric",
+	graphTemplateLayoutAxisAttr:     "The axis every metric is drawn against",
+	graphTemplateLayoutColorsAttr:   "Colors assigned to the metrics in turn, starting over once exhausted",
+	graphTemplateLayoutFunctionAttr: "The function applied to every metric (gauge, derive or counter)",
+	graphTemplateLayoutLimitAttr:    "The maximum number of metrics to emit, all of them when 0",
+	graphTemplateLayoutNameAttr:     "The name of each metric, {check}, {metric_name} and {metric_type} are replaced with the metric's values",
+	graphTemplateLayoutStackAttr:    "The stack group every metric is part of",
+}
+
+func dataSourceCirconusGraphTemplate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCirconusGraphTemplateRead,
+
+		Schema: map[string]*schema.Schema{
+			graphTemplateSearchAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateRegexp(graphTemplateSearchAttr, `.+`),
+				Description:  graphTemplateDescription[graphTemplateSearchAttr],
+			},
+			graphTemplateLayoutAttr: {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: graphTemplateDescription[graphTemplateLayoutAttr],
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(graphTemplateLayoutDescriptions, map[schemaAttr]*schema.Schema{
+						graphTemplateLayoutAlphaAttr: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						graphTemplateLayoutAxisAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "left",
+							ValidateFunc: validateStringIn(graphTemplateLayoutAxisAttr, validAxisAttrs),
+						},
+						graphTemplateLayoutColorsAttr: {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateRegexp(graphTemplateLayoutColorsAttr, `^#[0-9a-fA-F]{6}$`),
+							},
+						},
+						graphTemplateLayoutFunctionAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "gauge",
+							ValidateFunc: validateStringIn(graphTemplateLayoutFunctionAttr, validGraphFunctionValues),
+						},
+						graphTemplateLayoutLimitAttr: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntMin(graphTemplateLayoutLimitAttr, 0),
+						},
+						graphTemplateLayoutNameAttr: {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  defaultGraphTemplateName,
+						},
+						graphTemplateLayoutStackAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphTemplateLayoutStackAttr, `^[\d]*$`),
+						},
+					}),
+				},
+			},
+			graphTemplateMetricsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: graphTemplateDescription[graphTemplateMetricsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						graphMetricActiveAttr: {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						graphMetricAlphaAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricAxisAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricCheckAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricColorAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricFunctionAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricHumanNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricMetricTypeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						graphMetricStackAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCirconusGraphTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+
+	search := d.Get(graphTemplateSearchAttr).(string)
+	query := api.SearchQueryType(search)
+
+	metrics, err := ctxt.client.SearchMetrics(&query, nil)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("unable to search for metrics %q: %w", search, err))
+	}
+
+	// Without a layout block the defaults of its attributes apply.
+	layout := newInterfaceMap(map[string]interface{}{
+		string(graphTemplateLayoutAxisAttr):     "left",
+		string(graphTemplateLayoutFunctionAttr): "gauge",
+		string(graphTemplateLayoutNameAttr):     defaultGraphTemplateName,
+	})
+	if l, ok := d.Get(graphTemplateLayoutAttr).([]interface{}); ok && len(l) > 0 && l[0] != nil {
+		layout = newInterfaceMap(l[0])
+	}
+
+	d.SetId(hashcode.Strings([]string{search}))
+
+	if err := d.Set(graphTemplateMetricsAttr, graphTemplateMetrics(*metrics, layout)); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", graphTemplateMetricsAttr, err))
+	}
+
+	return nil
+}
+
+// graphTemplateMetrics lays out the active metrics among metrics as graph
+// metric objects.  Metrics are ordered by check and metric name so the
+// assigned colors do not move around between runs.
+func graphTemplateMetrics(metrics []api.Metric, layout interfaceMap) []interface{} {
+	active := make([]api.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		if m.Active {
+			active = append(active, m)
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].CheckCID != active[j].CheckCID {
+			return active[i].CheckCID < active[j].CheckCID
+		}
+		return active[i].MetricName < active[j].MetricName
+	})
+
+	if limit, ok := layout[string(graphTemplateLayoutLimitAttr)].(int); ok && limit > 0 && len(active) > limit {
+		active = active[:limit]
+	}
+
+	var colors []string
+	if l, ok := layout[string(graphTemplateLayoutColorsAttr)].([]interface{}); ok {
+		colors = interfaceList(l).List()
+	}
+	nameTemplate, _ := layout[string(graphTemplateLayoutNameAttr)].(string)
+
+	out := make([]interface{}, 0, len(active))
+	for i, m := range active {
+		name := strings.NewReplacer(
+			graphTemplateCheckPlaceholder, m.CheckCID,
+			graphTemplateMetricNamePlaceholder, m.MetricName,
+			graphTemplateMetricTypePlaceholder, m.MetricType,
+		).Replace(nameTemplate)
+
+		var color string
+		if len(colors) > 0 {
+			color = colors[i%len(colors)]
+		}
+
+		out = append(out, map[string]interface{}{
+			graphMetricActiveAttr:     true,
+			graphMetricAlphaAttr:      layout[string(graphTemplateLayoutAlphaAttr)],
+			graphMetricAxisAttr:       layout[string(graphTemplateLayoutAxisAttr)],
+			graphMetricCheckAttr:      m.CheckCID,
+			graphMetricColorAttr:      color,
+			graphMetricFunctionAttr:   layout[string(graphTemplateLayoutFunctionAttr)],
+			graphMetricHumanNameAttr:  name,
+			graphMetricMetricTypeAttr: m.MetricType,
+			graphMetricNameAttr:       m.MetricName,
+			graphMetricStackAttr:      layout[string(graphTemplateLayoutStackAttr)],
+		})
+	}
+
+	return out
+}
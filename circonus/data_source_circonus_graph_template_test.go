@@ -0,0 +1,62 @@
+package circonus
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+)
+
+func TestGraphTemplateMetrics(t *testing.T) {
+	metrics := []api.Metric{
+		{CheckCID: "/check/2", MetricName: "requests", MetricType: "numeric", Active: true},
+		{CheckCID: "/check/1", MetricName: "requests", MetricType: "numeric", Active: true},
+		{CheckCID: "/check/1", MetricName: "latency", MetricType: "histogram", Active: true},
+		{CheckCID: "/check/1", MetricName: "errors", MetricType: "numeric", Active: false},
+	}
+
+	layout := interfaceMap{
+		string(graphTemplateLayoutAxisAttr):     "right",
+		string(graphTemplateLayoutColorsAttr):   []interface{}{"#ff0000", "#00ff00"},
+		string(graphTemplateLayoutFunctionAttr): "derive",
+		string(graphTemplateLayoutNameAttr):     "{metric_name} ({check})",
+		string(graphTemplateLayoutStackAttr):    "0",
+	}
+
+	got := graphTemplateMetrics(metrics, layout)
+
+	expected := []struct {
+		check, metricName, metricType, name, color string
+	}{
+		{"/check/1", "latency", "histogram", "latency (/check/1)", "#ff0000"},
+		{"/check/1", "requests", "numeric", "requests (/check/1)", "#00ff00"},
+		{"/check/2", "requests", "numeric", "requests (/check/2)", "#ff0000"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d metrics, got %d: %#v", len(expected), len(got), got)
+	}
+
+	for i, e := range expected {
+		m := got[i].(map[string]interface{})
+		want := map[string]interface{}{
+			graphMetricActiveAttr:     true,
+			graphMetricAlphaAttr:      nil,
+			graphMetricAxisAttr:       "right",
+			graphMetricCheckAttr:      e.check,
+			graphMetricColorAttr:      e.color,
+			graphMetricFunctionAttr:   "derive",
+			graphMetricHumanNameAttr:  e.name,
+			graphMetricMetricTypeAttr: e.metricType,
+			graphMetricNameAttr:       e.metricName,
+			graphMetricStackAttr:      "0",
+		}
+		if !reflect.DeepEqual(m, want) {
+			t.Errorf("metric %d: expected %#v, got %#v", i, want, m)
+		}
+	}
+
+	layout[string(graphTemplateLayoutLimitAttr)] = 1
+	if got := graphTemplateMetrics(metrics, layout); len(got) != 1 {
+		t.Errorf("expected the limit to keep 1 metric, got %d", len(got))
+	}
+}
@@ -0,0 +1,435 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	topologyCheckIDsAttr        = "check_ids"
+	topologyContactGroupIDsAttr = "contact_group_ids"
+	topologyDOTAttr             = "dot"
+	topologyEdgesAttr           = "edges"
+	topologyGraphIDsAttr        = "graph_ids"
+	topologyJSONAttr            = "json"
+	topologyNodesAttr           = "nodes"
+	topologyRuleSetIDsAttr      = "rule_set_ids"
+	topologyUncoveredAttr       = "uncovered_check_ids"
+
+	// circonus_topology.nodes.* attribute names.
+	topologyNodeIDAttr           = "id"
+	topologyNodeNameAttr         = "name"
+	topologyNodeResourceTypeAttr = "resource_type"
+
+	// circonus_topology.edges.* attribute names.
+	topologyEdgeFromAttr     = "from"
+	topologyEdgeRelationAttr = "relation"
+	topologyEdgeSeverityAttr = "severity"
+	topologyEdgeToAttr       = "to"
+)
+
+// Relations between the nodes of a topology.
+const (
+	topologyRelationAlerts   = "alerts"
+	topologyRelationGraphs   = "graphs"
+	topologyRelationNotifies = "notifies"
+)
+
+var topologyDescription = map[schemaAttr]string{
+	topologyCheckIDsAttr:        "IDs of the check bundles to include",
+	topologyContactGroupIDsAttr: "IDs of contact groups to include even when no rule set notifies them",
+	topologyDOTAttr:             "The topology rendered as a Graphviz DOT digraph",
+	topologyEdgesAttr:           "The relationships between the nodes",
+	topologyGraphIDsAttr:        "IDs of the graphs to include",
+	topologyJSONAttr:            "The nodes and edges rendered as JSON",
+	topologyNodesAttr:           "The checks, rule sets, contact groups and graphs of the topology",
+	topologyRuleSetIDsAttr:      "IDs of the rule sets to include",
+	topologyUncoveredAttr:       "IDs of the check bundles without a rule set notifying a contact group",
+}
+
+// topologyResourceTypes orders the nodes of a topology.
+var topologyResourceTypes = []string{
+	"circonus_check",
+	"circonus_rule_set",
+	"circonus_contact_group",
+	"circonus_graph",
+}
+
+// topologyDOTShapes are the DOT node shapes of each resource type.
+var topologyDOTShapes = map[string]string{
+	"circonus_check":         "box",
+	"circonus_contact_group": "ellipse",
+	"circonus_graph":         "note",
+	"circonus_rule_set":      "diamond",
+}
+
+type topologyNode struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ResourceType string `json:"resource_type"`
+}
+
+type topologyEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+	Severity int    `json:"severity,omitempty"`
+}
+
+// topology is the graph of relationships between Circonus objects.  Rule sets
+// and graphs reference individual checks, the edges point at the check bundle
+// owning the check when it is part of the topology and at the check otherwise.
+type topology struct {
+	Nodes []topologyNode `json:"nodes"`
+	Edges []topologyEdge `json:"edges"`
+
+	// checkBundles maps the check IDs of each check bundle to the bundle.
+	checkBundles map[string]string
+	nodes        map[string]struct{}
+}
+
+func dataSourceCirconusTopology() *schema.Resource {
+	idSet := func(attrName schemaAttr) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: topologyDescription[attrName],
+		}
+	}
+
+	computedString := func(attrName schemaAttr) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: topologyDescription[attrName],
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceCirconusTopologyRead,
+
+		Schema: map[string]*schema.Schema{
+			topologyCheckIDsAttr:        idSet(topologyCheckIDsAttr),
+			topologyContactGroupIDsAttr: idSet(topologyContactGroupIDsAttr),
+			topologyGraphIDsAttr:        idSet(topologyGraphIDsAttr),
+			topologyRuleSetIDsAttr:      idSet(topologyRuleSetIDsAttr),
+			topologyDOTAttr:             computedString(topologyDOTAttr),
+			topologyJSONAttr:            computedString(topologyJSONAttr),
+			topologyEdgesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: topologyDescription[topologyEdgesAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						topologyEdgeFromAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						topologyEdgeRelationAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						topologyEdgeSeverityAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						topologyEdgeToAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			topologyNodesAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: topologyDescription[topologyNodesAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						topologyNodeIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						topologyNodeNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						topologyNodeResourceTypeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			topologyUncoveredAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: topologyDescription[topologyUncoveredAttr],
+			},
+		},
+	}
+}
+
+func dataSourceCirconusTopologyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+
+	ids := func(attrName schemaAttr) []string {
+		l := derefStringList(flattenSet(d.Get(string(attrName)).(*schema.Set)))
+		sort.Strings(l)
+		return l
+	}
+
+	checkIDs := ids(topologyCheckIDsAttr)
+	ruleSetIDs := ids(topologyRuleSetIDsAttr)
+	contactGroupIDs := ids(topologyContactGroupIDsAttr)
+	graphIDs := ids(topologyGraphIDsAttr)
+
+	t, err := loadTopology(ctxt, checkIDs, ruleSetIDs, contactGroupIDs, graphIDs)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	buf, err := json.Marshal(t)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("unable to encode topology: %w", err))
+	}
+
+	nodes := make([]interface{}, 0, len(t.Nodes))
+	for _, n := range t.Nodes {
+		nodes = append(nodes, map[string]interface{}{
+			topologyNodeIDAttr:           n.ID,
+			topologyNodeNameAttr:         n.Name,
+			topologyNodeResourceTypeAttr: n.ResourceType,
+		})
+	}
+
+	edges := make([]interface{}, 0, len(t.Edges))
+	for _, e := range t.Edges {
+		edges = append(edges, map[string]interface{}{
+			topologyEdgeFromAttr:     e.From,
+			topologyEdgeRelationAttr: e.Relation,
+			topologyEdgeSeverityAttr: e.Severity,
+			topologyEdgeToAttr:       e.To,
+		})
+	}
+
+	all := make([]string, 0, len(checkIDs)+len(ruleSetIDs)+len(contactGroupIDs)+len(graphIDs))
+	all = append(append(append(append(all, checkIDs...), ruleSetIDs...), contactGroupIDs...), graphIDs...)
+	d.SetId(hashcode.Strings(all))
+
+	if err := d.Set(topologyNodesAttr, nodes); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", topologyNodesAttr, err))
+	}
+
+	if err := d.Set(topologyEdgesAttr, edges); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", topologyEdgesAttr, err))
+	}
+
+	if err := d.Set(topologyUncoveredAttr, t.uncoveredCheckBundles()); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", topologyUncoveredAttr, err))
+	}
+
+	if err := d.Set(topologyJSONAttr, string(buf)); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", topologyJSONAttr, err))
+	}
+
+	if err := d.Set(topologyDOTAttr, t.dot()); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", topologyDOTAttr, err))
+	}
+
+	return nil
+}
+
+// loadTopology fetches the given objects, and the contact groups notified by
+// the rule sets, and links them together.
+func loadTopology(ctxt *providerContext, checkIDs, ruleSetIDs, contactGroupIDs, graphIDs []string) (*topology, error) {
+	t := &topology{
+		Nodes:        make([]topologyNode, 0),
+		Edges:        make([]topologyEdge, 0),
+		checkBundles: make(map[string]string),
+		nodes:        make(map[string]struct{}),
+	}
+
+	for _, cid := range checkIDs {
+		cid := cid
+		c, err := ctxt.client.FetchCheckBundle(api.CIDType(&cid))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch check bundle %q: %w", cid, err)
+		}
+		t.addNode(c.CID, c.DisplayName, "circonus_check")
+		for _, checkCID := range c.Checks {
+			t.checkBundles[checkCID] = c.CID
+		}
+	}
+
+	notified := make([]string, 0)
+	for _, cid := range ruleSetIDs {
+		cid := cid
+		rs, err := ctxt.client.FetchRuleSet(api.CIDType(&cid))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch rule set %q: %w", cid, err)
+		}
+		t.addNode(rs.CID, rs.Name, "circonus_rule_set")
+		t.addEdge(rs.CID, t.checkNode(rs.CheckCID), topologyRelationAlerts, 0)
+
+		for severity, groups := range rs.ContactGroups {
+			for _, cgCID := range groups {
+				t.addEdge(rs.CID, cgCID, topologyRelationNotifies, int(severity))
+				notified = append(notified, cgCID)
+			}
+		}
+	}
+
+	for _, cid := range append(append([]string{}, contactGroupIDs...), notified...) {
+		cid := cid
+		if _, found := t.nodes[cid]; found {
+			continue
+		}
+		cg, err := ctxt.client.FetchContactGroup(api.CIDType(&cid))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch contact group %q: %w", cid, err)
+		}
+		t.addNode(cid, cg.Name, "circonus_contact_group")
+	}
+
+	for _, cid := range graphIDs {
+		cid := cid
+		g, err := ctxt.client.FetchGraph(api.CIDType(&cid))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch graph %q: %w", cid, err)
+		}
+		t.addNode(g.CID, g.Title, "circonus_graph")
+		for _, dp := range g.Datapoints {
+			if dp.CheckID == 0 {
+				continue
+			}
+			t.addEdge(g.CID, t.checkNode(makeCID(config.CheckPrefix, strconv.FormatUint(uint64(dp.CheckID), 10))), topologyRelationGraphs, 0)
+		}
+	}
+
+	// Checks referenced from outside of the given check bundles still get a
+	// node, without a name.
+	for _, e := range t.Edges {
+		if _, found := t.nodes[e.To]; !found {
+			t.addNode(e.To, "", "circonus_check")
+		}
+	}
+
+	order := make(map[string]int, len(topologyResourceTypes))
+	for i, resourceType := range topologyResourceTypes {
+		order[resourceType] = i
+	}
+	sort.SliceStable(t.Nodes, func(i, j int) bool {
+		if t.Nodes[i].ResourceType != t.Nodes[j].ResourceType {
+			return order[t.Nodes[i].ResourceType] < order[t.Nodes[j].ResourceType]
+		}
+		return t.Nodes[i].ID < t.Nodes[j].ID
+	})
+	sort.SliceStable(t.Edges, func(i, j int) bool {
+		a, b := t.Edges[i], t.Edges[j]
+		switch {
+		case a.From != b.From:
+			return a.From < b.From
+		case a.To != b.To:
+			return a.To < b.To
+		default:
+			return a.Severity < b.Severity
+		}
+	})
+
+	return t, nil
+}
+
+func (t *topology) addNode(id, name, resourceType string) {
+	if _, found := t.nodes[id]; found {
+		return
+	}
+	t.nodes[id] = struct{}{}
+	t.Nodes = append(t.Nodes, topologyNode{ID: id, Name: name, ResourceType: resourceType})
+}
+
+// addEdge adds an edge unless it is already present, e.g. for a graph showing
+// several metrics of the same check.
+func (t *topology) addEdge(from, to, relation string, severity int) {
+	e := topologyEdge{From: from, To: to, Relation: relation, Severity: severity}
+	for _, existing := range t.Edges {
+		if existing == e {
+			return
+		}
+	}
+	t.Edges = append(t.Edges, e)
+}
+
+// checkNode returns the node ID of the check bundle owning checkCID, or
+// checkCID when the bundle is not part of the topology.
+func (t *topology) checkNode(checkCID string) string {
+	if bundleCID, found := t.checkBundles[checkCID]; found {
+		return bundleCID
+	}
+	return checkCID
+}
+
+// uncoveredCheckBundles returns the check bundles of the topology that no
+// rule set notifying at least one contact group alerts on.
+func (t *topology) uncoveredCheckBundles() []string {
+	notifying := make(map[string]struct{})
+	for _, e := range t.Edges {
+		if e.Relation == topologyRelationNotifies {
+			notifying[e.From] = struct{}{}
+		}
+	}
+
+	covered := make(map[string]struct{})
+	for _, e := range t.Edges {
+		if _, found := notifying[e.From]; found && e.Relation == topologyRelationAlerts {
+			covered[e.To] = struct{}{}
+		}
+	}
+
+	uncovered := make([]string, 0)
+	for _, n := range t.Nodes {
+		if !strings.HasPrefix(n.ID, config.CheckBundlePrefix+"/") {
+			continue
+		}
+		if _, found := covered[n.ID]; !found {
+			uncovered = append(uncovered, n.ID)
+		}
+	}
+
+	return uncovered
+}
+
+// dot renders the topology as a Graphviz digraph.
+func (t *topology) dot() string {
+	var b strings.Builder
+	b.WriteString("digraph circonus {\n")
+	for _, n := range t.Nodes {
+		label := n.Name
+		if label == "" {
+			label = n.ID
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.ID, label, topologyDOTShapes[n.ResourceType])
+	}
+	for _, e := range t.Edges {
+		label := e.Relation
+		if e.Relation == topologyRelationNotifies {
+			label = fmt.Sprintf("%s (sev %d)", e.Relation, e.Severity)
+		}
+		fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", e.From, e.To, label)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package circonus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusTopology(t *testing.T) {
+	groupName := fmt.Sprintf("Topology contacts - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusContactGroup,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceCirconusTopologyConfigFmt, groupName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.circonus_topology.ops", "nodes.#", "1"),
+					resource.TestCheckResourceAttrPair("data.circonus_topology.ops", "nodes.0.id", "circonus_contact_group.ops", "id"),
+					resource.TestCheckResourceAttr("data.circonus_topology.ops", "nodes.0.name", groupName),
+					resource.TestCheckResourceAttr("data.circonus_topology.ops", "nodes.0.resource_type", "circonus_contact_group"),
+					resource.TestCheckResourceAttr("data.circonus_topology.ops", "edges.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestTopology(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v interface{}
+		switch r.URL.Path {
+		case "/check_bundle/1":
+			v = api.CheckBundle{CID: "/check_bundle/1", DisplayName: "web http", Checks: []string{"/check/11", "/check/12"}}
+		case "/check_bundle/2":
+			v = api.CheckBundle{CID: "/check_bundle/2", DisplayName: "db ping", Checks: []string{"/check/21"}}
+		case "/rule_set/3_latency":
+			v = api.RuleSet{
+				CID:           "/rule_set/3_latency",
+				Name:          "web latency",
+				CheckCID:      "/check/12",
+				ContactGroups: map[uint8][]string{1: {"/contact_group/5"}, 2: {}},
+			}
+		case "/contact_group/5":
+			v = api.ContactGroup{CID: "/contact_group/5", Name: "Ops"}
+		case "/graph/abc":
+			v = api.Graph{CID: "/graph/abc", Title: "latency", Datapoints: []api.GraphDatapoint{
+				{CheckID: 11, MetricName: "duration"},
+				{CheckID: 12, MetricName: "duration"},
+				{CheckID: 11, MetricName: "code"},
+				{CheckID: 99, MetricName: "duration"},
+				{Name: "caql"},
+			}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(v)
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	topo, err := loadTopology(ctxt, []string{"/check_bundle/1", "/check_bundle/2"}, []string{"/rule_set/3_latency"}, nil, []string{"/graph/abc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedNodes := []topologyNode{
+		{ID: "/check/99", ResourceType: "circonus_check"},
+		{ID: "/check_bundle/1", Name: "web http", ResourceType: "circonus_check"},
+		{ID: "/check_bundle/2", Name: "db ping", ResourceType: "circonus_check"},
+		{ID: "/rule_set/3_latency", Name: "web latency", ResourceType: "circonus_rule_set"},
+		{ID: "/contact_group/5", Name: "Ops", ResourceType: "circonus_contact_group"},
+		{ID: "/graph/abc", Name: "latency", ResourceType: "circonus_graph"},
+	}
+	if fmt.Sprint(topo.Nodes) != fmt.Sprint(expectedNodes) {
+		t.Errorf("expected nodes %v, got %v", expectedNodes, topo.Nodes)
+	}
+
+	// The datapoints of the same check bundle collapse into a single edge.
+	expectedEdges := []topologyEdge{
+		{From: "/graph/abc", To: "/check/99", Relation: topologyRelationGraphs},
+		{From: "/graph/abc", To: "/check_bundle/1", Relation: topologyRelationGraphs},
+		{From: "/rule_set/3_latency", To: "/check_bundle/1", Relation: topologyRelationAlerts},
+		{From: "/rule_set/3_latency", To: "/contact_group/5", Relation: topologyRelationNotifies, Severity: 1},
+	}
+	if fmt.Sprint(topo.Edges) != fmt.Sprint(expectedEdges) {
+		t.Errorf("expected edges %v, got %v", expectedEdges, topo.Edges)
+	}
+
+	if got := topo.uncoveredCheckBundles(); strings.Join(got, ",") != "/check_bundle/2" {
+		t.Errorf("expected only /check_bundle/2 to be uncovered, got %q", got)
+	}
+
+	dot := topo.dot()
+	for _, line := range []string{
+		`  "/check/99" [label="/check/99", shape=box];`,
+		`  "/rule_set/3_latency" [label="web latency", shape=diamond];`,
+		`  "/rule_set/3_latency" -> "/contact_group/5" [label="notifies (sev 1)"];`,
+	} {
+		if !strings.Contains(dot, line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, dot)
+		}
+	}
+
+	if _, err := loadTopology(ctxt, nil, []string{"/rule_set/4_missing"}, nil, nil); err == nil {
+		t.Error("expected an error for a missing rule set")
+	}
+}
+
+const testAccDataSourceCirconusTopologyConfigFmt = `
+resource "circonus_contact_group" "ops" {
+  name        = "%s"
+  allow_empty = true
+}
+
+data "circonus_topology" "ops" {
+  contact_group_ids = [ circonus_contact_group.ops.id ]
+}
+`
@@ -0,0 +1,278 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	unmanagedImportBlocksAttr  = "import_blocks"
+	unmanagedManagedIDsAttr    = "managed_ids"
+	unmanagedObjectsAttr       = "objects"
+	unmanagedResourceTypesAttr = "resource_types"
+	unmanagedTagsAttr          = "tags"
+
+	// circonus_unmanaged.objects.* attribute names.
+	unmanagedObjectIDAttr           = "id"
+	unmanagedObjectImportBlockAttr  = "import_block"
+	unmanagedObjectNameAttr         = "name"
+	unmanagedObjectResourceNameAttr = "resource_name"
+	unmanagedObjectResourceTypeAttr = "resource_type"
+)
+
+// API filter used when searching for objects by tag.
+const unmanagedSearchTagFilter = "f_tags_has"
+
+var unmanagedDescription = map[schemaAttr]string{
+	unmanagedImportBlocksAttr:  "Import blocks for every unmanaged object, ready to paste into a configuration",
+	unmanagedManagedIDsAttr:    "IDs of the objects already managed by Terraform, which are left out of the results",
+	unmanagedObjectsAttr:       "Objects present in the account but not listed in managed_ids",
+	unmanagedResourceTypesAttr: "The resource types to search for, all supported types when omitted",
+	unmanagedTagsAttr:          "Only objects carrying all of these tags are listed",
+}
+
+// unmanagedResourceTypes are the resource types the circonus_unmanaged data
+// source can search for, in the order their import blocks are emitted.
+var unmanagedResourceTypes = []string{
+	"circonus_check",
+	"circonus_contact_group",
+	"circonus_graph",
+}
+
+// unmanagedObject is an object found in the account that can be imported.
+type unmanagedObject struct {
+	cid          string
+	name         string
+	resourceType string
+	resourceName string
+}
+
+func dataSourceCirconusUnmanaged() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCirconusUnmanagedRead,
+
+		Schema: map[string]*schema.Schema{
+			unmanagedManagedIDsAttr: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: unmanagedDescription[unmanagedManagedIDsAttr],
+			},
+			unmanagedResourceTypesAttr: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(unmanagedResourceTypes, false),
+				},
+				Description: unmanagedDescription[unmanagedResourceTypesAttr],
+			},
+			unmanagedTagsAttr: tagMakeConfigSchema(unmanagedTagsAttr),
+			unmanagedImportBlocksAttr: {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: unmanagedDescription[unmanagedImportBlocksAttr],
+			},
+			unmanagedObjectsAttr: {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: unmanagedDescription[unmanagedObjectsAttr],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						unmanagedObjectIDAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						unmanagedObjectImportBlockAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						unmanagedObjectNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						unmanagedObjectResourceNameAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						unmanagedObjectResourceTypeAttr: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCirconusUnmanagedRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+
+	tags := derefStringList(flattenSet(d.Get(unmanagedTagsAttr).(*schema.Set)))
+	sort.Strings(tags)
+
+	managed := make(map[string]struct{})
+	for _, cid := range derefStringList(flattenSet(d.Get(unmanagedManagedIDsAttr).(*schema.Set))) {
+		managed[cid] = struct{}{}
+	}
+
+	resourceTypes := unmanagedResourceTypes
+	if l := derefStringList(flattenSet(d.Get(unmanagedResourceTypesAttr).(*schema.Set))); len(l) > 0 {
+		resourceTypes = l
+	}
+
+	var filter *api.SearchFilterType
+	if len(tags) > 0 {
+		filter = &api.SearchFilterType{unmanagedSearchTagFilter: tags}
+	}
+
+	found := make([]unmanagedObject, 0)
+	for _, resourceType := range unmanagedResourceTypes {
+		if !stringInSlice(resourceType, resourceTypes) {
+			continue
+		}
+
+		objects, err := searchUnmanaged(ctxt, resourceType, filter, tags)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, o := range objects {
+			if _, ok := managed[o.cid]; !ok {
+				found = append(found, o)
+			}
+		}
+	}
+
+	nameUnmanagedObjects(found)
+
+	objects := make([]interface{}, 0, len(found))
+	blocks := make([]string, 0, len(found))
+	for _, o := range found {
+		block := o.importBlock()
+		blocks = append(blocks, block)
+		objects = append(objects, map[string]interface{}{
+			unmanagedObjectIDAttr:           o.cid,
+			unmanagedObjectImportBlockAttr:  block,
+			unmanagedObjectNameAttr:         o.name,
+			unmanagedObjectResourceNameAttr: o.resourceName,
+			unmanagedObjectResourceTypeAttr: o.resourceType,
+		})
+	}
+
+	d.SetId(hashcode.Strings(append(append([]string{}, tags...), resourceTypes...)))
+
+	if err := d.Set(unmanagedObjectsAttr, objects); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", unmanagedObjectsAttr, err))
+	}
+
+	if err := d.Set(unmanagedImportBlocksAttr, strings.Join(blocks, "\n")); err != nil {
+		return diag.FromErr(fmt.Errorf("Unable to store %q attribute: %w", unmanagedImportBlocksAttr, err))
+	}
+
+	return nil
+}
+
+// searchUnmanaged returns the objects of resourceType carrying all of tags.
+func searchUnmanaged(ctxt *providerContext, resourceType string, filter *api.SearchFilterType, tags []string) ([]unmanagedObject, error) {
+	objects := make([]unmanagedObject, 0)
+
+	switch resourceType {
+	case "circonus_check":
+		bundles, err := ctxt.client.SearchCheckBundles(nil, filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to search for check bundles: %w", err)
+		}
+		for _, c := range matchCheckBundles(*bundles, "", tags) {
+			objects = append(objects, unmanagedObject{cid: c.CID, name: c.DisplayName, resourceType: resourceType})
+		}
+	case "circonus_contact_group":
+		groups, err := ctxt.client.SearchContactGroups(nil, filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to search for contact groups: %w", err)
+		}
+		for _, cg := range *groups {
+			if hasAllTags(cg.Tags, tags) {
+				objects = append(objects, unmanagedObject{cid: cg.CID, name: cg.Name, resourceType: resourceType})
+			}
+		}
+	case "circonus_graph":
+		graphs, err := ctxt.client.SearchGraphs(nil, filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to search for graphs: %w", err)
+		}
+		for _, g := range *graphs {
+			if hasAllTags(g.Tags, tags) {
+				objects = append(objects, unmanagedObject{cid: g.CID, name: g.Title, resourceType: resourceType})
+			}
+		}
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].cid < objects[j].cid
+	})
+
+	return objects, nil
+}
+
+// nameUnmanagedObjects derives a Terraform resource name for each object from
+// its display name.  Names are made unique within a resource type by
+// appending a counter.
+func nameUnmanagedObjects(objects []unmanagedObject) {
+	used := make(map[string]struct{}, len(objects))
+	for i := range objects {
+		base := terraformResourceName(objects[i].name)
+
+		name := base
+		for n := 2; ; n++ {
+			if _, found := used[objects[i].resourceType+"."+name]; !found {
+				break
+			}
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+
+		used[objects[i].resourceType+"."+name] = struct{}{}
+		objects[i].resourceName = name
+	}
+}
+
+// terraformResourceName turns a display name into a valid Terraform resource
+// name: lower case letters, digits and underscores, starting with a letter or
+// an underscore.
+func terraformResourceName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			underscore = false
+		case !underscore && b.Len() > 0:
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	s := strings.TrimSuffix(b.String(), "_")
+	switch {
+	case s == "":
+		return "unnamed"
+	case s[0] >= '0' && s[0] <= '9':
+		return "_" + s
+	}
+
+	return s
+}
+
+func (o unmanagedObject) importBlock() string {
+	return fmt.Sprintf("import {\n  to = %s.%s\n  id = %q\n}\n", o.resourceType, o.resourceName, o.cid)
+}
@@ -0,0 +1,79 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCirconusUnmanaged(t *testing.T) {
+	tag := fmt.Sprintf("unmanaged:%s", acctest.RandString(8))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusContactGroup,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceCirconusUnmanagedConfigFmt, tag),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.circonus_unmanaged.all", "objects.#", "1"),
+					resource.TestCheckResourceAttrPair("data.circonus_unmanaged.all", "objects.0.id", "circonus_contact_group.unmanaged", "id"),
+					resource.TestCheckResourceAttr("data.circonus_unmanaged.all", "objects.0.resource_name", "unmanaged_contacts"),
+					resource.TestCheckResourceAttr("data.circonus_unmanaged.all", "objects.0.resource_type", "circonus_contact_group"),
+					resource.TestMatchResourceAttr("data.circonus_unmanaged.all", "import_blocks", regexp.MustCompile(`to = circonus_contact_group\.unmanaged_contacts`)),
+				),
+			},
+		},
+	})
+}
+
+func TestUnmanagedImportBlocks(t *testing.T) {
+	objects := []unmanagedObject{
+		{cid: "/check_bundle/1", name: "Web Server (HTTP)", resourceType: "circonus_check"},
+		{cid: "/check_bundle/2", name: "web server http", resourceType: "circonus_check"},
+		{cid: "/check_bundle/3", name: "web_server_http_2", resourceType: "circonus_check"},
+		{cid: "/contact_group/4", name: "Web Server (HTTP)", resourceType: "circonus_contact_group"},
+		{cid: "/graph/5", name: "99th percentile", resourceType: "circonus_graph"},
+		{cid: "/graph/6", name: "¯\\_(ツ)_/¯", resourceType: "circonus_graph"},
+	}
+
+	nameUnmanagedObjects(objects)
+
+	expected := []string{
+		"web_server_http",
+		"web_server_http_2",
+		"web_server_http_2_2",
+		"web_server_http",
+		"_99th_percentile",
+		"unnamed",
+	}
+	for i, name := range expected {
+		if objects[i].resourceName != name {
+			t.Errorf("%s: expected resource name %q, got %q", objects[i].cid, name, objects[i].resourceName)
+		}
+	}
+
+	block := objects[0].importBlock()
+	if want := "import {\n  to = circonus_check.web_server_http\n  id = \"/check_bundle/1\"\n}\n"; block != want {
+		t.Errorf("expected import block %q, got %q", want, block)
+	}
+}
+
+const testAccDataSourceCirconusUnmanagedConfigFmt = `
+resource "circonus_contact_group" "unmanaged" {
+  name = "Unmanaged contacts"
+  tags = [ "%[1]s" ]
+  allow_empty = true
+}
+
+data "circonus_unmanaged" "all" {
+  tags = [ "%[1]s" ]
+  resource_types = [ "circonus_contact_group" ]
+
+  depends_on = [ circonus_contact_group.unmanaged ]
+}
+`
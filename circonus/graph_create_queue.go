@@ -0,0 +1,90 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Large modules create many graphs at once.  Terraform runs the creates of up
+// to -parallelism resources concurrently, which trips the rate limit of the
+// Circonus API and leaves the workers sleeping in the API client's backoff
+// after each 429.  The graph create queue pipelines the creates of concurrent
+// workers instead: at most circonus.graph_create_concurrency creates are in
+// flight and they start at most circonus.graph_create_rate per second, so the
+// API is kept busy without being pushed into rate limiting.
+
+// graphCreateQueue paces the graph creates of concurrent workers.  The zero
+// value, and a nil queue, run creates as they come.
+type graphCreateQueue struct {
+	// slots holds a token for each create in flight, nil when the number of
+	// creates in flight is not bounded
+	slots chan struct{}
+	// interval is the minimum delay between the start of two creates
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newGraphCreateQueue returns a queue running at most concurrency creates at
+// once, starting at most rate creates per second.  Zero disables either
+// limit.
+func newGraphCreateQueue(concurrency, rate int) *graphCreateQueue {
+	q := &graphCreateQueue{}
+	if concurrency > 0 {
+		q.slots = make(chan struct{}, concurrency)
+	}
+	if rate > 0 {
+		q.interval = time.Second / time.Duration(rate)
+	}
+
+	return q
+}
+
+// Do runs fn once a create slot is free and the create is due.
+func (q *graphCreateQueue) Do(ctx context.Context, fn func() error) error {
+	if q == nil {
+		return fn()
+	}
+
+	if q.slots != nil {
+		select {
+		case q.slots <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for a graph create slot: %w", ctx.Err())
+		}
+		defer func() { <-q.slots }()
+	}
+
+	if wait := q.reserve(); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the graph create rate: %w", ctx.Err())
+		}
+	}
+
+	return fn()
+}
+
+// reserve returns how long to wait for the next create start of the queue,
+// and reserves it.
+func (q *graphCreateQueue) reserve() time.Duration {
+	if q.interval <= 0 {
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	start := q.next
+	if start.Before(now) {
+		start = now
+	}
+	q.next = start.Add(q.interval)
+
+	return start.Sub(now)
+}
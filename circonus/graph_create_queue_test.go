@@ -0,0 +1,113 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestGraphCreateQueue(t *testing.T) {
+	q := newGraphCreateQueue(2, 100)
+
+	var (
+		inFlight, maxInFlight int32
+		wg                    sync.WaitGroup
+	)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = q.Do(context.Background(), func() error {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 creates in flight, got %d", maxInFlight)
+	}
+
+	// 6 creates at 100/s start over at least 50ms.
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the creates to be paced, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	q = newGraphCreateQueue(0, 1)
+	_ = q.Do(ctx, func() error { return nil })
+	if err := q.Do(ctx, func() error { return nil }); err == nil {
+		t.Errorf("expected an error once the context is canceled")
+	}
+
+	var nilQueue *graphCreateQueue
+	if err := nilQueue.Do(context.Background(), func() error { return fmt.Errorf("create failed") }); err == nil {
+		t.Errorf("expected the error of the create")
+	}
+}
+
+func TestGraphCreateNoReadBack(t *testing.T) {
+	var posts, gets int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var g api.Graph
+			_ = json.NewDecoder(r.Body).Decode(&g)
+			g.CID = fmt.Sprintf("/graph/%d", atomic.AddInt32(&posts, 1))
+			_ = json.NewEncoder(w).Encode(g)
+		default:
+			atomic.AddInt32(&gets, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client, graphCreates: newGraphCreateQueue(2, 0)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d := schema.TestResourceDataRaw(t, resourceGraph().Schema, map[string]interface{}{
+				string(graphNameAttr): fmt.Sprintf("graph %d", i),
+			})
+			if diags := graphCreate(context.Background(), d, ctxt); diags.HasError() {
+				t.Errorf("unexpected error: %v", diags)
+				return
+			}
+			if d.Id() == "" || d.Get(graphOutUUIDAttr).(string) == "" {
+				t.Errorf("expected the ID of the created graph, got %q", d.Id())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if posts != 4 || gets != 0 {
+		t.Errorf("expected 4 creates and no reads, got %d creates and %d reads", posts, gets)
+	}
+}
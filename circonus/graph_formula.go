@@ -0,0 +1,331 @@
+package circonus
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Graph datapoint and guide formulas are arithmetic expressions evaluated by
+// the Circonus UI, e.g. "=VAL*8" or "=round(VAL/1000,2)".  The leading '=' is
+// optional.  VAL is the value of the datapoint and upper case identifiers
+// (A, B, ...) reference other datapoints of the graph.  Formulas are only
+// checked for syntax: a malformed formula is an error, a call to a function
+// that is not known to the provider is a warning.
+
+// graphFormulaFuncs is the minimum and maximum number of arguments of each
+// known formula function.  A maximum of -1 is unbounded.
+var graphFormulaFuncs = map[string]struct{ min, max int }{
+	"abs":   {1, 1},
+	"ceil":  {1, 1},
+	"exp":   {1, 1},
+	"floor": {1, 1},
+	"ln":    {1, 1},
+	"log":   {1, 2},
+	"log10": {1, 1},
+	"max":   {1, -1},
+	"min":   {1, -1},
+	"pow":   {2, 2},
+	"round": {1, 2},
+	"sqrt":  {1, 1},
+}
+
+const graphFormulaValueVar = "VAL"
+
+type graphFormulaParser struct {
+	s        string
+	pos      int
+	warnings []string
+	refs     []string
+}
+
+// parseGraphFormula checks the syntax of formula and returns the warnings
+// found along the way.
+func parseGraphFormula(formula string) ([]string, error) {
+	p := &graphFormulaParser{s: formula}
+	err := p.parse()
+
+	return p.warnings, err
+}
+
+// graphFormulaDatapointRefs returns the datapoint references (A, B, ...) in
+// formula, in the order they appear.
+func graphFormulaDatapointRefs(formula string) ([]string, error) {
+	p := &graphFormulaParser{s: formula}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.refs, nil
+}
+
+// parse parses the whole formula: '='? expr
+func (p *graphFormulaParser) parse() error {
+
+	p.skipSpace()
+	if p.peek() == '=' {
+		p.pos++
+	}
+
+	p.skipSpace()
+	if p.eof() {
+		return fmt.Errorf("empty expression")
+	}
+
+	if err := p.parseExpr(); err != nil {
+		return err
+	}
+
+	p.skipSpace()
+	if !p.eof() {
+		return p.errorf("unexpected %q", p.peek())
+	}
+
+	return nil
+}
+
+func (p *graphFormulaParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *graphFormulaParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *graphFormulaParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *graphFormulaParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseExpr parses a sum: term (('+' | '-') term)*
+func (p *graphFormulaParser) parseExpr() error {
+	if err := p.parseTerm(); err != nil {
+		return err
+	}
+
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case '+', '-':
+			p.pos++
+			if err := p.parseTerm(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// parseTerm parses a product: power (('*' | '/' | '%') power)*
+func (p *graphFormulaParser) parseTerm() error {
+	if err := p.parsePower(); err != nil {
+		return err
+	}
+
+	for {
+		p.skipSpace()
+		switch p.peek() {
+		case '*', '/', '%':
+			p.pos++
+			if err := p.parsePower(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// parsePower parses an exponentiation: unary ('^' power)?
+func (p *graphFormulaParser) parsePower() error {
+	if err := p.parseUnary(); err != nil {
+		return err
+	}
+
+	p.skipSpace()
+	if p.peek() == '^' {
+		p.pos++
+		return p.parsePower()
+	}
+
+	return nil
+}
+
+// parseUnary parses a signed operand: ('+' | '-')* primary
+func (p *graphFormulaParser) parseUnary() error {
+	p.skipSpace()
+	for p.peek() == '+' || p.peek() == '-' {
+		p.pos++
+		p.skipSpace()
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a number, a variable, a function call or a
+// parenthesized expression.
+func (p *graphFormulaParser) parsePrimary() error {
+	p.skipSpace()
+	c := p.peek()
+
+	switch {
+	case p.eof():
+		return p.errorf("unexpected end of expression")
+	case c == '(':
+		p.pos++
+		if err := p.parseExpr(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return p.errorf("missing ')'")
+		}
+		p.pos++
+		return nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case c == '_' || unicode.IsLetter(rune(c)):
+		return p.parseIdent()
+	default:
+		return p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *graphFormulaParser) parseNumber() error {
+	start := p.pos
+	digits := 0
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.pos++
+		digits++
+	}
+	if p.peek() == '.' {
+		p.pos++
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			p.pos++
+			digits++
+		}
+	}
+	if digits == 0 {
+		p.pos = start
+		return p.errorf("invalid number")
+	}
+
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if c := p.peek(); c < '0' || c > '9' {
+			return p.errorf("invalid number exponent")
+		}
+		for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+			p.pos++
+		}
+	}
+
+	return nil
+}
+
+func (p *graphFormulaParser) parseIdent() error {
+	start := p.pos
+	for c := p.peek(); c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)); c = p.peek() {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+
+	p.skipSpace()
+	if p.peek() == '(' {
+		return p.parseCall(start, name)
+	}
+
+	if name == graphFormulaValueVar {
+		return nil
+	}
+
+	if isGraphFormulaDatapointRef(name) {
+		p.refs = append(p.refs, name)
+		return nil
+	}
+
+	p.pos = start
+	return p.errorf("unknown variable %q, must be %s or a datapoint reference (A, B, ...)", name, graphFormulaValueVar)
+}
+
+func (p *graphFormulaParser) parseCall(start int, name string) error {
+	// consume '('
+	p.pos++
+
+	args := 0
+	p.skipSpace()
+	if p.peek() != ')' {
+		for {
+			if err := p.parseExpr(); err != nil {
+				return err
+			}
+			args++
+
+			p.skipSpace()
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+	}
+
+	p.skipSpace()
+	if p.peek() != ')' {
+		return p.errorf("missing ')' in call to %s()", name)
+	}
+	p.pos++
+
+	arity, ok := graphFormulaFuncs[strings.ToLower(name)]
+	if !ok {
+		p.warnings = append(p.warnings, fmt.Sprintf("unknown function %s() at position %d", name, start+1))
+		return nil
+	}
+
+	if args < arity.min || (arity.max >= 0 && args > arity.max) {
+		switch {
+		case arity.max < 0:
+			return fmt.Errorf("position %d: %s() takes at least %d argument(s), %d given", start+1, name, arity.min, args)
+		case arity.min == arity.max:
+			return fmt.Errorf("position %d: %s() takes %d argument(s), %d given", start+1, name, arity.min, args)
+		default:
+			return fmt.Errorf("position %d: %s() takes %d to %d arguments, %d given", start+1, name, arity.min, arity.max, args)
+		}
+	}
+
+	return nil
+}
+
+// isGraphFormulaDatapointRef returns true if name is an upper case letter
+// reference to another datapoint of the graph.
+func isGraphFormulaDatapointRef(name string) bool {
+	for _, c := range name {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// graphFormulaDatapointIndex returns the index of the datapoint referenced by
+// ref, counting like spreadsheet columns: A is 0, Z is 25 and AA is 26.
+func graphFormulaDatapointIndex(ref string) int {
+	i := 0
+	for _, c := range ref {
+		i = i*26 + int(c-'A') + 1
+	}
+
+	return i - 1
+}
@@ -40,6 +40,15 @@ func (m *circonusMetric) ParseConfig(id string, d *schema.ResourceData) error {
 		m.Type = v.(string)
 	}
 
+	if v, found := d.GetOk(metricTagsAttr); found {
+		m.Tags = derefStringList(flattenSet(v.(*schema.Set)))
+	}
+
+	if v, found := d.GetOk(metricUnitAttr); found {
+		unit := v.(string)
+		m.Units = &unit
+	}
+
 	return nil
 }
 
@@ -58,6 +67,17 @@ func (m *circonusMetric) ParseConfigMap(id string, attrMap map[string]interface{
 		m.Type = v.(string)
 	}
 
+	if v, found := attrMap[metricTagsAttr]; found {
+		if s, ok := v.(*schema.Set); ok && s.Len() > 0 {
+			m.Tags = derefStringList(flattenSet(s))
+		}
+	}
+
+	if v, found := attrMap[metricUnitAttr]; found && v.(string) != "" {
+		unit := v.(string)
+		m.Units = &unit
+	}
+
 	return nil
 }
 
@@ -67,6 +87,12 @@ func (m *circonusMetric) SaveState(d *schema.ResourceData) error {
 	_ = d.Set(metricActiveAttr, metricAPIStatusToBool(m.Status))
 	_ = d.Set(metricNameAttr, m.Name)
 	_ = d.Set(metricTypeAttr, m.Type)
+	_ = d.Set(metricTagsAttr, tagsToState(apiToTags(m.Tags)))
+	if m.Units != nil {
+		_ = d.Set(metricUnitAttr, *m.Units)
+	} else {
+		_ = d.Set(metricUnitAttr, nil)
+	}
 
 	return nil
 }
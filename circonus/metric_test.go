@@ -1,6 +1,12 @@
 package circonus
 
-import "testing"
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func Test_MetricChecksum(t *testing.T) {
 	m := interfaceMap{
@@ -13,3 +19,40 @@ func Test_MetricChecksum(t *testing.T) {
 		t.Fatalf("Checksum mismatch")
 	}
 }
+
+func Test_MetricParseConfigMap(t *testing.T) {
+	m := newMetric()
+	if err := m.ParseConfigMap("1", map[string]interface{}{
+		string(metricActiveAttr): true,
+		string(metricNameAttr):   "duration",
+		string(metricTagsAttr):   schema.NewSet(schema.HashString, []interface{}{"unit:ms", "team:web"}),
+		string(metricTypeAttr):   "numeric",
+		string(metricUnitAttr):   "milliseconds",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.Units == nil || *m.Units != "milliseconds" {
+		t.Errorf("expected the unit milliseconds, got %v", m.Units)
+	}
+
+	tags := append([]string(nil), m.Tags...)
+	sort.Strings(tags)
+	if !reflect.DeepEqual(tags, []string{"team:web", "unit:ms"}) {
+		t.Errorf("expected the tags of the metric, got %v", m.Tags)
+	}
+
+	m = newMetric()
+	if err := m.ParseConfigMap("2", map[string]interface{}{
+		string(metricNameAttr): "duration",
+		string(metricTagsAttr): schema.NewSet(schema.HashString, nil),
+		string(metricTypeAttr): "numeric",
+		string(metricUnitAttr): "",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.Units != nil || m.Tags != nil {
+		t.Errorf("expected no unit and no tags, got %v and %v", m.Units, m.Tags)
+	}
+}
@@ -0,0 +1,209 @@
+package circonus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The API answers a request the token is not allowed to make with a 403 and a
+// terse message.  Operations failing that way are reported as a permission
+// denied diagnostic naming the token's app, the role of its user on the
+// account and the privilege the operation needs on its endpoint.
+
+const (
+	permissionRead  = "read"
+	permissionWrite = "write"
+)
+
+// permissionEndpoints maps each resource and data source type to the API
+// endpoint it operates on.  Types spanning several endpoints are left out.
+var permissionEndpoints = map[string]string{
+	"circonus_account":        config.AccountPrefix,
+	"circonus_check":          config.CheckBundlePrefix,
+	"circonus_collector":      config.BrokerPrefix,
+	"circonus_contact_group":  config.ContactGroupPrefix,
+	"circonus_dashboard":      config.DashboardPrefix,
+	"circonus_graph":          config.GraphPrefix,
+	"circonus_graph_template": config.GraphPrefix,
+	"circonus_maintenance":    config.MaintenancePrefix,
+	"circonus_overlay_set":    config.GraphPrefix,
+	"circonus_rule_set":       config.RuleSetPrefix,
+	"circonus_rule_set_group": config.RuleSetGroupPrefix,
+	"circonus_worksheet":      config.WorksheetPrefix,
+}
+
+// permissionDeniedError describes an operation the API token was not allowed
+// to make.
+type permissionDeniedError struct {
+	ResourceType string
+	Action       string
+	Endpoint     string
+	Privilege    string
+	TokenApp     string
+	Role         string
+	Err          error
+}
+
+func (e *permissionDeniedError) Error() string {
+	target := e.ResourceType
+	if e.Endpoint != "" {
+		target = e.Endpoint
+	}
+
+	return fmt.Sprintf("permission denied: %s %s requires %s access to %s", e.ResourceType, e.Action, e.Privilege, target)
+}
+
+func (e *permissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// Detail describes the token the operation was made with and what it lacks.
+func (e *permissionDeniedError) Detail() string {
+	var b strings.Builder
+
+	app := e.TokenApp
+	if app == "" {
+		app = "unknown"
+	}
+	role := e.Role
+	if role == "" {
+		role = "unknown"
+	}
+
+	fmt.Fprintf(&b, "The API token (app %q, role %q) is not allowed to %s %s.", app, role, e.Privilege, e.endpointOrType())
+	if e.Privilege == permissionWrite && strings.EqualFold(role, "Read Only") {
+		b.WriteString(" Read only users can not make changes, use a token of a user with the Normal or Admin role.")
+	} else {
+		fmt.Fprintf(&b, " Check that the token is approved for the %q app and that its user's role grants %s access.", app, e.Privilege)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, "\n\n%s", e.Err)
+	}
+
+	return b.String()
+}
+
+func (e *permissionDeniedError) endpointOrType() string {
+	if e.Endpoint != "" {
+		return e.Endpoint
+	}
+
+	return e.ResourceType
+}
+
+// isPermissionDeniedMessage returns true when msg describes a 403 response
+// from the API.
+func isPermissionDeniedMessage(msg string) bool {
+	return strings.Contains(msg, defaultCirconus403ErrorString)
+}
+
+// permissionPrivilege returns the privilege a resource action needs.
+func permissionPrivilege(action string) string {
+	switch action {
+	case "read", "exists":
+		return permissionRead
+	default:
+		return permissionWrite
+	}
+}
+
+// tokenRole returns the role of the API token's user on the account, looked
+// up via the user and account APIs.  An empty string is returned when the
+// role can not be determined, e.g. because the token may not read either.
+func (c *providerContext) tokenRole() string {
+	cache := c.lookupCaches()
+	cache.tokenRoleMu.Lock()
+	defer cache.tokenRoleMu.Unlock()
+
+	if cache.tokenRoleFetched || c.client == nil {
+		return cache.tokenRoleName
+	}
+	cache.tokenRoleFetched = true
+
+	user, err := c.client.FetchUser(nil)
+	if err != nil {
+		return ""
+	}
+
+	account, err := c.client.FetchAccount(nil)
+	if err != nil {
+		return ""
+	}
+
+	for _, u := range account.Users {
+		if u.UserCID == user.CID {
+			cache.tokenRoleName = u.Role
+			break
+		}
+	}
+
+	return cache.tokenRoleName
+}
+
+// newPermissionDeniedError returns the permission denied error of a failed
+// resource action made with the provider context meta.
+func newPermissionDeniedError(resourceType, action string, meta interface{}, err error) *permissionDeniedError {
+	e := &permissionDeniedError{
+		ResourceType: resourceType,
+		Action:       action,
+		Endpoint:     permissionEndpoints[resourceType],
+		Privilege:    permissionPrivilege(action),
+		Err:          err,
+	}
+
+	if ctxt, ok := meta.(*providerContext); ok && ctxt != nil {
+		if ctxt.apiConfig != nil {
+			e.TokenApp = ctxt.apiConfig.TokenApp
+		}
+		e.Role = ctxt.tokenRole()
+	}
+
+	return e
+}
+
+// withPermissionDenied wraps the CRUD functions of r, which must be context
+// aware, so 403 responses are reported as permission denied diagnostics.
+func withPermissionDenied(resourceType string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = wrapPermissionDeniedContextFunc(resourceType, "create", r.CreateContext)
+	r.ReadContext = wrapPermissionDeniedContextFunc(resourceType, "read", r.ReadContext)
+	r.UpdateContext = wrapPermissionDeniedContextFunc(resourceType, "update", r.UpdateContext)
+	r.DeleteContext = wrapPermissionDeniedContextFunc(resourceType, "delete", r.DeleteContext)
+
+	if fn := r.Exists; fn != nil {
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			exists, err := fn(d, meta)
+			if err != nil && isPermissionDeniedMessage(err.Error()) {
+				return exists, newPermissionDeniedError(resourceType, "exists", meta, err)
+			}
+			return exists, err
+		}
+	}
+
+	return r
+}
+
+func wrapPermissionDeniedContextFunc(resourceType, action string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := fn(ctx, d, meta)
+		for i, dg := range diags {
+			if dg.Severity != diag.Error || !isPermissionDeniedMessage(dg.Summary+" "+dg.Detail) {
+				continue
+			}
+
+			e := newPermissionDeniedError(resourceType, action, meta, fmt.Errorf("%s", dg.Summary))
+			diags[i].Summary = e.Error()
+			diags[i].Detail = e.Detail()
+		}
+
+		return diags
+	}
+}
@@ -0,0 +1,90 @@
+package circonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithPermissionDenied(t *testing.T) {
+	var lookups int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/current":
+			lookups++
+			fmt.Fprint(w, `{"_cid":"/user/7","email":"ci@example.com"}`)
+		case "/account/current":
+			fmt.Fprint(w, `{"_cid":"/account/1","users":[{"user":"/user/3","role":"Admin"},{"user":"/user/7","role":"Read Only"}]}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"code":403,"message":"Forbidden"}`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &api.Config{URL: srv.URL, TokenKey: "test", TokenApp: "terraform-provider-circonus"}
+	client, err := api.NewAPI(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	meta := &providerContext{client: client, apiConfig: cfg}
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		CreateContext: func(_ context.Context, _ *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			_, err := meta.(*providerContext).client.CreateCheckBundle(&api.CheckBundle{})
+			return diag.FromErr(err)
+		},
+		ReadContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+			return diag.FromErr(errors.New("API response code 500: boom"))
+		},
+	}
+	withPermissionDenied("circonus_check", r)
+
+	d := r.TestResourceData()
+	diags := r.CreateContext(context.Background(), d, meta)
+	if !diags.HasError() {
+		t.Fatalf("expected create to fail")
+	}
+	if want := "permission denied: circonus_check create requires write access to /check_bundle"; diags[0].Summary != want {
+		t.Fatalf("expected summary %q, got %q", want, diags[0].Summary)
+	}
+	for _, s := range []string{`app "terraform-provider-circonus"`, `role "Read Only"`, "Normal or Admin", defaultCirconus403ErrorString} {
+		if !strings.Contains(diags[0].Detail, s) {
+			t.Fatalf("expected detail to contain %q, got %q", s, diags[0].Detail)
+		}
+	}
+
+	_ = r.CreateContext(context.Background(), d, meta)
+	if lookups != 1 {
+		t.Fatalf("expected the token role to be looked up once, got %d", lookups)
+	}
+
+	diags = r.ReadContext(context.Background(), d, meta)
+	if !diags.HasError() || strings.Contains(diags[0].Summary, "permission denied") {
+		t.Fatalf("expected other errors to be returned as is, got %v", diags)
+	}
+}
+
+func TestPermissionDeniedError(t *testing.T) {
+	e := newPermissionDeniedError("circonus_topology", "read", nil, errors.New("API response code 403: Forbidden"))
+	if want := "permission denied: circonus_topology read requires read access to circonus_topology"; e.Error() != want {
+		t.Fatalf("expected %q, got %q", want, e.Error())
+	}
+	if !strings.Contains(e.Detail(), `role "unknown"`) {
+		t.Fatalf("expected an unknown role, got %q", e.Detail())
+	}
+
+	var target *permissionDeniedError
+	if !errors.As(fmt.Errorf("wrapped: %w", e), &target) || !strings.Contains(errors.Unwrap(target).Error(), "403") {
+		t.Fatalf("expected the API error to be wrapped")
+	}
+}
@@ -3,9 +3,12 @@ package circonus
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -13,6 +16,7 @@ import (
 )
 
 const (
+	defaultCirconus403ErrorString        = "API response code 403:"
 	defaultCirconus404ErrorString        = "API response code 404:"
 	defaultCirconusAggregationWindow     = "300s"
 	defaultCirconusAlertMinEscalateAfter = "300s"
@@ -28,17 +32,43 @@ const (
 )
 
 var providerDescription = map[string]string{
-	providerAPIURLAttr:  "URL of the Circonus API",
-	providerAutoTagAttr: "Signals that the provider should automatically add a tag to all API calls denoting that the resource was created by Terraform",
-	providerKeyAttr:     "API token used to authenticate with the Circonus API",
+	providerAccountIDAttr:              "ID of the account API requests are made against, for tokens with access to several accounts",
+	providerActivityLogActorAttr:       "Who is applying the changes, reported in each activity log event",
+	providerActivityLogTokenAttr:       "Bearer token sent to the activity log endpoint",
+	providerActivityLogURLAttr:         "Webhook URL an event is POSTed to after each resource is created, updated or deleted",
+	providerActivityLogWorkspaceAttr:   "The Terraform workspace reported in each activity log event and request annotation",
+	providerAPIMaintenanceTimeoutAttr:  "How long to wait for a Circonus API maintenance window to end before failing (e.g. 15m, 0s disables waiting).  When set, rate limited (429) and other 5xx responses are retried at most 4 times rather than until they succeed",
+	providerAPIURLAttr:                 "URL or hostname of the Circonus API, or the name of a preset (saas)",
+	providerAutoTagAttr:                "Signals that the provider should automatically add a tag to all API calls denoting that the resource was created by Terraform",
+	providerGraphCreateConcurrencyAttr: "The maximum number of graphs created at once, 0 leaves it to Terraform's parallelism",
+	providerGraphCreateRateAttr:        "The maximum number of graph creates started per second, 0 disables pacing",
+	providerKeyAttr:                    "API token used to authenticate with the Circonus API",
+	providerLinkTemplateAttr:           "URL template used as the link of rule sets that do not set one (e.g. https://wiki.example.org/{check_name}/{metric})",
+	providerRunIDAttr:                  "ID of the Terraform run reported in each request annotation, generated when not set",
+	providerValidateCAQLAttr:           "Signals that the provider should verify the queries of caql checks against the Circonus API during plan",
+	providerValidateReferencesAttr:     "Signals that the provider should verify that referenced users, contact groups and the metrics of caql queries exist in the Circonus API during plan",
+	providerVaultAddressAttr:           "Address of the Vault server vault: secret references of checks are read from",
+	providerVaultTokenAttr:             "Token used to read vault: secret references of checks",
 }
 
 // Constants that want to be a constant but can't in Go.
 var (
-	validContactHTTPFormats = validStringValues{"json", "params"}
-	validContactHTTPMethods = validStringValues{"GET", "POST"}
+	validContactFloodControls = validStringValues{"off", "low", "medium", "high"}
+	validContactGroupTypes    = validStringValues{"normal", "on_call"}
+	validContactHTTPFormats   = validStringValues{"json", "params"}
+	validContactHTTPMethods   = validStringValues{"GET", "POST"}
 )
 
+// contactFloodControlWindows maps each contact group flood_control preset to
+// the aggregation window it applies.  The API has no per-contact rate limit,
+// batching alerts into a larger window is how floods are contained.
+var contactFloodControlWindows = map[string]string{
+	"off":    "0s",
+	"low":    "60s",
+	"medium": defaultCirconusAggregationWindow,
+	"high":   "900s",
+}
+
 type contactMethods string
 
 // globalAutoTag controls whether or not the provider should automatically add a
@@ -52,21 +82,120 @@ var globalAutoTag bool //nolint:unused
 type providerContext struct {
 	// Circonus API client
 	client *api.API
+	// apiConfig is the configuration client was created with, used to create
+	// the clients of resources with api_overrides
+	apiConfig *api.Config
 	// defaultTag make up the tag to be used when autoTag tags a tag.
 	defaultTag circonusTag
 	// autoTag, when true, automatically appends defaultCirconusTag
 	autoTag bool
+	// validateRefs, when true, verifies referenced CIDs exist during plan
+	validateRefs bool
+	// validateCAQL, when true, verifies the queries of caql checks during plan
+	validateCAQL bool
+	// apiMaintenanceTimeout bounds how long operations wait for an API
+	// maintenance window to end
+	apiMaintenanceTimeout time.Duration
+	// linkTemplate is rendered into the link of rule sets created without one
+	linkTemplate string
+	// activityLog, when not nil, is notified of each change to a resource
+	activityLog *activityLog
+	// secrets resolves the secret references of checks
+	secrets secretResolvers
+	// runID and workspace annotate each operation on a resource
+	runID     string
+	workspace string
+	// graphCreates paces the graph creates of concurrent workers
+	graphCreates *graphCreateQueue
+	// caches holds the API lookups cached for the life of the provider, by
+	// pointer so copies of the context (see apiOverrides) share them
+	caches *providerCaches
+}
+
+// providerCaches holds the results of API lookups cached for the life of the
+// provider.  Its zero value is ready to use.
+type providerCaches struct {
+	// contactGroupCIDs caches contact group names resolved to CIDs, and
+	// contactGroupNames CIDs resolved to names
+	contactGroupCIDs   map[string]string
+	contactGroupNames  map[string]string
+	contactGroupCIDsMu sync.Mutex
+	// userCIDs caches user email addresses resolved to CIDs
+	userCIDs   map[string]string
+	userCIDsMu sync.Mutex
+	// tokenRoleName caches the role of the API token's user, reported when
+	// the API denies an operation
+	tokenRoleName    string
+	tokenRoleFetched bool
+	tokenRoleMu      sync.Mutex
+}
+
+// providerCachesMu guards the creation of the caches of contexts created
+// without them, e.g. in tests.
+var providerCachesMu sync.Mutex
+
+// lookupCaches returns the caches of c, creating them on first use.
+func (c *providerContext) lookupCaches() *providerCaches {
+	providerCachesMu.Lock()
+	defer providerCachesMu.Unlock()
+
+	if c.caches == nil {
+		c.caches = &providerCaches{}
+	}
+
+	return c.caches
 }
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() *schema.Provider {
 	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
-			providerAPIURLAttr: {
+			providerAccountIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_ACCOUNT_ID", ""),
+				ValidateFunc: validateRegexp(providerAccountIDAttr, `^[0-9]*$`),
+				Description:  providerDescription[providerAccountIDAttr],
+			},
+			providerActivityLogActorAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"CIRCONUS_ACTIVITY_LOG_ACTOR", "USER"}, ""),
+				Description: providerDescription[providerActivityLogActorAttr],
+			},
+			providerActivityLogTokenAttr: {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_API_URL", "https://api.circonus.com/v2"),
-				Description: providerDescription[providerAPIURLAttr],
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_ACTIVITY_LOG_TOKEN", ""),
+				Description: providerDescription[providerActivityLogTokenAttr],
+			},
+			providerActivityLogURLAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_ACTIVITY_LOG_URL", ""),
+				ValidateFunc: validateHTTPURL(providerActivityLogURLAttr, urlIsAbs|urlOptional),
+				Description:  providerDescription[providerActivityLogURLAttr],
+			},
+			providerActivityLogWorkspaceAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_WORKSPACE", "default"),
+				Description: providerDescription[providerActivityLogWorkspaceAttr],
+			},
+			providerAPIMaintenanceTimeoutAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_API_MAINTENANCE_TIMEOUT", defaultAPIMaintenanceTimeout),
+				ValidateFunc: validateDurationMin(providerAPIMaintenanceTimeoutAttr, "0s"),
+				Description:  providerDescription[providerAPIMaintenanceTimeoutAttr],
+			},
+			providerAPIURLAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_API_URL", defaultAPIURL),
+				ValidateFunc: validateAPIURL,
+				Description:  providerDescription[providerAPIURLAttr],
 			},
 			providerAutoTagAttr: {
 				Type:        schema.TypeBool,
@@ -74,6 +203,20 @@ func Provider() *schema.Provider {
 				Default:     defaultAutoTag,
 				Description: providerDescription[providerAutoTagAttr],
 			},
+			providerGraphCreateConcurrencyAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_GRAPH_CREATE_CONCURRENCY", defaultGraphCreateConcurrency),
+				ValidateFunc: validateIntMin(providerGraphCreateConcurrencyAttr, 0),
+				Description:  providerDescription[providerGraphCreateConcurrencyAttr],
+			},
+			providerGraphCreateRateAttr: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_GRAPH_CREATE_RATE", defaultGraphCreateRate),
+				ValidateFunc: validateIntMin(providerGraphCreateRateAttr, 0),
+				Description:  providerDescription[providerGraphCreateRateAttr],
+			},
 			providerKeyAttr: {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -81,11 +224,53 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_API_TOKEN", nil),
 				Description: providerDescription[providerKeyAttr],
 			},
+			providerLinkTemplateAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIRCONUS_LINK_TEMPLATE", ""),
+				ValidateFunc: validateLinkTemplate,
+				Description:  providerDescription[providerLinkTemplateAttr],
+			},
+			providerRunIDAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc([]string{"CIRCONUS_RUN_ID", "TFC_RUN_ID"}, ""),
+				Description: providerDescription[providerRunIDAttr],
+			},
+			providerValidateCAQLAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_VALIDATE_CAQL", defaultValidateCAQL),
+				Description: providerDescription[providerValidateCAQLAttr],
+			},
+			providerValidateReferencesAttr: {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIRCONUS_VALIDATE_REFERENCES", defaultValidateReferences),
+				Description: providerDescription[providerValidateReferencesAttr],
+			},
+			providerVaultAddressAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VAULT_ADDR", ""),
+				ValidateFunc: validateHTTPURL(providerVaultAddressAttr, urlIsAbs|urlOptional),
+				Description:  providerDescription[providerVaultAddressAttr],
+			},
+			providerVaultTokenAttr: {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VAULT_TOKEN", ""),
+				Description: providerDescription[providerVaultTokenAttr],
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"circonus_account":   dataSourceCirconusAccount(),
-			"circonus_collector": dataSourceCirconusCollector(),
+			"circonus_account":        dataSourceCirconusAccount(),
+			"circonus_collector":      dataSourceCirconusCollector(),
+			"circonus_graph_template": dataSourceCirconusGraphTemplate(),
+			"circonus_topology":       dataSourceCirconusTopology(),
+			"circonus_unmanaged":      dataSourceCirconusUnmanaged(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -104,6 +289,19 @@ func Provider() *schema.Provider {
 		ConfigureContextFunc: providerConfigure,
 	}
 
+	for name, r := range p.ResourcesMap {
+		withAPIOverrides(withAPIMaintenanceRetry(withContextFuncs(r)))
+		withActivityLog(name, r)
+		withPermissionDenied(name, r)
+		withRequestAnnotations(name, r)
+	}
+
+	for name, r := range p.DataSourcesMap {
+		withAPIMaintenanceRetry(r)
+		withPermissionDenied(name, r)
+		withRequestAnnotations(name, r)
+	}
+
 	return p
 }
 
@@ -115,10 +313,16 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		debug = true
 	}
 
+	apiURL, err := normalizeAPIURL(d.Get(providerAPIURLAttr).(string))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
 	config := &api.Config{
-		URL:      d.Get(providerAPIURLAttr).(string),
-		TokenKey: d.Get(providerKeyAttr).(string),
-		TokenApp: "terraform-provider-circonus",
+		URL:            apiURL,
+		TokenKey:       d.Get(providerKeyAttr).(string),
+		TokenApp:       "terraform-provider-circonus",
+		TokenAccountID: d.Get(providerAccountIDAttr).(string),
 	}
 
 	if debug {
@@ -133,11 +337,54 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diag.FromErr(err)
 	}
 
-	client.EnableExponentialBackoff()
+	maintenanceTimeout, err := time.ParseDuration(d.Get(providerAPIMaintenanceTimeoutAttr).(string))
+	if err != nil {
+		return nil, diag.FromErr(fmt.Errorf("invalid %s: %w", providerAPIMaintenanceTimeoutAttr, err))
+	}
+
+	// The exponential backoff in the API client retries every 5xx response,
+	// maintenance responses included, without bound: it never returns the 503
+	// the maintenance retry needs to see, so the two can not be layered.  When
+	// waiting for maintenance windows is enabled the client's bounded retries
+	// (4 retries, 1s to 15s apart) are used instead, at the cost of failing on
+	// rate limiting or outages that outlast them.  The attribute documents the
+	// trade-off.
+	if maintenanceTimeout == 0 {
+		client.EnableExponentialBackoff()
+	}
+
+	runID := d.Get(providerRunIDAttr).(string)
+	if runID == "" {
+		runID = newRequestID()
+	}
+	workspace := d.Get(providerActivityLogWorkspaceAttr).(string)
+	log.Printf("[DEBUG] circonus: run_id=%s workspace=%s", runID, workspace)
 
 	return &providerContext{
-		client:     client,
-		autoTag:    d.Get(providerAutoTagAttr).(bool),
-		defaultTag: defaultCirconusTag,
+		client:       client,
+		apiConfig:    config,
+		autoTag:      d.Get(providerAutoTagAttr).(bool),
+		defaultTag:   defaultCirconusTag,
+		validateRefs: d.Get(providerValidateReferencesAttr).(bool),
+		validateCAQL: d.Get(providerValidateCAQLAttr).(bool),
+
+		apiMaintenanceTimeout: maintenanceTimeout,
+		linkTemplate:          d.Get(providerLinkTemplateAttr).(string),
+		activityLog: newActivityLog(
+			d.Get(providerActivityLogURLAttr).(string),
+			d.Get(providerActivityLogTokenAttr).(string),
+			d.Get(providerActivityLogActorAttr).(string),
+			workspace,
+		),
+		secrets: newSecretResolvers(
+			d.Get(providerVaultAddressAttr).(string),
+			d.Get(providerVaultTokenAttr).(string),
+		),
+		runID:     runID,
+		workspace: workspace,
+		graphCreates: newGraphCreateQueue(
+			d.Get(providerGraphCreateConcurrencyAttr).(int),
+			d.Get(providerGraphCreateRateAttr).(int),
+		),
 	}, diags
 }
@@ -0,0 +1,157 @@
+package circonus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Each operation on a resource is annotated with a request ID, the run ID of
+// the provider and the Terraform workspace so Circonus-side audit logs can be
+// correlated with Terraform runs.  The annotations are logged at DEBUG around
+// each operation, bracketing the API requests the client logs, and sent as
+// headers with the requests the provider makes itself (activity log events,
+// Vault).
+//
+// Requests to the Circonus API are NOT annotated.  The API client builds a new
+// http.Transport for every request and sets a fixed list of headers, it has no
+// option to add headers or to wrap its transport in an http.RoundTripper, and
+// it takes no context the annotation could travel in.  Annotating them needs
+// support in the API client.
+
+const (
+	requestIDHeader        = "X-Request-Id"
+	requestRunIDHeader     = "X-Terraform-Run-Id"
+	requestWorkspaceHeader = "X-Terraform-Workspace"
+)
+
+// requestAnnotation is the annotation of a single resource operation.
+type requestAnnotation struct {
+	ID        string
+	RunID     string
+	Workspace string
+}
+
+type requestAnnotationKey struct{}
+
+// newRequestID returns a random ID, used for request and run IDs.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// withRequestAnnotation returns a copy of ctx carrying a.
+func withRequestAnnotation(ctx context.Context, a requestAnnotation) context.Context {
+	return context.WithValue(ctx, requestAnnotationKey{}, a)
+}
+
+// requestAnnotationFromContext returns the annotation of the operation ctx
+// belongs to, if any.
+func requestAnnotationFromContext(ctx context.Context) (requestAnnotation, bool) {
+	a, ok := ctx.Value(requestAnnotationKey{}).(requestAnnotation)
+	return a, ok
+}
+
+// setRequestAnnotationHeaders sets the annotation headers of the operation
+// ctx belongs to on h.
+func setRequestAnnotationHeaders(ctx context.Context, h http.Header) {
+	a, ok := requestAnnotationFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	h.Set(requestIDHeader, a.ID)
+	if a.RunID != "" {
+		h.Set(requestRunIDHeader, a.RunID)
+	}
+	if a.Workspace != "" {
+		h.Set(requestWorkspaceHeader, a.Workspace)
+	}
+}
+
+// newRequestAnnotation returns the annotation of a new operation made with
+// the provider context meta.
+func newRequestAnnotation(meta interface{}) requestAnnotation {
+	a := requestAnnotation{ID: newRequestID()}
+	if ctxt, ok := meta.(*providerContext); ok && ctxt != nil {
+		a.RunID = ctxt.runID
+		a.Workspace = ctxt.workspace
+	}
+
+	return a
+}
+
+// withContextFuncs replaces the CRUD functions of r with their context aware
+// equivalent, so the wrappers of the provider can pass the context on.
+func withContextFuncs(r *schema.Resource) *schema.Resource {
+	toContextFunc := func(fn func(*schema.ResourceData, interface{}) error) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return diag.FromErr(fn(d, meta))
+		}
+	}
+
+	if r.Create != nil {
+		r.CreateContext, r.Create = toContextFunc(r.Create), nil
+	}
+	if r.Read != nil {
+		r.ReadContext, r.Read = toContextFunc(r.Read), nil
+	}
+	if r.Update != nil {
+		r.UpdateContext, r.Update = toContextFunc(r.Update), nil
+	}
+	if r.Delete != nil {
+		r.DeleteContext, r.Delete = toContextFunc(r.Delete), nil
+	}
+
+	return r
+}
+
+// withRequestAnnotations wraps the CRUD functions of r, which must be context
+// aware, so each call is annotated and logged.
+func withRequestAnnotations(resourceType string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = wrapRequestAnnotationsContextFunc(resourceType, "create", r.CreateContext)
+	r.ReadContext = wrapRequestAnnotationsContextFunc(resourceType, "read", r.ReadContext)
+	r.UpdateContext = wrapRequestAnnotationsContextFunc(resourceType, "update", r.UpdateContext)
+	r.DeleteContext = wrapRequestAnnotationsContextFunc(resourceType, "delete", r.DeleteContext)
+
+	if fn := r.Exists; fn != nil {
+		r.Exists = func(d *schema.ResourceData, meta interface{}) (bool, error) {
+			a := newRequestAnnotation(meta)
+			logRequestAnnotation(resourceType, "exists", d.Id(), a)
+			return fn(d, meta)
+		}
+	}
+
+	return r
+}
+
+func wrapRequestAnnotationsContextFunc(resourceType, action string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		a := newRequestAnnotation(meta)
+		logRequestAnnotation(resourceType, action, d.Id(), a)
+
+		start := time.Now()
+		diags := fn(withRequestAnnotation(ctx, a), d, meta)
+		log.Printf("[DEBUG] %s %s %s: request_id=%s done in %s (error=%t)", resourceType, action, d.Id(), a.ID, time.Since(start).Round(time.Millisecond), diags.HasError())
+
+		return diags
+	}
+}
+
+func logRequestAnnotation(resourceType, action, cid string, a requestAnnotation) {
+	log.Printf("[DEBUG] %s %s %s: request_id=%s run_id=%s workspace=%s", resourceType, action, cid, a.ID, a.RunID, a.Workspace)
+}
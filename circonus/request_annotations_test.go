@@ -0,0 +1,110 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestWithRequestAnnotations(t *testing.T) {
+	var (
+		events  []activityLogEvent
+		headers []http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e activityLogEvent
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		events = append(events, e)
+		headers = append(headers, r.Header)
+	}))
+	defer srv.Close()
+
+	meta := &providerContext{
+		activityLog: newActivityLog(srv.URL, "", "", "prod"),
+		runID:       "run-1",
+		workspace:   "prod",
+	}
+
+	var annotations []requestAnnotation
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Create: func(d *schema.ResourceData, meta interface{}) error {
+			d.SetId("/graph/1")
+			return nil
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			a, ok := requestAnnotationFromContext(ctx)
+			if !ok {
+				return diag.FromErr(fmt.Errorf("no request annotation"))
+			}
+			annotations = append(annotations, a)
+			return nil
+		},
+		Delete: func(d *schema.ResourceData, meta interface{}) error {
+			return fmt.Errorf("delete failed")
+		},
+	}
+	withRequestAnnotations("circonus_graph", withActivityLog("circonus_graph", withContextFuncs(r)))
+
+	if r.Create != nil || r.Delete != nil {
+		t.Fatalf("expected the CRUD functions to be context aware")
+	}
+
+	d := r.TestResourceData()
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := r.DeleteContext(context.Background(), d, meta); !diags.HasError() {
+		t.Fatalf("expected an error")
+	}
+
+	if len(annotations) != 2 {
+		t.Fatalf("expected 2 annotated reads, got %d", len(annotations))
+	}
+	for _, a := range annotations {
+		if a.ID == "" || a.RunID != "run-1" || a.Workspace != "prod" {
+			t.Errorf("unexpected annotation: %#v", a)
+		}
+	}
+	if annotations[0].ID == annotations[1].ID {
+		t.Errorf("expected each operation to have its own request ID, got %q twice", annotations[0].ID)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %#v", len(events), events)
+	}
+	if e := events[0]; e.RequestID == "" || e.RunID != "run-1" || e.Workspace != "prod" {
+		t.Errorf("unexpected event: %#v", e)
+	}
+
+	h := headers[0]
+	if h.Get(requestIDHeader) != events[0].RequestID || h.Get(requestRunIDHeader) != "run-1" || h.Get(requestWorkspaceHeader) != "prod" {
+		t.Errorf("unexpected headers: %v", h)
+	}
+}
+
+func TestSetRequestAnnotationHeaders(t *testing.T) {
+	h := http.Header{}
+	setRequestAnnotationHeaders(context.Background(), h)
+	if len(h) != 0 {
+		t.Errorf("expected no headers without an annotation, got %v", h)
+	}
+
+	ctx := withRequestAnnotation(context.Background(), requestAnnotation{ID: "abc"})
+	setRequestAnnotationHeaders(ctx, h)
+	if h.Get(requestIDHeader) != "abc" || h.Get(requestRunIDHeader) != "" || h.Get(requestWorkspaceHeader) != "" {
+		t.Errorf("unexpected headers: %v", h)
+	}
+}
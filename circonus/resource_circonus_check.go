@@ -20,52 +20,73 @@ package circonus
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/timeutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_check.* global resource attribute names.
-	checkActiveAttr       = "active"
-	checkCAQLAttr         = "caql"
-	checkCloudWatchAttr   = "cloudwatch"
-	checkCollectorAttr    = "collector"
-	checkConsulAttr       = "consul"
-	checkDNSAttr          = "dns"
-	checkExternalAttr     = "external"
-	checkHTTPAttr         = "http"
-	checkHTTPTrapAttr     = "httptrap"
-	checkICMPPingAttr     = "icmp_ping"
-	checkJMXAttr          = "jmx"
-	checkJSONAttr         = "json"
-	checkMemcachedAttr    = "memcached"
-	checkMetricAttr       = "metric"
-	checkMetricFilterAttr = "metric_filter"
-	checkMetricLimitAttr  = "metric_limit"
-	checkMySQLAttr        = "mysql"
-	checkNameAttr         = "name"
-	checkNTPAttr          = "ntp"
-	checkNotesAttr        = "notes"
-	checkPeriodAttr       = "period"
-	checkPostgreSQLAttr   = "postgresql"
-	checkPromTextAttr     = "promtext"
-	checkRedisAttr        = "redis"
-	checkSMTPAttr         = "smtp"
-	checkSNMPAttr         = "snmp"
-	checkStatsdAttr       = "statsd"
-	checkTCPAttr          = "tcp"
-	checkTagsAttr         = "tags"
-	checkTargetAttr       = "target"
-	checkTimeoutAttr      = "timeout"
-	checkTypeAttr         = "type"
+	checkActiveAttr                    = "active"
+	checkActivateDiscoveredMetricsAttr = "activate_discovered_metrics"
+	checkCAQLAttr                      = "caql"
+	checkCloudWatchAttr                = "cloudwatch"
+	checkCollectorAttr                 = "collector"
+	checkConsulAttr                    = "consul"
+	checkDNSAttr                       = "dns"
+	checkExternalAttr                  = "external"
+	checkHAProxyAttr                   = "haproxy"
+	checkHTTPAttr                      = "http"
+	checkHTTPSequenceAttr              = "http_sequence"
+	checkHTTPTrapAttr                  = "httptrap"
+	checkHistogramAttr                 = "histogram_ingest"
+	checkICMPPingAttr                  = "icmp_ping"
+	checkIMAPAttr                      = "imap"
+	checkJMXAttr                       = "jmx"
+	checkJolokiaAttr                   = "jolokia"
+	checkJSONAttr                      = "json"
+	checkLDAPAttr                      = "ldap"
+	checkMemcachedAttr                 = "memcached"
+	checkMetricAttr                    = "metric"
+	checkMetricFilterAttr              = "metric_filter"
+	checkMetricLimitAttr               = "metric_limit"
+	checkMySQLAttr                     = "mysql"
+	checkNameAttr                      = "name"
+	checkNTPAttr                       = "ntp"
+	checkNotesAttr                     = "notes"
+	checkOTLPAttr                      = "otlp"
+	checkPeriodAttr                    = "period"
+	checkPOP3Attr                      = "pop3"
+	checkPostgreSQLAttr                = "postgresql"
+	checkPreventCollectorChangeAttr    = "prevent_collector_change"
+	checkPromTextAttr                  = "promtext"
+	checkQuiesceAttr                   = "quiesce_on_destroy"
+	checkRedisAttr                     = "redis"
+	checkResmonAttr                    = "resmon"
+	checkSelfcheckAttr                 = "selfcheck"
+	checkSMTPAttr                      = "smtp"
+	checkSNMPAttr                      = "snmp"
+	checkStatsdAttr                    = "statsd"
+	checkStrictConfigAttr              = "strict_config"
+	checkTCPAttr                       = "tcp"
+	checkTagsAttr                      = "tags"
+	checkTargetAttr                    = "target"
+	checkTimeoutAttr                   = "timeout"
+	checkTypeAttr                      = "type"
 
 	// circonus_check.collector.* resource attribute names.
 	checkCollectorIDAttr = "id"
 
+	// Out parameters for circonus_check.metric_filter.
+	checkMetricFilterMatchedMetricsAttr = "matched_metrics"
+
 	// circonus_check.metric.* resource attribute names are aliased to
 	// circonus_metric.* resource attributes.
 
@@ -73,16 +94,25 @@ const (
 	// metricIDAttr  = "id".
 
 	// Out parameters for circonus_check.
-	checkOutByCollectorAttr        = "check_by_collector"
-	checkOutIDAttr                 = "check_id"
-	checkOutChecksAttr             = "checks"
-	checkOutCreatedAttr            = "created"
-	checkOutLastModifiedAttr       = "last_modified"
-	checkOutLastModifiedByAttr     = "last_modified_by"
-	checkOutReverseConnectURLsAttr = "reverse_connect_urls"
-	checkOutCheckUUIDsAttr         = "uuids"
+	checkOutActiveMetricCountAttr     = "active_metric_count"
+	checkOutAppliedConfigChecksumAttr = "applied_config_checksum"
+	checkOutByCollectorAttr           = "check_by_collector"
+	checkOutIDAttr                    = "check_id"
+	checkOutChecksAttr                = "checks"
+	checkOutConfigChecksumAttr        = "config_checksum"
+	checkOutCreatedAttr               = "created"
+	checkOutCreatedByAttr             = "created_by"
+	checkOutLastModifiedAttr          = "last_modified"
+	checkOutLastModifiedByAttr        = "last_modified_by"
+	checkOutReverseConnectURLsAttr    = "reverse_connect_urls"
+	checkOutSubmissionURLsAttr        = "submission_urls"
+	checkOutCheckUUIDsAttr            = "uuids"
 )
 
+// checkImportNamePrefix is the prefix of an import ID that identifies a check
+// by name rather than by check bundle CID (e.g. `name=www latency`).
+const checkImportNamePrefix = "name="
+
 const (
 	// Circonus API constants from their API endpoints.
 	apiCheckTypeCAQLAttr       apiCheckType = "caql"
@@ -90,17 +120,27 @@ const (
 	apiCheckTypeConsulAttr     apiCheckType = "consul"
 	apiCheckTypeDNSAttr        apiCheckType = "dns"
 	apiCheckTypeExternalAttr   apiCheckType = "external"
+	apiCheckTypeHAProxyAttr    apiCheckType = "haproxy"
 	apiCheckTypeHTTPAttr       apiCheckType = "http"
+	apiCheckTypeHTTPSeqAttr    apiCheckType = "http_sequence"
 	apiCheckTypeHTTPTrapAttr   apiCheckType = "httptrap"
+	apiCheckTypeHistogramAttr  apiCheckType = "histogram_ingest"
 	apiCheckTypeJMXAttr        apiCheckType = "jmx"
+	apiCheckTypeJolokiaAttr    apiCheckType = "jolokia" // a json check, see isJolokiaCheck
 	apiCheckTypeMemcachedAttr  apiCheckType = "memcached"
 	apiCheckTypeICMPPingAttr   apiCheckType = "ping_icmp"
+	apiCheckTypeIMAPAttr       apiCheckType = "imap"
 	apiCheckTypeJSONAttr       apiCheckType = "json"
+	apiCheckTypeLDAPAttr       apiCheckType = "ldap"
 	apiCheckTypeMySQLAttr      apiCheckType = "mysql"
 	apiCheckTypeNTPAttr        apiCheckType = "ntp"
+	apiCheckTypeOTLPAttr       apiCheckType = "otlphttp"
+	apiCheckTypePOP3Attr       apiCheckType = "pop3"
 	apiCheckTypePostgreSQLAttr apiCheckType = "postgres"
 	apiCheckTypePromTextAttr   apiCheckType = "promtext"
 	apiCheckTypeRedisAttr      apiCheckType = "redis"
+	apiCheckTypeResmonAttr     apiCheckType = "resmon"
+	apiCheckTypeSelfcheckAttr  apiCheckType = "selfcheck"
 	apiCheckTypeSMTPAttr       apiCheckType = "smtp"
 	apiCheckTypeSNMPAttr       apiCheckType = "snmp"
 	apiCheckTypeStatsdAttr     apiCheckType = "statsd"
@@ -108,47 +148,69 @@ const (
 )
 
 var checkDescriptions = attrDescrs{
-	checkActiveAttr:       "If the check is activate or disabled",
-	checkCAQLAttr:         "CAQL check configuration",
-	checkCloudWatchAttr:   "CloudWatch check configuration",
-	checkCollectorAttr:    "The collector(s) that are responsible for gathering the metrics",
-	checkConsulAttr:       "Consul check configuration",
-	checkDNSAttr:          "DNS check configuration",
-	checkExternalAttr:     "External check configuration",
-	checkHTTPAttr:         "HTTP check configuration",
-	checkHTTPTrapAttr:     "HTTP Trap check configuration",
-	checkICMPPingAttr:     "ICMP ping check configuration",
-	checkJMXAttr:          "JMX check configuration",
-	checkJSONAttr:         "JSON check configuration",
-	checkMemcachedAttr:    "Memcached check configuration",
-	checkMetricAttr:       "Configuration for a stream of metrics",
-	checkMetricFilterAttr: "Allow/deny configuration for regex based metric ingestion",
-	checkMetricLimitAttr:  `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer)`,
-	checkMySQLAttr:        "MySQL check configuration",
-	checkNameAttr:         "The name of the check bundle that will be displayed in the web interface",
-	checkNTPAttr:          "NTP check configuration",
-	checkNotesAttr:        "Notes about this check bundle",
-	checkPeriodAttr:       "The period between each time the check is made",
-	checkPostgreSQLAttr:   "PostgreSQL check configuration",
-	checkPromTextAttr:     "Prometheus URL scraper check configuration",
-	checkSMTPAttr:         "SMTP check configuration",
-	checkRedisAttr:        "Redis check configuration",
-	checkSNMPAttr:         "SNMP check configuration",
-	checkStatsdAttr:       "statsd check configuration",
-	checkTCPAttr:          "TCP check configuration",
-	checkTagsAttr:         "A list of tags assigned to the check",
-	checkTargetAttr:       "The target of the check (e.g. hostname, URL, IP, etc)",
-	checkTimeoutAttr:      "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
-	checkTypeAttr:         "The check type",
-
-	checkOutByCollectorAttr:        "",
-	checkOutCheckUUIDsAttr:         "",
-	checkOutChecksAttr:             "",
-	checkOutCreatedAttr:            "",
-	checkOutIDAttr:                 "",
-	checkOutLastModifiedAttr:       "",
-	checkOutLastModifiedByAttr:     "",
-	checkOutReverseConnectURLsAttr: "",
+	checkActiveAttr:                    "If the check is activate or disabled",
+	checkActivateDiscoveredMetricsAttr: "Activate the metrics matched by allow metric filters as they are discovered, rather than only recording them as available",
+	apiOverridesAttr:                   "Overrides of the provider's API retry settings for this check",
+	checkCAQLAttr:                      "CAQL check configuration",
+	checkCloudWatchAttr:                "CloudWatch check configuration",
+	checkCollectorAttr:                 "The collector(s) that are responsible for gathering the metrics",
+	checkConsulAttr:                    "Consul check configuration",
+	checkDNSAttr:                       "DNS check configuration",
+	checkExternalAttr:                  "External check configuration",
+	checkHAProxyAttr:                   "HAProxy stats check configuration",
+	checkHTTPAttr:                      "HTTP check configuration",
+	checkHTTPSequenceAttr:              "HTTP transaction (multi-step) check configuration",
+	checkHTTPTrapAttr:                  "HTTP Trap check configuration",
+	checkHistogramAttr:                 "IRONdb histogram ingest check configuration",
+	checkICMPPingAttr:                  "ICMP ping check configuration",
+	checkIMAPAttr:                      "IMAP check configuration",
+	checkJMXAttr:                       "JMX check configuration",
+	checkJolokiaAttr:                   "JMX over HTTP (Jolokia) check configuration",
+	checkJSONAttr:                      "JSON check configuration",
+	checkIgnoreKeysAttr:                "Config keys managed outside of Terraform, e.g. by the broker, that are excluded from diffs and preserved on update",
+	checkLDAPAttr:                      "LDAP check configuration",
+	checkMemcachedAttr:                 "Memcached check configuration",
+	checkMetricAttr:                    "Configuration for a stream of metrics",
+	checkMetricFilterAttr:              "Allow/deny configuration for regex based metric ingestion",
+	checkMetricLimitAttr:               `Setting a metric_limit will enable all (-1), disable (0), or allow up to the specified limit of metrics for this check ("N+", where N is a positive integer)`,
+	checkMySQLAttr:                     "MySQL check configuration",
+	checkNameAttr:                      "The name of the check bundle that will be displayed in the web interface",
+	checkNTPAttr:                       "NTP check configuration",
+	checkNotesAttr:                     "Notes about this check bundle",
+	checkOTLPAttr:                      "OpenTelemetry (OTLP/HTTP) trap check configuration",
+	checkPeriodAttr:                    "The period between each time the check is made",
+	checkPOP3Attr:                      "POP3 check configuration",
+	checkPostgreSQLAttr:                "PostgreSQL check configuration",
+	checkPreventCollectorChangeAttr:    "Fail the plan of any change to the collectors of the check, which re-provisions the check and changes its UUIDs",
+	checkPromTextAttr:                  "Prometheus URL scraper check configuration",
+	checkQuiesceAttr:                   "Place the check in a short maintenance window before it is destroyed so its alerts do not page",
+	checkSMTPAttr:                      "SMTP check configuration",
+	checkRedisAttr:                     "Redis check configuration",
+	checkResmonAttr:                    "Resmon check configuration",
+	checkSelfcheckAttr:                 "Broker selfcheck configuration",
+	checkSNMPAttr:                      "SNMP check configuration",
+	checkStatsdAttr:                    "statsd check configuration",
+	checkSecretAttr:                    "Config keys of the check set from secrets the provider resolves when the check is applied, never written to the statefile",
+	checkStrictConfigAttr:              "Flag any out-of-band change to the check's config as a diff that requires reconciliation",
+	checkTCPAttr:                       "TCP check configuration",
+	checkTagsAttr:                      "A list of tags assigned to the check",
+	checkTargetAttr:                    "The target of the check (e.g. hostname, URL, IP, etc)",
+	checkTimeoutAttr:                   "The length of time in seconds (and fractions of a second) before the check will timeout if no response is returned to the collector",
+	checkTypeAttr:                      "The check type",
+
+	checkOutActiveMetricCountAttr:     "The number of metrics of the check bundle that are active as of the last refresh",
+	checkOutAppliedConfigChecksumAttr: "Checksum of the check's config as of the last apply",
+	checkOutByCollectorAttr:           "",
+	checkOutCheckUUIDsAttr:            "",
+	checkOutChecksAttr:                "",
+	checkOutConfigChecksumAttr:        "Checksum of the check's config as stored by the Circonus API, including keys not represented in the schema",
+	checkOutCreatedAttr:               "When the check bundle was created, in epoch seconds",
+	checkOutCreatedByAttr:             "The CID of the user who created the check bundle, when reported by the API",
+	checkOutIDAttr:                    "",
+	checkOutLastModifiedAttr:          "When the check bundle was last modified, in epoch seconds",
+	checkOutLastModifiedByAttr:        "The CID of the user who last modified the check bundle",
+	checkOutReverseConnectURLsAttr:    "",
+	checkOutSubmissionURLsAttr:        "The httptrap submission URL of the check on each collector, ordered by collector",
 }
 
 var checkCollectorDescriptions = attrDescrs{
@@ -162,6 +224,8 @@ var (
 		"regex":     "Regex of the filter",
 		"comment":   "Comment on this filter",
 		"tag_query": "The tag query to apply",
+
+		checkMetricFilterMatchedMetricsAttr: "How many of the check's metrics the filter decided on as of the last refresh",
 	}
 )
 
@@ -173,7 +237,17 @@ func resourceCheck() *schema.Resource {
 		DeleteContext: checkDelete,
 		// Exists: checkExists,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: checkImportState,
+		},
+		CustomizeDiff: checkCustomizeDiff,
+
+		// Check bundles with thousands of metrics can take the API longer than
+		// the defaults, the deadlines are honored by apiCallContext.
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultCheckTimeout),
+			Read:   schema.DefaultTimeout(defaultCheckTimeout),
+			Update: schema.DefaultTimeout(defaultCheckTimeout),
+			Delete: schema.DefaultTimeout(defaultCheckTimeout),
 		},
 
 		Schema: convertToHelperSchema(checkDescriptions, map[schemaAttr]*schema.Schema{
@@ -183,6 +257,18 @@ func resourceCheck() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			checkOutActiveMetricCountAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			checkOutAppliedConfigChecksumAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			checkOutConfigChecksumAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			// _brokers
 			checkOutByCollectorAttr: {
 				Type:     schema.TypeMap,
@@ -212,6 +298,11 @@ func resourceCheck() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			// _created_by
+			checkOutCreatedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			// _last_modified
 			checkOutLastModifiedAttr: {
 				Type:     schema.TypeInt,
@@ -230,6 +321,14 @@ func resourceCheck() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			checkOutSubmissionURLsAttr: {
+				Type:      schema.TypeList,
+				Computed:  true,
+				Sensitive: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			// brokers
 			checkCollectorAttr: {
 				Type:     schema.TypeSet,
@@ -277,6 +376,10 @@ func resourceCheck() *schema.Resource {
 							Optional:     true,
 							ValidateFunc: validateRegexp(metricNameAttr, `.+`),
 						},
+						checkMetricFilterMatchedMetricsAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
 					}),
 				},
 			},
@@ -297,19 +400,38 @@ func resourceCheck() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: convertToHelperSchema(checkMetricDescriptions, map[schemaAttr]*schema.Schema{
 						metricActiveAttr: {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
+							Type:             schema.TypeBool,
+							Optional:         true,
+							Default:          true,
+							DiffSuppressFunc: suppressCheckMetricOrder,
 						},
 						metricNameAttr: {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateRegexp(metricNameAttr, `[\S]+`),
+							DiffSuppressFunc: suppressCheckMetricOrder,
+						},
+						metricTagsAttr: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:             schema.TypeString,
+								ValidateFunc:     validateTag,
+								DiffSuppressFunc: suppressCheckMetricOrder,
+							},
+							DiffSuppressFunc: suppressCheckMetricOrder,
 						},
 						metricTypeAttr: {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validateMetricType,
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateFunc:     validateMetricType,
+							DiffSuppressFunc: suppressCheckMetricOrder,
+						},
+						metricUnitAttr: {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validateRegexp(metricUnitAttr, `.+`),
+							DiffSuppressFunc: suppressCheckMetricOrder,
 						},
 					}),
 				},
@@ -326,7 +448,7 @@ func resourceCheck() *schema.Resource {
 				Type:      schema.TypeString,
 				Optional:  true,
 				Computed:  true,
-				StateFunc: normalizeTimeDurationStringToSeconds,
+				StateFunc: timeutil.NormalizeSeconds,
 				ValidateFunc: validateFuncs(
 					validateDurationMin(checkPeriodAttr, defaultCirconusCheckPeriodMin),
 					validateDurationMax(checkPeriodAttr, defaultCirconusCheckPeriodMax),
@@ -338,6 +460,30 @@ func resourceCheck() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			// metric_limit == 0
+			checkActivateDiscoveredMetricsAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			apiOverridesAttr: schemaAPIOverrides,
+			checkPreventCollectorChangeAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkQuiesceAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkIgnoreKeysAttr: schemaCheckIgnoreKeys,
+			checkSecretAttr:     schemaCheckSecret,
+			checkStrictConfigAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			// tags
 			checkTagsAttr: tagMakeConfigSchema(checkTagsAttr),
 			// target
@@ -352,7 +498,7 @@ func resourceCheck() *schema.Resource {
 				Type:      schema.TypeString,
 				Optional:  true,
 				Computed:  true,
-				StateFunc: normalizeTimeDurationStringToSeconds,
+				StateFunc: timeutil.NormalizeSeconds,
 				ValidateFunc: validateFuncs(
 					validateDurationMin(checkTimeoutAttr, defaultCirconusTimeoutMin),
 					validateDurationMax(checkTimeoutAttr, defaultCirconusTimeoutMax),
@@ -370,26 +516,36 @@ func resourceCheck() *schema.Resource {
 			// specific check types, their attributes go into
 			// the check_bundle.config attribute
 			//
-			checkCAQLAttr:       schemaCheckCAQL,
-			checkCloudWatchAttr: schemaCheckCloudWatch,
-			checkConsulAttr:     schemaCheckConsul,
-			checkDNSAttr:        schemaCheckDNS,
-			checkExternalAttr:   schemaCheckExternal,
-			checkHTTPAttr:       schemaCheckHTTP,
-			checkHTTPTrapAttr:   schemaCheckHTTPTrap,
-			checkICMPPingAttr:   schemaCheckICMPPing,
-			checkJMXAttr:        schemaCheckJMX,
-			checkMemcachedAttr:  schemaCheckMemcached,
-			checkMySQLAttr:      schemaCheckMySQL,
-			checkNTPAttr:        schemaCheckNTP,
-			checkJSONAttr:       schemaCheckJSON,
-			checkPostgreSQLAttr: schemaCheckPostgreSQL,
-			checkPromTextAttr:   schemaCheckPromText,
-			checkRedisAttr:      schemaCheckRedis,
-			checkSMTPAttr:       schemaCheckSMTP,
-			checkSNMPAttr:       schemaCheckSNMP,
-			checkStatsdAttr:     schemaCheckStatsd,
-			checkTCPAttr:        schemaCheckTCP,
+			checkCAQLAttr:         schemaCheckCAQL,
+			checkCloudWatchAttr:   schemaCheckCloudWatch,
+			checkConsulAttr:       schemaCheckConsul,
+			checkDNSAttr:          schemaCheckDNS,
+			checkExternalAttr:     schemaCheckExternal,
+			checkHAProxyAttr:      schemaCheckHAProxy,
+			checkHTTPAttr:         schemaCheckHTTP,
+			checkHTTPSequenceAttr: schemaCheckHTTPSequence,
+			checkHTTPTrapAttr:     schemaCheckHTTPTrap,
+			checkHistogramAttr:    schemaCheckHistogramIngest,
+			checkICMPPingAttr:     schemaCheckICMPPing,
+			checkIMAPAttr:         schemaCheckIMAP,
+			checkJMXAttr:          schemaCheckJMX,
+			checkJolokiaAttr:      schemaCheckJolokia,
+			checkMemcachedAttr:    schemaCheckMemcached,
+			checkMySQLAttr:        schemaCheckMySQL,
+			checkNTPAttr:          schemaCheckNTP,
+			checkOTLPAttr:         schemaCheckOTLP,
+			checkPOP3Attr:         schemaCheckPOP3,
+			checkJSONAttr:         schemaCheckJSON,
+			checkLDAPAttr:         schemaCheckLDAP,
+			checkPostgreSQLAttr:   schemaCheckPostgreSQL,
+			checkPromTextAttr:     schemaCheckPromText,
+			checkRedisAttr:        schemaCheckRedis,
+			checkResmonAttr:       schemaCheckResmon,
+			checkSelfcheckAttr:    schemaCheckSelfcheck,
+			checkSMTPAttr:         schemaCheckSMTP,
+			checkSNMPAttr:         schemaCheckSNMP,
+			checkStatsdAttr:       schemaCheckStatsd,
+			checkTCPAttr:          schemaCheckTCP,
 		}),
 	}
 }
@@ -401,13 +557,21 @@ func checkCreate(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
-	if err := c.Create(ctxt); err != nil {
+	if err := checkSecretsToAPI(ctx, ctxt, &c, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := checkIgnoredKeysToAPI(&c, nil, checkIgnoredKeys(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := apiCallContext(ctx, func() error { return c.Create(ctxt) }); err != nil {
 		return diag.FromErr(err)
 	}
 
 	d.SetId(c.CID)
 
-	return checkRead(ctx, d, meta)
+	return checkReadApplied(ctx, d, meta)
 }
 
 // checkRead now covers "existence"
@@ -427,6 +591,62 @@ func checkCreate(ctx context.Context, d *schema.ResourceData, meta interface{})
 // 	return true, nil
 // }
 
+// checkImportState imports a check by its check bundle CID or, when the ID is
+// of the form name=<name>, resolves the name to a CID via the search API.  The
+// check is read as part of the import so the imported state already holds the
+// check type block of every type, including its headers and sensitive
+// attributes, rather than relying on the refresh that follows the import.
+func checkImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if strings.HasPrefix(d.Id(), checkImportNamePrefix) {
+		cid, err := checkImportCIDByName(meta.(*providerContext), strings.TrimPrefix(d.Id(), checkImportNamePrefix))
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(cid)
+	}
+
+	cid := d.Id()
+	if diags := checkReadApplied(ctx, d, meta); diags.HasError() {
+		for _, e := range diags {
+			if e.Severity == diag.Error {
+				return nil, fmt.Errorf("unable to import check %q: %s", cid, e.Summary)
+			}
+		}
+	}
+
+	if d.Id() == "" {
+		return nil, fmt.Errorf("unable to import check %q: check bundle not found", cid)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// checkImportCIDByName resolves the display name of a check bundle to its CID
+// via the search API.  The name must match exactly one check bundle.
+func checkImportCIDByName(ctxt *providerContext, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("check name is required when importing with %q", checkImportNamePrefix)
+	}
+
+	checks, err := searchChecks(ctxt, name, nil)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(checks) {
+	case 0:
+		return "", fmt.Errorf("no check named %q found", name)
+	case 1:
+		return checks[0].CID, nil
+	default:
+		cids := make([]string, 0, len(checks))
+		for i := range checks {
+			cids = append(cids, checks[i].CID)
+		}
+		return "", fmt.Errorf("check name %q is ambiguous, import by CID instead: %s", name, strings.Join(cids, ", "))
+	}
+}
+
 // checkRead pulls data out of the CheckBundle object and stores it into the
 // appropriate place in the statefile.
 func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -435,7 +655,11 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 
 	cid := d.Id()
 	var c circonusCheck
-	c, err := loadCheck(ctxt, api.CIDType(&cid))
+	err := apiCallContext(ctx, func() error {
+		var err error
+		c, err = loadCheck(ctxt, api.CIDType(&cid))
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -452,6 +676,10 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 
 	d.SetId(c.CID)
 
+	// The values of secrets are never read into the statefile.
+	checkSecretsFromAPI(&c, d)
+	checkIgnoredKeysFromAPI(&c, checkIgnoredKeys(d))
+
 	// Global circonus_check attributes are saved first, followed by the check
 	// type specific attributes handled below in their respective checkRead*().
 
@@ -465,22 +693,38 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		checkID = c.Checks[0]
 	}
 
+	checks, checkUUIDs, reverseConnectURLs := c.SortedByCollector()
+
+	hintOnlyMetrics := checkHTTPTrapHintOnlyMetrics(d)
+
 	metrics := make([]interface{}, 0)
 	for _, m := range c.Metrics {
+		if _, found := hintOnlyMetrics[m.Name]; found {
+			continue
+		}
+
 		metricAttrs := map[string]interface{}{
 			string(metricActiveAttr): metricAPIStatusToBool(m.Status),
 			string(metricNameAttr):   m.Name,
 			string(metricTypeAttr):   m.Type,
+			string(metricTagsAttr):   tagsToState(apiToTags(m.Tags)),
+		}
+		if m.Units != nil && *m.Units != "" {
+			metricAttrs[string(metricUnitAttr)] = *m.Units
 		}
 
 		metrics = append(metrics, metricAttrs)
 	}
+	orderCheckMetrics(metrics, d.Get(checkMetricAttr))
 
+	metricFilterMatches := c.MetricFilterMatches()
 	metricFilters := make([]interface{}, 0)
-	for _, m := range c.MetricFilters {
+	for i, m := range c.MetricFilters {
 		metricFilterAttrs := map[string]interface{}{
 			"type":  m[0],
 			"regex": m[1],
+
+			checkMetricFilterMatchedMetricsAttr: metricFilterMatches[i],
 		}
 		if m[2] == "tags" {
 			metricFilterAttrs["tag_query"] = m[3]
@@ -508,6 +752,14 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.FromErr(err)
 	}
 
+	if err := d.Set(checkActivateDiscoveredMetricsAttr, c.MetricLimit != 0); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkOutActiveMetricCountAttr, c.ActiveMetricCount()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	if err := d.Set(checkNameAttr, c.DisplayName); err != nil {
 		return diag.FromErr(err)
 	}
@@ -560,11 +812,11 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diag.FromErr(err) // fmt.Errorf("Unable to store check %q attribute: %w", checkOutByCollectorAttr, err)
 	}
 
-	if err := d.Set(checkOutCheckUUIDsAttr, c.CheckUUIDs); err != nil {
+	if err := d.Set(checkOutCheckUUIDsAttr, checkUUIDs); err != nil {
 		return diag.FromErr(err) // fmt.Errorf("Unable to store check %q attribute: %w", checkOutCheckUUIDsAttr, err)
 	}
 
-	if err := d.Set(checkOutChecksAttr, c.Checks); err != nil {
+	if err := d.Set(checkOutChecksAttr, checks); err != nil {
 		return diag.FromErr(err) // fmt.Errorf("Unable to store check %q attribute: %w", checkOutChecksAttr, err)
 	}
 
@@ -574,22 +826,41 @@ func checkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		}
 	}
 
-	if err := d.Set(checkOutCreatedAttr, c.Created); err != nil {
+	if err := d.Set(checkOutConfigChecksumAttr, c.ConfigChecksum()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkOutCreatedAttr, c.audit.Created); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkOutCreatedByAttr, c.audit.CreatedBy); err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set(checkOutLastModifiedAttr, c.LastModified); err != nil {
+	if err := d.Set(checkOutLastModifiedAttr, c.audit.LastModified); err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set(checkOutLastModifiedByAttr, c.LastModifedBy); err != nil {
+	if err := d.Set(checkOutLastModifiedByAttr, c.audit.LastModifiedBy); err != nil {
 		return diag.FromErr(err)
 	}
 
-	if err := d.Set(checkOutReverseConnectURLsAttr, c.ReverseConnectURLs); err != nil {
+	if err := d.Set(checkOutReverseConnectURLsAttr, reverseConnectURLs); err != nil {
 		return diag.FromErr(err) // fmt.Errorf("Unable to store check %q attribute: %w", checkOutReverseConnectURLsAttr, err)
 	}
 
+	submissionURLs, err := checkHTTPTrapSubmissionURLs(&c, func(cid string) (*api.Broker, error) {
+		return ctxt.client.FetchBroker(api.CIDType(&cid))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set(checkOutSubmissionURLsAttr, submissionURLs); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -600,18 +871,336 @@ func checkUpdate(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.FromErr(err)
 	}
 
+	if err := checkSecretsToAPI(ctx, ctxt, &c, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	c.CID = d.Id()
-	if err := c.Update(ctxt); err != nil {
+
+	if ignored := checkIgnoredKeys(d); len(ignored) > 0 {
+		var current circonusCheck
+		err := apiCallContext(ctx, func() error {
+			var err error
+			current, err = loadCheck(ctxt, api.CIDType(&c.CID))
+			return err
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := checkIgnoredKeysToAPI(&c, &current, ignored); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := apiCallContext(ctx, func() error { return c.Update(ctxt) }); err != nil {
 		return diag.FromErr(err) // fmt.Errorf("unable to update check %q: %w", d.Id(), err)
 	}
 
-	return checkRead(ctx, d, meta)
+	return checkReadApplied(ctx, d, meta)
+}
+
+// checkReadApplied reads the check after it has been written and records the
+// checksum of the config Terraform applied.
+func checkReadApplied(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	diags := checkRead(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := d.Set(checkOutAppliedConfigChecksumAttr, d.Get(checkOutConfigChecksumAttr)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// checkCustomizeDiff forces an update of checks in strict_config mode whose
+// config was changed outside of Terraform since the last apply.
+func checkCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := checkCustomizeDiffPeriodTimeout(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffCollectors(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffMetrics(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffActivateDiscoveredMetrics(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffPromTextIntervals(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffSubmissionURLs(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffCloudWatchCredentials(d); err != nil {
+		return err
+	}
+
+	if err := checkCustomizeDiffCAQL(d, meta); err != nil {
+		return err
+	}
+
+	if d.Id() == "" || !d.Get(checkStrictConfigAttr).(bool) {
+		return nil
+	}
+
+	current := d.Get(checkOutConfigChecksumAttr).(string)
+	applied := d.Get(checkOutAppliedConfigChecksumAttr).(string)
+	if current == "" || applied == "" || current == applied {
+		return nil
+	}
+
+	log.Printf("[WARN] config of check %s was changed outside of Terraform, reconciling", d.Id())
+
+	if err := d.SetNewComputed(checkOutConfigChecksumAttr); err != nil {
+		return err
+	}
+
+	return d.SetNewComputed(checkOutAppliedConfigChecksumAttr)
+}
+
+// checkCustomizeDiffCollectors fails the plan of a change to the collectors of
+// an existing check with prevent_collector_change set.  Moving a check to
+// other collectors re-provisions it under new check UUIDs, breaking the rule
+// sets and graphs referencing the old ones.  The flag is read from the new
+// config, clearing it in the same apply opts in to the move.
+func checkCustomizeDiffCollectors(d *schema.ResourceDiff) error {
+	if d.Id() == "" || !d.Get(checkPreventCollectorChangeAttr).(bool) || !d.HasChange(checkCollectorAttr) {
+		return nil
+	}
+
+	collectorIDs := func(v interface{}) string {
+		s, ok := v.(*schema.Set)
+		if !ok || s.Len() == 0 {
+			return "none"
+		}
+
+		ids := interfaceList(s.List()).CollectList(checkCollectorIDAttr)
+		sort.Strings(ids)
+
+		return strings.Join(ids, ", ")
+	}
+
+	o, n := d.GetChange(checkCollectorAttr)
+	if !d.NewValueKnown(checkCollectorAttr) {
+		return fmt.Errorf("check %s has %s set and its %s are not known until apply, they may move the check from %s", d.Id(), checkPreventCollectorChangeAttr, checkCollectorAttr, collectorIDs(o))
+	}
+
+	return fmt.Errorf("check %s has %s set, changing its %s from %s to %s re-provisions the check with new UUIDs and breaks the rule sets referencing them; set %s = false to allow the move", d.Id(), checkPreventCollectorChangeAttr, checkCollectorAttr, collectorIDs(o), collectorIDs(n), checkPreventCollectorChangeAttr)
+}
+
+// checkCustomizeDiffMetrics fails the plan when two metric blocks declare the
+// same name and type, which the API only rejects once the check is applied.
+// Metrics whose name or type is not known until apply are not compared.
+func checkCustomizeDiffMetrics(d *schema.ResourceDiff) error {
+	l, ok := d.Get(checkMetricAttr).([]interface{})
+	if !ok {
+		return nil
+	}
+
+	metrics := make([]interface{}, len(l))
+	for i, metricRaw := range l {
+		nameKey := fmt.Sprintf("%s.%d.%s", checkMetricAttr, i, metricNameAttr)
+		typeKey := fmt.Sprintf("%s.%d.%s", checkMetricAttr, i, metricTypeAttr)
+		if d.NewValueKnown(nameKey) && d.NewValueKnown(typeKey) {
+			metrics[i] = metricRaw
+		}
+	}
+
+	return validateCheckMetricsUnique(metrics)
+}
+
+// checkCustomizeDiffActivateDiscoveredMetrics keeps activate_discovered_metrics
+// and metric_limit in agreement: the API records discovered metrics without
+// activating them when the metric_limit is 0, so changing either one plans the
+// matching change of the other.
+func checkCustomizeDiffActivateDiscoveredMetrics(d *schema.ResourceDiff) error {
+	activateChanged := d.HasChange(checkActivateDiscoveredMetricsAttr) && d.NewValueKnown(checkActivateDiscoveredMetricsAttr)
+	limitChanged := d.HasChange(checkMetricLimitAttr) && d.NewValueKnown(checkMetricLimitAttr)
+
+	activate := d.Get(checkActivateDiscoveredMetricsAttr).(bool)
+	limit := d.Get(checkMetricLimitAttr).(int)
+
+	switch {
+	case activateChanged && limitChanged:
+		if activate != (limit != 0) {
+			return fmt.Errorf("%s of %d conflicts with %s = %t, a %s of 0 is what stops discovered metrics from being activated", checkMetricLimitAttr, limit, checkActivateDiscoveredMetricsAttr, activate, checkMetricLimitAttr)
+		}
+	case activateChanged && !activate:
+		return d.SetNew(checkMetricLimitAttr, 0)
+	case activateChanged && limit == 0:
+		return d.SetNew(checkMetricLimitAttr, -1)
+	case limitChanged:
+		return d.SetNew(checkActivateDiscoveredMetricsAttr, limit != 0)
+	}
+
+	return nil
+}
+
+// suppressCheckMetricOrder suppresses the diff of the metric blocks when they
+// were only reordered.  Metrics are identified by their name and type and the
+// API keeps no order among them, any other change diffs the whole list.
+func suppressCheckMetricOrder(_, _, _ string, d *schema.ResourceData) bool {
+	o, n := d.GetChange(checkMetricAttr)
+	return checkMetricsReordered(o, n)
+}
+
+// checkMetricsReordered returns true when the metric lists oldRaw and newRaw
+// hold the same metrics in a different order.
+func checkMetricsReordered(oldRaw, newRaw interface{}) bool {
+	oldList, _ := oldRaw.([]interface{})
+	newList, _ := newRaw.([]interface{})
+	if len(oldList) != len(newList) {
+		return false
+	}
+
+	count := make(map[string]int, len(oldList))
+	for _, metricRaw := range oldList {
+		count[checkMetricContentKey(metricRaw)]++
+	}
+
+	for _, metricRaw := range newList {
+		key := checkMetricContentKey(metricRaw)
+		if count[key] == 0 {
+			return false
+		}
+		count[key]--
+	}
+
+	return true
+}
+
+// checkMetricKey returns the identity of a metric block: its type and name.
+func checkMetricKey(metricRaw interface{}) string {
+	metricAttrs, _ := metricRaw.(map[string]interface{})
+	return fmt.Sprintf("%v`%v", metricAttrs[string(metricTypeAttr)], metricAttrs[string(metricNameAttr)])
+}
+
+// checkMetricContentKey returns the identity of a metric block followed by the
+// rest of its content.  Tags are sorted as they are a set.
+func checkMetricContentKey(metricRaw interface{}) string {
+	metricAttrs, _ := metricRaw.(map[string]interface{})
+
+	var tags []string
+	if s, ok := metricAttrs[string(metricTagsAttr)].(*schema.Set); ok {
+		tags = derefStringList(flattenSet(s))
+	}
+	sort.Strings(tags)
+
+	unit := metricAttrs[string(metricUnitAttr)]
+	if unit == nil {
+		unit = ""
+	}
+
+	return fmt.Sprintf("%s`%v`%v`%s", checkMetricKey(metricRaw), metricAttrs[string(metricActiveAttr)], unit, strings.Join(tags, ","))
+}
+
+// orderCheckMetrics sorts the metrics read from the API in the order of the
+// same metrics in prior, so refreshing does not reorder the statefile.
+// Metrics missing from prior follow in the order of the API.
+func orderCheckMetrics(metrics []interface{}, prior interface{}) {
+	priorList, _ := prior.([]interface{})
+	index := make(map[string]int, len(priorList))
+	for i, metricRaw := range priorList {
+		if _, found := index[checkMetricKey(metricRaw)]; !found {
+			index[checkMetricKey(metricRaw)] = i
+		}
+	}
+
+	position := func(metricRaw interface{}) int {
+		if i, found := index[checkMetricKey(metricRaw)]; found {
+			return i
+		}
+		return len(priorList)
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return position(metrics[i]) < position(metrics[j])
+	})
+}
+
+// checkCustomizeDiffSubmissionURLs marks the submission URLs as unknown when
+// an update changes the secret or the collectors they are made of, so
+// resources consuming them are planned with the new URLs.
+func checkCustomizeDiffSubmissionURLs(d *schema.ResourceDiff) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	for _, attrName := range []string{
+		checkCollectorAttr,
+		checkHTTPTrapAttr + ".0." + checkHTTPTrapRotateSecretAttr,
+		checkHTTPTrapAttr + ".0." + checkHTTPTrapSecretAttr,
+	} {
+		if d.HasChange(attrName) {
+			return d.SetNewComputed(checkOutSubmissionURLsAttr)
+		}
+	}
+
+	return nil
+}
+
+// checkCustomizeDiffPeriodTimeout fails the plan when the timeout exceeds the
+// period, or the period is not supported by the check type.  Values that are
+// not known until apply are validated by circonusCheck.Validate instead.
+func checkCustomizeDiffPeriodTimeout(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown(checkPeriodAttr) || !d.NewValueKnown(checkTimeoutAttr) {
+		return nil
+	}
+
+	periodRaw, timeoutRaw := d.Get(checkPeriodAttr).(string), d.Get(checkTimeoutAttr).(string)
+	if periodRaw == "" {
+		return nil
+	}
+
+	period, err := time.ParseDuration(periodRaw)
+	if err != nil {
+		return nil
+	}
+
+	var timeout time.Duration
+	if timeoutRaw != "" {
+		if timeout, err = time.ParseDuration(timeoutRaw); err != nil {
+			return nil
+		}
+	}
+
+	checkType := apiCheckType(d.Get(checkTypeAttr).(string))
+	if v, ok := d.Get(checkCloudWatchAttr).(*schema.Set); ok && v.Len() > 0 {
+		checkType = apiCheckTypeCloudWatchAttr
+	}
+
+	return validateCheckPeriodTimeout(checkType, period, timeout)
 }
 
 func checkDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 
-	if _, err := ctxt.client.Delete(d.Id()); err != nil {
+	if d.Get(checkQuiesceAttr).(bool) {
+		checks := d.Get(checkOutChecksAttr).([]interface{})
+		err := apiCallContext(ctx, func() error {
+			return quiesceChecks(ctxt, d.Id(), interfaceList(checks).List(), checkQuiesceWindow)
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := apiCallContext(ctx, func() error {
+		_, err := ctxt.client.Delete(d.Id())
+		return err
+	})
+	if err != nil {
 		return diag.FromErr(err) // fmt.Errorf("unable to delete check %q: %w", d.Id(), err)
 	}
 
@@ -644,6 +1233,13 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		c.MetricLimit = v.(int)
 	}
 
+	// GetOkExists tells a false set in the config apart from a value that is
+	// not known until the check is read.
+	if v, found := d.GetOkExists(checkActivateDiscoveredMetricsAttr); found && !v.(bool) {
+		c.MetricLimit = 0
+		c.deactivateDiscoveredMetrics = true
+	}
+
 	if v, found := d.GetOk(checkNameAttr); found {
 		c.DisplayName = v.(string)
 	}
@@ -740,6 +1336,10 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 		return fmt.Errorf("unable to parse check type: %w", err)
 	}
 
+	if err := checkHTTPTrapRotateSecret(c, d); err != nil {
+		return err
+	}
+
 	if err := c.Fixup(); err != nil {
 		return err
 	}
@@ -755,26 +1355,36 @@ func (c *circonusCheck) ParseConfig(d *schema.ResourceData) error {
 // type api.Config attributes.
 func checkConfigToAPI(c *circonusCheck, d *schema.ResourceData) error {
 	checkTypeParseMap := map[string]func(*circonusCheck, interfaceList) error{
-		checkCAQLAttr:       checkConfigToAPICAQL,
-		checkCloudWatchAttr: checkConfigToAPICloudWatch,
-		checkConsulAttr:     checkConfigToAPIConsul,
-		checkDNSAttr:        checkConfigToAPIDNS,
-		checkExternalAttr:   checkConfigToAPIExternal,
-		checkHTTPAttr:       checkConfigToAPIHTTP,
-		checkHTTPTrapAttr:   checkConfigToAPIHTTPTrap,
-		checkICMPPingAttr:   checkConfigToAPIICMPPing,
-		checkJMXAttr:        checkConfigToAPIJMX,
-		checkMemcachedAttr:  checkConfigToAPIMemcached,
-		checkJSONAttr:       checkConfigToAPIJSON,
-		checkMySQLAttr:      checkConfigToAPIMySQL,
-		checkNTPAttr:        checkConfigToAPINTP,
-		checkPostgreSQLAttr: checkConfigToAPIPostgreSQL,
-		checkPromTextAttr:   checkConfigToAPIPromText,
-		checkRedisAttr:      checkConfigToAPIRedis,
-		checkSMTPAttr:       checkConfigToAPISMTP,
-		checkSNMPAttr:       checkConfigToAPISNMP,
-		checkStatsdAttr:     checkConfigToAPIStatsd,
-		checkTCPAttr:        checkConfigToAPITCP,
+		checkCAQLAttr:         checkConfigToAPICAQL,
+		checkCloudWatchAttr:   checkConfigToAPICloudWatch,
+		checkConsulAttr:       checkConfigToAPIConsul,
+		checkDNSAttr:          checkConfigToAPIDNS,
+		checkExternalAttr:     checkConfigToAPIExternal,
+		checkHAProxyAttr:      checkConfigToAPIHAProxy,
+		checkHTTPAttr:         checkConfigToAPIHTTP,
+		checkHTTPSequenceAttr: checkConfigToAPIHTTPSequence,
+		checkHTTPTrapAttr:     checkConfigToAPIHTTPTrap,
+		checkHistogramAttr:    checkConfigToAPIHistogramIngest,
+		checkICMPPingAttr:     checkConfigToAPIICMPPing,
+		checkIMAPAttr:         checkConfigToAPIIMAP,
+		checkJMXAttr:          checkConfigToAPIJMX,
+		checkJolokiaAttr:      checkConfigToAPIJolokia,
+		checkMemcachedAttr:    checkConfigToAPIMemcached,
+		checkJSONAttr:         checkConfigToAPIJSON,
+		checkLDAPAttr:         checkConfigToAPILDAP,
+		checkMySQLAttr:        checkConfigToAPIMySQL,
+		checkNTPAttr:          checkConfigToAPINTP,
+		checkOTLPAttr:         checkConfigToAPIOTLP,
+		checkPOP3Attr:         checkConfigToAPIPOP3,
+		checkPostgreSQLAttr:   checkConfigToAPIPostgreSQL,
+		checkPromTextAttr:     checkConfigToAPIPromText,
+		checkRedisAttr:        checkConfigToAPIRedis,
+		checkResmonAttr:       checkConfigToAPIResmon,
+		checkSelfcheckAttr:    checkConfigToAPISelfcheck,
+		checkSMTPAttr:         checkConfigToAPISMTP,
+		checkSNMPAttr:         checkConfigToAPISNMP,
+		checkStatsdAttr:       checkConfigToAPIStatsd,
+		checkTCPAttr:          checkConfigToAPITCP,
 	}
 
 	for checkType, fn := range checkTypeParseMap {
@@ -806,17 +1416,27 @@ func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
 		apiCheckTypeConsulAttr:     checkAPIToStateConsul,
 		apiCheckTypeDNSAttr:        checkAPIToStateDNS,
 		apiCheckTypeExternalAttr:   checkAPIToStateExternal,
+		apiCheckTypeHAProxyAttr:    checkAPIToStateHAProxy,
 		apiCheckTypeHTTPAttr:       checkAPIToStateHTTP,
+		apiCheckTypeHTTPSeqAttr:    checkAPIToStateHTTPSequence,
 		apiCheckTypeHTTPTrapAttr:   checkAPIToStateHTTPTrap,
+		apiCheckTypeHistogramAttr:  checkAPIToStateHistogramIngest,
 		apiCheckTypeICMPPingAttr:   checkAPIToStateICMPPing,
+		apiCheckTypeIMAPAttr:       checkAPIToStateIMAP,
 		apiCheckTypeJMXAttr:        checkAPIToStateJMX,
+		apiCheckTypeJolokiaAttr:    checkAPIToStateJolokia,
 		apiCheckTypeMemcachedAttr:  checkAPIToStateMemcached,
 		apiCheckTypeJSONAttr:       checkAPIToStateJSON,
+		apiCheckTypeLDAPAttr:       checkAPIToStateLDAP,
 		apiCheckTypeMySQLAttr:      checkAPIToStateMySQL,
 		apiCheckTypeNTPAttr:        checkAPIToStateNTP,
+		apiCheckTypeOTLPAttr:       checkAPIToStateOTLP,
+		apiCheckTypePOP3Attr:       checkAPIToStatePOP3,
 		apiCheckTypePostgreSQLAttr: checkAPIToStatePostgreSQL,
 		apiCheckTypePromTextAttr:   checkAPIToStatePromText,
 		apiCheckTypeRedisAttr:      checkAPIToStateRedis,
+		apiCheckTypeResmonAttr:     checkAPIToStateResmon,
+		apiCheckTypeSelfcheckAttr:  checkAPIToStateSelfcheck,
 		apiCheckTypeSMTPAttr:       checkAPIToStateSMTP,
 		apiCheckTypeSNMPAttr:       checkAPIToStateSNMP,
 		apiCheckTypeStatsdAttr:     checkAPIToStateStatsd,
@@ -824,6 +1444,10 @@ func parseCheckTypeConfig(c *circonusCheck, d *schema.ResourceData) error {
 	}
 
 	var checkType apiCheckType = apiCheckType(c.Type)
+	if isJolokiaCheck(c) {
+		checkType = apiCheckTypeJolokiaAttr
+	}
+
 	fn, ok := checkTypeConfigHandlers[checkType]
 	if !ok {
 		return fmt.Errorf("check type %q not supported", c.Type)
@@ -2,9 +2,15 @@ package circonus
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -86,3 +92,198 @@ func checkConfigToAPICAQL(c *circonusCheck, l interfaceList) error { //nolint:un
 
 	return nil
 }
+
+// apiCAQLPath is the API endpoint that evaluates a CAQL query.  Queries it can
+// not parse are rejected with a 4xx response.
+const apiCAQLPath = "/caql"
+
+// checkCustomizeDiffCAQL verifies a new or changed caql.query against the API
+// during plan when the provider has been configured with validate_caql, so an
+// invalid query fails the plan rather than the apply, or worse, a check that
+// silently collects nothing.  With validate_references the metrics of other
+// checks the query references are verified to exist as well.
+func checkCustomizeDiffCAQL(d *schema.ResourceDiff, meta interface{}) error {
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt == nil || (!ctxt.validateCAQL && !ctxt.validateRefs) {
+		return nil
+	}
+
+	if !d.HasChange(checkCAQLAttr) || !d.NewValueKnown(checkCAQLAttr) {
+		return nil
+	}
+
+	s, ok := d.Get(checkCAQLAttr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return nil
+	}
+
+	query, _ := newInterfaceMap(s.List()[0])[string(checkCAQLQueryAttr)].(string)
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+
+	if ctxt.validateCAQL {
+		if err := validateCAQLQuery(ctxt, query); err != nil {
+			return err
+		}
+	}
+
+	if ctxt.validateRefs {
+		return validateCAQLReferences(ctxt, query)
+	}
+
+	return nil
+}
+
+// validateCAQLQuery evaluates query over the last few minutes, which is enough
+// for the API to parse it.  A query the API rejects is reported against the
+// caql.query attribute along with the API's explanation.
+func validateCAQLQuery(ctxt *providerContext, query string) error {
+	end := time.Now()
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("start", strconv.FormatInt(end.Add(-5*time.Minute).Unix(), 10))
+	q.Set("end", strconv.FormatInt(end.Unix(), 10))
+	q.Set("period", "60")
+
+	reqURL := url.URL{
+		Path:     apiCAQLPath,
+		RawQuery: q.Encode(),
+	}
+
+	_, err := ctxt.client.Get(reqURL.String())
+	if err == nil {
+		return nil
+	}
+
+	if msg, ok := caqlQueryError(err); ok {
+		return fmt.Errorf("%s.0.%s: invalid CAQL query: %s", checkCAQLAttr, checkCAQLQueryAttr, msg)
+	}
+
+	return fmt.Errorf("unable to validate %s.0.%s, set the provider's %s to false to plan without the API: %w", checkCAQLAttr, checkCAQLQueryAttr, providerValidateCAQLAttr, err)
+}
+
+// caqlQueryErrorRE matches the API client's error for a 4xx response.
+var caqlQueryErrorRE = regexp.MustCompile(`(?s)^API response code (4\d\d): (.*)$`)
+
+// caqlNotQueryErrors are the 4xx responses that do not reflect on the query:
+// authentication, a missing endpoint and rate limiting.
+var caqlNotQueryErrors = map[string]struct{}{
+	"401": {},
+	"403": {},
+	"404": {},
+	"429": {},
+}
+
+// caqlQueryError returns the explanation the API gave for rejecting a query.
+// ok is false when err is not a rejection of the query itself.
+func caqlQueryError(err error) (msg string, ok bool) {
+	m := caqlQueryErrorRE.FindStringSubmatch(strings.TrimSpace(err.Error()))
+	if m == nil {
+		return "", false
+	}
+	if _, found := caqlNotQueryErrors[m[1]]; found {
+		return "", false
+	}
+
+	var body struct {
+		Message     string `json:"message"`
+		Explanation string `json:"explanation"`
+	}
+	if err := json.Unmarshal([]byte(m[2]), &body); err != nil || (body.Message == "" && body.Explanation == "") {
+		return m[2], true
+	}
+
+	switch {
+	case body.Explanation == "":
+		return body.Message, true
+	case body.Message == "":
+		return body.Explanation, true
+	default:
+		return body.Message + ": " + body.Explanation, true
+	}
+}
+
+const (
+	// Metric search filters of the API.
+	caqlMetricCheckFilter     = "f__check"
+	caqlMetricCheckUUIDFilter = "f__check_uuid"
+	caqlMetricNameFilter      = "f__metric_name"
+)
+
+// caqlMetricRefRE matches the streams a CAQL query references by check and
+// metric name, e.g. metric:average("<check uuid>", "duration") or
+// histogram("<check id>", "latency").  The arguments of the search: functions
+// are patterns, not references, they are matched to be skipped.
+var caqlMetricRefRE = regexp.MustCompile(`\b(search:)?(?:metric|histogram)(?::[a-z_]+)?\(\s*"([^"]+)"\s*,\s*"([^"]+)"`)
+
+// caqlMetricRef is a stream referenced by a CAQL query.
+type caqlMetricRef struct {
+	check  string // check UUID or check ID
+	metric string
+}
+
+func (r caqlMetricRef) String() string {
+	return fmt.Sprintf("%s %q", r.check, r.metric)
+}
+
+// caqlMetricRefs returns the de-duplicated streams query references by check
+// and metric name.  Metric names with wildcards match any number of streams and
+// are skipped.
+func caqlMetricRefs(query string) []caqlMetricRef {
+	seen := make(map[caqlMetricRef]struct{})
+	refs := make([]caqlMetricRef, 0)
+	for _, m := range caqlMetricRefRE.FindAllStringSubmatch(query, -1) {
+		if m[1] != "" {
+			continue
+		}
+		ref := caqlMetricRef{check: strings.TrimPrefix(m[2], config.CheckPrefix+"/"), metric: m[3]}
+		if strings.ContainsAny(ref.metric, "*?") {
+			continue
+		}
+		if _, found := seen[ref]; found {
+			continue
+		}
+		seen[ref] = struct{}{}
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// validateCAQLReferences verifies that the streams query references by check
+// and metric name exist, so a composite does not silently evaluate to nothing
+// after the metrics it depends on were renamed or their check was removed.
+func validateCAQLReferences(ctxt *providerContext, query string) error {
+	unknown := make([]string, 0)
+	for _, ref := range caqlMetricRefs(query) {
+		filter := api.SearchFilterType{caqlMetricNameFilter: []string{ref.metric}}
+		if _, err := strconv.ParseUint(ref.check, 10, 64); err == nil {
+			filter[caqlMetricCheckFilter] = []string{config.CheckPrefix + "/" + ref.check}
+		} else {
+			filter[caqlMetricCheckUUIDFilter] = []string{ref.check}
+		}
+
+		metrics, err := ctxt.client.SearchMetrics(nil, &filter)
+		if err != nil {
+			return fmt.Errorf("unable to verify the metrics referenced by %s.0.%s, set the provider's %s to false to plan without the API: %w", checkCAQLAttr, checkCAQLQueryAttr, providerValidateReferencesAttr, err)
+		}
+
+		found := false
+		for _, m := range *metrics {
+			if m.MetricName == ref.metric && (m.CheckUUID == ref.check || m.CheckCID == config.CheckPrefix+"/"+ref.check) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, ref.String())
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%s.0.%s references metrics that do not exist in Circonus: %s", checkCAQLAttr, checkCAQLQueryAttr, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -2,8 +2,12 @@ package circonus
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -40,6 +44,135 @@ func TestAccCirconusCheckCAQL_basic(t *testing.T) {
 	})
 }
 
+func TestValidateCAQLQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != apiCAQLPath {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("start") == "" || q.Get("end") == "" || q.Get("period") == "" {
+			http.Error(w, `{"code":400,"message":"missing time range"}`, http.StatusBadRequest)
+			return
+		}
+		switch q.Get("query") {
+		case "search:metric:average(\"cpu\")":
+			_, _ = w.Write([]byte(`{"_data":[]}`))
+		case "denied":
+			http.Error(w, `{"code":403,"message":"forbidden"}`, http.StatusForbidden)
+		case "broken":
+			http.Error(w, "upstream failure", http.StatusInternalServerError)
+		default:
+			http.Error(w, `{"code":400,"message":"CAQL parse error","explanation":"unexpected token at 1:7"}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client, validateCAQL: true}
+
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{query: "search:metric:average(\"cpu\")"},
+		{query: "search:(", wantErr: "caql.0.query: invalid CAQL query: CAQL parse error: unexpected token at 1:7"},
+		{query: "denied", wantErr: "unable to validate caql.0.query"},
+		{query: "broken", wantErr: "unable to validate caql.0.query"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			err := validateCAQLQuery(ctxt, test.query)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.wantErr != "" && err == nil:
+				t.Errorf("expected an error")
+			case test.wantErr != "" && !strings.HasPrefix(err.Error(), test.wantErr):
+				t.Errorf("expected an error starting with %q, got %q", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCAQLReferences(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metric" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		switch {
+		case q.Get(caqlMetricCheckUUIDFilter) == "9a8b7c6d-1111-2222-3333-444455556666" && q.Get(caqlMetricNameFilter) == "duration":
+			_, _ = w.Write([]byte(`[{"_check_uuid":"9a8b7c6d-1111-2222-3333-444455556666","_check":"/check/42","_metric_name":"duration"}]`))
+		case q.Get(caqlMetricCheckFilter) == "/check/42" && q.Get(caqlMetricNameFilter) == "latency":
+			_, _ = w.Write([]byte(`[{"_check_uuid":"9a8b7c6d-1111-2222-3333-444455556666","_check":"/check/42","_metric_name":"latency"}]`))
+		case q.Get(caqlMetricNameFilter) == "broken":
+			http.Error(w, "upstream failure", http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client, validateRefs: true}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "existing",
+			query: `metric:average("9a8b7c6d-1111-2222-3333-444455556666", "duration") + histogram("/check/42", "latency") | histogram:percentile(99)`,
+		},
+		{
+			name:  "searches and wildcards",
+			query: `search:metric:average("cpu", "x") + metric:average("9a8b7c6d-1111-2222-3333-444455556666", "dur*")`,
+		},
+		{
+			name:    "renamed",
+			query:   `metric:average("9a8b7c6d-1111-2222-3333-444455556666", "duration_ms") + metric:average("42", "requests")`,
+			wantErr: `caql.0.query references metrics that do not exist in Circonus: 9a8b7c6d-1111-2222-3333-444455556666 "duration_ms", 42 "requests"`,
+		},
+		{
+			name:    "api error",
+			query:   `metric:average("42", "broken")`,
+			wantErr: "unable to verify the metrics referenced by caql.0.query",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateCAQLReferences(ctxt, test.query)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.wantErr != "" && err == nil:
+				t.Errorf("expected an error")
+			case test.wantErr != "" && !strings.HasPrefix(err.Error(), test.wantErr):
+				t.Errorf("expected an error starting with %q, got %q", test.wantErr, err)
+			}
+		})
+	}
+}
+
 const testAccCirconusCheckCAQLConfigFmt = `
 variable "test_tags" {
   type = list(string)
@@ -16,23 +16,44 @@ const (
 	// circonus_check.cloudwatch.* resource attribute names.
 	checkCloudWatchAPIKeyAttr      = "api_key"
 	checkCloudWatchAPISecretAttr   = "api_secret"
+	checkCloudWatchDimensionAttr   = "dimension"
 	checkCloudWatchDimmensionsAttr = "dimmensions"
+	checkCloudWatchExternalIDAttr  = "external_id"
 	checkCloudWatchMetricAttr      = "metric"
 	checkCloudWatchNamespaceAttr   = "namespace"
+	checkCloudWatchRoleARNAttr     = "role_arn"
 	checkCloudWatchURLAttr         = "url"
 	checkCloudWatchVersionAttr     = "version"
+
+	// circonus_check.cloudwatch.dimension.* resource attribute names.
+	checkCloudWatchDimensionNameAttr  = "name"
+	checkCloudWatchDimensionValueAttr = "value"
+)
+
+const (
+	// Config keys of the cloudwatch check type not known to the API client.
+	checkCloudWatchExternalIDKey config.Key = "external_id"
+	checkCloudWatchRoleARNKey    config.Key = "role_arn"
 )
 
 var checkCloudWatchDescriptions = attrDescrs{
-	checkCloudWatchAPIKeyAttr:      "The AWS API Key",
-	checkCloudWatchAPISecretAttr:   "The AWS API Secret",
+	checkCloudWatchAPIKeyAttr:      "The AWS API Key, conflicts with role_arn",
+	checkCloudWatchAPISecretAttr:   "The AWS API Secret, conflicts with role_arn",
+	checkCloudWatchDimensionAttr:   "A dimension to query for the metric, may be repeated",
 	checkCloudWatchDimmensionsAttr: "The dimensions to query for the metric",
+	checkCloudWatchExternalIDAttr:  "The external ID to present when assuming role_arn",
 	checkCloudWatchMetricAttr:      "One or more CloudWatch Metric attributes",
 	checkCloudWatchNamespaceAttr:   "The namespace to pull telemetry from",
+	checkCloudWatchRoleARNAttr:     "The ARN of the IAM role to assume through STS for credentials, conflicts with api_key and api_secret",
 	checkCloudWatchURLAttr:         "The URL including schema and hostname for the Cloudwatch monitoring server. This value will be used to specify the region - for example, to pull from us-east-1, the URL would be https://monitoring.us-east-1.amazonaws.com.",
 	checkCloudWatchVersionAttr:     "The version of the Cloudwatch API to use.",
 }
 
+var checkCloudWatchDimensionDescriptions = attrDescrs{
+	checkCloudWatchDimensionNameAttr:  "The name of the dimension",
+	checkCloudWatchDimensionValueAttr: "The value of the dimension",
+}
+
 var schemaCheckCloudWatch = &schema.Schema{
 	Type:     schema.TypeSet,
 	Optional: true,
@@ -43,24 +64,48 @@ var schemaCheckCloudWatch = &schema.Schema{
 		Schema: convertToHelperSchema(checkCloudWatchDescriptions, map[schemaAttr]*schema.Schema{
 			checkCloudWatchAPIKeyAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkCloudWatchAPIKeyAttr, `[\S]+`),
 				DefaultFunc:  schema.EnvDefaultFunc("AWS_ACCESS_KEY_ID", ""),
 			},
 			checkCloudWatchAPISecretAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkCloudWatchAPISecretAttr, `[\S]+`),
 				DefaultFunc:  schema.EnvDefaultFunc("AWS_SECRET_ACCESS_KEY", ""),
 			},
+			checkCloudWatchDimensionAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkCloudWatchDimensionDescriptions, map[schemaAttr]*schema.Schema{
+						checkCloudWatchDimensionNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkCloudWatchDimensionNameAttr, `^[\S]+$`),
+						},
+						checkCloudWatchDimensionValueAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkCloudWatchDimensionValueAttr, `^[\S]+$`),
+						},
+					}),
+				},
+			},
 			checkCloudWatchDimmensionsAttr: {
 				Type:         schema.TypeMap,
-				Required:     true,
+				Optional:     true,
 				Elem:         schema.TypeString,
 				ValidateFunc: validateCheckCloudWatchDimmensions,
 			},
+			checkCloudWatchExternalIDAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkCloudWatchExternalIDAttr, `^[\w+=,.@:/-]{2,}$`),
+			},
 			checkCloudWatchMetricAttr: {
 				Type:     schema.TypeSet,
 				Required: true,
@@ -76,6 +121,11 @@ var schemaCheckCloudWatch = &schema.Schema{
 				Required:     true,
 				ValidateFunc: validateRegexp(checkCloudWatchNamespaceAttr, `.+`),
 			},
+			checkCloudWatchRoleARNAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkCloudWatchRoleARNAttr, `^arn:aws[\w-]*:iam::\d{12}:role/.+$`),
+			},
 			checkCloudWatchURLAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -112,6 +162,21 @@ func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
 
 	saveStringConfigToState(config.APIKey, checkCloudWatchAPIKeyAttr)
 	saveStringConfigToState(config.APISecret, checkCloudWatchAPISecretAttr)
+	saveStringConfigToState(checkCloudWatchExternalIDKey, checkCloudWatchExternalIDAttr)
+	saveStringConfigToState(checkCloudWatchRoleARNKey, checkCloudWatchRoleARNAttr)
+
+	// Credentials obtained through STS leave the static keys out of the API
+	// config, keep whatever the environment defaulted them to so they do not
+	// show up as a diff.
+	if _, ok := cloudwatchConfig[string(checkCloudWatchRoleARNAttr)]; ok {
+		if prior := checkCloudWatchState(d); prior != nil {
+			for _, attrName := range []schemaAttr{checkCloudWatchAPIKeyAttr, checkCloudWatchAPISecretAttr} {
+				if _, ok := cloudwatchConfig[string(attrName)]; !ok {
+					cloudwatchConfig[string(attrName)] = prior[string(attrName)]
+				}
+			}
+		}
+	}
 
 	dimmensions := make(map[string]interface{}, len(c.Config))
 	dimmensionPrefixLen := len(config.DimPrefix)
@@ -126,7 +191,12 @@ func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
 		}
 		delete(swamp, k)
 	}
-	cloudwatchConfig[string(checkCloudWatchDimmensionsAttr)] = dimmensions
+	if dimensionOrder, ok := checkCloudWatchStateDimensionOrder(d); ok {
+		cloudwatchConfig[string(checkCloudWatchDimensionAttr)] = checkCloudWatchDimensionList(dimmensions, dimensionOrder)
+		cloudwatchConfig[string(checkCloudWatchDimmensionsAttr)] = map[string]interface{}{}
+	} else {
+		cloudwatchConfig[string(checkCloudWatchDimmensionsAttr)] = dimmensions
+	}
 
 	metricSet := schema.NewSet(schema.HashString, nil)
 	metricList := strings.Split(c.Config[config.CloudwatchMetrics], ",")
@@ -161,6 +231,76 @@ func checkAPIToStateCloudWatch(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
+// checkCloudWatchState returns the cloudwatch block of d, or nil when d has
+// none.
+func checkCloudWatchState(d *schema.ResourceData) interfaceMap {
+	s, ok := d.Get(checkCloudWatchAttr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return nil
+	}
+
+	return newInterfaceMap(s.List()[0])
+}
+
+// checkCloudWatchStateDimensionOrder returns the dimension names of the
+// dimension blocks of the cloudwatch check in d, in the order they were
+// declared.  ok is false when the dimensions are kept in the dimmensions map
+// instead.
+func checkCloudWatchStateDimensionOrder(d *schema.ResourceData) (names []string, ok bool) {
+	cloudwatchConfig := checkCloudWatchState(d)
+	if cloudwatchConfig == nil {
+		return nil, false
+	}
+
+	dimensionsRaw, found := cloudwatchConfig[checkCloudWatchDimensionAttr]
+	if !found || len(dimensionsRaw.([]interface{})) == 0 {
+		return nil, false
+	}
+
+	for _, dimensionRaw := range dimensionsRaw.([]interface{}) {
+		dimension := newInterfaceMap(dimensionRaw)
+		names = append(names, dimension[string(checkCloudWatchDimensionNameAttr)].(string))
+	}
+
+	return names, true
+}
+
+// checkCloudWatchDimensionList returns dimensions as a list of dimension
+// blocks.  Dimensions named in order come first and in that order, any
+// others follow sorted by name.
+func checkCloudWatchDimensionList(dimensions map[string]interface{}, order []string) []interface{} {
+	l := make([]interface{}, 0, len(dimensions))
+	seen := make(map[string]struct{}, len(dimensions))
+	add := func(name string) {
+		v, found := dimensions[name]
+		if _, dup := seen[name]; !found || dup {
+			return
+		}
+		seen[name] = struct{}{}
+		l = append(l, map[string]interface{}{
+			string(checkCloudWatchDimensionNameAttr):  name,
+			string(checkCloudWatchDimensionValueAttr): v,
+		})
+	}
+
+	for _, name := range order {
+		add(name)
+	}
+
+	rest := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		if _, ok := seen[name]; !ok {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		add(name)
+	}
+
+	return l
+}
+
 // hashCheckCloudWatch creates a stable hash of the normalized values.
 func hashCheckCloudWatch(v interface{}) int {
 	m := v.(map[string]interface{})
@@ -175,8 +315,12 @@ func hashCheckCloudWatch(v interface{}) int {
 
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
-	writeString(checkCloudWatchAPIKeyAttr)
-	writeString(checkCloudWatchAPISecretAttr)
+	// The static keys are not used, and may have been defaulted from the
+	// environment, when credentials are obtained through STS.
+	if v, ok := m[string(checkCloudWatchRoleARNAttr)]; !ok || v.(string) == "" {
+		writeString(checkCloudWatchAPIKeyAttr)
+		writeString(checkCloudWatchAPISecretAttr)
+	}
 
 	if dimmensionsRaw, ok := m[string(checkCloudWatchDimmensionsAttr)]; ok {
 		dimmensionMap := dimmensionsRaw.(map[string]interface{})
@@ -191,6 +335,13 @@ func hashCheckCloudWatch(v interface{}) int {
 		}
 	}
 
+	if dimensionsRaw, ok := m[string(checkCloudWatchDimensionAttr)]; ok {
+		for _, dimensionRaw := range dimensionsRaw.([]interface{}) {
+			dimension := newInterfaceMap(dimensionRaw)
+			fmt.Fprintf(b, "%s=%s", dimension[string(checkCloudWatchDimensionNameAttr)], dimension[string(checkCloudWatchDimensionValueAttr)])
+		}
+	}
+
 	if metricsRaw, ok := m[string(checkCloudWatchMetricAttr)]; ok {
 		metricListRaw := flattenSet(metricsRaw.(*schema.Set))
 		for i := range metricListRaw {
@@ -201,7 +352,9 @@ func hashCheckCloudWatch(v interface{}) int {
 		}
 	}
 
+	writeString(checkCloudWatchExternalIDAttr)
 	writeString(checkCloudWatchNamespaceAttr)
+	writeString(checkCloudWatchRoleARNAttr)
 	writeString(checkCloudWatchURLAttr)
 	writeString(checkCloudWatchVersionAttr)
 
@@ -209,7 +362,7 @@ func hashCheckCloudWatch(v interface{}) int {
 	return hashcode.String(s)
 }
 
-func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeCloudWatchAttr)
 
 	// Iterate over all `cloudwatch` attributes, even though we have a max of 1 in the
@@ -217,12 +370,8 @@ func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nol
 	for _, mapRaw := range l {
 		cloudwatchConfig := newInterfaceMap(mapRaw)
 
-		if v, found := cloudwatchConfig[checkCloudWatchAPIKeyAttr]; found {
-			c.Config[config.APIKey] = v.(string)
-		}
-
-		if v, found := cloudwatchConfig[checkCloudWatchAPISecretAttr]; found {
-			c.Config[config.APISecret] = v.(string)
+		if err := checkConfigToAPICloudWatchCredentials(c, cloudwatchConfig); err != nil {
+			return err
 		}
 
 		for k, v := range cloudwatchConfig.CollectMap(checkCloudWatchDimmensionsAttr) {
@@ -230,6 +379,18 @@ func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nol
 			c.Config[dimKey] = v
 		}
 
+		if v, found := cloudwatchConfig[checkCloudWatchDimensionAttr]; found {
+			for _, dimensionRaw := range v.([]interface{}) {
+				dimension := newInterfaceMap(dimensionRaw)
+				name := dimension[string(checkCloudWatchDimensionNameAttr)].(string)
+				dimKey := config.DimPrefix + config.Key(name)
+				if _, dup := c.Config[dimKey]; dup {
+					return fmt.Errorf("CloudWatch dimension %q is set more than once", name)
+				}
+				c.Config[dimKey] = dimension[string(checkCloudWatchDimensionValueAttr)].(string)
+			}
+		}
+
 		if v, found := cloudwatchConfig[checkCloudWatchMetricAttr]; found {
 			metricsRaw := v.(*schema.Set).List()
 			metrics := make([]string, 0, len(metricsRaw))
@@ -255,3 +416,69 @@ func checkConfigToAPICloudWatch(c *circonusCheck, l interfaceList) error { //nol
 
 	return nil
 }
+
+// checkConfigToAPICloudWatchCredentials sets the credentials the collector
+// uses to query CloudWatch: either a role it assumes through STS, or a static
+// API key and secret.  The static keys are ignored when a role is set since
+// they may have been defaulted from the environment; conflicts in the
+// configuration itself are caught by checkCustomizeDiffCloudWatchCredentials.
+func checkConfigToAPICloudWatchCredentials(c *circonusCheck, cloudwatchConfig interfaceMap) error {
+	getString := func(attrName schemaAttr) string {
+		if v, found := cloudwatchConfig[string(attrName)]; found && v != nil {
+			return v.(string)
+		}
+		return ""
+	}
+
+	if roleARN := getString(checkCloudWatchRoleARNAttr); roleARN != "" {
+		c.Config[checkCloudWatchRoleARNKey] = roleARN
+		if externalID := getString(checkCloudWatchExternalIDAttr); externalID != "" {
+			c.Config[checkCloudWatchExternalIDKey] = externalID
+		}
+		return nil
+	}
+
+	if getString(checkCloudWatchExternalIDAttr) != "" {
+		return fmt.Errorf("CloudWatch %s requires %s", checkCloudWatchExternalIDAttr, checkCloudWatchRoleARNAttr)
+	}
+
+	apiKey, apiSecret := getString(checkCloudWatchAPIKeyAttr), getString(checkCloudWatchAPISecretAttr)
+	if apiKey == "" || apiSecret == "" {
+		return fmt.Errorf("CloudWatch credentials require either %s or both %s and %s", checkCloudWatchRoleARNAttr, checkCloudWatchAPIKeyAttr, checkCloudWatchAPISecretAttr)
+	}
+
+	c.Config[config.APIKey] = apiKey
+	c.Config[config.APISecret] = apiSecret
+
+	return nil
+}
+
+// checkCustomizeDiffCloudWatchCredentials rejects a cloudwatch check that
+// sets both role_arn and a static api_key or api_secret.  The raw config is
+// used so keys defaulted from the environment do not count as set.
+func checkCustomizeDiffCloudWatchCredentials(d *schema.ResourceDiff) error {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+
+	cloudwatch := raw.GetAttr(checkCloudWatchAttr)
+	if cloudwatch.IsNull() || !cloudwatch.IsKnown() {
+		return nil
+	}
+
+	for it := cloudwatch.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || v.GetAttr(string(checkCloudWatchRoleARNAttr)).IsNull() {
+			continue
+		}
+
+		for _, attrName := range []schemaAttr{checkCloudWatchAPIKeyAttr, checkCloudWatchAPISecretAttr} {
+			if !v.GetAttr(string(attrName)).IsNull() {
+				return fmt.Errorf("CloudWatch %s conflicts with %s", attrName, checkCloudWatchRoleARNAttr)
+			}
+		}
+	}
+
+	return nil
+}
@@ -3,10 +3,13 @@ package circonus
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckCloudWatch_basic(t *testing.T) {
@@ -127,6 +130,146 @@ func TestAccCirconusCheckCloudWatch_basic(t *testing.T) {
 	})
 }
 
+func TestCheckCloudWatchDimensions(t *testing.T) {
+	dimension := func(name, value string) interface{} {
+		return map[string]interface{}{
+			string(checkCloudWatchDimensionNameAttr):  name,
+			string(checkCloudWatchDimensionValueAttr): value,
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkCloudWatchAttr): []interface{}{
+			map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):    "key",
+				string(checkCloudWatchAPISecretAttr): "secret",
+				string(checkCloudWatchDimensionAttr): []interface{}{
+					dimension("LoadBalancerName", "www"),
+					dimension("AvailabilityZone", "us-east-1a"),
+				},
+				string(checkCloudWatchMetricAttr):    []interface{}{"Latency", "RequestCount"},
+				string(checkCloudWatchNamespaceAttr): "AWS/ELB",
+				string(checkCloudWatchURLAttr):       "https://monitoring.us-east-1.amazonaws.com",
+			},
+		},
+	})
+
+	cloudwatchConfig := d.Get(string(checkCloudWatchAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPICloudWatch(&c, cloudwatchConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.DimPrefix + "LoadBalancerName": "www",
+		config.DimPrefix + "AvailabilityZone": "us-east-1a",
+		config.CloudwatchMetrics:              "Latency,RequestCount",
+		config.Namespace:                      "AWS/ELB",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	// A dimension added outside of Terraform follows the declared ones.
+	c.Config[config.DimPrefix+"Extra"] = "x"
+	if err := checkAPIToStateCloudWatch(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := d.Get(string(checkCloudWatchAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	expectedDimensions := []interface{}{
+		dimension("LoadBalancerName", "www"),
+		dimension("AvailabilityZone", "us-east-1a"),
+		dimension("Extra", "x"),
+	}
+	if !reflect.DeepEqual(m[string(checkCloudWatchDimensionAttr)], expectedDimensions) {
+		t.Errorf("expected dimensions %#v, got %#v", expectedDimensions, m[string(checkCloudWatchDimensionAttr)])
+	}
+	if n := len(m[string(checkCloudWatchDimmensionsAttr)].(map[string]interface{})); n != 0 {
+		t.Errorf("expected no %s, got %d", checkCloudWatchDimmensionsAttr, n)
+	}
+
+	// The same dimension in the map and in a block is rejected.
+	dup := newInterfaceMap(cloudwatchConfig[0])
+	dup[string(checkCloudWatchDimmensionsAttr)] = map[string]interface{}{"LoadBalancerName": "api"}
+	c = newCheck()
+	if err := checkConfigToAPICloudWatch(&c, interfaceList{map[string]interface{}(dup)}); err == nil {
+		t.Errorf("expected an error for a duplicate dimension")
+	}
+}
+
+func TestCheckCloudWatchCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[config.Key]string
+		wantErr  bool
+	}{
+		{
+			name: "static keys",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):    "key",
+				string(checkCloudWatchAPISecretAttr): "secret",
+			},
+			expected: map[config.Key]string{
+				config.APIKey:    "key",
+				config.APISecret: "secret",
+			},
+		},
+		{
+			name: "role with keys defaulted from the environment",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):     "key",
+				string(checkCloudWatchAPISecretAttr):  "secret",
+				string(checkCloudWatchRoleARNAttr):    "arn:aws:iam::123456789012:role/circonus",
+				string(checkCloudWatchExternalIDAttr): "circonus-ext",
+			},
+			expected: map[config.Key]string{
+				checkCloudWatchRoleARNKey:    "arn:aws:iam::123456789012:role/circonus",
+				checkCloudWatchExternalIDKey: "circonus-ext",
+			},
+		},
+		{
+			name: "missing secret",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr): "key",
+			},
+			wantErr: true,
+		},
+		{
+			name: "external id without a role",
+			config: map[string]interface{}{
+				string(checkCloudWatchAPIKeyAttr):     "key",
+				string(checkCloudWatchAPISecretAttr):  "secret",
+				string(checkCloudWatchExternalIDAttr): "circonus-ext",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newCheck()
+			err := checkConfigToAPICloudWatchCredentials(&c, newInterfaceMap(test.config))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(map[config.Key]string(c.Config), test.expected) {
+				t.Errorf("expected config %#v, got %#v", test.expected, c.Config)
+			}
+		})
+	}
+}
+
 const testAccCirconusCheckCloudWatchConfigFmt = `
 variable "cloudwatch_rds_tags" {
   type = list(string)
@@ -8,21 +8,32 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
 	// circonus_check.dns.* resource attribute names.
-	checkDNSCTypeAttr      = "ctype"
-	checkDNSNameserverAttr = "nameserver"
-	checkDNSQueryAttr      = "query"
-	checkDNSRTypeAttr      = "rtype"
+	checkDNSCTypeAttr          = "ctype"
+	checkDNSDNSSECAttr         = "dnssec"
+	checkDNSExpectedAnswerAttr = "expected_answer"
+	checkDNSNameserverAttr     = "nameserver"
+	checkDNSQueryAttr          = "query"
+	checkDNSRTypeAttr          = "rtype"
+)
+
+const (
+	// circonus_check.dns.* config keys not known to the API client.
+	apiDNSDNSSEC         config.Key = "dnssec"
+	apiDNSExpectedAnswer config.Key = "expected_answer"
 )
 
 var checkDNSDescriptions = attrDescrs{
-	checkDNSCTypeAttr:      "The DNS class of the query. IN: Internet, CH: Chaos, HS: Hesoid.",
-	checkDNSNameserverAttr: "The domain name server to query. If the name of the check is in-addr.arpa, the system default nameserver is used. Otherwise, the nameserver is the %[target] of the the check.",
-	checkDNSQueryAttr:      "The query to send. If the name of the check is in-addr.arpa, the reverse IP octet notation of in-addr.arpa syntax is synthesized by default. Otherwise the default query is the name of the check itself.",
-	checkDNSRTypeAttr:      "The DNS resource record type of the query. If the name of the check is in-addr.arpa, the default is PTR, otherwise it is A.",
+	checkDNSCTypeAttr:          "The DNS class of the query. IN: Internet, CH: Chaos, HS: Hesoid.",
+	checkDNSDNSSECAttr:         "Request DNSSEC records and fail the check when the answer does not validate.",
+	checkDNSExpectedAnswerAttr: "A regular expression the answer must match for the check to succeed.",
+	checkDNSNameserverAttr:     "The domain name server to query. If the name of the check is in-addr.arpa, the system default nameserver is used. Otherwise, the nameserver is the %[target] of the the check.",
+	checkDNSQueryAttr:          "The query to send. If the name of the check is in-addr.arpa, the reverse IP octet notation of in-addr.arpa syntax is synthesized by default. Otherwise the default query is the name of the check itself.",
+	checkDNSRTypeAttr:          "The DNS resource record type of the query. If the name of the check is in-addr.arpa, the default is PTR, otherwise it is A.",
 }
 
 var schemaCheckDNS = &schema.Schema{
@@ -39,6 +50,16 @@ var schemaCheckDNS = &schema.Schema{
 				Default:      "IN",
 				ValidateFunc: validateStringIn(checkDNSCTypeAttr, validStringValues{"IN", "CH", "HS"}),
 			},
+			checkDNSDNSSECAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkDNSExpectedAnswerAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
 			checkDNSNameserverAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -83,6 +104,12 @@ func checkAPIToStateDNS(c *circonusCheck, d *schema.ResourceData) error {
 		dnsConfig[string(checkDNSCTypeAttr)] = ctype
 	}
 
+	dnsConfig[string(checkDNSDNSSECAttr)] = c.Config[apiDNSDNSSEC] == "true"
+
+	if expected, ok := c.Config[apiDNSExpectedAnswer]; ok {
+		dnsConfig[string(checkDNSExpectedAnswerAttr)] = expected
+	}
+
 	if ns, ok := c.Config[config.Nameserver]; ok {
 		dnsConfig[string(checkDNSNameserverAttr)] = ns
 	}
@@ -108,6 +135,12 @@ func hashCheckDNS(v interface{}) int {
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
 	writeString := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
 			fmt.Fprint(b, strings.TrimSpace(v.(string)))
@@ -115,6 +148,8 @@ func hashCheckDNS(v interface{}) int {
 	}
 
 	writeString(checkDNSCTypeAttr)
+	writeBool(checkDNSDNSSECAttr)
+	writeString(checkDNSExpectedAnswerAttr)
 	writeString(checkDNSNameserverAttr)
 	writeString(checkDNSQueryAttr)
 	writeString(checkDNSRTypeAttr)
@@ -133,6 +168,14 @@ func checkConfigToAPIDNS(c *circonusCheck, l interfaceList) error { //nolint:unp
 		c.Config[config.CType] = v.(string)
 	}
 
+	if v, found := dnsConfig[checkDNSDNSSECAttr]; found && v.(bool) {
+		c.Config[apiDNSDNSSEC] = "true"
+	}
+
+	if v, found := dnsConfig[checkDNSExpectedAnswerAttr]; found && v.(string) != "" {
+		c.Config[apiDNSExpectedAnswer] = v.(string)
+	}
+
 	if v, found := dnsConfig[checkDNSNameserverAttr]; found && v.(string) != "" {
 		c.Config[config.Nameserver] = v.(string)
 	}
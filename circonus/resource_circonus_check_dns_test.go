@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckDNS_basic(t *testing.T) {
@@ -43,6 +44,47 @@ func TestAccCirconusCheckDNS_basic(t *testing.T) {
 	})
 }
 
+func TestCheckDNSConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkDNSAttr): []interface{}{
+			map[string]interface{}{
+				string(checkDNSDNSSECAttr):         true,
+				string(checkDNSExpectedAnswerAttr): `^10\.0\.`,
+				string(checkDNSNameserverAttr):     "8.8.8.8",
+				string(checkDNSQueryAttr):          "example.com",
+			},
+		},
+	})
+
+	c := newCheck()
+	if err := checkConfigToAPIDNS(&c, d.Get(checkDNSAttr).(*schema.Set).List()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.CType:         "IN",
+		apiDNSDNSSEC:         "true",
+		apiDNSExpectedAnswer: `^10\.0\.`,
+		config.Nameserver:    "8.8.8.8",
+		config.Query:         "example.com",
+		config.RType:         "A",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := parseCheckTypeConfig(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dns := d.Get(checkDNSAttr).(*schema.Set).List()[0].(map[string]interface{})
+	if dns[string(checkDNSDNSSECAttr)] != true || dns[string(checkDNSExpectedAnswerAttr)] != `^10\.0\.` {
+		t.Errorf("unexpected state %#v", dns)
+	}
+}
+
 const testAccCirconusCheckDNSConfigFmt = `
 variable "test_tags" {
   type = list(string)
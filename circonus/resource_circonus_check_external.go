@@ -2,6 +2,7 @@ package circonus
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/circonus-labs/go-apiclient/config"
@@ -25,9 +26,21 @@ const (
 	checkExternalEnvAttr   = "env"
 )
 
+const (
+	// output_extract modes of the external check, any other value is a
+	// regular expression matched against the output of the command.
+	checkExternalOutputExtractJSON   = "JSON"
+	checkExternalOutputExtractNagios = "NAGIOS"
+)
+
+var validCheckExternalOutputExtractModes = validStringValues{
+	checkExternalOutputExtractJSON,
+	checkExternalOutputExtractNagios,
+}
+
 var checkExternalDescriptions = attrDescrs{
 	checkCommandAttr:       "The full path to the command to run",
-	checkOutputExtractAttr: "The output extraction method: JSON or NAGIOS, otherwise treated as regexp",
+	checkOutputExtractAttr: "The output extraction method: json or nagios, otherwise treated as regexp",
 	checkArg1Attr:          "The 1st argument to the command",
 	checkArg2Attr:          "The 2nd argument to the command",
 	checkArg3Attr:          "The 3rd argument to the command",
@@ -48,10 +61,9 @@ var schemaCheckExternal = &schema.Schema{
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkExternalDescriptions, map[schemaAttr]*schema.Schema{
 			checkOutputExtractAttr: {
-				Type:     schema.TypeString,
-				Required: true,
-				// anything other than JSON or NAGIOS treated as regexp
-				// ValidateFunc: validateRegexp(checkOutputExtractAttr, `^(?:JSON|NAGIOS)$`),
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressExternalOutputExtractCase,
 			},
 			checkCommandAttr: {
 				Type:     schema.TypeString,
@@ -98,9 +110,10 @@ var schemaCheckExternal = &schema.Schema{
 				Optional: true,
 			},
 			checkExternalEnvAttr: {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem:     schema.TypeString,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         schema.TypeString,
+				ValidateFunc: validateExternalEnv,
 			},
 		}),
 	},
@@ -191,7 +204,7 @@ func checkConfigToAPIExternal(c *circonusCheck, l interfaceList) error { //nolin
 		}
 
 		if v, found := externalConfig[checkOutputExtractAttr]; found {
-			c.Config["output_extract"] = v.(string)
+			c.Config["output_extract"] = normalizeExternalOutputExtract(v.(string))
 		}
 
 		if v, found := externalConfig[checkArg1Attr]; found {
@@ -242,3 +255,37 @@ func checkConfigToAPIExternal(c *circonusCheck, l interfaceList) error { //nolin
 
 	return nil
 }
+
+// normalizeExternalOutputExtract returns the output_extract mode named by v,
+// in any case, the way the broker's external module expects it.  Regular
+// expressions are returned as they are.
+func normalizeExternalOutputExtract(v string) string {
+	for _, mode := range validCheckExternalOutputExtractModes {
+		if strings.EqualFold(v, string(mode)) {
+			return string(mode)
+		}
+	}
+
+	return v
+}
+
+// suppressExternalOutputExtractCase ignores changes to the case of an
+// output_extract mode, e.g. json and JSON.
+func suppressExternalOutputExtractCase(k, old, update string, d *schema.ResourceData) bool {
+	return normalizeExternalOutputExtract(old) == normalizeExternalOutputExtract(update)
+}
+
+// checkExternalEnvNameRE matches the names of environment variables.
+var checkExternalEnvNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateExternalEnv rejects environment variables whose name could not be
+// set by the broker.
+func validateExternalEnv(v interface{}, key string) (warnings []string, errors []error) {
+	for name := range v.(map[string]interface{}) {
+		if !checkExternalEnvNameRE.MatchString(name) {
+			errors = append(errors, fmt.Errorf("Invalid %s name specified (%q): must match %s", checkExternalEnvAttr, name, checkExternalEnvNameRE))
+		}
+	}
+
+	return warnings, errors
+}
@@ -0,0 +1,59 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckExternalConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkExternalAttr): []interface{}{
+			map[string]interface{}{
+				string(checkCommandAttr):       "/opt/checks/queue_depth.py",
+				string(checkOutputExtractAttr): "json",
+				string(checkArg1Attr):          "--queue=orders",
+				string(checkExternalEnvAttr): map[string]interface{}{
+					"QUEUE_HOST": "mq.example.org",
+					"PYTHONPATH": "/opt/checks/lib",
+				},
+			},
+		},
+	})
+
+	c := newCheck()
+	if err := checkConfigToAPIExternal(&c, d.Get(string(checkExternalAttr)).([]interface{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		"command":        "/opt/checks/queue_depth.py",
+		"output_extract": checkExternalOutputExtractJSON,
+		"arg1":           "--queue=orders",
+		"env_QUEUE_HOST": "mq.example.org",
+		"env_PYTHONPATH": "/opt/checks/lib",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStateExternal(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := d.Get("external.0.env.QUEUE_HOST").(string); v != "mq.example.org" {
+		t.Errorf("expected the env to read back, got %q", v)
+	}
+	if v := d.Get("external.0.output_extract").(string); !suppressExternalOutputExtractCase("", v, "json", d) {
+		t.Errorf("expected %q to be equivalent to %q", v, "json")
+	}
+}
+
+func TestValidateExternalEnv(t *testing.T) {
+	_, errs := validateExternalEnv(map[string]interface{}{"GOOD_NAME": "1", "_ALSO_GOOD": "2", "1BAD": "3", "ALSO-BAD": "4"}, string(checkExternalEnvAttr))
+	if len(errs) != 2 {
+		t.Errorf("expected 2 invalid env names, got %v", errs)
+	}
+}
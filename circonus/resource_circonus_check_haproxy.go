@@ -0,0 +1,151 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.haproxy.* resource attribute names.
+	checkHAProxyAuthPasswordAttr = "auth_password"
+	checkHAProxyAuthUserAttr     = "auth_user"
+	checkHAProxySelectAttr       = "select"
+	checkHAProxyURLAttr          = "url"
+)
+
+var checkHAProxyDescriptions = attrDescrs{
+	checkHAProxyAuthPasswordAttr: "The password used to authenticate against the HAProxy stats page",
+	checkHAProxyAuthUserAttr:     "The user used to authenticate against the HAProxy stats page",
+	checkHAProxySelectAttr:       "A regular expression matched against `<proxy>,<server>` to select the frontends and backends to collect",
+	checkHAProxyURLAttr:          "The URL of the HAProxy CSV stats page",
+}
+
+var schemaCheckHAProxy = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckHAProxy,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkHAProxyDescriptions, map[schemaAttr]*schema.Schema{
+			checkHAProxyAuthPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkHAProxyAuthPasswordAttr, `^.*`),
+			},
+			checkHAProxyAuthUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkHAProxyAuthUserAttr, `[^:]+`),
+			},
+			checkHAProxySelectAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkHAProxySelectAttr, `.+`),
+			},
+			checkHAProxyURLAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHTTPURL(checkHAProxyURLAttr, urlIsAbs),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateHAProxy reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateHAProxy(c *circonusCheck, d *schema.ResourceData) error {
+	haproxyConfig := make(map[string]interface{}, len(c.Config))
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			haproxyConfig[string(attrName)] = s
+		}
+	}
+
+	saveStringConfigToState(config.AuthPassword, checkHAProxyAuthPasswordAttr)
+	saveStringConfigToState(config.AuthUser, checkHAProxyAuthUserAttr)
+	saveStringConfigToState(config.Select, checkHAProxySelectAttr)
+	saveStringConfigToState(config.URL, checkHAProxyURLAttr)
+
+	if err := d.Set(checkHAProxyAttr, schema.NewSet(hashCheckHAProxy, []interface{}{haproxyConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkHAProxyAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckHAProxy creates a stable hash of the normalized values.
+func hashCheckHAProxy(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkHAProxyAuthPasswordAttr)
+	writeString(checkHAProxyAuthUserAttr)
+	writeString(checkHAProxySelectAttr)
+	writeString(checkHAProxyURLAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIHAProxy(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeHAProxy)
+
+	// Iterate over all `haproxy` attributes, even though we have a max of 1 in
+	// the schema.
+	for _, mapRaw := range l {
+		haproxyConfig := newInterfaceMap(mapRaw)
+
+		if v, found := haproxyConfig[checkHAProxyAuthPasswordAttr]; found && v.(string) != "" {
+			c.Config[config.AuthPassword] = v.(string)
+		}
+
+		if v, found := haproxyConfig[checkHAProxyAuthUserAttr]; found && v.(string) != "" {
+			c.Config[config.AuthUser] = v.(string)
+		}
+
+		if v, found := haproxyConfig[checkHAProxySelectAttr]; found && v.(string) != "" {
+			c.Config[config.Select] = v.(string)
+		}
+
+		if v, found := haproxyConfig[checkHAProxyURLAttr]; found {
+			c.Config[config.URL] = v.(string)
+
+			// The haproxy module also consumes the host, port and use_ssl
+			// settings, derive them from the stats URL.
+			u, err := url.Parse(v.(string))
+			if err != nil {
+				return fmt.Errorf("unable to parse %s %q: %w", checkHAProxyURLAttr, v.(string), err)
+			}
+
+			c.Config[config.Host] = u.Hostname()
+			c.Config[config.UseSSL] = fmt.Sprintf("%t", u.Scheme == "https")
+			if port := u.Port(); port != "" {
+				c.Config[config.Port] = port
+			}
+
+			if len(c.Target) == 0 {
+				c.Target = u.Hostname()
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckHAProxy_basic(t *testing.T) {
+	checkName := fmt.Sprintf("HAProxy check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHAProxyConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.lb", "active", "true"),
+					resource.TestCheckNoResourceAttr("circonus_check.lb", "check_id"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.lb", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.lb", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "haproxy.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "haproxy.0.auth_user", "stats"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "haproxy.0.select", "^(?:www|api),"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "haproxy.0.url", "http://lb1.example.org:8080/haproxy?stats;csv"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.lb", "period", "60s"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "target", "lb1.example.org"),
+					resource.TestCheckResourceAttr("circonus_check.lb", "type", "haproxy"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckHAProxyConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "lb" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  haproxy {
+    url = "http://lb1.example.org:8080/haproxy?stats;csv"
+    auth_user = "stats"
+    auth_password = "secret"
+    select = "^(?:www|api),"
+  }
+
+  metric {
+    name = "www` + "`" + `FRONTEND` + "`" + `scur"
+    type = "numeric"
+  }
+
+  metric {
+    name = "api` + "`" + `BACKEND` + "`" + `qcur"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+  target = "lb1.example.org"
+}
+`
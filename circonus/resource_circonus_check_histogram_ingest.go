@@ -0,0 +1,106 @@
+package circonus
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.histogram_ingest.* resource attribute names.
+	checkHistogramIngestSecretAttr        = "secret"
+	checkHistogramIngestSubmissionURLAttr = "submission_url"
+)
+
+var checkHistogramIngestDescriptions = attrDescrs{
+	checkHistogramIngestSecretAttr:        "The secret histogram submitters authenticate with, generated by Circonus when omitted",
+	checkHistogramIngestSubmissionURLAttr: "The URL histograms are submitted to",
+}
+
+// The histogram_ingest block is a list rather than a set so its computed
+// attributes are kept in the statefile alongside the configured ones.
+var schemaCheckHistogramIngest = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkHistogramIngestDescriptions, map[schemaAttr]*schema.Schema{
+			checkHistogramIngestSecretAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkHistogramIngestSecretAttr, `^[a-zA-Z0-9_]+$`),
+			},
+			checkHistogramIngestSubmissionURLAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		}),
+	},
+}
+
+// checkAPIToStateHistogramIngest reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStateHistogramIngest(c *circonusCheck, d *schema.ResourceData) error {
+	histogramConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok {
+			histogramConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.Secret, checkHistogramIngestSecretAttr)
+	saveStringConfigToState(config.SubmissionURL, checkHistogramIngestSubmissionURLAttr)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkHistogramAttr, []interface{}{histogramConfig}); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkHistogramAttr, err)
+	}
+
+	return nil
+}
+
+func checkConfigToAPIHistogramIngest(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeHistogram)
+
+	// Iterate over all `histogram_ingest` attributes, even though we have a max
+	// of 1 in the schema.
+	for _, mapRaw := range l {
+		histogramConfig, ok := mapRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if v, found := histogramConfig[string(checkHistogramIngestSecretAttr)]; found && v.(string) != "" {
+			c.Config[config.Secret] = v.(string)
+		}
+	}
+
+	return nil
+}
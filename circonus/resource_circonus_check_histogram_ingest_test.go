@@ -0,0 +1,104 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccCirconusCheckHistogramIngest_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Histogram ingest check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHistogramIngestConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.histogram", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.histogram", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "collector.0.id", "/broker/2110"),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "histogram_ingest.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.histogram", "histogram_ingest.0.secret", regexp.MustCompile(`^[a-zA-Z0-9_]+$`)),
+					resource.TestMatchResourceAttr("circonus_check.histogram", "histogram_ingest.0.submission_url", regexp.MustCompile(`^https?://`)),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "target", "histogram-submitters"),
+					resource.TestCheckResourceAttr("circonus_check.histogram", "type", "histogram_ingest"),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckHistogramIngestConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkHistogramAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHistogramIngestSecretAttr): "s3cr3t",
+			},
+		},
+	})
+
+	c := newCheck()
+	if err := checkConfigToAPIHistogramIngest(&c, d.Get(string(checkHistogramAttr)).([]interface{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.Type != string(apiCheckTypeHistogram) || c.Config[config.Secret] != "s3cr3t" {
+		t.Errorf("unexpected type %q or secret %q", c.Type, c.Config[config.Secret])
+	}
+
+	// The API fills in the submission URL and reverse secret on create.
+	c.Config[config.SubmissionURL] = "https://api.circonus.com/module/histogram_ingest/abc/s3cr3t"
+	c.Config[config.ReverseSecretKey] = "reverse"
+
+	if err := parseCheckTypeConfig(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"histogram_ingest.0.secret":         "s3cr3t",
+		"histogram_ingest.0.submission_url": "https://api.circonus.com/module/histogram_ingest/abc/s3cr3t",
+	}
+	for k, v := range expected {
+		if got := d.Get(k).(string); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+}
+
+const testAccCirconusCheckHistogramIngestConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "histogram" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/2110"
+  }
+
+  histogram_ingest {}
+
+  metric_filter {
+    type = "allow"
+    regex = ".*"
+    comment = "Allow all metrics"
+  }
+
+  target = "histogram-submitters"
+  tags = "${var.test_tags}"
+}
+`
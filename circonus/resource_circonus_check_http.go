@@ -33,8 +33,35 @@ const (
 	checkHTTPURLAttr          = "url"
 	checkHTTPVersionAttr      = "version"
 	checkHTTPRedirectsAttr    = "redirects"
+
+	// circonus_check.http.tls_config.* resource attribute names only
+	// supported by the http check.
+	checkHTTPTLSExpectedNamesAttr = "expected_names"
+	checkHTTPTLSMaxVersionAttr    = "max_version"
+	checkHTTPTLSMinVersionAttr    = "min_version"
+	checkHTTPTLSServerNameAttr    = "server_name"
+)
+
+const (
+	// circonus_check.http.tls_config.* config keys not known to the API
+	// client.
+	apiHTTPTLSExpectedNames config.Key = "tls_expected_names"
+	apiHTTPTLSMaxVersion    config.Key = "tls_max_version"
+	apiHTTPTLSMinVersion    config.Key = "tls_min_version"
+	apiHTTPTLSServerName    config.Key = "tls_sni"
 )
 
+// checkHTTPTLSVersions are the TLS protocol versions accepted by min_version
+// and max_version, oldest first.
+var checkHTTPTLSVersions = validStringValues{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}
+
+var checkHTTPTLSDescriptions = attrDescrs{
+	checkHTTPTLSExpectedNamesAttr: "Names the certificate of the server must be valid for, through its CN or a SAN",
+	checkHTTPTLSMaxVersionAttr:    "The newest TLS protocol version offered to the server",
+	checkHTTPTLSMinVersionAttr:    "The oldest TLS protocol version accepted from the server",
+	checkHTTPTLSServerNameAttr:    "The server name sent with SNI, overriding the host of the URL",
+}
+
 var checkHTTPDescriptions = attrDescrs{
 	checkHTTPAuthMethodAttr:   "The HTTP Authentication method",
 	checkHTTPAuthPasswordAttr: "The HTTP Authentication user password",
@@ -53,6 +80,7 @@ var checkHTTPDescriptions = attrDescrs{
 	checkHTTPURLAttr:          "The URL to use as the target of the check",
 	checkHTTPVersionAttr:      "Sets the HTTP version for the check to use",
 	checkHTTPRedirectsAttr:    "The maximum number of Location header redirects to follow.",
+	checkTLSConfigAttr:        checkTLSConfigDescription,
 }
 
 var schemaCheckHTTP = &schema.Schema{
@@ -87,16 +115,19 @@ var schemaCheckHTTP = &schema.Schema{
 			checkHTTPCAChainAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkHTTPCAChainAttr, `.+`),
 			},
 			checkHTTPCertFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkHTTPCertFileAttr, `.+`),
 			},
 			checkHTTPCiphersAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkHTTPCiphersAttr, `.+`),
 			},
 			checkHTTPCodeRegexpAttr: {
@@ -119,6 +150,7 @@ var schemaCheckHTTP = &schema.Schema{
 			checkHTTPKeyFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkHTTPKeyFileAttr, `.+`),
 			},
 			checkHTTPMethodAttr: {
@@ -158,6 +190,31 @@ var schemaCheckHTTP = &schema.Schema{
 				Default:      defaultCheckHTTPRedirects,
 				ValidateFunc: validateRegexp(checkHTTPRedirectsAttr, `^[0-9]+$`),
 			},
+			checkTLSConfigAttr: newSchemaCheckTLS(checkHTTPTLSDescriptions, map[schemaAttr]*schema.Schema{
+				checkHTTPTLSExpectedNamesAttr: {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validateRegexp(checkHTTPTLSExpectedNamesAttr, `^[^,\s]+$`),
+					},
+				},
+				checkHTTPTLSMaxVersionAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringIn(checkHTTPTLSMaxVersionAttr, checkHTTPTLSVersions),
+				},
+				checkHTTPTLSMinVersionAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringIn(checkHTTPTLSMinVersionAttr, checkHTTPTLSVersions),
+				},
+				checkHTTPTLSServerNameAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkHTTPTLSServerNameAttr, `^[^\s:/]+$`),
+				},
+			}),
 		}),
 	},
 }
@@ -199,9 +256,14 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.AuthPassword, checkHTTPAuthPasswordAttr)
 	saveStringConfigToState(config.AuthUser, checkHTTPAuthUserAttr)
 	saveStringConfigToState(config.Body, checkHTTPBodyRegexpAttr)
-	saveStringConfigToState(config.CAChain, checkHTTPCAChainAttr)
-	saveStringConfigToState(config.CertFile, checkHTTPCertFileAttr)
-	saveStringConfigToState(config.Ciphers, checkHTTPCiphersAttr)
+	if checkTLSLegacyInUse(d, checkHTTPAttr) {
+		saveStringConfigToState(config.CAChain, checkHTTPCAChainAttr)
+		saveStringConfigToState(config.CertFile, checkHTTPCertFileAttr)
+		saveStringConfigToState(config.Ciphers, checkHTTPCiphersAttr)
+		saveStringConfigToState(config.KeyFile, checkHTTPKeyFileAttr)
+	} else {
+		httpConfig[string(checkTLSConfigAttr)] = checkHTTPTLSAPIToState(c, checkTLSAPIToState(c, swamp), swamp)
+	}
 	saveStringConfigToState(config.Code, checkHTTPCodeRegexpAttr)
 	saveStringConfigToState(config.Extract, checkHTTPExtractAttr)
 
@@ -220,7 +282,6 @@ func checkAPIToStateHTTP(c *circonusCheck, d *schema.ResourceData) error {
 	}
 	httpConfig[string(checkHTTPHeadersAttr)] = headers
 
-	saveStringConfigToState(config.KeyFile, checkHTTPKeyFileAttr)
 	saveStringConfigToState(config.Method, checkHTTPMethodAttr)
 	saveStringConfigToState(config.Payload, checkHTTPPayloadAttr)
 	saveIntConfigToState(config.ReadLimit, checkHTTPReadLimitAttr)
@@ -301,6 +362,20 @@ func hashCheckHTTP(v interface{}) int {
 	writeString(checkHTTPURLAttr)
 	writeString(checkHTTPVersionAttr)
 	writeString(checkHTTPRedirectsAttr)
+	writeCheckTLSHash(b, m)
+	for _, tlsConfig := range checkTLSConfigList(m) {
+		if l, ok := tlsConfig[string(checkHTTPTLSExpectedNamesAttr)].([]interface{}); ok {
+			for _, name := range interfaceList(l).List() {
+				fmt.Fprint(b, name)
+			}
+		}
+
+		for _, attrName := range []schemaAttr{checkHTTPTLSMaxVersionAttr, checkHTTPTLSMinVersionAttr, checkHTTPTLSServerNameAttr} {
+			if v, ok := tlsConfig[string(attrName)].(string); ok && v != "" {
+				fmt.Fprint(b, attrName, strings.TrimSpace(v))
+			}
+		}
+	}
 
 	s := b.String()
 	return hashcode.String(s)
@@ -324,6 +399,9 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 	}
 
 	httpConfig := newInterfaceMap(l[0])
+	if err := checkTLSConflict(checkHTTPAttr, httpConfig); err != nil {
+		return err
+	}
 	// for _, mapRaw := range l {
 	// 	httpConfig := newInterfaceMap(mapRaw)
 
@@ -372,6 +450,11 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 		c.Config[config.KeyFile] = v.(string)
 	}
 
+	checkTLSConfigToAPI(c, httpConfig)
+	if err := checkHTTPTLSConfigToAPI(c, httpConfig); err != nil {
+		return err
+	}
+
 	if v, found := httpConfig[checkHTTPMethodAttr]; found {
 		c.Config[config.Method] = v.(string)
 	}
@@ -409,3 +492,76 @@ func checkConfigToAPIHTTP(c *circonusCheck, l interfaceList) error {
 
 	return nil
 }
+
+// checkHTTPTLSAPIToState adds the http only TLS settings found in the check's
+// config to tlsState, the tls_config state of the shared settings, removing
+// them from swamp.
+func checkHTTPTLSAPIToState(c *circonusCheck, tlsState []interface{}, swamp map[config.Key]string) []interface{} {
+	tlsConfig := make(map[string]interface{})
+	if len(tlsState) > 0 {
+		tlsConfig = tlsState[0].(map[string]interface{})
+	}
+
+	for attrName, apiKey := range map[schemaAttr]config.Key{
+		checkHTTPTLSMaxVersionAttr: apiHTTPTLSMaxVersion,
+		checkHTTPTLSMinVersionAttr: apiHTTPTLSMinVersion,
+		checkHTTPTLSServerNameAttr: apiHTTPTLSServerName,
+	} {
+		if v, ok := c.Config[apiKey]; ok && v != "" {
+			tlsConfig[string(attrName)] = v
+		}
+		delete(swamp, apiKey)
+	}
+
+	if v, ok := c.Config[apiHTTPTLSExpectedNames]; ok && v != "" {
+		tlsConfig[string(checkHTTPTLSExpectedNamesAttr)] = strings.Split(v, ",")
+	}
+	delete(swamp, apiHTTPTLSExpectedNames)
+
+	if len(tlsConfig) == 0 {
+		return []interface{}{}
+	}
+
+	return []interface{}{tlsConfig}
+}
+
+// checkHTTPTLSConfigToAPI copies the http only settings of the tls_config
+// block into the check's config.
+func checkHTTPTLSConfigToAPI(c *circonusCheck, httpConfig interfaceMap) error {
+	for _, tlsConfig := range checkTLSConfigList(httpConfig) {
+		minVersion, _ := tlsConfig[string(checkHTTPTLSMinVersionAttr)].(string)
+		maxVersion, _ := tlsConfig[string(checkHTTPTLSMaxVersionAttr)].(string)
+		if minVersion != "" && maxVersion != "" && tlsVersionIndex(minVersion) > tlsVersionIndex(maxVersion) {
+			return fmt.Errorf("%s.%s: %s %s is newer than %s %s", checkHTTPAttr, checkTLSConfigAttr, checkHTTPTLSMinVersionAttr, minVersion, checkHTTPTLSMaxVersionAttr, maxVersion)
+		}
+
+		if minVersion != "" {
+			c.Config[apiHTTPTLSMinVersion] = minVersion
+		}
+
+		if maxVersion != "" {
+			c.Config[apiHTTPTLSMaxVersion] = maxVersion
+		}
+
+		if v, ok := tlsConfig[string(checkHTTPTLSServerNameAttr)].(string); ok && v != "" {
+			c.Config[apiHTTPTLSServerName] = v
+		}
+
+		if l, ok := tlsConfig[string(checkHTTPTLSExpectedNamesAttr)].([]interface{}); ok && len(l) > 0 {
+			c.Config[apiHTTPTLSExpectedNames] = strings.Join(interfaceList(l).List(), ",")
+		}
+	}
+
+	return nil
+}
+
+// tlsVersionIndex returns the position of version in checkHTTPTLSVersions.
+func tlsVersionIndex(version string) int {
+	for i, v := range checkHTTPTLSVersions {
+		if string(v) == version {
+			return i
+		}
+	}
+
+	return -1
+}
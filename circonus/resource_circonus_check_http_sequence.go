@@ -0,0 +1,312 @@
+package circonus
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.http_sequence.* resource attribute names.
+	checkHTTPSequenceStepAttr = "step"
+
+	// circonus_check.http_sequence.step.* resource attribute names.
+	checkHTTPSequenceStepCodeAttr    = "code"
+	checkHTTPSequenceStepExtractAttr = "extract"
+	checkHTTPSequenceStepHeadersAttr = "headers"
+	checkHTTPSequenceStepMethodAttr  = "method"
+	checkHTTPSequenceStepNameAttr    = "name"
+	checkHTTPSequenceStepPayloadAttr = "payload"
+	checkHTTPSequenceStepURLAttr     = "url"
+)
+
+const (
+	// circonus_check.http_sequence.* config keys not known to the API client.
+	// Each step is flattened into keys prefixed with "step<N>_", N starting
+	// at 1.
+	apiHTTPSequenceSteps           config.Key = "steps"
+	apiHTTPSequenceStepPrefix                 = "step"
+	apiHTTPSequenceStepCode                   = "code"
+	apiHTTPSequenceStepExtract                = "extract_"
+	apiHTTPSequenceStepHeader                 = "header_"
+	apiHTTPSequenceStepMethod                 = "method"
+	apiHTTPSequenceStepName                   = "name"
+	apiHTTPSequenceStepPayload                = "payload"
+	apiHTTPSequenceStepURL                    = "url"
+	defaultCheckHTTPSequenceCode              = defaultCheckHTTPCodeRegexp
+	defaultCheckHTTPSequenceMethod            = defaultCheckHTTPMethod
+)
+
+// httpSequenceRefRE matches the references to the values extracted by an
+// earlier step, e.g. {{login.token}}.
+var httpSequenceRefRE = regexp.MustCompile(`\{\{\s*([^.{}\s]+)\.([^.{}\s]+)\s*\}\}`)
+
+var checkHTTPSequenceDescriptions = attrDescrs{
+	checkHTTPSequenceStepAttr: "The requests of the transaction, made in order",
+}
+
+var checkHTTPSequenceStepDescriptions = attrDescrs{
+	checkHTTPSequenceStepCodeAttr:    "The HTTP code expected from the step, as a regular expression",
+	checkHTTPSequenceStepExtractAttr: "Map of variable names to regular expressions whose first capture group, matched against the body of the response, sets the variable for the following steps",
+	checkHTTPSequenceStepHeadersAttr: "Map of HTTP Headers to send along with the request",
+	checkHTTPSequenceStepMethodAttr:  "The HTTP method to use",
+	checkHTTPSequenceStepNameAttr:    "The name of the step, referenced with {{name.variable}} by the following steps",
+	checkHTTPSequenceStepPayloadAttr: "The information transferred as the payload of the request",
+	checkHTTPSequenceStepURLAttr:     "The URL of the request",
+}
+
+var schemaCheckHTTPSequence = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkHTTPSequenceDescriptions, map[schemaAttr]*schema.Schema{
+			checkHTTPSequenceStepAttr: {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkHTTPSequenceStepDescriptions, map[schemaAttr]*schema.Schema{
+						checkHTTPSequenceStepCodeAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultCheckHTTPSequenceCode,
+							ValidateFunc: validateRegexp(checkHTTPSequenceStepCodeAttr, `.+`),
+						},
+						checkHTTPSequenceStepExtractAttr: {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     schema.TypeString,
+						},
+						checkHTTPSequenceStepHeadersAttr: {
+							Type:         schema.TypeMap,
+							Optional:     true,
+							Elem:         schema.TypeString,
+							ValidateFunc: validateHTTPHeaders,
+						},
+						checkHTTPSequenceStepMethodAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      defaultCheckHTTPSequenceMethod,
+							ValidateFunc: validateRegexp(checkHTTPSequenceStepMethodAttr, `^\S+$`),
+						},
+						checkHTTPSequenceStepNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkHTTPSequenceStepNameAttr, `^[a-zA-Z0-9_-]+$`),
+						},
+						checkHTTPSequenceStepPayloadAttr: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						checkHTTPSequenceStepURLAttr: {
+							Type:     schema.TypeString,
+							Required: true,
+							// References to extracted values make the URL
+							// unparsable until the step runs.
+							ValidateFunc: validateRegexp(checkHTTPSequenceStepURLAttr, `^https?://\S+$`),
+						},
+					}),
+				},
+			},
+		}),
+	},
+}
+
+// checkAPIToStateHTTPSequence reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStateHTTPSequence(c *circonusCheck, d *schema.ResourceData) error {
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	numSteps, err := strconv.Atoi(c.Config[apiHTTPSequenceSteps])
+	if err != nil {
+		return fmt.Errorf("invalid %s config %q: %w", apiHTTPSequenceSteps, c.Config[apiHTTPSequenceSteps], err)
+	}
+	delete(swamp, apiHTTPSequenceSteps)
+
+	steps := make([]interface{}, 0, numSteps)
+	for i := 1; i <= numSteps; i++ {
+		prefix := httpSequenceStepPrefix(i)
+		step := make(map[string]interface{})
+
+		saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+			if v, ok := c.Config[apiKey]; ok {
+				step[string(attrName)] = v
+			}
+
+			delete(swamp, apiKey)
+		}
+
+		saveStringConfigToState(prefix+apiHTTPSequenceStepCode, checkHTTPSequenceStepCodeAttr)
+		saveStringConfigToState(prefix+apiHTTPSequenceStepMethod, checkHTTPSequenceStepMethodAttr)
+		saveStringConfigToState(prefix+apiHTTPSequenceStepName, checkHTTPSequenceStepNameAttr)
+		saveStringConfigToState(prefix+apiHTTPSequenceStepPayload, checkHTTPSequenceStepPayloadAttr)
+		saveStringConfigToState(prefix+apiHTTPSequenceStepURL, checkHTTPSequenceStepURLAttr)
+
+		extract := make(map[string]interface{})
+		headers := make(map[string]interface{})
+		for k, v := range c.Config {
+			switch {
+			case strings.HasPrefix(string(k), string(prefix+apiHTTPSequenceStepExtract)):
+				extract[strings.TrimPrefix(string(k), string(prefix+apiHTTPSequenceStepExtract))] = v
+				delete(swamp, k)
+			case strings.HasPrefix(string(k), string(prefix+apiHTTPSequenceStepHeader)):
+				headers[strings.TrimPrefix(string(k), string(prefix+apiHTTPSequenceStepHeader))] = v
+				delete(swamp, k)
+			}
+		}
+		step[string(checkHTTPSequenceStepExtractAttr)] = extract
+		step[string(checkHTTPSequenceStepHeadersAttr)] = headers
+
+		steps = append(steps, step)
+	}
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	httpSequenceConfig := map[string]interface{}{
+		string(checkHTTPSequenceStepAttr): steps,
+	}
+
+	if err := d.Set(checkHTTPSequenceAttr, []interface{}{httpSequenceConfig}); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkHTTPSequenceAttr, err)
+	}
+
+	return nil
+}
+
+func checkConfigToAPIHTTPSequence(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeHTTPSequence)
+
+	// Iterate over all `http_sequence` attributes, even though we have a max
+	// of 1 in the schema.
+	for _, mapRaw := range l {
+		httpSequenceConfig := newInterfaceMap(mapRaw)
+
+		stepsRaw, _ := httpSequenceConfig[string(checkHTTPSequenceStepAttr)].([]interface{})
+		if err := validateHTTPSequenceSteps(stepsRaw); err != nil {
+			return err
+		}
+
+		c.Config[apiHTTPSequenceSteps] = strconv.Itoa(len(stepsRaw))
+		for i, stepRaw := range stepsRaw {
+			step := newInterfaceMap(stepRaw)
+			prefix := httpSequenceStepPrefix(i + 1)
+
+			for attrName, apiKey := range map[schemaAttr]config.Key{
+				checkHTTPSequenceStepCodeAttr:    apiHTTPSequenceStepCode,
+				checkHTTPSequenceStepMethodAttr:  apiHTTPSequenceStepMethod,
+				checkHTTPSequenceStepNameAttr:    apiHTTPSequenceStepName,
+				checkHTTPSequenceStepPayloadAttr: apiHTTPSequenceStepPayload,
+				checkHTTPSequenceStepURLAttr:     apiHTTPSequenceStepURL,
+			} {
+				if v, ok := step[string(attrName)].(string); ok && v != "" {
+					c.Config[prefix+apiKey] = v
+				}
+			}
+
+			for k, v := range step.CollectMap(checkHTTPSequenceStepExtractAttr) {
+				c.Config[prefix+apiHTTPSequenceStepExtract+config.Key(k)] = v
+			}
+
+			for k, v := range step.CollectMap(checkHTTPSequenceStepHeadersAttr) {
+				c.Config[prefix+apiHTTPSequenceStepHeader+config.Key(k)] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+// httpSequenceStepPrefix returns the prefix of the config keys of the n-th
+// step, counting from 1.
+func httpSequenceStepPrefix(n int) config.Key {
+	return config.Key(apiHTTPSequenceStepPrefix + strconv.Itoa(n) + "_")
+}
+
+// validateHTTPSequenceSteps makes sure step names are unique, extract
+// expressions compile and every {{step.variable}} reference is to a value
+// extracted by an earlier step.
+func validateHTTPSequenceSteps(steps []interface{}) error {
+	extracted := make(map[string]map[string]struct{}, len(steps))
+
+	for i, stepRaw := range steps {
+		step := newInterfaceMap(stepRaw)
+		name, _ := step[string(checkHTTPSequenceStepNameAttr)].(string)
+
+		if _, found := extracted[name]; found {
+			return fmt.Errorf("%s.%d: duplicate %s %q", checkHTTPSequenceStepAttr, i, checkHTTPSequenceStepNameAttr, name)
+		}
+
+		refs := []string{
+			step[string(checkHTTPSequenceStepURLAttr)].(string),
+		}
+		if v, ok := step[string(checkHTTPSequenceStepPayloadAttr)].(string); ok {
+			refs = append(refs, v)
+		}
+		for _, v := range step.CollectMap(checkHTTPSequenceStepHeadersAttr) {
+			refs = append(refs, v)
+		}
+
+		for _, s := range refs {
+			for _, m := range httpSequenceRefRE.FindAllStringSubmatch(s, -1) {
+				vars, found := extracted[m[1]]
+				if !found {
+					return fmt.Errorf("%s.%d: %s refers to step %q which does not run before it", checkHTTPSequenceStepAttr, i, m[0], m[1])
+				}
+
+				if _, found := vars[m[2]]; !found {
+					return fmt.Errorf("%s.%d: %s refers to a value step %q does not extract", checkHTTPSequenceStepAttr, i, m[0], m[1])
+				}
+			}
+		}
+
+		extract := step.CollectMap(checkHTTPSequenceStepExtractAttr)
+		vars := make(map[string]struct{}, len(extract))
+		varNames := make([]string, 0, len(extract))
+		for k := range extract {
+			varNames = append(varNames, k)
+		}
+		sort.Strings(varNames)
+
+		for _, k := range varNames {
+			re, err := regexp.Compile(extract[k])
+			if err != nil {
+				return fmt.Errorf("%s.%d: invalid %s expression for %q: %w", checkHTTPSequenceStepAttr, i, checkHTTPSequenceStepExtractAttr, k, err)
+			}
+
+			if re.NumSubexp() < 1 {
+				return fmt.Errorf("%s.%d: %s expression for %q needs a capture group", checkHTTPSequenceStepAttr, i, checkHTTPSequenceStepExtractAttr, k)
+			}
+
+			vars[k] = struct{}{}
+		}
+
+		extracted[name] = vars
+	}
+
+	return nil
+}
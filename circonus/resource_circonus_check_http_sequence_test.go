@@ -0,0 +1,225 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccCirconusCheckHTTPSequence_basic(t *testing.T) {
+	checkName := fmt.Sprintf("HTTP sequence check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckHTTPSequenceConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.login", "active", "true"),
+					resource.TestMatchResourceAttr("circonus_check.login", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.0.name", "login"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.0.method", "POST"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.0.extract.token", `"token":"([^"]+)"`),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.1.name", "profile"),
+					resource.TestCheckResourceAttr("circonus_check.login", "http_sequence.0.step.1.headers.Authorization", "Bearer {{login.token}}"),
+					resource.TestCheckResourceAttr("circonus_check.login", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.login", "type", "http_sequence"),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckHTTPSequenceConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkHTTPSequenceAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHTTPSequenceStepAttr): []interface{}{
+					map[string]interface{}{
+						string(checkHTTPSequenceStepNameAttr):    "login",
+						string(checkHTTPSequenceStepURLAttr):     "https://app.example.org/login",
+						string(checkHTTPSequenceStepMethodAttr):  "POST",
+						string(checkHTTPSequenceStepPayloadAttr): `{"user":"monitor"}`,
+						string(checkHTTPSequenceStepExtractAttr): map[string]interface{}{
+							"token": `"token":"([^"]+)"`,
+						},
+					},
+					map[string]interface{}{
+						string(checkHTTPSequenceStepNameAttr): "profile",
+						string(checkHTTPSequenceStepURLAttr):  "https://app.example.org/profile",
+						string(checkHTTPSequenceStepHeadersAttr): map[string]interface{}{
+							"Authorization": "Bearer {{login.token}}",
+						},
+					},
+				},
+			},
+		},
+	})
+
+	c := newCheck()
+	if err := checkConfigToAPIHTTPSequence(&c, d.Get(string(checkHTTPSequenceAttr)).([]interface{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedConfig := map[config.Key]string{
+		"steps":                      "2",
+		"step1_name":                 "login",
+		"step1_url":                  "https://app.example.org/login",
+		"step1_method":               "POST",
+		"step1_code":                 defaultCheckHTTPCodeRegexp,
+		"step1_payload":              `{"user":"monitor"}`,
+		"step1_extract_token":        `"token":"([^"]+)"`,
+		"step2_name":                 "profile",
+		"step2_url":                  "https://app.example.org/profile",
+		"step2_method":               defaultCheckHTTPMethod,
+		"step2_code":                 defaultCheckHTTPCodeRegexp,
+		"step2_header_Authorization": "Bearer {{login.token}}",
+	}
+	if c.Type != string(apiCheckTypeHTTPSequence) {
+		t.Errorf("expected type %q, got %q", apiCheckTypeHTTPSequence, c.Type)
+	}
+	if len(c.Config) != len(expectedConfig) {
+		t.Errorf("expected %d config keys, got %#v", len(expectedConfig), c.Config)
+	}
+	for k, v := range expectedConfig {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := parseCheckTypeConfig(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedState := map[string]string{
+		"http_sequence.0.step.#":                       "2",
+		"http_sequence.0.step.0.name":                  "login",
+		"http_sequence.0.step.0.extract.token":         `"token":"([^"]+)"`,
+		"http_sequence.0.step.1.method":                defaultCheckHTTPMethod,
+		"http_sequence.0.step.1.headers.Authorization": "Bearer {{login.token}}",
+	}
+	for k, v := range expectedState {
+		if got := fmt.Sprint(d.Get(k)); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestValidateHTTPSequenceSteps(t *testing.T) {
+	step := func(name, url string, extract map[string]interface{}) interface{} {
+		return map[string]interface{}{
+			string(checkHTTPSequenceStepNameAttr):    name,
+			string(checkHTTPSequenceStepURLAttr):     url,
+			string(checkHTTPSequenceStepExtractAttr): extract,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		steps   []interface{}
+		wantErr bool
+	}{
+		{
+			name: "reference to an earlier step",
+			steps: []interface{}{
+				step("login", "https://example.org/login", map[string]interface{}{"id": `id=(\d+)`}),
+				step("item", "https://example.org/item/{{ login.id }}", nil),
+			},
+		},
+		{
+			name: "reference to a later step",
+			steps: []interface{}{
+				step("item", "https://example.org/item/{{login.id}}", nil),
+				step("login", "https://example.org/login", map[string]interface{}{"id": `id=(\d+)`}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "reference to a value not extracted",
+			steps: []interface{}{
+				step("login", "https://example.org/login", map[string]interface{}{"id": `id=(\d+)`}),
+				step("item", "https://example.org/item/{{login.token}}", nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate step name",
+			steps: []interface{}{
+				step("login", "https://example.org/login", nil),
+				step("login", "https://example.org/login", nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "extract without a capture group",
+			steps: []interface{}{
+				step("login", "https://example.org/login", map[string]interface{}{"id": `id=\d+`}),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateHTTPSequenceSteps(test.steps)
+			if test.wantErr && err == nil {
+				t.Errorf("expected an error")
+			}
+			if !test.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+const testAccCirconusCheckHTTPSequenceConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "login" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  http_sequence {
+    step {
+      name    = "login"
+      url     = "https://app.example.org/login"
+      method  = "POST"
+      payload = "{\"user\":\"monitor\"}"
+      extract = {
+        token = "\"token\":\"([^\"]+)\""
+      }
+    }
+
+    step {
+      name = "profile"
+      url  = "https://app.example.org/profile"
+      headers = {
+        Authorization = "Bearer {{login.token}}"
+      }
+    }
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+}
+`
@@ -1,33 +1,53 @@
 package circonus
 
 import (
-	"bytes"
+	"crypto/rand"
 	"fmt"
 	"log"
+	"math/big"
+	"sort"
 	"strings"
 
+	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
-	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_check.httptrap.* resource attribute names.
-	checkHTTPTrapAsyncMetricsAttr = "async_metrics"
-	checkHTTPTrapSecretAttr       = "secret"
+	checkHTTPTrapAsyncMetricsAttr  = "async_metrics"
+	checkHTTPTrapMetricTypesAttr   = "metric_types"
+	checkHTTPTrapRotateSecretAttr  = "rotate_secret"
+	checkHTTPTrapSecretAttr        = "secret"
+	checkHTTPTrapSubmissionURLAttr = "submission_url"
+)
+
+const (
+	// Generated httptrap secrets are made of httpTrapSecretLen characters
+	// drawn from httpTrapSecretChars.
+	httpTrapSecretChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	httpTrapSecretLen   = 16
+
+	// defaultBrokerHTTPTrapPort is the port httptrap submissions are sent to
+	// on brokers that do not advertise one.
+	defaultBrokerHTTPTrapPort = 43191
 )
 
 var checkHTTPTrapDescriptions = attrDescrs{
-	checkHTTPTrapAsyncMetricsAttr: "Specify whether httptrap metrics are logged immediately or held until the status message is emitted",
-	checkHTTPTrapSecretAttr:       "",
+	checkHTTPTrapAsyncMetricsAttr:  "Specify whether httptrap metrics are logged immediately or held until the status message is emitted",
+	checkHTTPTrapMetricTypesAttr:   "Map of submitted metric names to their expected type (histogram, numeric or text), the metrics are registered with these types before the first sample arrives",
+	checkHTTPTrapRotateSecretAttr:  "Changing this value generates a new secret, and with it a new submission URL, when the check is updated",
+	checkHTTPTrapSecretAttr:        "The secret submitters authenticate with, generated by Circonus when omitted",
+	checkHTTPTrapSubmissionURLAttr: "The URL metrics are submitted to, including the secret",
 }
 
+// The httptrap block is a list rather than a set so its computed attributes
+// are kept in the statefile alongside the configured ones.
 var schemaCheckHTTPTrap = &schema.Schema{
-	Type:     schema.TypeSet,
+	Type:     schema.TypeList,
 	Optional: true,
 	MaxItems: 1,
 	MinItems: 1,
-	Set:      hashCheckHTTPTrap,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkHTTPTrapDescriptions, map[schemaAttr]*schema.Schema{
 			checkHTTPTrapAsyncMetricsAttr: {
@@ -35,12 +55,29 @@ var schemaCheckHTTPTrap = &schema.Schema{
 				Optional: true,
 				Default:  defaultCheckHTTPTrapAsync,
 			},
+			checkHTTPTrapMetricTypesAttr: {
+				Type:         schema.TypeMap,
+				Elem:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateHTTPTrapMetricTypes,
+			},
+			checkHTTPTrapRotateSecretAttr: {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{checkHTTPTrapAttr + ".0." + checkHTTPTrapSecretAttr},
+			},
 			checkHTTPTrapSecretAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkHTTPTrapSecretAttr, `^[a-zA-Z0-9_]+$`),
 			},
+			checkHTTPTrapSubmissionURLAttr: {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 		}),
 	},
 }
@@ -81,10 +118,30 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 
 	saveBoolConfigToState(config.AsyncMetrics, checkHTTPTrapAsyncMetricsAttr)
 	saveStringConfigToState(config.Secret, checkHTTPTrapSecretAttr)
+	saveStringConfigToState(config.SubmissionURL, checkHTTPTrapSubmissionURLAttr)
+
+	// rotate_secret is a trigger that only lives in the statefile.
+	if v, ok := d.GetOk(checkHTTPTrapAttr + ".0." + checkHTTPTrapRotateSecretAttr); ok {
+		httpTrapConfig[string(checkHTTPTrapRotateSecretAttr)] = v.(string)
+	}
+
+	// metric_types are not part of the API config, a hint is kept for as long
+	// as the check has the metric registered with the hinted type.
+	apiMetricTypes := make(map[string]string, len(c.Metrics))
+	for _, m := range c.Metrics {
+		apiMetricTypes[m.Name] = m.Type
+	}
+
+	metricTypes := make(map[string]interface{})
+	for name, metricType := range checkHTTPTrapMetricTypes(d) {
+		if apiMetricTypes[name] == metricType {
+			metricTypes[name] = metricType
+		}
+	}
+	httpTrapConfig[string(checkHTTPTrapMetricTypesAttr)] = metricTypes
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
-		config.SubmissionURL:    {},
 	}
 
 	for k := range swamp {
@@ -97,41 +154,14 @@ func checkAPIToStateHTTPTrap(c *circonusCheck, d *schema.ResourceData) error {
 		}
 	}
 
-	if err := d.Set(checkHTTPTrapAttr, schema.NewSet(hashCheckHTTPTrap, []interface{}{httpTrapConfig})); err != nil {
+	if err := d.Set(checkHTTPTrapAttr, []interface{}{httpTrapConfig}); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkHTTPTrapAttr, err)
 	}
 
 	return nil
 }
 
-// hashCheckHTTPTrap creates a stable hash of the normalized values.
-func hashCheckHTTPTrap(v interface{}) int {
-	m := v.(map[string]interface{})
-	b := &bytes.Buffer{}
-	b.Grow(defaultHashBufSize)
-
-	writeBool := func(attrName schemaAttr) {
-		if v, ok := m[string(attrName)]; ok {
-			fmt.Fprintf(b, "%t", v.(bool))
-		}
-	}
-
-	writeString := func(attrName schemaAttr) {
-		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
-			fmt.Fprint(b, strings.TrimSpace(v.(string)))
-		}
-	}
-
-	// Order writes to the buffer using lexically sorted list for easy visual
-	// reconciliation with other lists.
-	writeBool(checkHTTPTrapAsyncMetricsAttr)
-	writeString(checkHTTPTrapSecretAttr)
-
-	s := b.String()
-	return hashcode.String(s)
-}
-
-func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeHTTPTrapAttr)
 
 	// Iterate over all `httptrap` attributes, even though we have a max of 1 in the
@@ -146,10 +176,211 @@ func checkConfigToAPIHTTPTrap(c *circonusCheck, l interfaceList) error { //nolin
 			}
 		}
 
-		if v, found := httpTrapConfig[checkHTTPTrapSecretAttr]; found {
+		if v, found := httpTrapConfig[checkHTTPTrapSecretAttr]; found && v.(string) != "" {
 			c.Config[config.Secret] = v.(string)
 		}
+
+		if err := checkHTTPTrapRegisterMetrics(c, httpTrapConfig.CollectMap(checkHTTPTrapMetricTypesAttr)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkHTTPTrapRegisterMetrics adds an active metric for each of the
+// metric_types hints that is not already declared by a metric block, so the
+// stream has the right type before the first, possibly ambiguous, sample is
+// submitted.
+func checkHTTPTrapRegisterMetrics(c *circonusCheck, metricTypes map[string]string) error {
+	names := make([]string, 0, len(metricTypes))
+	for name := range metricTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	declared := make(map[string]string, len(c.Metrics))
+	for _, m := range c.Metrics {
+		declared[m.Name] = m.Type
+	}
+
+	for _, name := range names {
+		metricType := metricTypes[name]
+		if t, found := declared[name]; found {
+			if t != metricType {
+				return fmt.Errorf("%s %q is declared as %s but hinted as %s", checkMetricAttr, name, t, metricType)
+			}
+			continue
+		}
+
+		c.Metrics = append(c.Metrics, api.CheckBundleMetric{
+			Name:   name,
+			Status: metricActiveToAPIStatus(true),
+			Type:   metricType,
+		})
+	}
+
+	return nil
+}
+
+// checkHTTPTrapRotateSecret replaces the secret of an existing httptrap check
+// with a newly generated one when the rotate_secret trigger of d has changed.
+// The API derives the submission URL from the secret, so the new URL is read
+// back along with the rest of the check.
+func checkHTTPTrapRotateSecret(c *circonusCheck, d *schema.ResourceData) error {
+	if c.Type != string(apiCheckTypeHTTPTrapAttr) || d.Id() == "" {
+		return nil
+	}
+
+	attrName := checkHTTPTrapAttr + ".0." + checkHTTPTrapRotateSecretAttr
+	if !d.HasChange(attrName) || d.Get(attrName).(string) == "" {
+		return nil
 	}
 
+	secret, err := newHTTPTrapSecret()
+	if err != nil {
+		return fmt.Errorf("unable to generate a new %s %s: %w", checkHTTPTrapAttr, checkHTTPTrapSecretAttr, err)
+	}
+
+	c.Config[config.Secret] = secret
+
 	return nil
 }
+
+// newHTTPTrapSecret returns a random secret accepted by the httptrap secret
+// validation.
+func newHTTPTrapSecret() (string, error) {
+	b := make([]byte, httpTrapSecretLen)
+	max := big.NewInt(int64(len(httpTrapSecretChars)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = httpTrapSecretChars[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// checkHTTPTrapSubmissionURLs returns the submission URL of the check on each
+// of its collectors, ordered by collector.  The URL returned by the API is used
+// for the collector it points to, the others are assembled from the
+// collector's address, the check's UUID and the secret.  fetchBroker is only
+// called for the latter.
+func checkHTTPTrapSubmissionURLs(c *circonusCheck, fetchBroker func(cid string) (*api.Broker, error)) ([]string, error) {
+	secret := c.Config[config.Secret]
+	if c.Type != string(apiCheckTypeHTTPTrapAttr) || secret == "" || len(c.CheckUUIDs) != len(c.Brokers) {
+		return []string{}, nil
+	}
+
+	order := make([]int, len(c.Brokers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.Brokers[order[i]] < c.Brokers[order[j]]
+	})
+
+	apiURL := c.Config[config.SubmissionURL]
+
+	urls := make([]string, 0, len(order))
+	for _, i := range order {
+		uuid := c.CheckUUIDs[i]
+		if apiURL != "" && strings.Contains(apiURL, "/"+uuid+"/") {
+			urls = append(urls, apiURL)
+			continue
+		}
+
+		broker, err := fetchBroker(c.Brokers[i])
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch collector %q: %w", c.Brokers[i], err)
+		}
+
+		host, port := brokerHTTPTrapAddress(broker)
+		if host == "" {
+			log.Printf("[WARN] collector %q has no address, no submission URL for check %q", c.Brokers[i], uuid)
+			continue
+		}
+
+		urls = append(urls, fmt.Sprintf("https://%s:%d/module/httptrap/%s/%s", host, port, uuid, secret))
+	}
+
+	return urls, nil
+}
+
+// brokerHTTPTrapAddress returns the host and port httptrap submissions are
+// sent to on the first active node of broker, preferring its external
+// address.
+func brokerHTTPTrapAddress(broker *api.Broker) (string, uint16) {
+	for _, detail := range broker.Details {
+		if detail.Status != "active" {
+			continue
+		}
+
+		var host string
+		switch {
+		case detail.ExternalHost != nil && *detail.ExternalHost != "":
+			host = *detail.ExternalHost
+		case detail.IP != nil && *detail.IP != "":
+			host = *detail.IP
+		default:
+			continue
+		}
+
+		port := uint16(defaultBrokerHTTPTrapPort)
+		switch {
+		case detail.ExternalPort != 0:
+			port = detail.ExternalPort
+		case detail.Port != nil && *detail.Port != 0:
+			port = *detail.Port
+		}
+
+		return host, port
+	}
+
+	return "", 0
+}
+
+// checkHTTPTrapMetricTypes returns the metric_types of the httptrap block of
+// d, if any.
+func checkHTTPTrapMetricTypes(d *schema.ResourceData) map[string]string {
+	metricTypes := make(map[string]string)
+
+	l, ok := d.Get(checkHTTPTrapAttr).([]interface{})
+	if !ok {
+		return metricTypes
+	}
+
+	for _, mapRaw := range l {
+		for k, v := range newInterfaceMap(mapRaw).CollectMap(checkHTTPTrapMetricTypesAttr) {
+			metricTypes[k] = v
+		}
+	}
+
+	return metricTypes
+}
+
+// checkHTTPTrapHintOnlyMetrics returns the names of the metrics registered by
+// metric_types that are not also declared by a metric block of d.  These are
+// left out of the metric list when reading the check.
+func checkHTTPTrapHintOnlyMetrics(d *schema.ResourceData) map[string]struct{} {
+	hintOnly := make(map[string]struct{})
+	for name := range checkHTTPTrapMetricTypes(d) {
+		hintOnly[name] = struct{}{}
+	}
+
+	if len(hintOnly) == 0 {
+		return hintOnly
+	}
+
+	if l, ok := d.Get(checkMetricAttr).([]interface{}); ok {
+		for _, metricRaw := range l {
+			if metricAttrs, ok := metricRaw.(map[string]interface{}); ok {
+				delete(hintOnly, metricAttrs[string(metricNameAttr)].(string))
+			}
+		}
+	}
+
+	return hintOnly
+}
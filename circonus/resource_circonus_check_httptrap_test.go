@@ -2,10 +2,16 @@ package circonus
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
 
+	api "github.com/circonus-labs/go-apiclient"
+	"github.com/circonus-labs/go-apiclient/config"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckHTTPTrap_basic(t *testing.T) {
@@ -24,7 +30,11 @@ func TestAccCirconusCheckHTTPTrap_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_check.consul", "collector.0.id", "/broker/35"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "httptrap.#", "1"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "httptrap.0.async_metrics", "false"),
+					resource.TestCheckResourceAttr("circonus_check.consul", "httptrap.0.metric_types.%", "1"),
+					resource.TestCheckResourceAttr("circonus_check.consul", "httptrap.0.metric_types.consul`consul`raft`apply", "histogram"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "httptrap.0.secret", "12345"),
+					resource.TestCheckResourceAttrSet("circonus_check.consul", "httptrap.0.submission_url"),
+					resource.TestCheckResourceAttr("circonus_check.consul", "submission_urls.#", "1"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "name", checkName),
 					resource.TestCheckResourceAttr("circonus_check.consul", "notes", "Check to receive consul server telemetry"),
 					resource.TestCheckResourceAttr("circonus_check.consul", "period", "60s"),
@@ -54,6 +64,179 @@ func TestAccCirconusCheckHTTPTrap_basic(t *testing.T) {
 	})
 }
 
+func TestCheckHTTPTrapRegisterMetrics(t *testing.T) {
+	c := newCheck()
+	c.Metrics = []api.CheckBundleMetric{
+		{Name: "declared", Status: "active", Type: "numeric"},
+	}
+
+	err := checkHTTPTrapRegisterMetrics(&c, map[string]string{
+		"latency":  "histogram",
+		"declared": "numeric",
+		"version":  "text",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []api.CheckBundleMetric{
+		{Name: "declared", Status: "active", Type: "numeric"},
+		{Name: "latency", Status: "active", Type: "histogram"},
+		{Name: "version", Status: "active", Type: "text"},
+	}
+	if !reflect.DeepEqual(c.Metrics, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, c.Metrics)
+	}
+
+	if err := checkHTTPTrapRegisterMetrics(&c, map[string]string{"declared": "histogram"}); err == nil {
+		t.Fatalf("expected an error for a hint conflicting with a declared metric")
+	}
+}
+
+func TestCheckHTTPTrapRotateSecret(t *testing.T) {
+	parse := func(cfg map[string]interface{}, id string) circonusCheck {
+		d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+			string(checkHTTPTrapAttr): []interface{}{cfg},
+		})
+		d.SetId(id)
+
+		c := newCheck()
+		if err := checkConfigToAPIHTTPTrap(&c, d.Get(checkHTTPTrapAttr).([]interface{})); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := checkHTTPTrapRotateSecret(&c, d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return c
+	}
+
+	c := parse(map[string]interface{}{string(checkHTTPTrapSecretAttr): "12345"}, "/check_bundle/1")
+	if c.Config[config.Secret] != "12345" {
+		t.Errorf("expected the configured secret to be kept, got %q", c.Config[config.Secret])
+	}
+
+	c = parse(map[string]interface{}{string(checkHTTPTrapRotateSecretAttr): "2024-01"}, "")
+	if _, found := c.Config[config.Secret]; found {
+		t.Errorf("expected no secret on create, got %q", c.Config[config.Secret])
+	}
+
+	c = parse(map[string]interface{}{string(checkHTTPTrapRotateSecretAttr): "2024-01"}, "/check_bundle/1")
+	if !regexp.MustCompile(fmt.Sprintf(`^[a-zA-Z0-9]{%d}$`, httpTrapSecretLen)).MatchString(c.Config[config.Secret]) {
+		t.Errorf("expected a generated secret, got %q", c.Config[config.Secret])
+	}
+
+	other := parse(map[string]interface{}{string(checkHTTPTrapRotateSecretAttr): "2024-01"}, "/check_bundle/1")
+	if other.Config[config.Secret] == c.Config[config.Secret] {
+		t.Errorf("expected rotated secrets to differ, got %q twice", c.Config[config.Secret])
+	}
+}
+
+func TestCheckHTTPTrapSubmissionURL(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkHTTPTrapAttr): []interface{}{
+			map[string]interface{}{
+				string(checkHTTPTrapRotateSecretAttr): "2024-01",
+			},
+		},
+	})
+
+	c := newCheck()
+	c.Type = string(apiCheckTypeHTTPTrapAttr)
+	c.Config[config.Secret] = "s3cr3t"
+	c.Config[config.SubmissionURL] = "https://api.circonus.com/module/httptrap/abc/s3cr3t"
+	c.Config[config.ReverseSecretKey] = "reverse"
+
+	if err := parseCheckTypeConfig(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"httptrap.0.rotate_secret":  "2024-01",
+		"httptrap.0.secret":         "s3cr3t",
+		"httptrap.0.submission_url": "https://api.circonus.com/module/httptrap/abc/s3cr3t",
+	}
+	for k, v := range expected {
+		if got := d.Get(k).(string); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestCheckHTTPTrapSubmissionURLs(t *testing.T) {
+	externalHost, ip := "trap.example.com", "10.0.0.3"
+	var port uint16 = 43192
+	brokers := map[string]*api.Broker{
+		"/broker/3": {Details: []api.BrokerDetail{
+			{Status: "unprovisioned", IP: &ip},
+			{Status: "active", ExternalHost: &externalHost, IP: &ip, Port: &port},
+		}},
+		"/broker/4": {Details: []api.BrokerDetail{
+			{Status: "active", IP: &ip, ExternalPort: 443},
+		}},
+	}
+
+	fetched := make([]string, 0)
+	fetchBroker := func(cid string) (*api.Broker, error) {
+		fetched = append(fetched, cid)
+		b, ok := brokers[cid]
+		if !ok {
+			return nil, fmt.Errorf("broker %q not found", cid)
+		}
+		return b, nil
+	}
+
+	c := newCheck()
+	c.Type = string(apiCheckTypeHTTPTrapAttr)
+	c.Brokers = []string{"/broker/4", "/broker/1", "/broker/3"}
+	c.CheckUUIDs = []string{"uuid-4", "uuid-1", "uuid-3"}
+	c.Config[config.Secret] = "s3cr3t"
+	c.Config[config.SubmissionURL] = "https://trap.noit.circonus.net/module/httptrap/uuid-1/s3cr3t"
+
+	urls, err := checkHTTPTrapSubmissionURLs(&c, fetchBroker)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"https://trap.noit.circonus.net/module/httptrap/uuid-1/s3cr3t",
+		"https://trap.example.com:43192/module/httptrap/uuid-3/s3cr3t",
+		"https://10.0.0.3:443/module/httptrap/uuid-4/s3cr3t",
+	}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expected %v, got %v", expected, urls)
+	}
+	if !reflect.DeepEqual(fetched, []string{"/broker/3", "/broker/4"}) {
+		t.Errorf("expected only the collectors without an API URL to be fetched, got %v", fetched)
+	}
+
+	c.Type = string(apiCheckTypeJSONAttr)
+	if urls, err := checkHTTPTrapSubmissionURLs(&c, fetchBroker); err != nil || len(urls) != 0 {
+		t.Errorf("expected no submission URLs for a json check, got %v, %v", urls, err)
+	}
+}
+
+func TestValidateHTTPTrapMetricTypes(t *testing.T) {
+	tests := []struct {
+		metricTypes map[string]interface{}
+		ok          bool
+	}{
+		{map[string]interface{}{"latency": "histogram", "requests": "numeric", "version": "text"}, true},
+		{map[string]interface{}{"latency": "caql"}, false},
+		{map[string]interface{}{"latency": ""}, false},
+	}
+
+	for _, test := range tests {
+		_, errs := validateHTTPTrapMetricTypes(test.metricTypes, string(checkHTTPTrapMetricTypesAttr))
+		if test.ok && len(errs) > 0 {
+			t.Errorf("%v: unexpected errors: %v", test.metricTypes, errs)
+		}
+		if !test.ok && len(errs) == 0 {
+			t.Errorf("%v: expected an error", test.metricTypes)
+		}
+	}
+}
+
 const testAccCirconusCheckHTTPTrapConfigFmt = `
 variable "httptrap_check_tags" {
   type = list(string)
@@ -78,6 +261,10 @@ resource "circonus_check" "consul" {
   httptrap {
     async_metrics = "false"
     secret = "12345"
+
+    metric_types = {
+      "consul` + "`" + `consul` + "`" + `raft` + "`" + `apply" = "histogram"
+    }
   }
 
   metric {
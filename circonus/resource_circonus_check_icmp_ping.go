@@ -21,7 +21,8 @@ const (
 var checkICMPPingDescriptions = attrDescrs{
 	checkICMPPingAvailabilityAttr: `The percentage of ICMP available required for the check to be considered "good."`,
 	checkICMPPingCountAttr:        "The number of ICMP requests to send during a single check.",
-	checkICMPPingIntervalAttr:     "The number of milliseconds between ICMP requests.",
+	checkICMPPingIntervalAttr:     "The time between ICMP requests, with millisecond precision.",
+	checkResolveTargetAttr:        checkResolveTargetDescription,
 }
 
 var schemaCheckICMPPing = &schema.Schema{
@@ -55,10 +56,11 @@ var schemaCheckICMPPing = &schema.Schema{
 				Optional: true,
 				Default:  defaultCheckICMPPingInterval,
 				ValidateFunc: validateFuncs(
-					validateDurationMin(checkICMPPingIntervalAttr, "100µs"),
+					validateDurationMin(checkICMPPingIntervalAttr, "1ms"),
 					validateDurationMax(checkICMPPingIntervalAttr, "5m"),
 				),
 			},
+			checkResolveTargetAttr: schemaCheckResolveTarget,
 		}),
 	},
 }
@@ -68,24 +70,38 @@ var schemaCheckICMPPing = &schema.Schema{
 func checkAPIToStateICMPPing(c *circonusCheck, d *schema.ResourceData) error {
 	icmpPingConfig := make(map[string]interface{}, len(c.Config))
 
-	availNeeded, err := strconv.ParseFloat(c.Config[config.AvailNeeded], 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.AvailNeeded, err)
+	// The broker applies its defaults to the options the API omits.
+	availNeeded := float64(defaultCheckICMPPingAvailability)
+	if v, ok := c.Config[config.AvailNeeded]; ok && v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.AvailNeeded, err)
+		}
+		availNeeded = f
 	}
 
-	count, err := strconv.ParseInt(c.Config[config.Count], 10, 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Count, err)
+	count := int64(defaultCheckICMPPingCount)
+	if v, ok := c.Config[config.Count]; ok && v != "" {
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Count, err)
+		}
+		count = i
 	}
 
-	interval, err := time.ParseDuration(fmt.Sprintf("%sms", c.Config[config.Interval]))
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Interval, err)
+	interval, _ := time.ParseDuration(defaultCheckICMPPingInterval)
+	if v, ok := c.Config[config.Interval]; ok && v != "" {
+		ms, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Interval, err)
+		}
+		interval = time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond)
 	}
 
 	icmpPingConfig[string(checkICMPPingAvailabilityAttr)] = availNeeded
 	icmpPingConfig[string(checkICMPPingCountAttr)] = int(count)
 	icmpPingConfig[string(checkICMPPingIntervalAttr)] = interval.String()
+	icmpPingConfig[checkResolveTargetAttr] = checkResolveTargetToState(c, checkResolveTargetState(d, checkICMPPingAttr))
 
 	if err := d.Set(checkICMPPingAttr, schema.NewSet(hashCheckICMPPing, []interface{}{icmpPingConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkICMPPingAttr, err)
@@ -124,12 +140,15 @@ func hashCheckICMPPing(v interface{}) int {
 	writeFloat64(checkICMPPingAvailabilityAttr)
 	writeInt(checkICMPPingCountAttr)
 	writeDuration(checkICMPPingIntervalAttr)
+	if v, ok := m[checkResolveTargetAttr]; ok && v.(string) != "" {
+		fmt.Fprint(b, v.(string))
+	}
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeICMPPing)
 
 	// Iterate over all `icmp_ping` attributes, even though we have a max of 1 in
@@ -138,8 +157,7 @@ func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error { //nolin
 		icmpPingConfig := newInterfaceMap(mapRaw)
 
 		if v, found := icmpPingConfig[checkICMPPingAvailabilityAttr]; found {
-			f := v.(float64)
-			c.Config[config.AvailNeeded] = fmt.Sprintf("%d", int(f))
+			c.Config[config.AvailNeeded] = strconv.FormatFloat(v.(float64), 'f', -1, 64)
 		}
 
 		if v, found := icmpPingConfig[checkICMPPingCountAttr]; found {
@@ -148,7 +166,13 @@ func checkConfigToAPIICMPPing(c *circonusCheck, l interfaceList) error { //nolin
 
 		if v, found := icmpPingConfig[checkICMPPingIntervalAttr]; found {
 			d, _ := time.ParseDuration(v.(string))
-			c.Config[config.Interval] = fmt.Sprintf("%d", int64(d/time.Millisecond))
+			c.Config[config.Interval] = fmt.Sprintf("%d", int64(d.Round(time.Millisecond)/time.Millisecond))
+		}
+
+		if v, found := icmpPingConfig[checkResolveTargetAttr]; found {
+			if err := checkResolveTargetToAPI(c, v.(string)); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckICMPPing_basic(t *testing.T) {
@@ -117,3 +118,55 @@ resource "circonus_check" "loopback_latency" {
   target = "api.circonus.com"
 }
 `
+
+func TestCheckICMPPingConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkICMPPingAttr): []interface{}{
+			map[string]interface{}{
+				string(checkICMPPingAvailabilityAttr): 62.5,
+				string(checkICMPPingCountAttr):        16,
+				string(checkICMPPingIntervalAttr):     "250ms",
+			},
+		},
+	})
+
+	icmpPingConfig := d.Get(string(checkICMPPingAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIICMPPing(&c, icmpPingConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.AvailNeeded: "62.5",
+		config.Count:       "16",
+		config.Interval:    "250",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStateICMPPing(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkICMPPingAttr)).(*schema.Set).List()
+	if hashCheckICMPPing(state[0]) != hashCheckICMPPing(icmpPingConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	// Options the API omits read back as the broker's defaults.
+	c.Config = map[config.Key]string{config.Count: "3"}
+	if err := checkAPIToStateICMPPing(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := d.Get(string(checkICMPPingAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	if m[string(checkICMPPingAvailabilityAttr)] != float64(defaultCheckICMPPingAvailability) ||
+		m[string(checkICMPPingCountAttr)] != 3 ||
+		m[string(checkICMPPingIntervalAttr)] != defaultCheckICMPPingInterval {
+		t.Errorf("unexpected state %#v", m)
+	}
+}
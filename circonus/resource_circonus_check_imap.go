@@ -0,0 +1,260 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	checkIMAPAuthPasswordAttr = "auth_password"
+	checkIMAPAuthUserAttr     = "auth_user"
+	checkIMAPCAChainAttr      = "ca_chain"
+	checkIMAPCertFileAttr     = "certificate_file"
+	checkIMAPCiphersAttr      = "ciphers"
+	checkIMAPFetchAttr        = "fetch"
+	checkIMAPFolderAttr       = "folder"
+	checkIMAPKeyFileAttr      = "key_file"
+	checkIMAPPortAttr         = "port"
+	checkIMAPSearchAttr       = "search"
+	checkIMAPUseSSLAttr       = "use_ssl"
+)
+
+var checkIMAPDescriptions = attrDescrs{
+	checkIMAPAuthPasswordAttr: "The password used to log in to the mailbox.",
+	checkIMAPAuthUserAttr:     "The user used to log in to the mailbox.",
+	checkIMAPCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks).",
+	checkIMAPCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS checks).",
+	checkIMAPCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for TLS checks).",
+	checkIMAPFetchAttr:        "Fetch the newest message matching the search and measure the time taken. (default: false)",
+	checkIMAPFolderAttr:       "The folder to select. (default: INBOX)",
+	checkIMAPKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks).",
+	checkIMAPPortAttr:         "Specifies the TCP port to connect to. (default: 143)",
+	checkIMAPSearchAttr:       "An IMAP SEARCH criteria run against the folder, e.g. UNSEEN.",
+	checkIMAPUseSSLAttr:       "Connect to the server using TLS (IMAPS). (default: false)",
+}
+
+var schemaCheckIMAP = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckIMAP,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkIMAPDescriptions, map[schemaAttr]*schema.Schema{
+			checkIMAPAuthPasswordAttr: {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			checkIMAPAuthUserAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			checkIMAPCAChainAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkIMAPCertFileAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkIMAPCiphersAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkIMAPFetchAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkIMAPFolderAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "INBOX",
+			},
+			checkIMAPKeyFileAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkIMAPPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  143,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkIMAPPortAttr, 1),
+					validateIntMax(checkIMAPPortAttr, 65535),
+				),
+			},
+			checkIMAPSearchAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkIMAPUseSSLAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		}),
+	},
+}
+
+// checkAPIToStateIMAP reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateIMAP(c *circonusCheck, d *schema.ResourceData) error {
+	imapConfig := make(map[string]interface{}, len(c.Config))
+
+	if authPassword, ok := c.Config[config.AuthPassword]; ok {
+		imapConfig[string(checkIMAPAuthPasswordAttr)] = authPassword
+	}
+
+	if authUser, ok := c.Config[config.AuthUser]; ok {
+		imapConfig[string(checkIMAPAuthUserAttr)] = authUser
+	}
+
+	if caChain, ok := c.Config[config.CAChain]; ok {
+		imapConfig[string(checkIMAPCAChainAttr)] = caChain
+	}
+
+	if certFile, ok := c.Config[config.CertFile]; ok {
+		imapConfig[string(checkIMAPCertFileAttr)] = certFile
+	}
+
+	if ciphers, ok := c.Config[config.Ciphers]; ok {
+		imapConfig[string(checkIMAPCiphersAttr)] = ciphers
+	}
+
+	if fetch, ok := c.Config[config.Fetch]; ok {
+		imapConfig[string(checkIMAPFetchAttr)], _ = strconv.ParseBool(fetch)
+	}
+
+	if folder, ok := c.Config[config.Folder]; ok {
+		imapConfig[string(checkIMAPFolderAttr)] = folder
+	}
+
+	if keyFile, ok := c.Config[config.KeyFile]; ok {
+		imapConfig[string(checkIMAPKeyFileAttr)] = keyFile
+	}
+
+	if port, ok := c.Config[config.Port]; ok {
+		imapConfig[string(checkIMAPPortAttr)], _ = strconv.Atoi(port)
+	}
+
+	if search, ok := c.Config[config.Search]; ok {
+		imapConfig[string(checkIMAPSearchAttr)] = search
+	}
+
+	if useSSL, ok := c.Config[config.UseSSL]; ok {
+		imapConfig[string(checkIMAPUseSSLAttr)], _ = strconv.ParseBool(useSSL)
+	}
+
+	if err := d.Set(checkIMAPAttr, schema.NewSet(hashCheckIMAP, []interface{}{imapConfig})); err != nil {
+		return fmt.Errorf("unable to store check %q attribute: %w", checkIMAPAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckIMAP creates a stable hash of the normalized values.
+func hashCheckIMAP(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			if v.(int) > 0 {
+				fmt.Fprintf(b, "%x", v.(int))
+			}
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%s", v.(string))
+		}
+	}
+
+	writeString(checkIMAPAuthPasswordAttr)
+	writeString(checkIMAPAuthUserAttr)
+	writeString(checkIMAPCAChainAttr)
+	writeString(checkIMAPCertFileAttr)
+	writeString(checkIMAPCiphersAttr)
+	writeBool(checkIMAPFetchAttr)
+	writeString(checkIMAPFolderAttr)
+	writeString(checkIMAPKeyFileAttr)
+	writeInt(checkIMAPPortAttr)
+	writeString(checkIMAPSearchAttr)
+	writeBool(checkIMAPUseSSLAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIIMAP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeIMAP)
+
+	mapRaw := l[0]
+	imapConfig := newInterfaceMap(mapRaw)
+
+	if v, found := imapConfig[checkIMAPAuthPasswordAttr]; found && v.(string) != "" {
+		c.Config[config.AuthPassword] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPAuthUserAttr]; found && v.(string) != "" {
+		c.Config[config.AuthUser] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPCAChainAttr]; found && v.(string) != "" {
+		c.Config[config.CAChain] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPCertFileAttr]; found && v.(string) != "" {
+		c.Config[config.CertFile] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPCiphersAttr]; found && v.(string) != "" {
+		c.Config[config.Ciphers] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPFetchAttr]; found {
+		c.Config[config.Fetch] = fmt.Sprintf("%t", v.(bool))
+	}
+
+	if v, found := imapConfig[checkIMAPFolderAttr]; found && v.(string) != "" {
+		c.Config[config.Folder] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPKeyFileAttr]; found && v.(string) != "" {
+		c.Config[config.KeyFile] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPPortAttr]; found && v.(int) > 0 {
+		c.Config[config.Port] = strconv.Itoa(v.(int))
+	}
+
+	if v, found := imapConfig[checkIMAPSearchAttr]; found && v.(string) != "" {
+		c.Config[config.Search] = v.(string)
+	}
+
+	if v, found := imapConfig[checkIMAPUseSSLAttr]; found {
+		c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckIMAP_basic(t *testing.T) {
+	checkName := fmt.Sprintf("IMAP check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckIMAPConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.imap", "active", "true"),
+					resource.TestMatchResourceAttr("circonus_check.imap", "check_id", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.imap", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "imap.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.imap", "period", "300s"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "target", "127.0.0.1"),
+					resource.TestCheckResourceAttr("circonus_check.imap", "type", "imap"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckIMAPConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "imap" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  imap {
+    auth_user = "monitor"
+    auth_password = "secret"
+    search = "UNSEEN"
+    fetch = true
+    port = 993
+    use_ssl = true
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  metric {
+    name = "messages"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+  target = "127.0.0.1"
+}
+`
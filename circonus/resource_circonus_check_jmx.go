@@ -22,6 +22,7 @@ const (
 	checkJMXHostAttr            = "host"
 	checkJMXURIAttr             = "uri"
 	checkJMXUsernameAttr        = "username"
+	checkJMXUseSSLAttr          = "use_ssl"
 )
 
 var checkJMXDescriptions = attrDescrs{
@@ -32,6 +33,8 @@ var checkJMXDescriptions = attrDescrs{
 	checkJMXPortAttr:            "JMX port",
 	checkJMXURIAttr:             "JMX uri, defaults to '/jmxrmi'",
 	checkJMXUsernameAttr:        "JMX username",
+	checkJMXUseSSLAttr:          "Connect to the JMX endpoint using SSL",
+	checkTLSConfigAttr:          checkTLSConfigDescription,
 }
 
 var schemaCheckJMX = &schema.Schema{
@@ -72,6 +75,7 @@ var schemaCheckJMX = &schema.Schema{
 			checkJMXPasswordAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkJMXPasswordAttr, `.+`),
 			},
 			checkJMXURIAttr: {
@@ -98,6 +102,12 @@ var schemaCheckJMX = &schema.Schema{
 					validateIntMax(checkJMXPortAttr, 65535),
 				),
 			},
+			checkJMXUseSSLAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkTLSConfigAttr: schemaCheckTLS,
 		}),
 	},
 }
@@ -134,10 +144,25 @@ func checkAPIToStateJMX(c *circonusCheck, d *schema.ResourceData) error {
 		delete(swamp, apiKey)
 	}
 
+	saveBoolConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Printf("[ERROR]: Unable to convert %s to a bool: %v", apiKey, err)
+				return
+			}
+			jmxConfig[string(attrName)] = b
+		}
+
+		delete(swamp, apiKey)
+	}
+
 	saveIntConfigToState(config.Port, checkJMXPortAttr)
 	saveStringConfigToState(config.Username, checkJMXUsernameAttr)
 	saveStringConfigToState(config.Password, checkJMXPasswordAttr)
 	saveStringConfigToState(config.URI, checkJMXURIAttr)
+	saveBoolConfigToState(config.UseSSL, checkJMXUseSSLAttr)
+	jmxConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
 	jmxConfig[string(checkJMXHostAttr)] = c.Target
 
 	l := make([]interface{}, 0, 3)
@@ -211,11 +236,19 @@ func hashCheckJMX(v interface{}) int {
 		}
 	}
 
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
 	writeString(checkJMXPasswordAttr)
 	writeString(checkJMXUsernameAttr)
 	writeString(checkJMXURIAttr)
 	writeString(checkJMXHostAttr)
 	writeInt(checkJMXPortAttr)
+	writeBool(checkJMXUseSSLAttr)
+	writeCheckTLSHash(b, m)
 
 	list := m[string(checkJMXMBeanDomainsAttr)].([]interface{})
 	for _, s := range list {
@@ -290,6 +323,12 @@ func checkConfigToAPIJMX(c *circonusCheck, l interfaceList) error { //nolint:unp
 				c.Config[config.Key(fmt.Sprintf("mbean_properties_%s", n["index"].(string)))] = fmt.Sprintf("name=%s,type=%s", n["name"].(string), n["type"].(string))
 			}
 		}
+
+		if v, found := jmxConfig[checkJMXUseSSLAttr]; found {
+			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+		}
+
+		checkTLSConfigToAPI(c, jmxConfig)
 	}
 
 	return nil
@@ -5,8 +5,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckJMX_basic(t *testing.T) {
@@ -52,6 +54,55 @@ func TestAccCirconusCheckJMX_basic(t *testing.T) {
 	})
 }
 
+func TestCheckJMXConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkJMXAttr): []interface{}{
+			map[string]interface{}{
+				string(checkJMXHostAttr):     "10.0.0.5",
+				string(checkJMXPortAttr):     9999,
+				string(checkJMXUsernameAttr): "monitor",
+				string(checkJMXPasswordAttr): "hunter2",
+				string(checkJMXUseSSLAttr):   true,
+				string(checkTLSConfigAttr): []interface{}{
+					map[string]interface{}{
+						string(checkTLSCAChainAttr): "/etc/ssl/jmx-ca.pem",
+					},
+				},
+			},
+		},
+	})
+
+	jmxConfig := d.Get(string(checkJMXAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIJMX(&c, jmxConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.CAChain:  "/etc/ssl/jmx-ca.pem",
+		config.Password: "hunter2",
+		config.Port:     "9999",
+		config.UseSSL:   "true",
+		config.Username: "monitor",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	c.Target = "10.0.0.5"
+	if err := checkAPIToStateJMX(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkJMXAttr)).(*schema.Set).List()
+	if hashCheckJMX(state[0]) != hashCheckJMX(jmxConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+}
+
 const testAccCirconusCheckJMXConfigFmt = `
 variable "jmx_check_tags" {
   type = list(string)
@@ -0,0 +1,369 @@
+package circonus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// A jolokia check reads MBeans through a Jolokia agent's HTTP endpoint so the
+// broker doesn't need access to a raw JMX port.  The API has no jolokia check
+// type: the check is stored as a json check that POSTs a Jolokia bulk read
+// request, and is recognized as a jolokia check by that payload.
+
+const (
+	// circonus_check.jolokia.* resource attribute names.
+	checkJolokiaAuthMethodAttr   = "auth_method"
+	checkJolokiaAuthPasswordAttr = "auth_password"
+	checkJolokiaAuthUserAttr     = "auth_user"
+	checkJolokiaMBeanAttr        = "mbean"
+	checkJolokiaURLAttr          = "url"
+
+	// circonus_check.jolokia.mbean.* resource attribute names.
+	checkJolokiaMBeanAttributesAttr = "attributes"
+	checkJolokiaMBeanNameAttr       = "name"
+	checkJolokiaMBeanPathAttr       = "path"
+)
+
+const (
+	// apiJolokiaRequestTypeRead is the type of the Jolokia requests the check
+	// sends.
+	apiJolokiaRequestTypeRead = "read"
+
+	// apiJolokiaContentType is the Content-Type header of the check's request.
+	apiJolokiaContentType config.Key = config.HeaderPrefix + "Content-Type"
+)
+
+var checkJolokiaDescriptions = attrDescrs{
+	checkJolokiaAuthMethodAttr:   "The HTTP Authentication method",
+	checkJolokiaAuthPasswordAttr: "The HTTP Authentication user password",
+	checkJolokiaAuthUserAttr:     "The HTTP Authentication user name",
+	checkJolokiaMBeanAttr:        "An MBean to read",
+	checkJolokiaURLAttr:          "The URL of the Jolokia agent",
+	checkTLSConfigAttr:           checkTLSConfigDescription,
+}
+
+var checkJolokiaMBeanDescriptions = attrDescrs{
+	checkJolokiaMBeanAttributesAttr: "The attributes of the MBean to read, all attributes are read when omitted",
+	checkJolokiaMBeanNameAttr:       "The object name of the MBean, may contain wildcards",
+	checkJolokiaMBeanPathAttr:       "A path into the value of the attribute(s) to read",
+}
+
+var schemaCheckJolokia = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckJolokia,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkJolokiaDescriptions, map[schemaAttr]*schema.Schema{
+			checkJolokiaAuthMethodAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkJolokiaAuthMethodAttr, `^(?:Basic|Digest|Auto)$`),
+			},
+			checkJolokiaAuthPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkJolokiaAuthPasswordAttr, `^.*`),
+			},
+			checkJolokiaAuthUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkJolokiaAuthUserAttr, `[^:]+`),
+			},
+			checkJolokiaMBeanAttr: {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkJolokiaMBeanDescriptions, map[schemaAttr]*schema.Schema{
+						checkJolokiaMBeanAttributesAttr: {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateRegexp(checkJolokiaMBeanAttributesAttr, `.+`),
+							},
+						},
+						checkJolokiaMBeanNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkJolokiaMBeanNameAttr, `^[^:]+:.+$`),
+						},
+						checkJolokiaMBeanPathAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkJolokiaMBeanPathAttr, `.+`),
+						},
+					}),
+				},
+			},
+			checkJolokiaURLAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHTTPURL(checkJolokiaURLAttr, urlIsAbs),
+			},
+			checkTLSConfigAttr: schemaCheckTLS,
+		}),
+	},
+}
+
+// jolokiaRequest is a single request of a Jolokia bulk read.  Attribute is a
+// string or a list of strings.
+type jolokiaRequest struct {
+	Type      string      `json:"type"`
+	MBean     string      `json:"mbean"`
+	Attribute interface{} `json:"attribute,omitempty"`
+	Path      string      `json:"path,omitempty"`
+}
+
+// parseJolokiaPayload decodes the payload of a jolokia check.  ok is false if
+// the payload is not a Jolokia bulk read request.
+func parseJolokiaPayload(payload string) (requests []jolokiaRequest, ok bool) {
+	if err := json.Unmarshal([]byte(payload), &requests); err != nil || len(requests) == 0 {
+		return nil, false
+	}
+
+	for _, r := range requests {
+		if r.Type != apiJolokiaRequestTypeRead || r.MBean == "" {
+			return nil, false
+		}
+
+		switch a := r.Attribute.(type) {
+		case nil, string:
+		case []interface{}:
+			for _, v := range a {
+				if _, ok := v.(string); !ok {
+					return nil, false
+				}
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	return requests, true
+}
+
+// isJolokiaCheck returns true if the json check c was created from a jolokia
+// block.
+func isJolokiaCheck(c *circonusCheck) bool {
+	if apiCheckType(c.Type) != apiCheckTypeJSONAttr || c.Config[config.Method] != http.MethodPost {
+		return false
+	}
+
+	_, ok := parseJolokiaPayload(c.Config[config.Payload])
+	return ok
+}
+
+// checkAPIToStateJolokia reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateJolokia(c *circonusCheck, d *schema.ResourceData) error {
+	jolokiaConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok && v != "" {
+			jolokiaConfig[string(attrName)] = v
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.AuthMethod, checkJolokiaAuthMethodAttr)
+	saveStringConfigToState(config.AuthPassword, checkJolokiaAuthPasswordAttr)
+	saveStringConfigToState(config.AuthUser, checkJolokiaAuthUserAttr)
+	saveStringConfigToState(config.URL, checkJolokiaURLAttr)
+	jolokiaConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
+
+	requests, ok := parseJolokiaPayload(c.Config[config.Payload])
+	if !ok {
+		return fmt.Errorf("unable to parse the Jolokia request %q", c.Config[config.Payload])
+	}
+	delete(swamp, config.Payload)
+
+	mbeans := make([]interface{}, 0, len(requests))
+	for _, r := range requests {
+		mbean := map[string]interface{}{
+			string(checkJolokiaMBeanNameAttr): r.MBean,
+		}
+
+		var attributes []interface{}
+		switch a := r.Attribute.(type) {
+		case string:
+			attributes = []interface{}{a}
+		case []interface{}:
+			attributes = a
+		}
+		if len(attributes) > 0 {
+			mbean[string(checkJolokiaMBeanAttributesAttr)] = attributes
+		}
+
+		if r.Path != "" {
+			mbean[string(checkJolokiaMBeanPathAttr)] = r.Path
+		}
+
+		mbeans = append(mbeans, mbean)
+	}
+	jolokiaConfig[string(checkJolokiaMBeanAttr)] = mbeans
+
+	// Keys derived from the schema by checkConfigToAPIJolokia or defaulted by
+	// the API.
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		apiJolokiaContentType:   {},
+		config.HTTPVersion:      {},
+		config.Method:           {},
+		config.Port:             {},
+		config.ReadLimit:        {},
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkJolokiaAttr, schema.NewSet(hashCheckJolokia, []interface{}{jolokiaConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkJolokiaAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckJolokia creates a stable hash of the normalized values.
+func hashCheckJolokia(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeString := func(m map[string]interface{}, attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(m, checkJolokiaAuthMethodAttr)
+	writeString(m, checkJolokiaAuthPasswordAttr)
+	writeString(m, checkJolokiaAuthUserAttr)
+
+	if mbeansRaw, ok := m[string(checkJolokiaMBeanAttr)]; ok {
+		for _, mbeanRaw := range mbeansRaw.([]interface{}) {
+			mbean, ok := mbeanRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if attributesRaw, ok := mbean[string(checkJolokiaMBeanAttributesAttr)]; ok {
+				for _, attribute := range attributesRaw.([]interface{}) {
+					fmt.Fprint(b, attribute)
+				}
+			}
+			writeString(mbean, checkJolokiaMBeanNameAttr)
+			writeString(mbean, checkJolokiaMBeanPathAttr)
+		}
+	}
+
+	writeString(m, checkJolokiaURLAttr)
+	writeCheckTLSHash(b, m)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIJolokia(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeJSON)
+
+	// Iterate over all `jolokia` attributes, even though we have a max of 1 in
+	// the schema.
+	for _, mapRaw := range l {
+		jolokiaConfig := newInterfaceMap(mapRaw)
+
+		if v, found := jolokiaConfig[checkJolokiaAuthMethodAttr]; found && v.(string) != "" {
+			c.Config[config.AuthMethod] = v.(string)
+		}
+
+		if v, found := jolokiaConfig[checkJolokiaAuthPasswordAttr]; found && v.(string) != "" {
+			c.Config[config.AuthPassword] = v.(string)
+		}
+
+		if v, found := jolokiaConfig[checkJolokiaAuthUserAttr]; found && v.(string) != "" {
+			c.Config[config.AuthUser] = v.(string)
+		}
+
+		mbeans, _ := jolokiaConfig[string(checkJolokiaMBeanAttr)].([]interface{})
+		requests := make([]jolokiaRequest, 0, len(mbeans))
+		for _, mbeanRaw := range mbeans {
+			mbean := newInterfaceMap(mbeanRaw)
+
+			r := jolokiaRequest{
+				Type:  apiJolokiaRequestTypeRead,
+				MBean: mbean[string(checkJolokiaMBeanNameAttr)].(string),
+			}
+
+			if v, found := mbean[string(checkJolokiaMBeanAttributesAttr)]; found {
+				if attributes := interfaceList(v.([]interface{})).List(); len(attributes) > 0 {
+					r.Attribute = attributes
+				}
+			}
+
+			if v, found := mbean[string(checkJolokiaMBeanPathAttr)]; found {
+				r.Path = v.(string)
+			}
+
+			requests = append(requests, r)
+		}
+
+		payload, err := json.Marshal(requests)
+		if err != nil {
+			return fmt.Errorf("unable to encode the Jolokia request: %w", err)
+		}
+
+		c.Config[config.Method] = http.MethodPost
+		c.Config[config.Payload] = string(payload)
+		c.Config[apiJolokiaContentType] = "application/json"
+
+		checkTLSConfigToAPI(c, jolokiaConfig)
+
+		if v, found := jolokiaConfig[checkJolokiaURLAttr]; found {
+			c.Config[config.URL] = v.(string)
+
+			u, err := url.Parse(v.(string))
+			if err != nil {
+				return fmt.Errorf("unable to parse %s %q: %w", checkJolokiaURLAttr, v.(string), err)
+			}
+
+			if len(c.Target) == 0 {
+				c.Target = u.Hostname()
+			}
+
+			if u.Port() != "" {
+				c.Config[config.Port] = u.Port()
+			}
+		}
+	}
+
+	return nil
+}
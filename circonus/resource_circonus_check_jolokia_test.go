@@ -0,0 +1,167 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccCirconusCheckJolokia_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Jolokia check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckJolokiaConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.jolokia", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.url", "http://app1.example.org:8778/jolokia/"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.mbean.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.mbean.0.name", "java.lang:type=Memory"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.mbean.0.attributes.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.mbean.0.path", "used"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "jolokia.0.mbean.1.name", "java.lang:type=Threading"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "target", "app1.example.org"),
+					resource.TestCheckResourceAttr("circonus_check.jolokia", "type", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckJolokiaConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkJolokiaAttr): []interface{}{
+			map[string]interface{}{
+				string(checkJolokiaURLAttr):      "https://app1.example.org:8778/jolokia/",
+				string(checkJolokiaAuthUserAttr): "monitor",
+				string(checkJolokiaMBeanAttr): []interface{}{
+					map[string]interface{}{
+						string(checkJolokiaMBeanNameAttr):       "java.lang:type=Memory",
+						string(checkJolokiaMBeanAttributesAttr): []interface{}{"HeapMemoryUsage", "NonHeapMemoryUsage"},
+						string(checkJolokiaMBeanPathAttr):       "used",
+					},
+					map[string]interface{}{
+						string(checkJolokiaMBeanNameAttr): "java.lang:type=Threading",
+					},
+				},
+			},
+		},
+	})
+
+	jolokiaConfig := d.Get(string(checkJolokiaAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIJolokia(&c, jolokiaConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.AuthUser:       "monitor",
+		config.Method:         "POST",
+		config.Payload:        `[{"type":"read","mbean":"java.lang:type=Memory","attribute":["HeapMemoryUsage","NonHeapMemoryUsage"],"path":"used"},{"type":"read","mbean":"java.lang:type=Threading"}]`,
+		config.Port:           "8778",
+		config.URL:            "https://app1.example.org:8778/jolokia/",
+		apiJolokiaContentType: "application/json",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if c.Type != string(apiCheckTypeJSON) || c.Target != "app1.example.org" {
+		t.Errorf("unexpected type %q or target %q", c.Type, c.Target)
+	}
+
+	if !isJolokiaCheck(&c) {
+		t.Fatalf("expected a jolokia check")
+	}
+
+	if err := parseCheckTypeConfig(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkJolokiaAttr)).(*schema.Set).List()
+	if len(state) != 1 || hashCheckJolokia(state[0]) != hashCheckJolokia(jolokiaConfig[0]) {
+		t.Errorf("expected the state to match the config, got %#v", state)
+	}
+}
+
+func TestIsJolokiaCheck(t *testing.T) {
+	tests := []struct {
+		method  string
+		payload string
+		want    bool
+	}{
+		{"POST", `[{"type":"read","mbean":"java.lang:type=Memory","attribute":"HeapMemoryUsage"}]`, true},
+		{"GET", `[{"type":"read","mbean":"java.lang:type=Memory"}]`, false},
+		{"POST", `{"type":"read","mbean":"java.lang:type=Memory"}`, false},
+		{"POST", `[{"type":"exec","mbean":"java.lang:type=Memory"}]`, false},
+		{"POST", `[{"type":"read","mbean":"java.lang:type=Memory","attribute":[1]}]`, false},
+		{"POST", `[]`, false},
+		{"POST", `not json`, false},
+	}
+
+	for _, test := range tests {
+		c := newCheck()
+		c.Type = string(apiCheckTypeJSON)
+		c.Config[config.Method] = test.method
+		c.Config[config.Payload] = test.payload
+
+		if got := isJolokiaCheck(&c); got != test.want {
+			t.Errorf("%s %s: expected %t, got %t", test.method, test.payload, test.want, got)
+		}
+	}
+}
+
+const testAccCirconusCheckJolokiaConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "jolokia" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  jolokia {
+    url = "http://app1.example.org:8778/jolokia/"
+
+    mbean {
+      name = "java.lang:type=Memory"
+      attributes = [ "HeapMemoryUsage" ]
+      path = "used"
+    }
+
+    mbean {
+      name = "java.lang:type=Threading"
+    }
+  }
+
+  metric {
+    name = "0` + "`" + `value"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+}
+`
@@ -22,6 +22,8 @@ const (
 	checkJSONCAChainAttr      = "ca_chain"
 	checkJSONCertFileAttr     = "certificate_file"
 	checkJSONCiphersAttr      = "ciphers"
+	checkJSONExtractAttr      = "extract"
+	checkJSONHeaderAttr       = "header"
 	checkJSONHeadersAttr      = "headers"
 	checkJSONKeyFileAttr      = "key_file"
 	checkJSONMethodAttr       = "method"
@@ -30,6 +32,21 @@ const (
 	checkJSONReadLimitAttr    = "read_limit"
 	checkJSONURLAttr          = "url"
 	checkJSONVersionAttr      = "version"
+
+	// circonus_check.json.extract.* resource attribute names.
+	checkJSONExtractNameAttr = "name"
+	checkJSONExtractPathAttr = "path"
+
+	// circonus_check.json.header.* resource attribute names.
+	checkJSONHeaderNameAttr  = "name"
+	checkJSONHeaderValueAttr = "value"
+)
+
+const (
+	// checkJSONExtractPathKeyFmt and checkJSONExtractNameKeyFmt are the formats
+	// of the config keys of the extract blocks, numbered from 1.
+	checkJSONExtractPathKeyFmt = "extract_%d_path"
+	checkJSONExtractNameKeyFmt = "extract_%d_name"
 )
 
 var checkJSONDescriptions = attrDescrs{
@@ -39,14 +56,32 @@ var checkJSONDescriptions = attrDescrs{
 	checkJSONCAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks)",
 	checkJSONCertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS-enabled checks)",
 	checkJSONCiphersAttr:      "A list of ciphers to be used in the TLS protocol (for HTTPS checks)",
+	checkJSONExtractAttr:      "JSONPath-style expressions selecting the values of the document to extract as metrics",
+	checkJSONHeaderAttr:       "An HTTP header to send along with HTTP Requests, repeated headers are sent as a list of values",
 	checkJSONHeadersAttr:      "Map of HTTP Headers to send along with HTTP Requests",
 	checkJSONKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks)",
 	checkJSONMethodAttr:       "The HTTP method to use",
 	checkJSONPayloadAttr:      "The information transferred as the payload of an HTTP request",
-	checkJSONPortAttr:         "Specifies the port on which the management interface can be reached",
+	checkJSONPortAttr:         "Specifies the port on which the management interface can be reached, overriding the port of the URL",
 	checkJSONReadLimitAttr:    "Sets an approximate limit on the data read (0 means no limit)",
 	checkJSONURLAttr:          "The URL to use as the target of the check",
 	checkJSONVersionAttr:      "Sets the HTTP version for the check to use",
+	checkTLSConfigAttr:        checkTLSConfigDescription,
+}
+
+// checkJSONPathRegexp matches the JSONPath-style expressions of the extract
+// blocks: a $ followed by member names and array indexes, either of which may
+// be a * wildcard.
+const checkJSONPathRegexp = `^\$(\.[^.\[\]\s]+|\[(\d+|\*)\])+$`
+
+var checkJSONExtractDescriptions = attrDescrs{
+	checkJSONExtractNameAttr: "The metric name to publish the extracted values under, defaults to the name of the path",
+	checkJSONExtractPathAttr: "A JSONPath-style expression, e.g. $.queues[*].depth",
+}
+
+var checkJSONHeaderDescriptions = attrDescrs{
+	checkJSONHeaderNameAttr:  "The name of the HTTP header",
+	checkJSONHeaderValueAttr: "A value of the HTTP header",
 }
 
 var schemaCheckJSON = &schema.Schema{
@@ -76,18 +111,57 @@ var schemaCheckJSON = &schema.Schema{
 			checkJSONCAChainAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkJSONCAChainAttr, `.+`),
 			},
 			checkJSONCertFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkJSONCertFileAttr, `.+`),
 			},
 			checkJSONCiphersAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkJSONCiphersAttr, `.+`),
 			},
+			checkJSONExtractAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkJSONExtractDescriptions, map[schemaAttr]*schema.Schema{
+						checkJSONExtractNameAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkJSONExtractNameAttr, `^\S+$`),
+						},
+						checkJSONExtractPathAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkJSONExtractPathAttr, checkJSONPathRegexp),
+						},
+					}),
+				},
+			},
+			checkJSONHeaderAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkJSONHeaderDescriptions, map[schemaAttr]*schema.Schema{
+						checkJSONHeaderNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkJSONHeaderNameAttr, `^[^\s:]+$`),
+						},
+						checkJSONHeaderValueAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkJSONHeaderValueAttr, `^[^,]+$`),
+						},
+					}),
+				},
+			},
 			checkJSONHeadersAttr: {
 				Type:         schema.TypeMap,
 				Elem:         schema.TypeString,
@@ -97,6 +171,7 @@ var schemaCheckJSON = &schema.Schema{
 			checkJSONKeyFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkJSONKeyFileAttr, `.+`),
 			},
 			checkJSONMethodAttr: {
@@ -112,8 +187,8 @@ var schemaCheckJSON = &schema.Schema{
 			},
 			checkJSONPortAttr: {
 				Type:     schema.TypeInt,
-				Default:  defaultCheckJSONPort,
 				Optional: true,
+				Computed: true,
 				ValidateFunc: validateFuncs(
 					validateIntMin(checkJSONPortAttr, 0),
 					validateIntMax(checkJSONPortAttr, 65535),
@@ -139,6 +214,7 @@ var schemaCheckJSON = &schema.Schema{
 				Default:      defaultCheckJSONVersion,
 				ValidateFunc: validateStringIn(checkJSONVersionAttr, supportedHTTPVersions),
 			},
+			checkTLSConfigAttr: schemaCheckTLS,
 		}),
 	},
 }
@@ -178,11 +254,18 @@ func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
 	saveStringConfigToState(config.AuthMethod, checkJSONAuthMethodAttr)
 	saveStringConfigToState(config.AuthPassword, checkJSONAuthPasswordAttr)
 	saveStringConfigToState(config.AuthUser, checkJSONAuthUserAttr)
-	saveStringConfigToState(config.CAChain, checkJSONCAChainAttr)
-	saveStringConfigToState(config.CertFile, checkJSONCertFileAttr)
-	saveStringConfigToState(config.Ciphers, checkJSONCiphersAttr)
+	if checkTLSLegacyInUse(d, checkJSONAttr) {
+		saveStringConfigToState(config.CAChain, checkJSONCAChainAttr)
+		saveStringConfigToState(config.CertFile, checkJSONCertFileAttr)
+		saveStringConfigToState(config.Ciphers, checkJSONCiphersAttr)
+		saveStringConfigToState(config.KeyFile, checkJSONKeyFileAttr)
+	} else {
+		jsonConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
+	}
 
+	headerListNames := checkJSONHeaderListNamesState(d)
 	headers := make(map[string]interface{}, len(c.Config))
+	headerList := make(map[string]string)
 	headerPrefixLen := len(config.HeaderPrefix)
 	for k, v := range c.Config {
 		if len(k) <= headerPrefixLen {
@@ -190,17 +273,25 @@ func checkAPIToStateJSON(c *circonusCheck, d *schema.ResourceData) error {
 		}
 
 		if strings.Compare(string(k[:headerPrefixLen]), string(config.HeaderPrefix)) == 0 {
-			key := k[headerPrefixLen:]
-			headers[string(key)] = v
+			key := string(k[headerPrefixLen:])
+			if _, found := headerListNames[key]; found {
+				headerList[key] = v
+			} else {
+				headers[key] = v
+			}
 		}
 		delete(swamp, k)
 	}
 	jsonConfig[string(checkJSONHeadersAttr)] = headers
+	jsonConfig[string(checkJSONHeaderAttr)] = checkJSONHeaderListToState(headerList)
+	jsonConfig[string(checkJSONExtractAttr)] = checkJSONExtractToState(c, swamp)
 
-	saveStringConfigToState(config.KeyFile, checkJSONKeyFileAttr)
 	saveStringConfigToState(config.Method, checkJSONMethodAttr)
 	saveStringConfigToState(config.Payload, checkJSONPayloadAttr)
 	saveIntConfigToState(config.Port, checkJSONPortAttr)
+	if _, found := jsonConfig[string(checkJSONPortAttr)]; !found {
+		jsonConfig[string(checkJSONPortAttr)] = checkJSONURLPort(c.Config[config.URL])
+	}
 	saveIntConfigToState(config.ReadLimit, checkJSONReadLimitAttr)
 	saveStringConfigToState(config.URL, checkJSONURLAttr)
 	saveStringConfigToState(config.HTTPVersion, checkJSONVersionAttr)
@@ -255,6 +346,40 @@ func checkJSONConfigChecksum(v interface{}) int {
 	writeString(checkJSONCertFileAttr)
 	writeString(checkJSONCiphersAttr)
 
+	if l, ok := m[string(checkJSONExtractAttr)].([]interface{}); ok {
+		for i, extractRaw := range l {
+			extract, ok := extractRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			fmt.Fprint(b, checkJSONExtractAttr, i)
+			for _, attrName := range []schemaAttr{checkJSONExtractNameAttr, checkJSONExtractPathAttr} {
+				if v, ok := extract[string(attrName)].(string); ok && v != "" {
+					fmt.Fprint(b, attrName, v)
+				}
+			}
+		}
+	}
+
+	// The header blocks are read back grouped by name, they hash the same in
+	// any order that keeps the values of each name in order.
+	if l, ok := m[string(checkJSONHeaderAttr)].([]interface{}); ok {
+		headerList := checkJSONHeaderList(l)
+		names := make([]string, 0, len(headerList))
+		for name := range headerList {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprint(b, checkJSONHeaderAttr, name)
+			for _, v := range headerList[name] {
+				fmt.Fprint(b, v)
+			}
+		}
+	}
+
 	if headersRaw, ok := m[string(checkJSONHeadersAttr)]; ok {
 		headerMap := headersRaw.(map[string]interface{})
 		headers := make([]string, 0, len(headerMap))
@@ -272,22 +397,32 @@ func checkJSONConfigChecksum(v interface{}) int {
 	writeString(checkJSONKeyFileAttr)
 	writeString(checkJSONMethodAttr)
 	writeString(checkJSONPayloadAttr)
-	writeInt(checkJSONPortAttr)
+	// The port is computed from the URL when not set, it only affects the
+	// hash when it overrides the port of the URL.
+	if v, ok := m[string(checkJSONPortAttr)].(int); ok && v != 0 {
+		if u, ok := m[string(checkJSONURLAttr)].(string); !ok || v != checkJSONURLPort(u) {
+			fmt.Fprintf(b, "%x", v)
+		}
+	}
 	writeInt(checkJSONReadLimitAttr)
 	writeString(checkJSONURLAttr)
 	writeString(checkJSONVersionAttr)
+	writeCheckTLSHash(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeJSON)
 
 	// Iterate over all `json` attributes, even though we have a max of 1 in the
 	// schema.
 	for _, mapRaw := range l {
 		jsonConfig := newInterfaceMap(mapRaw)
+		if err := checkTLSConflict(checkJSONAttr, jsonConfig); err != nil {
+			return err
+		}
 
 		if v, found := jsonConfig[checkJSONAuthMethodAttr]; found {
 			c.Config[config.AuthMethod] = v.(string)
@@ -318,10 +453,34 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 			c.Config[h] = v
 		}
 
+		if v, found := jsonConfig[checkJSONHeaderAttr]; found {
+			for name, values := range checkJSONHeaderList(v.([]interface{})) {
+				h := config.HeaderPrefix + config.Key(name)
+				if _, found := c.Config[h]; found {
+					return fmt.Errorf("%s: %s %q is also set in %s", checkJSONAttr, checkJSONHeaderAttr, name, checkJSONHeadersAttr)
+				}
+				c.Config[h] = strings.Join(values, checkJSONHeaderValueSeparator)
+			}
+		}
+
+		if v, found := jsonConfig[checkJSONExtractAttr]; found {
+			for i, extractRaw := range v.([]interface{}) {
+				extract := newInterfaceMap(extractRaw)
+				if path, ok := extract[checkJSONExtractPathAttr].(string); ok && path != "" {
+					c.Config[config.Key(fmt.Sprintf(checkJSONExtractPathKeyFmt, i+1))] = path
+				}
+				if name, ok := extract[checkJSONExtractNameAttr].(string); ok && name != "" {
+					c.Config[config.Key(fmt.Sprintf(checkJSONExtractNameKeyFmt, i+1))] = name
+				}
+			}
+		}
+
 		if v, found := jsonConfig[checkJSONKeyFileAttr]; found {
 			c.Config[config.KeyFile] = v.(string)
 		}
 
+		checkTLSConfigToAPI(c, jsonConfig)
+
 		if v, found := jsonConfig[checkJSONMethodAttr]; found {
 			c.Config[config.Method] = v.(string)
 		}
@@ -330,6 +489,8 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 			c.Config[config.Payload] = v.(string)
 		}
 
+		// The port overrides the port of the URL, which defaults to the port
+		// of its scheme.
 		if v, found := jsonConfig[checkJSONPortAttr]; found {
 			i := v.(int)
 			if i != 0 {
@@ -353,8 +514,10 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 				c.Target = hostInfo[0]
 			}
 
-			if len(hostInfo) > 1 && c.Config[config.Port] == "" {
-				c.Config[config.Port] = hostInfo[1]
+			if c.Config[config.Port] == "" {
+				if port := checkJSONURLPort(v.(string)); port != 0 {
+					c.Config[config.Port] = strconv.Itoa(port)
+				}
 			}
 		}
 
@@ -365,3 +528,118 @@ func checkConfigToAPIJSON(c *circonusCheck, l interfaceList) error { //nolint:un
 
 	return nil
 }
+
+// checkJSONHeaderValueSeparator joins the values of repeated header blocks,
+// the list syntax of HTTP header values.
+const checkJSONHeaderValueSeparator = ", "
+
+// checkJSONHeaderList groups the values of the header blocks l by name,
+// keeping the order of the values of each name.
+func checkJSONHeaderList(l []interface{}) map[string][]string {
+	headerList := make(map[string][]string)
+	for _, headerRaw := range l {
+		header := newInterfaceMap(headerRaw)
+		name, _ := header[checkJSONHeaderNameAttr].(string)
+		value, _ := header[checkJSONHeaderValueAttr].(string)
+		if name == "" {
+			continue
+		}
+
+		headerList[name] = append(headerList[name], value)
+	}
+
+	return headerList
+}
+
+// checkJSONHeaderListNamesState returns the names of the header blocks of the
+// json block of d.  The headers of the check with another name are read into
+// the headers map.
+func checkJSONHeaderListNamesState(d *schema.ResourceData) map[string]struct{} {
+	names := make(map[string]struct{})
+	s, ok := d.Get(checkJSONAttr).(*schema.Set)
+	if !ok || s.Len() == 0 {
+		return names
+	}
+
+	m, ok := s.List()[0].(map[string]interface{})
+	if !ok {
+		return names
+	}
+
+	l, _ := m[string(checkJSONHeaderAttr)].([]interface{})
+	for name := range checkJSONHeaderList(l) {
+		names[name] = struct{}{}
+	}
+
+	return names
+}
+
+// checkJSONHeaderListToState splits the headers of headerList into header
+// blocks, sorted by name.
+func checkJSONHeaderListToState(headerList map[string]string) []interface{} {
+	names := make([]string, 0, len(headerList))
+	for name := range headerList {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	l := make([]interface{}, 0, len(headerList))
+	for _, name := range names {
+		for _, v := range strings.Split(headerList[name], ",") {
+			l = append(l, map[string]interface{}{
+				string(checkJSONHeaderNameAttr):  name,
+				string(checkJSONHeaderValueAttr): strings.TrimSpace(v),
+			})
+		}
+	}
+
+	return l
+}
+
+// checkJSONExtractToState returns the extract blocks found in the check's
+// config, removing them from swamp.  The blocks are read until the first
+// number without a path.
+func checkJSONExtractToState(c *circonusCheck, swamp map[config.Key]string) []interface{} {
+	extracts := make([]interface{}, 0)
+	for i := 1; ; i++ {
+		pathKey := config.Key(fmt.Sprintf(checkJSONExtractPathKeyFmt, i))
+		nameKey := config.Key(fmt.Sprintf(checkJSONExtractNameKeyFmt, i))
+		path, found := c.Config[pathKey]
+		if !found {
+			return extracts
+		}
+
+		extracts = append(extracts, map[string]interface{}{
+			string(checkJSONExtractPathAttr): path,
+			string(checkJSONExtractNameAttr): c.Config[nameKey],
+		})
+		delete(swamp, pathKey)
+		delete(swamp, nameKey)
+	}
+}
+
+// checkJSONURLPort returns the port of rawURL, or the default port of its
+// scheme.  Zero is returned when neither is known.
+func checkJSONURLPort(rawURL string) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+
+	if p := u.Port(); p != "" {
+		i, err := strconv.Atoi(p)
+		if err != nil {
+			return 0
+		}
+		return i
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		return 80
+	case "https":
+		return 443
+	default:
+		return 0
+	}
+}
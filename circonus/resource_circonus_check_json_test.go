@@ -6,6 +6,7 @@ import (
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckJSON_basic(t *testing.T) {
@@ -81,6 +82,111 @@ func TestAccCirconusCheckJSON_basic(t *testing.T) {
 	})
 }
 
+func TestCheckJSONConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkJSONAttr): []interface{}{
+			map[string]interface{}{
+				string(checkJSONURLAttr): "https://app1.example.org:8443/status",
+				string(checkJSONHeadersAttr): map[string]interface{}{
+					"X-Token": "abc",
+				},
+				string(checkJSONHeaderAttr): []interface{}{
+					map[string]interface{}{
+						string(checkJSONHeaderNameAttr):  "Accept",
+						string(checkJSONHeaderValueAttr): "application/json",
+					},
+					map[string]interface{}{
+						string(checkJSONHeaderNameAttr):  "Cache-Control",
+						string(checkJSONHeaderValueAttr): "no-cache",
+					},
+					map[string]interface{}{
+						string(checkJSONHeaderNameAttr):  "Accept",
+						string(checkJSONHeaderValueAttr): "text/plain",
+					},
+				},
+				string(checkJSONExtractAttr): []interface{}{
+					map[string]interface{}{
+						string(checkJSONExtractPathAttr): "$.queues[*].depth",
+						string(checkJSONExtractNameAttr): "queue_depth",
+					},
+					map[string]interface{}{
+						string(checkJSONExtractPathAttr): "$.db.connections",
+					},
+				},
+			},
+		},
+	})
+
+	jsonConfig := d.Get(string(checkJSONAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIJSON(&c, jsonConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.HeaderPrefix + "Accept":        "application/json, text/plain",
+		config.HeaderPrefix + "Cache-Control": "no-cache",
+		config.HeaderPrefix + "X-Token":       "abc",
+		config.Port:                           "8443",
+		"extract_1_path":                      "$.queues[*].depth",
+		"extract_1_name":                      "queue_depth",
+		"extract_2_path":                      "$.db.connections",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+	if _, found := c.Config["extract_2_name"]; found {
+		t.Errorf("expected no name for the second extract, got %q", c.Config["extract_2_name"])
+	}
+
+	if err := checkAPIToStateJSON(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkJSONAttr)).(*schema.Set).List()
+	if checkJSONConfigChecksum(state[0]) != checkJSONConfigChecksum(jsonConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+	if port := state[0].(map[string]interface{})[string(checkJSONPortAttr)]; port != 8443 {
+		t.Errorf("expected the port of the URL, got %v", port)
+	}
+
+	c = newCheck()
+	jsonConfig[0].(map[string]interface{})[string(checkJSONPortAttr)] = 9443
+	if err := checkConfigToAPIJSON(&c, jsonConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Config[config.Port] != "9443" {
+		t.Errorf("expected the port to override the port of the URL, got %q", c.Config[config.Port])
+	}
+
+	c = newCheck()
+	jsonConfig[0].(map[string]interface{})[string(checkJSONHeadersAttr)] = map[string]interface{}{
+		"Accept": "*/*",
+	}
+	if err := checkConfigToAPIJSON(&c, jsonConfig); err == nil {
+		t.Errorf("expected an error for a header set in both headers and header")
+	}
+}
+
+func TestCheckJSONURLPort(t *testing.T) {
+	tests := map[string]int{
+		"http://app1.example.org/healthz":       80,
+		"https://app1.example.org/healthz":      443,
+		"https://app1.example.org:8443/healthz": 8443,
+		"ftp://app1.example.org/":               0,
+	}
+
+	for u, expected := range tests {
+		if port := checkJSONURLPort(u); port != expected {
+			t.Errorf("%s: expected %d, got %d", u, expected, port)
+		}
+	}
+}
+
 const testAccCirconusCheckJSONConfig1 = `
 
 resource "circonus_metric" "limit" {
@@ -0,0 +1,242 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.ldap.* resource attribute names.
+	checkLDAPBindDNAttr     = "bind_dn"
+	checkLDAPFilterAttr     = "filter"
+	checkLDAPPasswordAttr   = "password"
+	checkLDAPPortAttr       = "port"
+	checkLDAPSearchBaseAttr = "search_base"
+	checkLDAPUseSSLAttr     = "use_ssl"
+)
+
+const (
+	// apiLDAPFilter is not one of the API client's config keys.
+	apiLDAPFilter config.Key = "filter"
+
+	// apiLDAPAuthTypeSimple is the authtype used when binding with a DN.
+	apiLDAPAuthTypeSimple = "simple"
+)
+
+var checkLDAPDescriptions = attrDescrs{
+	checkLDAPBindDNAttr:     "The DN to bind as, an anonymous bind is made when omitted",
+	checkLDAPFilterAttr:     "The LDAP search filter to run under the search base",
+	checkLDAPPasswordAttr:   "The password of the bind DN",
+	checkLDAPPortAttr:       "Specifies the port on which the directory server can be reached",
+	checkLDAPSearchBaseAttr: "The DN of the entry the search starts from",
+	checkLDAPUseSSLAttr:     "Connect to the directory server using TLS (LDAPS)",
+	checkTLSConfigAttr:      checkTLSConfigDescription,
+}
+
+var schemaCheckLDAP = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckLDAP,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkLDAPDescriptions, map[schemaAttr]*schema.Schema{
+			checkLDAPBindDNAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPBindDNAttr, `.+=.+`),
+			},
+			checkLDAPFilterAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPFilterAttr, `^\(.+\)$`),
+			},
+			checkLDAPPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkLDAPPasswordAttr, `.+`),
+			},
+			checkLDAPPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  389,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkLDAPPortAttr, 1),
+					validateIntMax(checkLDAPPortAttr, 65535),
+				),
+			},
+			checkLDAPSearchBaseAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkLDAPSearchBaseAttr, `.+=.+`),
+			},
+			checkLDAPUseSSLAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkTLSConfigAttr: schemaCheckTLS,
+		}),
+	},
+}
+
+// checkAPIToStateLDAP reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateLDAP(c *circonusCheck, d *schema.ResourceData) error {
+	ldapConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveBoolConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Printf("[ERROR]: Unable to convert %s to a bool: %v", apiKey, err)
+				return
+			}
+			ldapConfig[string(attrName)] = b
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveIntConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				return
+			}
+			ldapConfig[string(attrName)] = int(i)
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok && v != "" {
+			ldapConfig[string(attrName)] = v
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.SecurityPrincipal, checkLDAPBindDNAttr)
+	saveStringConfigToState(apiLDAPFilter, checkLDAPFilterAttr)
+	saveStringConfigToState(config.Password, checkLDAPPasswordAttr)
+	saveIntConfigToState(config.Port, checkLDAPPortAttr)
+	saveStringConfigToState(config.DN, checkLDAPSearchBaseAttr)
+	saveBoolConfigToState(config.UseSSL, checkLDAPUseSSLAttr)
+	ldapConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.AuthType:         {},
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkLDAPAttr, schema.NewSet(hashCheckLDAP, []interface{}{ldapConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkLDAPAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckLDAP creates a stable hash of the normalized values.
+func hashCheckLDAP(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkLDAPBindDNAttr)
+	writeString(checkLDAPFilterAttr)
+	writeString(checkLDAPPasswordAttr)
+	writeInt(checkLDAPPortAttr)
+	writeString(checkLDAPSearchBaseAttr)
+	writeBool(checkLDAPUseSSLAttr)
+	writeCheckTLSHash(b, m)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPILDAP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeLDAP)
+
+	// Iterate over all `ldap` attributes, even though we have a max of 1 in the
+	// schema.
+	for _, mapRaw := range l {
+		ldapConfig := newInterfaceMap(mapRaw)
+
+		if v, found := ldapConfig[checkLDAPBindDNAttr]; found && v.(string) != "" {
+			c.Config[config.SecurityPrincipal] = v.(string)
+			c.Config[config.AuthType] = apiLDAPAuthTypeSimple
+		}
+
+		if v, found := ldapConfig[checkLDAPFilterAttr]; found && v.(string) != "" {
+			c.Config[apiLDAPFilter] = v.(string)
+		}
+
+		if v, found := ldapConfig[checkLDAPPasswordAttr]; found && v.(string) != "" {
+			c.Config[config.Password] = v.(string)
+		}
+
+		if v, found := ldapConfig[checkLDAPPortAttr]; found {
+			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
+		}
+
+		if v, found := ldapConfig[checkLDAPSearchBaseAttr]; found && v.(string) != "" {
+			c.Config[config.DN] = v.(string)
+		}
+
+		if v, found := ldapConfig[checkLDAPUseSSLAttr]; found {
+			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+		}
+
+		checkTLSConfigToAPI(c, ldapConfig)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckLDAP_basic(t *testing.T) {
+	checkName := fmt.Sprintf("LDAP check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckLDAPConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.directory", "active", "true"),
+					resource.TestCheckNoResourceAttr("circonus_check.directory", "check_id"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.directory", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.directory", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.0.bind_dn", "cn=monitor,dc=example,dc=org"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.0.filter", "(objectClass=person)"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.0.port", "636"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.0.search_base", "ou=people,dc=example,dc=org"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "ldap.0.use_ssl", "true"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.directory", "period", "60s"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "target", "ldap1.example.org"),
+					resource.TestCheckResourceAttr("circonus_check.directory", "type", "ldap"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckLDAPConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "directory" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  ldap {
+    bind_dn = "cn=monitor,dc=example,dc=org"
+    password = "secret"
+    search_base = "ou=people,dc=example,dc=org"
+    filter = "(objectClass=person)"
+    port = 636
+    use_ssl = true
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  metric {
+    name = "entries"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+  target = "ldap1.example.org"
+}
+`
@@ -30,7 +30,7 @@ var schemaCheckMemcached = &schema.Schema{
 			checkMemcachedPortAttr: {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  11211,
+				Default:  defaultCheckMemcachedPort,
 				ValidateFunc: validateFuncs(
 					validateIntMin(checkMemcachedPortAttr, 1),
 					validateIntMax(checkMemcachedPortAttr, 65535),
@@ -45,12 +45,17 @@ var schemaCheckMemcached = &schema.Schema{
 func checkAPIToStateMemcached(c *circonusCheck, d *schema.ResourceData) error {
 	memcachedConfig := make(map[string]interface{}, len(c.Config))
 
-	port, err := strconv.ParseInt(c.Config[config.Port], 10, 64)
-	if err != nil {
-		return fmt.Errorf("unable to parse %s: %w", config.Port, err)
-	}
+	// The broker's memcached module connects to the default port when the
+	// config has none, e.g. for checks created in the UI.
+	memcachedConfig[string(checkMemcachedPortAttr)] = defaultCheckMemcachedPort
+	if v, found := c.Config[config.Port]; found && v != "" {
+		port, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Port, err)
+		}
 
-	memcachedConfig[string(checkMemcachedPortAttr)] = int(port)
+		memcachedConfig[string(checkMemcachedPortAttr)] = int(port)
+	}
 
 	if err := d.Set(checkMemcachedAttr, schema.NewSet(hashCheckMemcached, []interface{}{memcachedConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkMemcachedAttr, err)
@@ -59,7 +64,7 @@ func checkAPIToStateMemcached(c *circonusCheck, d *schema.ResourceData) error {
 	return nil
 }
 
-// hashCheckICMPPing creates a stable hash of the normalized values.
+// hashCheckMemcached creates a stable hash of the normalized values.
 func hashCheckMemcached(v interface{}) int {
 	m := v.(map[string]interface{})
 	b := &bytes.Buffer{}
@@ -0,0 +1,49 @@
+package circonus
+
+import (
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestCheckMemcachedConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkMemcachedAttr): []interface{}{
+			map[string]interface{}{
+				string(checkMemcachedPortAttr): 11311,
+			},
+		},
+	})
+
+	memcachedConfig := d.Get(string(checkMemcachedAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIMemcached(&c, memcachedConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.Config[config.Port] != "11311" {
+		t.Errorf("%s: expected %q, got %q", config.Port, "11311", c.Config[config.Port])
+	}
+
+	if err := checkAPIToStateMemcached(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkMemcachedAttr)).(*schema.Set).List()
+	if hashCheckMemcached(state[0]) != hashCheckMemcached(memcachedConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	// A check created outside of terraform may not carry the port.
+	delete(c.Config, config.Port)
+	if err := checkAPIToStateMemcached(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state = d.Get(string(checkMemcachedAttr)).(*schema.Set).List()
+	if port := state[0].(map[string]interface{})[string(checkMemcachedPortAttr)]; port != defaultCheckMemcachedPort {
+		t.Errorf("expected port %d, got %v", defaultCheckMemcachedPort, port)
+	}
+}
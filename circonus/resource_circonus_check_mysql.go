@@ -12,13 +12,36 @@ import (
 
 const (
 	// circonus_check.mysql.* resource attribute names.
-	checkMySQLDSNAttr   = "dsn"
-	checkMySQLQueryAttr = "query"
+	checkMySQLDBNameAttr   = "dbname"
+	checkMySQLDSNAttr      = "dsn"
+	checkMySQLHostAttr     = "host"
+	checkMySQLPasswordAttr = "password"
+	checkMySQLPortAttr     = "port"
+	checkMySQLQueryAttr    = "query"
+	checkMySQLTLSAttr      = "tls"
+	checkMySQLUserAttr     = "user"
 )
 
 var checkMySQLDescriptions = attrDescrs{
-	checkMySQLDSNAttr:   "The connect DSN for the MySQL instance",
-	checkMySQLQueryAttr: "The SQL to use as the query",
+	checkMySQLDBNameAttr:   "The database name to connect to",
+	checkMySQLDSNAttr:      "The connect DSN for the MySQL instance",
+	checkMySQLHostAttr:     "The hostname to connect to",
+	checkMySQLPasswordAttr: "The password to use",
+	checkMySQLPortAttr:     "The TCP port number to use to connect on",
+	checkMySQLQueryAttr:    "The SQL to use as the query",
+	checkMySQLTLSAttr:      "The TLS mode to connect with",
+	checkMySQLUserAttr:     "The username to connect as",
+}
+
+// checkMySQLDSNKeys maps the connect options of the mysql block to their DSN
+// keyword, in the order they are written to the DSN.
+var checkMySQLDSNKeys = []checkDSNKey{
+	{attr: checkMySQLHostAttr, keyword: "host"},
+	{attr: checkMySQLPortAttr, keyword: "port", numeric: true},
+	{attr: checkMySQLUserAttr, keyword: "user"},
+	{attr: checkMySQLPasswordAttr, keyword: "password"},
+	{attr: checkMySQLDBNameAttr, keyword: "dbname"},
+	{attr: checkMySQLTLSAttr, keyword: "sslmode"},
 }
 
 var schemaCheckMySQL = &schema.Schema{
@@ -29,27 +52,65 @@ var schemaCheckMySQL = &schema.Schema{
 	Set:      hashCheckMySQL,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkMySQLDescriptions, map[schemaAttr]*schema.Schema{
+			checkMySQLDBNameAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMySQLDBNameAttr, `^[\S]+$`),
+			},
 			checkMySQLDSNAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validateRegexp(checkMySQLDSNAttr, `^.+$`),
 			},
+			checkMySQLHostAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMySQLHostAttr, `^(/.+|[\S]+)$`),
+			},
+			checkMySQLPasswordAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			checkMySQLPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkMySQLPortAttr, 1),
+					validateIntMax(checkMySQLPortAttr, 65535),
+				),
+			},
 			checkMySQLQueryAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				StateFunc:    func(v interface{}) string { return strings.TrimSpace(v.(string)) },
 				ValidateFunc: validateRegexp(checkMySQLQueryAttr, `.+`),
 			},
+			checkMySQLTLSAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMySQLTLSAttr, `^(disabled|preferred|required|verify_ca|verify_identity)$`),
+			},
+			checkMySQLUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkMySQLUserAttr, `.+`),
+			},
 		}),
 	},
 }
 
 // checkAPIToStateMySQL reads the Config data out of circonusCheck.CheckBundle into the
-// statefile.
+// statefile.  The DSN is parsed into its connect options when the mysql block
+// of d sets them rather than a dsn.
 func checkAPIToStateMySQL(c *circonusCheck, d *schema.ResourceData) error {
 	MySQLConfig := make(map[string]interface{}, len(c.Config))
 
-	MySQLConfig[string(checkMySQLDSNAttr)] = c.Config[config.DSN]
+	if checkDSNInUseState(d, checkMySQLAttr, checkMySQLDSNKeys) {
+		checkDSNAPIToState(checkMySQLDSNAttr, c.Config[config.DSN], checkMySQLDSNKeys, MySQLConfig)
+	} else {
+		MySQLConfig[string(checkMySQLDSNAttr)] = c.Config[config.DSN]
+	}
 	MySQLConfig[string(checkMySQLQueryAttr)] = c.Config[config.SQL]
 
 	if err := d.Set(checkMySQLAttr, schema.NewSet(hashCheckMySQL, []interface{}{MySQLConfig})); err != nil {
@@ -65,6 +126,12 @@ func hashCheckMySQL(v interface{}) int {
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(int) != 0 {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
+
 	writeString := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
 			fmt.Fprint(b, strings.TrimSpace(v.(string)))
@@ -73,14 +140,20 @@ func hashCheckMySQL(v interface{}) int {
 
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
+	writeString(checkMySQLDBNameAttr)
 	writeString(checkMySQLDSNAttr)
+	writeString(checkMySQLHostAttr)
+	writeString(checkMySQLPasswordAttr)
+	writeInt(checkMySQLPortAttr)
 	writeString(checkMySQLQueryAttr)
+	writeString(checkMySQLTLSAttr)
+	writeString(checkMySQLUserAttr)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIMySQL(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIMySQL(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeMySQL)
 
 	// Iterate over all `mysql` attributes, even though we have a max of 1 in the
@@ -88,8 +161,13 @@ func checkConfigToAPIMySQL(c *circonusCheck, l interfaceList) error { //nolint:u
 	for _, mapRaw := range l {
 		mysqlConfig := newInterfaceMap(mapRaw)
 
-		if v, found := mysqlConfig[checkMySQLDSNAttr]; found {
-			c.Config[config.DSN] = v.(string)
+		dsn, err := checkDSNConfigToAPI(checkMySQLAttr, checkMySQLDSNAttr, mysqlConfig, checkMySQLDSNKeys)
+		if err != nil {
+			return err
+		}
+
+		if dsn != "" {
+			c.Config[config.DSN] = dsn
 		}
 
 		if v, found := mysqlConfig[checkMySQLQueryAttr]; found {
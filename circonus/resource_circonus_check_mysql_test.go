@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckMySQL_basic(t *testing.T) {
@@ -43,6 +45,74 @@ func TestAccCirconusCheckMySQL_basic(t *testing.T) {
 	})
 }
 
+func TestCheckMySQLStructuredDSN(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkMySQLAttr): []interface{}{
+			map[string]interface{}{
+				string(checkMySQLHostAttr):     "db.example.org",
+				string(checkMySQLPortAttr):     3307,
+				string(checkMySQLUserAttr):     "monitor",
+				string(checkMySQLPasswordAttr): "hunter 2",
+				string(checkMySQLDBNameAttr):   "orders",
+				string(checkMySQLTLSAttr):      "verify_identity",
+				string(checkMySQLQueryAttr):    "SHOW GLOBAL STATUS",
+			},
+		},
+	})
+
+	mysqlConfig := d.Get(string(checkMySQLAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIMySQL(&c, mysqlConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `host=db.example.org port=3307 user=monitor password='hunter 2' dbname=orders sslmode=verify_identity`
+	if c.Config[config.DSN] != expected {
+		t.Errorf("expected DSN %q, got %q", expected, c.Config[config.DSN])
+	}
+
+	if err := checkAPIToStateMySQL(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkMySQLAttr)).(*schema.Set).List()
+	if hashCheckMySQL(state[0]) != hashCheckMySQL(mysqlConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	// A raw dsn is kept as is.
+	d = schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkMySQLAttr): []interface{}{
+			map[string]interface{}{
+				string(checkMySQLDSNAttr):   "user=monitor password=secret",
+				string(checkMySQLQueryAttr): "SHOW GLOBAL STATUS",
+			},
+		},
+	})
+
+	c = newCheck()
+	if err := checkConfigToAPIMySQL(&c, d.Get(string(checkMySQLAttr)).(*schema.Set).List()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkAPIToStateMySQL(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state = d.Get(string(checkMySQLAttr)).(*schema.Set).List()
+	if dsn := state[0].(map[string]interface{})[string(checkMySQLDSNAttr)]; dsn != "user=monitor password=secret" {
+		t.Errorf("expected the raw %s to be kept, got %q", checkMySQLDSNAttr, dsn)
+	}
+
+	conflict := interfaceList{map[string]interface{}{
+		string(checkMySQLDSNAttr):  "host=db.example.org",
+		string(checkMySQLHostAttr): "db.example.org",
+	}}
+	if err := checkConfigToAPIMySQL(&c, conflict); err == nil {
+		t.Errorf("expected an error when both dsn and connect options are set")
+	}
+}
+
 const testAccCirconusCheckMySQLConfigFmt = `
 variable "test_tags" {
   type = list(string)
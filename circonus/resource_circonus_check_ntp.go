@@ -18,6 +18,7 @@ const (
 
 var checkNTPDescriptions = attrDescrs{
 	checkNTPPortAttr:       "The port to talk to NTP over (default: 123)",
+	checkResolveTargetAttr: checkResolveTargetDescription,
 	checkNTPUseControlAttr: "Control protocol means that the agent will request the NTP telemetry of the target regarding its preferred peer, (default: false)",
 }
 
@@ -32,8 +33,13 @@ var schemaCheckNTP = &schema.Schema{
 			checkNTPPortAttr: {
 				Type:     schema.TypeInt,
 				Optional: true,
-				Default:  123,
+				Default:  defaultCheckNTPPort,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkNTPPortAttr, 1),
+					validateIntMax(checkNTPPortAttr, 65535),
+				),
 			},
+			checkResolveTargetAttr: schemaCheckResolveTarget,
 			checkNTPUseControlAttr: {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -48,14 +54,28 @@ var schemaCheckNTP = &schema.Schema{
 func checkAPIToStateNTP(c *circonusCheck, d *schema.ResourceData) error {
 	ntpConfig := make(map[string]interface{}, len(c.Config))
 
-	if port, ok := c.Config[config.Port]; ok {
-		ntpConfig[string(checkNTPPortAttr)], _ = strconv.Atoi(port)
+	// The broker's ntp module falls back to its defaults for the keys missing
+	// from the config, which are the defaults of the schema.
+	ntpConfig[string(checkNTPPortAttr)] = defaultCheckNTPPort
+	if port, ok := c.Config[config.Port]; ok && port != "" {
+		i, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Port, err)
+		}
+		ntpConfig[string(checkNTPPortAttr)] = i
 	}
 
-	if control, ok := c.Config[config.Control]; ok {
-		ntpConfig[string(checkNTPUseControlAttr)], _ = strconv.ParseBool(control)
+	ntpConfig[string(checkNTPUseControlAttr)] = false
+	if control, ok := c.Config[config.Control]; ok && control != "" {
+		b, err := strconv.ParseBool(control)
+		if err != nil {
+			return fmt.Errorf("unable to parse %s: %w", config.Control, err)
+		}
+		ntpConfig[string(checkNTPUseControlAttr)] = b
 	}
 
+	ntpConfig[checkResolveTargetAttr] = checkResolveTargetToState(c, checkResolveTargetState(d, checkNTPAttr))
+
 	if err := d.Set(checkNTPAttr, schema.NewSet(hashCheckNTP, []interface{}{ntpConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkNTPAttr, err)
 	}
@@ -81,14 +101,21 @@ func hashCheckNTP(v interface{}) int {
 		}
 	}
 
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, v.(string))
+		}
+	}
+
 	writeInt(checkNTPPortAttr)
+	writeString(checkResolveTargetAttr)
 	writeBool(checkNTPUseControlAttr)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPINTP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPINTP(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeNTP)
 
 	mapRaw := l[0]
@@ -102,5 +129,11 @@ func checkConfigToAPINTP(c *circonusCheck, l interfaceList) error { //nolint:unp
 		c.Config[config.Control] = fmt.Sprintf("%t", v.(bool))
 	}
 
+	if v, found := ntpConfig[checkResolveTargetAttr]; found {
+		if err := checkResolveTargetToAPI(c, v.(string)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckNTP_basic(t *testing.T) {
@@ -43,6 +44,59 @@ func TestAccCirconusCheckNTP_basic(t *testing.T) {
 	})
 }
 
+func TestCheckNTPConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkNTPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkNTPPortAttr):       1123,
+				string(checkNTPUseControlAttr): true,
+				string(checkResolveTargetAttr): "ipv6",
+			},
+		},
+	})
+
+	ntpConfig := d.Get(string(checkNTPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPINTP(&c, ntpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.Control: "true",
+		config.Port:    "1123",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStateNTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkNTPAttr)).(*schema.Set).List()
+	if hashCheckNTP(state[0]) != hashCheckNTP(ntpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	// Missing keys read back as the broker's defaults.
+	delete(c.Config, config.Port)
+	delete(c.Config, config.Control)
+	if err := checkAPIToStateNTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := d.Get(string(checkNTPAttr)).(*schema.Set).List()[0].(map[string]interface{})
+	if m[string(checkNTPPortAttr)] != defaultCheckNTPPort {
+		t.Errorf("expected port %d, got %v", defaultCheckNTPPort, m[string(checkNTPPortAttr)])
+	}
+	if m[string(checkNTPUseControlAttr)] != false {
+		t.Errorf("expected use_control false, got %v", m[string(checkNTPUseControlAttr)])
+	}
+}
+
 const testAccCirconusCheckNTPConfigFmt = `
 variable "test_tags" {
   type = list(string)
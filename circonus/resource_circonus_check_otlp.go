@@ -0,0 +1,106 @@
+package circonus
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.otlp.* resource attribute names.
+	checkOTLPSecretAttr        = "secret"
+	checkOTLPSubmissionURLAttr = "submission_url"
+)
+
+var checkOTLPDescriptions = attrDescrs{
+	checkOTLPSecretAttr:        "The secret OpenTelemetry collectors authenticate with, generated by Circonus when omitted",
+	checkOTLPSubmissionURLAttr: "The URL OpenTelemetry collectors export OTLP/HTTP metrics to",
+}
+
+// The otlp block is a list rather than a set so its computed attributes are
+// kept in the statefile alongside the configured ones.
+var schemaCheckOTLP = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkOTLPDescriptions, map[schemaAttr]*schema.Schema{
+			checkOTLPSecretAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkOTLPSecretAttr, `^[a-zA-Z0-9_]+$`),
+			},
+			checkOTLPSubmissionURLAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		}),
+	},
+}
+
+// checkAPIToStateOTLP reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateOTLP(c *circonusCheck, d *schema.ResourceData) error {
+	otlpConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, v := range c.Config {
+		swamp[k] = v
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok {
+			otlpConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.Secret, checkOTLPSecretAttr)
+	saveStringConfigToState(config.SubmissionURL, checkOTLPSubmissionURLAttr)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkOTLPAttr, []interface{}{otlpConfig}); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkOTLPAttr, err)
+	}
+
+	return nil
+}
+
+func checkConfigToAPIOTLP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeOTLP)
+
+	// Iterate over all `otlp` attributes, even though we have a max of 1 in the
+	// schema.
+	for _, mapRaw := range l {
+		otlpConfig, ok := mapRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if v, found := otlpConfig[string(checkOTLPSecretAttr)]; found && v.(string) != "" {
+			c.Config[config.Secret] = v.(string)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	checkPOP3AuthPasswordAttr = "auth_password"
+	checkPOP3AuthUserAttr     = "auth_user"
+	checkPOP3CAChainAttr      = "ca_chain"
+	checkPOP3CertFileAttr     = "certificate_file"
+	checkPOP3CiphersAttr      = "ciphers"
+	checkPOP3KeyFileAttr      = "key_file"
+	checkPOP3PortAttr         = "port"
+	checkPOP3UseSSLAttr       = "use_ssl"
+)
+
+var checkPOP3Descriptions = attrDescrs{
+	checkPOP3AuthPasswordAttr: "The password used to log in to the mailbox.",
+	checkPOP3AuthUserAttr:     "The user used to log in to the mailbox.",
+	checkPOP3CAChainAttr:      "A path to a file containing all the certificate authorities that should be loaded to validate the remote certificate (for TLS checks).",
+	checkPOP3CertFileAttr:     "A path to a file containing the client certificate that will be presented to the remote server (for TLS checks).",
+	checkPOP3CiphersAttr:      "A list of ciphers to be used in the TLS protocol (for TLS checks).",
+	checkPOP3KeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks).",
+	checkPOP3PortAttr:         "Specifies the TCP port to connect to. (default: 110)",
+	checkPOP3UseSSLAttr:       "Connect to the server using TLS (POP3S). (default: false)",
+}
+
+var schemaCheckPOP3 = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckPOP3,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkPOP3Descriptions, map[schemaAttr]*schema.Schema{
+			checkPOP3AuthPasswordAttr: {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			checkPOP3AuthUserAttr: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			checkPOP3CAChainAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkPOP3CertFileAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkPOP3CiphersAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkPOP3KeyFileAttr: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+			checkPOP3PortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  110,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkPOP3PortAttr, 1),
+					validateIntMax(checkPOP3PortAttr, 65535),
+				),
+			},
+			checkPOP3UseSSLAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		}),
+	},
+}
+
+// checkAPIToStatePOP3 reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStatePOP3(c *circonusCheck, d *schema.ResourceData) error {
+	pop3Config := make(map[string]interface{}, len(c.Config))
+
+	if authPassword, ok := c.Config[config.AuthPassword]; ok {
+		pop3Config[string(checkPOP3AuthPasswordAttr)] = authPassword
+	}
+
+	if authUser, ok := c.Config[config.AuthUser]; ok {
+		pop3Config[string(checkPOP3AuthUserAttr)] = authUser
+	}
+
+	if caChain, ok := c.Config[config.CAChain]; ok {
+		pop3Config[string(checkPOP3CAChainAttr)] = caChain
+	}
+
+	if certFile, ok := c.Config[config.CertFile]; ok {
+		pop3Config[string(checkPOP3CertFileAttr)] = certFile
+	}
+
+	if ciphers, ok := c.Config[config.Ciphers]; ok {
+		pop3Config[string(checkPOP3CiphersAttr)] = ciphers
+	}
+
+	if keyFile, ok := c.Config[config.KeyFile]; ok {
+		pop3Config[string(checkPOP3KeyFileAttr)] = keyFile
+	}
+
+	if port, ok := c.Config[config.Port]; ok {
+		pop3Config[string(checkPOP3PortAttr)], _ = strconv.Atoi(port)
+	}
+
+	if useSSL, ok := c.Config[config.UseSSL]; ok {
+		pop3Config[string(checkPOP3UseSSLAttr)], _ = strconv.ParseBool(useSSL)
+	}
+
+	if err := d.Set(checkPOP3Attr, schema.NewSet(hashCheckPOP3, []interface{}{pop3Config})); err != nil {
+		return fmt.Errorf("unable to store check %q attribute: %w", checkPOP3Attr, err)
+	}
+
+	return nil
+}
+
+// hashCheckPOP3 creates a stable hash of the normalized values.
+func hashCheckPOP3(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			if v.(int) > 0 {
+				fmt.Fprintf(b, "%x", v.(int))
+			}
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%s", v.(string))
+		}
+	}
+
+	writeString(checkPOP3AuthPasswordAttr)
+	writeString(checkPOP3AuthUserAttr)
+	writeString(checkPOP3CAChainAttr)
+	writeString(checkPOP3CertFileAttr)
+	writeString(checkPOP3CiphersAttr)
+	writeString(checkPOP3KeyFileAttr)
+	writeInt(checkPOP3PortAttr)
+	writeBool(checkPOP3UseSSLAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIPOP3(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypePOP3)
+
+	mapRaw := l[0]
+	pop3Config := newInterfaceMap(mapRaw)
+
+	if v, found := pop3Config[checkPOP3AuthPasswordAttr]; found && v.(string) != "" {
+		c.Config[config.AuthPassword] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3AuthUserAttr]; found && v.(string) != "" {
+		c.Config[config.AuthUser] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3CAChainAttr]; found && v.(string) != "" {
+		c.Config[config.CAChain] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3CertFileAttr]; found && v.(string) != "" {
+		c.Config[config.CertFile] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3CiphersAttr]; found && v.(string) != "" {
+		c.Config[config.Ciphers] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3KeyFileAttr]; found && v.(string) != "" {
+		c.Config[config.KeyFile] = v.(string)
+	}
+
+	if v, found := pop3Config[checkPOP3PortAttr]; found && v.(int) > 0 {
+		c.Config[config.Port] = strconv.Itoa(v.(int))
+	}
+
+	if v, found := pop3Config[checkPOP3UseSSLAttr]; found {
+		c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckPOP3_basic(t *testing.T) {
+	checkName := fmt.Sprintf("POP3 check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckPOP3ConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.pop3", "active", "true"),
+					resource.TestMatchResourceAttr("circonus_check.pop3", "check_id", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "pop3.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "period", "300s"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "target", "127.0.0.1"),
+					resource.TestCheckResourceAttr("circonus_check.pop3", "type", "pop3"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckPOP3ConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "pop3" {
+  active = true
+  name = "%s"
+  period = "300s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  pop3 {
+    auth_user = "monitor"
+    auth_password = "secret"
+  }
+
+  metric {
+    name = "duration"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+  target = "127.0.0.1"
+}
+`
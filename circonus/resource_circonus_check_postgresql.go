@@ -12,25 +12,36 @@ import (
 
 const (
 	// circonus_check.postgresql.* resource attribute names.
-	checkPostgreSQLDSNAttr = "dsn"
-	// checkPostgreSQLHostAttr      = "host"
-	// checkPostgreSQLNameAttr      = "name"
-	// checkPostgreSQLPasswordAttr  = "password"
-	// checkPostgreSQLPortAttr      = "port".
-	checkPostgreSQLQueryAttr = "query"
-	// checkPostgreSQLSSLModeAttr   = "sslmode"
-	// checkPostgreSQLUserAttr      = "user".
+	checkPostgreSQLDBNameAttr   = "dbname"
+	checkPostgreSQLDSNAttr      = "dsn"
+	checkPostgreSQLHostAttr     = "host"
+	checkPostgreSQLPasswordAttr = "password"
+	checkPostgreSQLPortAttr     = "port"
+	checkPostgreSQLQueryAttr    = "query"
+	checkPostgreSQLSSLModeAttr  = "sslmode"
+	checkPostgreSQLUserAttr     = "user"
 )
 
 var checkPostgreSQLDescriptions = attrDescrs{
-	checkPostgreSQLDSNAttr: "The connect DSN for the PostgreSQL instance",
-	// checkPostgreSQLHostAttr:     "The Hostname to connect to",
-	// checkPostgreSQLNameAttr:     "The database name to connect to",
-	// checkPostgreSQLPasswordAttr: "The password to use",
-	// checkPostgreSQLPortAttr:     "The TCP port number to use to connect on",
-	checkPostgreSQLQueryAttr: "The SQL to use as the query",
-	// checkPostgreSQLSSLModeAttr:  "The SSL Mode to connect as",
-	// checkPostgreSQLUserAttr:     "The username to connect as",
+	checkPostgreSQLDBNameAttr:   "The database name to connect to",
+	checkPostgreSQLDSNAttr:      "The connect DSN for the PostgreSQL instance",
+	checkPostgreSQLHostAttr:     "The hostname to connect to",
+	checkPostgreSQLPasswordAttr: "The password to use",
+	checkPostgreSQLPortAttr:     "The TCP port number to use to connect on",
+	checkPostgreSQLQueryAttr:    "The SQL to use as the query",
+	checkPostgreSQLSSLModeAttr:  "The SSL mode to connect as",
+	checkPostgreSQLUserAttr:     "The username to connect as",
+}
+
+// checkPostgreSQLDSNKeys maps the connect options of the postgresql block to
+// their libpq DSN keyword, in the order they are written to the DSN.
+var checkPostgreSQLDSNKeys = []checkDSNKey{
+	{attr: checkPostgreSQLHostAttr, keyword: "host"},
+	{attr: checkPostgreSQLPortAttr, keyword: "port", numeric: true},
+	{attr: checkPostgreSQLUserAttr, keyword: "user"},
+	{attr: checkPostgreSQLPasswordAttr, keyword: "password"},
+	{attr: checkPostgreSQLDBNameAttr, keyword: "dbname"},
+	{attr: checkPostgreSQLSSLModeAttr, keyword: "sslmode"},
 }
 
 var schemaCheckPostgreSQL = &schema.Schema{
@@ -41,69 +52,68 @@ var schemaCheckPostgreSQL = &schema.Schema{
 	Set:      hashCheckPostgreSQL,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkPostgreSQLDescriptions, map[schemaAttr]*schema.Schema{
+			checkPostgreSQLDBNameAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLDBNameAttr, `^[\S]+$`),
+			},
 			checkPostgreSQLDSNAttr: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validateRegexp(checkPostgreSQLDSNAttr, `^.+$`),
 			},
-			// TODO(sean@): Parse out the DSN into individual PostgreSQL connect
-			// options.
-			//
-			// checkPostgreSQLHostAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Optional:     true,
-			// 	Default:      "/tmp",
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLHostAttr, `^(/.+|[\S]+)$`),
-			// },
-			// checkPostgreSQLNameAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Required:     true,
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLNameAttr, `^[\S]+$`),
-			// },
-			// checkPostgreSQLPasswordAttr: &schema.Schema{
-			// 	Type:      schema.TypeString,
-			// 	Optional:  true,
-			// 	Sensitive: true,
-			// },
-			// checkPostgreSQLPortAttr: &schema.Schema{
-			// 	Type:     schema.TypeInt,
-			// 	Optional: true,
-			// 	Default:  5432,
-			// 	ValidateFunc: validateFuncs(
-			// 		validateIntMin(checkPostgreSQLPortAttr, 1),
-			// 		validateIntMax(checkPostgreSQLPortAttr, 65535),
-			// 	),
-			// },
+			checkPostgreSQLHostAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLHostAttr, `^(/.+|[\S]+)$`),
+			},
+			checkPostgreSQLPasswordAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			checkPostgreSQLPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkPostgreSQLPortAttr, 1),
+					validateIntMax(checkPostgreSQLPortAttr, 65535),
+				),
+			},
 			checkPostgreSQLQueryAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				StateFunc:    suppressWhitespace,
 				ValidateFunc: validateRegexp(checkPostgreSQLQueryAttr, `.+`),
 			},
-			// checkPostgreSQLSSLModeAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Optional:     true,
-			// 	Default:      "require",
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLSSLModeAttr, `^(disable|require|verify-ca|verify-full)$`),
-			// },
-			// checkPostgreSQLUserAttr: &schema.Schema{
-			// 	Type:         schema.TypeString,
-			// 	Required:     true,
-			// 	ValidateFunc: validateRegexp(checkPostgreSQLUserAttr, `.+`),
-			// },
+			checkPostgreSQLSSLModeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLSSLModeAttr, `^(disable|allow|prefer|require|verify-ca|verify-full)$`),
+			},
+			checkPostgreSQLUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkPostgreSQLUserAttr, `.+`),
+			},
 		}),
 	},
 }
 
 // checkAPIToStatePostgreSQL reads the Config data out of circonusCheck.CheckBundle into the
-// statefile.
+// statefile.  The DSN is parsed into its connect options when the postgresql
+// block of d sets them rather than a dsn.
 func checkAPIToStatePostgreSQL(c *circonusCheck, d *schema.ResourceData) error {
 	postgresqlConfig := make(map[string]interface{}, len(c.Config))
 
-	// TODO(sean@): Parse out the DSN into individual PostgreSQL connect options
-	postgresqlConfig[string(checkPostgreSQLDSNAttr)] = c.Config[config.DSN]
 	postgresqlConfig[string(checkPostgreSQLQueryAttr)] = c.Config[config.SQL]
 
+	if checkDSNInUseState(d, checkPostgreSQLAttr, checkPostgreSQLDSNKeys) {
+		checkDSNAPIToState(checkPostgreSQLDSNAttr, c.Config[config.DSN], checkPostgreSQLDSNKeys, postgresqlConfig)
+	} else {
+		postgresqlConfig[string(checkPostgreSQLDSNAttr)] = c.Config[config.DSN]
+	}
+
 	if err := d.Set(checkPostgreSQLAttr, schema.NewSet(hashCheckPostgreSQL, []interface{}{postgresqlConfig})); err != nil {
 		return fmt.Errorf("Unable to store check %q attribute: %w", checkPostgreSQLAttr, err)
 	}
@@ -117,11 +127,11 @@ func hashCheckPostgreSQL(v interface{}) int {
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 
-	// writeInt := func(attrName schemaAttr) {
-	// 	if v, ok := m[string(attrName)]; ok {
-	// 		fmt.Fprintf(b, "%x", v.(int))
-	// 	}
-	// }
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(int) != 0 {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
 
 	writeString := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
@@ -131,20 +141,20 @@ func hashCheckPostgreSQL(v interface{}) int {
 
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
+	writeString(checkPostgreSQLDBNameAttr)
 	writeString(checkPostgreSQLDSNAttr)
-	// writeString(checkPostgreSQLHostAttr)
-	// writeString(checkPostgreSQLNameAttr)
-	// writeString(checkPostgreSQLPasswordAttr)
-	// writeInt(checkPostgreSQLPortAttr)
-	// writeString(checkPostgreSQLSSLModeAttr)
+	writeString(checkPostgreSQLHostAttr)
+	writeString(checkPostgreSQLPasswordAttr)
+	writeInt(checkPostgreSQLPortAttr)
 	writeString(checkPostgreSQLQueryAttr)
-	// writeString(checkPostgreSQLUserAttr)
+	writeString(checkPostgreSQLSSLModeAttr)
+	writeString(checkPostgreSQLUserAttr)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypePostgreSQL)
 
 	// Iterate over all `postgres` attributes, even though we have a max of 1 in
@@ -152,8 +162,13 @@ func checkConfigToAPIPostgreSQL(c *circonusCheck, l interfaceList) error { //nol
 	for _, mapRaw := range l {
 		postgresConfig := newInterfaceMap(mapRaw)
 
-		if v, found := postgresConfig[checkPostgreSQLDSNAttr]; found {
-			c.Config[config.DSN] = v.(string)
+		dsn, err := checkDSNConfigToAPI(checkPostgreSQLAttr, checkPostgreSQLDSNAttr, postgresConfig, checkPostgreSQLDSNKeys)
+		if err != nil {
+			return err
+		}
+
+		if dsn != "" {
+			c.Config[config.DSN] = dsn
 		}
 
 		if v, found := postgresConfig[checkPostgreSQLQueryAttr]; found {
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckPostgreSQL_basic(t *testing.T) {
@@ -61,6 +63,54 @@ func TestAccCirconusCheckPostgreSQL_basic(t *testing.T) {
 	})
 }
 
+func TestCheckPostgreSQLStructuredDSN(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkPostgreSQLAttr): []interface{}{
+			map[string]interface{}{
+				string(checkPostgreSQLHostAttr):     "db.example.org",
+				string(checkPostgreSQLPortAttr):     5433,
+				string(checkPostgreSQLUserAttr):     "monitor",
+				string(checkPostgreSQLPasswordAttr): `it's a \secret`,
+				string(checkPostgreSQLDBNameAttr):   "orders",
+				string(checkPostgreSQLSSLModeAttr):  "verify-full",
+				string(checkPostgreSQLQueryAttr):    "SELECT 1",
+			},
+		},
+	})
+
+	postgresqlConfig := d.Get(string(checkPostgreSQLAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIPostgreSQL(&c, postgresqlConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `host=db.example.org port=5433 user=monitor password='it\'s a \\secret' dbname=orders sslmode=verify-full`
+	if c.Config[config.DSN] != expected {
+		t.Errorf("expected DSN %q, got %q", expected, c.Config[config.DSN])
+	}
+
+	if err := checkAPIToStatePostgreSQL(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkPostgreSQLAttr)).(*schema.Set).List()
+	if hashCheckPostgreSQL(state[0]) != hashCheckPostgreSQL(postgresqlConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+	if dsn := state[0].(map[string]interface{})[string(checkPostgreSQLDSNAttr)]; dsn != "" {
+		t.Errorf("expected no %s in the state, got %q", checkPostgreSQLDSNAttr, dsn)
+	}
+
+	conflict := interfaceList{map[string]interface{}{
+		string(checkPostgreSQLDSNAttr):  "host=db.example.org",
+		string(checkPostgreSQLUserAttr): "monitor",
+	}}
+	if err := checkConfigToAPIPostgreSQL(&c, conflict); err == nil {
+		t.Errorf("expected an error when both dsn and connect options are set")
+	}
+}
+
 const testAccCirconusCheckPostgreSQLConfigFmt = `
 variable "test_tags" {
   type = list(string)
@@ -7,21 +7,44 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/timeutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
-	// circonus_check.json.* resource attribute names.
-	checkPromTextPortAttr = "port"
-	checkPromTextURLAttr  = "url"
+	// circonus_check.promtext.* resource attribute names.
+	checkPromTextDropAttr           = "drop"
+	checkPromTextKeepAttr           = "keep"
+	checkPromTextLabelAllowlistAttr = "label_allowlist"
+	checkPromTextPortAttr           = "port"
+	checkPromTextScrapeIntervalAttr = "scrape_interval"
+	checkPromTextSubmitIntervalAttr = "submission_interval"
+	checkPromTextURLAttr            = "url"
+)
+
+const (
+	// Config keys of the broker's promtext module that are not known to the
+	// API client.
+	checkPromTextDropKey           config.Key = "drop"
+	checkPromTextKeepKey           config.Key = "keep"
+	checkPromTextLabelAllowlistKey config.Key = "label_allowlist"
+	checkPromTextScrapeIntervalKey config.Key = "scrape_interval"
+	checkPromTextSubmitIntervalKey config.Key = "submission_interval"
 )
 
 var checkPromTextDescriptions = attrDescrs{
-	checkPromTextPortAttr: "Specifies the port on which the prometheus metrics can be scraped",
-	checkPromTextURLAttr:  "The URL to use as the target of the check",
+	checkPromTextDropAttr:           "A regular expression, metrics whose name matches are dropped",
+	checkPromTextKeepAttr:           "A regular expression, only metrics whose name matches are kept",
+	checkPromTextLabelAllowlistAttr: "The labels kept on the metrics, all other labels are removed before metrics are stored",
+	checkPromTextPortAttr:           "Specifies the port on which the prometheus metrics can be scraped",
+	checkPromTextScrapeIntervalAttr: "How often the broker scrapes the URL within each period of the check, must divide the period evenly",
+	checkPromTextSubmitIntervalAttr: "How often the broker submits the scraped metrics, must be a multiple of the period of the check",
+	checkPromTextURLAttr:            "The URL to use as the target of the check",
+	checkTLSConfigAttr:              checkTLSConfigDescription,
 }
 
 var schemaCheckPromText = &schema.Schema{
@@ -32,6 +55,24 @@ var schemaCheckPromText = &schema.Schema{
 	Set:      checkPromTextConfigChecksum,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkPromTextDescriptions, map[schemaAttr]*schema.Schema{
+			checkPromTextDropAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexpSyntax(checkPromTextDropAttr),
+			},
+			checkPromTextKeepAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexpSyntax(checkPromTextKeepAttr),
+			},
+			checkPromTextLabelAllowlistAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateRegexp(checkPromTextLabelAllowlistAttr, `^[a-zA-Z_][a-zA-Z0-9_]*$`),
+				},
+			},
 			checkPromTextPortAttr: {
 				Type:     schema.TypeInt,
 				Default:  443,
@@ -41,6 +82,24 @@ var schemaCheckPromText = &schema.Schema{
 					validateIntMax(checkPromTextPortAttr, 65535),
 				),
 			},
+			checkPromTextScrapeIntervalAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: timeutil.NormalizeSeconds,
+				ValidateFunc: validateFuncs(
+					validateDurationMin(checkPromTextScrapeIntervalAttr, "1s"),
+					validateDurationMax(checkPromTextScrapeIntervalAttr, defaultCirconusCheckPeriodMax),
+				),
+			},
+			checkPromTextSubmitIntervalAttr: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: timeutil.NormalizeSeconds,
+				ValidateFunc: validateFuncs(
+					validateDurationMin(checkPromTextSubmitIntervalAttr, defaultCirconusCheckPeriodMin),
+					validateDurationMax(checkPromTextSubmitIntervalAttr, "1h"),
+				),
+			},
 			checkPromTextURLAttr: {
 				Type:     schema.TypeString,
 				Required: true,
@@ -48,6 +107,7 @@ var schemaCheckPromText = &schema.Schema{
 					validateHTTPURL(checkPromTextURLAttr, urlIsAbs),
 				),
 			},
+			checkTLSConfigAttr: schemaCheckTLS,
 		}),
 	},
 }
@@ -84,8 +144,28 @@ func checkAPIToStatePromText(c *circonusCheck, d *schema.ResourceData) error {
 		delete(swamp, apiKey)
 	}
 
+	saveStringConfigToState(checkPromTextDropKey, checkPromTextDropAttr)
+	saveStringConfigToState(checkPromTextKeepKey, checkPromTextKeepAttr)
+	if v, found := c.Config[checkPromTextLabelAllowlistKey]; found && v != "" {
+		labels := make([]interface{}, 0)
+		for _, label := range strings.Split(v, ",") {
+			labels = append(labels, strings.TrimSpace(label))
+		}
+		ptConfig[string(checkPromTextLabelAllowlistAttr)] = labels
+	}
+	delete(swamp, checkPromTextLabelAllowlistKey)
 	saveIntConfigToState(config.Port, checkPromTextPortAttr)
+	for key, attrName := range map[config.Key]schemaAttr{
+		checkPromTextScrapeIntervalKey: checkPromTextScrapeIntervalAttr,
+		checkPromTextSubmitIntervalKey: checkPromTextSubmitIntervalAttr,
+	} {
+		if v, found := c.Config[key]; found && v != "" {
+			ptConfig[string(attrName)] = timeutil.NormalizeSeconds(v)
+		}
+		delete(swamp, key)
+	}
 	saveStringConfigToState(config.URL, checkPromTextURLAttr)
+	ptConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
@@ -130,14 +210,27 @@ func checkPromTextConfigChecksum(v interface{}) int {
 
 	// Order writes to the buffer using lexically sorted list for easy visual
 	// reconciliation with other lists.
+	writeString(checkPromTextDropAttr)
+	writeString(checkPromTextKeepAttr)
+	if v, ok := m[string(checkPromTextLabelAllowlistAttr)]; ok {
+		for _, label := range interfaceList(v.([]interface{})).List() {
+			fmt.Fprint(b, label)
+		}
+	}
 	writeInt(checkPromTextPortAttr)
+	for _, attrName := range []schemaAttr{checkPromTextScrapeIntervalAttr, checkPromTextSubmitIntervalAttr} {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, timeutil.NormalizeSeconds(v))
+		}
+	}
 	writeString(checkPromTextURLAttr)
+	writeCheckTLSHash(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypePromText)
 
 	// Iterate over all `promtext` attributes, even though we have a max of 1 in the
@@ -145,6 +238,20 @@ func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolin
 	for _, mapRaw := range l {
 		ptConfig := newInterfaceMap(mapRaw)
 
+		if v, found := ptConfig[checkPromTextDropAttr]; found && v.(string) != "" {
+			c.Config[checkPromTextDropKey] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextKeepAttr]; found && v.(string) != "" {
+			c.Config[checkPromTextKeepKey] = v.(string)
+		}
+
+		if v, found := ptConfig[checkPromTextLabelAllowlistAttr]; found {
+			if labels := interfaceList(v.([]interface{})).List(); len(labels) > 0 {
+				c.Config[checkPromTextLabelAllowlistKey] = strings.Join(labels, ",")
+			}
+		}
+
 		if v, found := ptConfig[checkPromTextPortAttr]; found {
 			i := v.(int)
 			if i != 0 {
@@ -152,6 +259,19 @@ func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolin
 			}
 		}
 
+		for attrName, key := range map[schemaAttr]config.Key{
+			checkPromTextScrapeIntervalAttr: checkPromTextScrapeIntervalKey,
+			checkPromTextSubmitIntervalAttr: checkPromTextSubmitIntervalKey,
+		} {
+			if v, found := ptConfig[string(attrName)]; found && v.(string) != "" {
+				d, err := timeutil.ParseDuration(v.(string))
+				if err != nil {
+					return fmt.Errorf("unable to parse %s %q: %w", attrName, v.(string), err)
+				}
+				c.Config[key] = timeutil.FormatSeconds(d)
+			}
+		}
+
 		if v, found := ptConfig[checkPromTextURLAttr]; found {
 			c.Config[config.URL] = v.(string)
 
@@ -165,7 +285,75 @@ func checkConfigToAPIPromText(c *circonusCheck, l interfaceList) error { //nolin
 				c.Config[config.Port] = hostInfo[1]
 			}
 		}
+
+		checkTLSConfigToAPI(c, ptConfig)
 	}
 
 	return nil
 }
+
+// validatePromTextIntervals checks the scrape and submission intervals of a
+// promtext check against its period.  The broker scrapes the URL every scrape
+// interval within each period and submits what it scraped every submission
+// interval, so the scrape interval has to divide the period and the submission
+// interval has to be a multiple of it.  Intervals that are not set, i.e. zero,
+// default to the period.
+func validatePromTextIntervals(period, scrape, submission time.Duration) error {
+	if period <= 0 {
+		return nil
+	}
+
+	if scrape > 0 && (scrape > period || period%scrape != 0) {
+		return fmt.Errorf("%s %s (%s) must divide the check's period (%s) evenly", checkPromTextAttr, checkPromTextScrapeIntervalAttr, scrape, period)
+	}
+
+	if submission > 0 && (submission < period || submission%period != 0) {
+		return fmt.Errorf("%s %s (%s) must be a multiple of the check's period (%s)", checkPromTextAttr, checkPromTextSubmitIntervalAttr, submission, period)
+	}
+
+	return nil
+}
+
+// checkPromTextIntervals returns the scrape and submission intervals set in the
+// config of a promtext check, zero when not set.
+func checkPromTextIntervals(c *circonusCheck) (scrape, submission time.Duration, err error) {
+	if v := c.Config[checkPromTextScrapeIntervalKey]; v != "" {
+		if scrape, err = timeutil.ParseDuration(v); err != nil {
+			return 0, 0, fmt.Errorf("unable to parse %s %q: %w", checkPromTextScrapeIntervalKey, v, err)
+		}
+	}
+
+	if v := c.Config[checkPromTextSubmitIntervalKey]; v != "" {
+		if submission, err = timeutil.ParseDuration(v); err != nil {
+			return 0, 0, fmt.Errorf("unable to parse %s %q: %w", checkPromTextSubmitIntervalKey, v, err)
+		}
+	}
+
+	return scrape, submission, nil
+}
+
+// checkCustomizeDiffPromTextIntervals validates the intervals of a promtext
+// block against the check's period while planning.
+func checkCustomizeDiffPromTextIntervals(d *schema.ResourceDiff) error {
+	s, ok := d.Get(checkPromTextAttr).(*schema.Set)
+	if !ok || s.Len() == 0 || !d.NewValueKnown(checkPeriodAttr) || !d.NewValueKnown(checkPromTextAttr) {
+		return nil
+	}
+
+	period, err := timeutil.ParseDuration(d.Get(checkPeriodAttr).(string))
+	if err != nil {
+		return nil
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPIPromText(&c, s.List()); err != nil {
+		return err
+	}
+
+	scrape, submission, err := checkPromTextIntervals(&c)
+	if err != nil {
+		return err
+	}
+
+	return validatePromTextIntervals(period, scrape, submission)
+}
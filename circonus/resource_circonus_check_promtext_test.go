@@ -0,0 +1,111 @@
+package circonus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCheckPromTextConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkPromTextAttr): []interface{}{
+			map[string]interface{}{
+				string(checkPromTextURLAttr):            "https://app1.example.org:9100/metrics",
+				string(checkPromTextKeepAttr):           "^(http|grpc)_",
+				string(checkPromTextDropAttr):           "_bucket$",
+				string(checkPromTextLabelAllowlistAttr): []interface{}{"job", "instance"},
+				string(checkPromTextScrapeIntervalAttr): "15s",
+				string(checkPromTextSubmitIntervalAttr): "2m",
+			},
+		},
+	})
+
+	ptConfig := d.Get(string(checkPromTextAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIPromText(&c, ptConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		checkPromTextDropKey:           "_bucket$",
+		checkPromTextKeepKey:           "^(http|grpc)_",
+		checkPromTextLabelAllowlistKey: "job,instance",
+		checkPromTextScrapeIntervalKey: "15",
+		checkPromTextSubmitIntervalKey: "120",
+		config.URL:                     "https://app1.example.org:9100/metrics",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStatePromText(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkPromTextAttr)).(*schema.Set).List()
+	if checkPromTextConfigChecksum(state[0]) != checkPromTextConfigChecksum(ptConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+}
+
+func TestValidatePromTextIntervals(t *testing.T) {
+	tests := []struct {
+		scrape, submission time.Duration
+		ok                 bool
+	}{
+		{0, 0, true},
+		{15 * time.Second, 0, true},
+		{time.Minute, 5 * time.Minute, true},
+		{0, 3 * time.Minute, true},
+		{7 * time.Second, 0, false},
+		{2 * time.Minute, 0, false},
+		{0, 30 * time.Second, false},
+		{0, 90 * time.Second, false},
+	}
+
+	for _, test := range tests {
+		err := validatePromTextIntervals(time.Minute, test.scrape, test.submission)
+		if (err == nil) != test.ok {
+			t.Errorf("scrape %s, submission %s: expected ok %t, got %v", test.scrape, test.submission, test.ok, err)
+		}
+	}
+
+	// The intervals are validated against the period while planning.
+	r := resourceCheck()
+	cfg := func(period, scrape string) map[string]interface{} {
+		return map[string]interface{}{
+			checkCollectorAttr:    []interface{}{map[string]interface{}{checkCollectorIDAttr: "/broker/1"}},
+			checkMetricFilterAttr: []interface{}{map[string]interface{}{"type": "allow", "regex": ".*"}},
+			checkPeriodAttr:       period,
+			checkPromTextAttr: []interface{}{map[string]interface{}{
+				string(checkPromTextURLAttr):            "https://app1.example.org:9100/metrics",
+				string(checkPromTextScrapeIntervalAttr): scrape,
+			}},
+		}
+	}
+
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(cfg("60s", "20s")), &providerContext{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(cfg("60s", "25s")), &providerContext{}); err == nil {
+		t.Error("expected a scrape interval that does not divide the period to fail the plan")
+	}
+}
+
+func TestValidateRegexpSyntax(t *testing.T) {
+	validate := validateRegexpSyntax(checkPromTextKeepAttr)
+
+	if _, errs := validate("^node_(cpu|memory)_", checkPromTextKeepAttr); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, errs := validate("^node_(cpu", checkPromTextKeepAttr); len(errs) == 0 {
+		t.Errorf("expected an error for an unbalanced regular expression")
+	}
+}
@@ -18,6 +18,7 @@ const (
 	checkRedisDbIndexAttr  = "db_index"
 	checkRedisPasswordAttr = "password"
 	checkRedisPortAttr     = "port"
+	checkRedisUseSSLAttr   = "use_ssl"
 )
 
 var checkRedisDescriptions = attrDescrs{
@@ -25,6 +26,8 @@ var checkRedisDescriptions = attrDescrs{
 	checkRedisDbIndexAttr:  "The database index to query, defaults to zero",
 	checkRedisPasswordAttr: "The pass required to run the command.",
 	checkRedisPortAttr:     "Specifies the port on which the Redis instance can be reached.",
+	checkRedisUseSSLAttr:   "Connect to the Redis instance using TLS",
+	checkTLSConfigAttr:     checkTLSConfigDescription,
 }
 
 var schemaCheckRedis = &schema.Schema{
@@ -62,6 +65,12 @@ var schemaCheckRedis = &schema.Schema{
 					validateIntMax(checkTCPPortAttr, 65535),
 				),
 			},
+			checkRedisUseSSLAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			checkTLSConfigAttr: schemaCheckTLS,
 		}),
 	},
 }
@@ -96,10 +105,25 @@ func checkAPIToStateRedis(c *circonusCheck, d *schema.ResourceData) error {
 		delete(swamp, apiKey)
 	}
 
+	saveBoolConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if v, ok := c.Config[apiKey]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Printf("[ERROR]: Unable to convert %s to a bool: %v", apiKey, err)
+				return
+			}
+			redisConfig[string(attrName)] = b
+		}
+
+		delete(swamp, apiKey)
+	}
+
 	saveStringConfigToState(config.Command, checkRedisCommandAttr)
 	saveIntConfigToState(config.DBIndex, checkRedisDbIndexAttr)
 	saveStringConfigToState(config.Password, checkRedisPasswordAttr)
 	saveIntConfigToState(config.Port, checkRedisPortAttr)
+	saveBoolConfigToState(config.UseSSL, checkRedisUseSSLAttr)
+	redisConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, swamp)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
 		config.ReverseSecretKey: {},
@@ -128,6 +152,12 @@ func hashCheckRedis(v interface{}) int {
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 
+	writeBool := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok {
+			fmt.Fprintf(b, "%t", v.(bool))
+		}
+	}
+
 	writeInt := func(attrName schemaAttr) {
 		if v, ok := m[string(attrName)]; ok {
 			fmt.Fprintf(b, "%x", v.(int))
@@ -146,6 +176,8 @@ func hashCheckRedis(v interface{}) int {
 	writeInt(checkRedisDbIndexAttr)
 	writeString(checkRedisPasswordAttr)
 	writeInt(checkRedisPortAttr)
+	writeBool(checkRedisUseSSLAttr)
+	writeCheckTLSHash(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
@@ -154,7 +186,7 @@ func hashCheckRedis(v interface{}) int {
 func checkConfigToAPIRedis(c *circonusCheck, l interfaceList) error { //nolint:unparam
 	c.Type = string(apiCheckTypeRedis)
 
-	// Iterate over all `tcp` attributes, even though we have a max of 1 in the
+	// Iterate over all `redis` attributes, even though we have a max of 1 in the
 	// schema.
 	for _, mapRaw := range l {
 		redisConfig := newInterfaceMap(mapRaw)
@@ -167,13 +199,19 @@ func checkConfigToAPIRedis(c *circonusCheck, l interfaceList) error { //nolint:u
 			c.Config[config.DBIndex] = fmt.Sprintf("%d", v.(int))
 		}
 
-		if v, found := redisConfig[checkRedisPasswordAttr]; found {
+		if v, found := redisConfig[checkRedisPasswordAttr]; found && v.(string) != "" {
 			c.Config[config.Password] = v.(string)
 		}
 
 		if v, found := redisConfig[checkRedisPortAttr]; found {
 			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
 		}
+
+		if v, found := redisConfig[checkRedisUseSSLAttr]; found {
+			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
+		}
+
+		checkTLSConfigToAPI(c, redisConfig)
 	}
 
 	return nil
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckRedis_basic(t *testing.T) {
@@ -36,6 +38,54 @@ func TestAccCirconusCheckRedis_basic(t *testing.T) {
 	})
 }
 
+func TestCheckRedisConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkRedisAttr): []interface{}{
+			map[string]interface{}{
+				string(checkRedisDbIndexAttr):  2,
+				string(checkRedisPasswordAttr): "hunter2",
+				string(checkRedisPortAttr):     6380,
+				string(checkRedisUseSSLAttr):   true,
+				string(checkTLSConfigAttr): []interface{}{
+					map[string]interface{}{
+						string(checkTLSCAChainAttr): "/etc/ssl/redis-ca.pem",
+					},
+				},
+			},
+		},
+	})
+
+	redisConfig := d.Get(string(checkRedisAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPIRedis(&c, redisConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.CAChain:  "/etc/ssl/redis-ca.pem",
+		config.Command:  "INFO",
+		config.DBIndex:  "2",
+		config.Password: "hunter2",
+		config.Port:     "6380",
+		config.UseSSL:   "true",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStateRedis(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkRedisAttr)).(*schema.Set).List()
+	if hashCheckRedis(state[0]) != hashCheckRedis(redisConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+}
+
 const testAccCirconusCheckRedisConfigFmt = `
 variable "tcp_check_tags" {
   type = list(string)
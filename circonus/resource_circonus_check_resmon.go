@@ -0,0 +1,206 @@
+package circonus
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// circonus_check.resmon.* resource attribute names.
+	checkResmonAuthMethodAttr   = "auth_method"
+	checkResmonAuthPasswordAttr = "auth_password"
+	checkResmonAuthUserAttr     = "auth_user"
+	checkResmonPortAttr         = "port"
+	checkResmonURLAttr          = "url"
+)
+
+var checkResmonDescriptions = attrDescrs{
+	checkResmonAuthMethodAttr:   "The HTTP Authentication method",
+	checkResmonAuthPasswordAttr: "The HTTP Authentication user password",
+	checkResmonAuthUserAttr:     "The HTTP Authentication user name",
+	checkResmonPortAttr:         "Specifies the port on which the Resmon endpoint can be reached",
+	checkResmonURLAttr:          "The URL of the Resmon XML endpoint",
+}
+
+var schemaCheckResmon = &schema.Schema{
+	Type:     schema.TypeSet,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Set:      hashCheckResmon,
+	Elem: &schema.Resource{
+		Schema: convertToHelperSchema(checkResmonDescriptions, map[schemaAttr]*schema.Schema{
+			checkResmonAuthMethodAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkResmonAuthMethodAttr, `^(?:Basic|Digest|Auto)$`),
+			},
+			checkResmonAuthPasswordAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateRegexp(checkResmonAuthPasswordAttr, `^.*`),
+			},
+			checkResmonAuthUserAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkResmonAuthUserAttr, `[^:]+`),
+			},
+			checkResmonPortAttr: {
+				Type:     schema.TypeInt,
+				Optional: true,
+				ValidateFunc: validateFuncs(
+					validateIntMin(checkResmonPortAttr, 0),
+					validateIntMax(checkResmonPortAttr, 65535),
+				),
+			},
+			checkResmonURLAttr: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateHTTPURL(checkResmonURLAttr, urlIsAbs),
+			},
+		}),
+	},
+}
+
+// checkAPIToStateResmon reads the Config data out of circonusCheck.CheckBundle
+// into the statefile.
+func checkAPIToStateResmon(c *circonusCheck, d *schema.ResourceData) error {
+	resmonConfig := make(map[string]interface{}, len(c.Config))
+
+	// swamp is a sanity check: it must be empty by the time this method returns
+	swamp := make(map[config.Key]string, len(c.Config))
+	for k, s := range c.Config {
+		swamp[k] = s
+	}
+
+	saveStringConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "" {
+			resmonConfig[string(attrName)] = s
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveIntConfigToState := func(apiKey config.Key, attrName schemaAttr) {
+		if s, ok := c.Config[apiKey]; ok && s != "0" {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				log.Printf("[ERROR]: Unable to convert %s to an integer: %v", apiKey, err)
+				return
+			}
+			resmonConfig[string(attrName)] = int(i)
+		}
+
+		delete(swamp, apiKey)
+	}
+
+	saveStringConfigToState(config.AuthMethod, checkResmonAuthMethodAttr)
+	saveStringConfigToState(config.AuthPassword, checkResmonAuthPasswordAttr)
+	saveStringConfigToState(config.AuthUser, checkResmonAuthUserAttr)
+	saveIntConfigToState(config.Port, checkResmonPortAttr)
+	saveStringConfigToState(config.URL, checkResmonURLAttr)
+
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range swamp {
+		if _, ok := whitelistedConfigKeys[k]; ok {
+			delete(c.Config, k)
+		}
+
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			return fmt.Errorf("PROVIDER BUG: API Config not empty: %#v", swamp)
+		}
+	}
+
+	if err := d.Set(checkResmonAttr, schema.NewSet(hashCheckResmon, []interface{}{resmonConfig})); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkResmonAttr, err)
+	}
+
+	return nil
+}
+
+// hashCheckResmon creates a stable hash of the normalized values.
+func hashCheckResmon(v interface{}) int {
+	m := v.(map[string]interface{})
+	b := &bytes.Buffer{}
+	b.Grow(defaultHashBufSize)
+
+	writeInt := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(int) != 0 {
+			fmt.Fprintf(b, "%x", v.(int))
+		}
+	}
+
+	writeString := func(attrName schemaAttr) {
+		if v, ok := m[string(attrName)]; ok && v.(string) != "" {
+			fmt.Fprint(b, strings.TrimSpace(v.(string)))
+		}
+	}
+
+	// Order writes to the buffer using lexically sorted list for easy visual
+	// reconciliation with other lists.
+	writeString(checkResmonAuthMethodAttr)
+	writeString(checkResmonAuthPasswordAttr)
+	writeString(checkResmonAuthUserAttr)
+	writeInt(checkResmonPortAttr)
+	writeString(checkResmonURLAttr)
+
+	s := b.String()
+	return hashcode.String(s)
+}
+
+func checkConfigToAPIResmon(c *circonusCheck, l interfaceList) error {
+	c.Type = string(apiCheckTypeResmon)
+
+	// Iterate over all `resmon` attributes, even though we have a max of 1 in
+	// the schema.
+	for _, mapRaw := range l {
+		resmonConfig := newInterfaceMap(mapRaw)
+
+		if v, found := resmonConfig[checkResmonAuthMethodAttr]; found && v.(string) != "" {
+			c.Config[config.AuthMethod] = v.(string)
+		}
+
+		if v, found := resmonConfig[checkResmonAuthPasswordAttr]; found && v.(string) != "" {
+			c.Config[config.AuthPassword] = v.(string)
+		}
+
+		if v, found := resmonConfig[checkResmonAuthUserAttr]; found && v.(string) != "" {
+			c.Config[config.AuthUser] = v.(string)
+		}
+
+		if v, found := resmonConfig[checkResmonPortAttr]; found {
+			i := v.(int)
+			if i != 0 {
+				c.Config[config.Port] = fmt.Sprintf("%d", i)
+			}
+		}
+
+		if v, found := resmonConfig[checkResmonURLAttr]; found {
+			c.Config[config.URL] = v.(string)
+
+			u, err := url.Parse(v.(string))
+			if err != nil {
+				return fmt.Errorf("unable to parse %s %q: %w", checkResmonURLAttr, v.(string), err)
+			}
+
+			if len(c.Target) == 0 {
+				c.Target = u.Hostname()
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckResmon_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Resmon check - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckResmonConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.resmon", "active", "true"),
+					resource.TestCheckNoResourceAttr("circonus_check.resmon", "check_id"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.resmon", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "resmon.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "resmon.0.auth_method", "Basic"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "resmon.0.auth_user", "resmon"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "resmon.0.port", "81"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "resmon.0.url", "http://resmon.example.org:81/"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "period", "60s"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "metric.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "target", "resmon.example.org"),
+					resource.TestCheckResourceAttr("circonus_check.resmon", "type", "resmon"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckResmonConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "resmon" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  resmon {
+    url = "http://resmon.example.org:81/"
+    port = 81
+    auth_method = "Basic"
+    auth_user = "resmon"
+    auth_password = "secret"
+  }
+
+  metric {
+    name = "Core::Uptime` + "`" + `uptime` + "`" + `seconds"
+    type = "numeric"
+  }
+
+  tags = "${var.test_tags}"
+}
+`
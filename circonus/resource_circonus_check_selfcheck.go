@@ -0,0 +1,51 @@
+package circonus
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The selfcheck module reports the health of the broker it runs on and takes
+// no configuration.  Unlike the other check types the block is a list: an
+// empty element of a set is dropped from the statefile, an empty element of a
+// list is not.
+var schemaCheckSelfcheck = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	MinItems: 1,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+	},
+}
+
+// checkAPIToStateSelfcheck reads the Config data out of
+// circonusCheck.CheckBundle into the statefile.
+func checkAPIToStateSelfcheck(c *circonusCheck, d *schema.ResourceData) error {
+	whitelistedConfigKeys := map[config.Key]struct{}{
+		config.ReverseSecretKey: {},
+		config.SubmissionURL:    {},
+	}
+
+	for k := range c.Config {
+		if _, ok := whitelistedConfigKeys[k]; !ok {
+			log.Printf("[ERROR]: PROVIDER BUG: API Config not empty: %#v", c.Config)
+			break
+		}
+	}
+
+	if err := d.Set(checkSelfcheckAttr, []interface{}{map[string]interface{}{}}); err != nil {
+		return fmt.Errorf("Unable to store check %q attribute: %w", checkSelfcheckAttr, err)
+	}
+
+	return nil
+}
+
+func checkConfigToAPISelfcheck(c *circonusCheck, l interfaceList) error { //nolint:unparam
+	c.Type = string(apiCheckTypeSelfcheck)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package circonus
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCirconusCheckSelfcheck_basic(t *testing.T) {
+	checkName := fmt.Sprintf("Broker selfcheck - %s", acctest.RandString(5))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDestroyCirconusCheckBundle,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCirconusCheckSelfcheckConfigFmt, checkName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("circonus_check.broker", "active", "true"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "checks.#", "1"),
+					resource.TestMatchResourceAttr("circonus_check.broker", "checks.0", regexp.MustCompile(config.CheckCIDRegex)),
+					resource.TestCheckResourceAttr("circonus_check.broker", "collector.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "collector.0.id", "/broker/1"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "selfcheck.#", "1"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "name", checkName),
+					resource.TestCheckResourceAttr("circonus_check.broker", "period", "60s"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "metric.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "tags.#", "2"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "target", "127.0.0.1"),
+					resource.TestCheckResourceAttr("circonus_check.broker", "type", "selfcheck"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCirconusCheckSelfcheckConfigFmt = `
+variable "test_tags" {
+  type = list(string)
+  default = [ "author:terraform", "lifecycle:unittest" ]
+}
+resource "circonus_check" "broker" {
+  active = true
+  name = "%s"
+  period = "60s"
+
+  collector {
+    id = "/broker/1"
+  }
+
+  selfcheck {}
+
+  metric {
+    name = "check_cnt"
+    type = "numeric"
+  }
+
+  metric {
+    name = "version"
+    type = "text"
+  }
+
+  tags = "${var.test_tags}"
+  target = "127.0.0.1"
+}
+`
@@ -11,6 +11,7 @@ import (
 )
 
 const (
+	checkSMTPCodeRegexpAttr         = "code"
 	checkSMTPEhloAttr               = "ehlo"
 	checkSMTPFromAttr               = "from"
 	checkSMTPPayloadAttr            = "payload"
@@ -30,6 +31,7 @@ const (
 )
 
 var checkSMTPDescriptions = attrDescrs{
+	checkSMTPCodeRegexpAttr:         "A regular expression the code of the final response of the server must match, the check is marked bad otherwise. (default: any 2xx code)",
 	checkSMTPEhloAttr:               "Specifies the EHLO parameter. (default: noit.local)",
 	checkSMTPFromAttr:               "Specifies the envelope sender.",
 	checkSMTPPayloadAttr:            "Specifies the payload sent (on the wire). CR LF DOT CR LF is appended automatically. (default: Subject: Testing)",
@@ -46,6 +48,7 @@ var checkSMTPDescriptions = attrDescrs{
 	checkSMTPSaslUserAttr:           "The SASL Authentication username.",
 	checkSMTPStartTLSAttr:           "Specified if the client should attempt a STARTTLS upgrade. (default: false)",
 	checkSMTPToAttr:                 "Specifies the envelope recipient.",
+	checkTLSConfigAttr:              checkTLSConfigDescription,
 }
 
 var schemaCheckSMTP = &schema.Schema{
@@ -56,6 +59,11 @@ var schemaCheckSMTP = &schema.Schema{
 	Set:      hashCheckSMTP,
 	Elem: &schema.Resource{
 		Schema: convertToHelperSchema(checkSMTPDescriptions, map[schemaAttr]*schema.Schema{
+			checkSMTPCodeRegexpAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(checkSMTPCodeRegexpAttr, `.+`),
+			},
 			checkSMTPEhloAttr: {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -139,6 +147,7 @@ var schemaCheckSMTP = &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			checkTLSConfigAttr: schemaCheckTLS,
 		}),
 	},
 }
@@ -148,6 +157,10 @@ var schemaCheckSMTP = &schema.Schema{
 func checkAPIToStateSMTP(c *circonusCheck, d *schema.ResourceData) error {
 	smtpConfig := make(map[string]interface{}, len(c.Config))
 
+	if code, ok := c.Config[config.Code]; ok {
+		smtpConfig[string(checkSMTPCodeRegexpAttr)] = code
+	}
+
 	if ehlo, ok := c.Config[config.EHLO]; ok {
 		smtpConfig[string(checkSMTPEhloAttr)] = ehlo
 	}
@@ -218,6 +231,8 @@ func checkAPIToStateSMTP(c *circonusCheck, d *schema.ResourceData) error {
 		smtpConfig[string(checkSMTPToAttr)] = to
 	}
 
+	smtpConfig[string(checkTLSConfigAttr)] = checkTLSAPIToState(c, nil)
+
 	if err := d.Set(checkSMTPAttr, schema.NewSet(hashCheckSMTP, []interface{}{smtpConfig})); err != nil {
 		return fmt.Errorf("unable to store check %q attribute: %w", checkSMTPAttr, err)
 	}
@@ -251,6 +266,9 @@ func hashCheckSMTP(v interface{}) int {
 		}
 	}
 
+	if v, ok := m[string(checkSMTPCodeRegexpAttr)]; ok && v.(string) != "" {
+		fmt.Fprint(b, checkSMTPCodeRegexpAttr, v.(string))
+	}
 	writeString(checkSMTPEhloAttr)
 	writeString(checkSMTPFromAttr)
 	writeString(checkSMTPPayloadAttr)
@@ -267,17 +285,34 @@ func hashCheckSMTP(v interface{}) int {
 	writeString(checkSMTPSaslUserAttr)
 	writeBool(checkSMTPStartTLSAttr)
 	writeString(checkSMTPToAttr)
+	writeCheckTLSHash(b, m)
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPISMTP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPISMTP(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeSMTP)
 
 	mapRaw := l[0]
 	smtpConfig := newInterfaceMap(mapRaw)
 
+	// The broker only authenticates with both a user and a password, and
+	// ignores them without an authentication type.
+	saslAuth, _ := smtpConfig[checkSMTPSaslAuthenticationAttr].(string)
+	saslUser, _ := smtpConfig[checkSMTPSaslUserAttr].(string)
+	saslPassword, _ := smtpConfig[checkSMTPSaslPasswordAttr].(string)
+	switch {
+	case saslAuth != "" && saslAuth != "off" && (saslUser == "" || saslPassword == ""):
+		return fmt.Errorf("%s: %s %q requires %s and %s", checkSMTPAttr, checkSMTPSaslAuthenticationAttr, saslAuth, checkSMTPSaslUserAttr, checkSMTPSaslPasswordAttr)
+	case (saslAuth == "" || saslAuth == "off") && (saslUser != "" || saslPassword != ""):
+		return fmt.Errorf("%s: %s and %s require %s to be login or plain", checkSMTPAttr, checkSMTPSaslUserAttr, checkSMTPSaslPasswordAttr, checkSMTPSaslAuthenticationAttr)
+	}
+
+	if v, found := smtpConfig[checkSMTPCodeRegexpAttr]; found && v.(string) != "" {
+		c.Config[config.Code] = v.(string)
+	}
+
 	if v, found := smtpConfig[checkSMTPEhloAttr]; found && v.(string) != "" {
 		c.Config[config.EHLO] = v.(string)
 	}
@@ -342,5 +377,7 @@ func checkConfigToAPISMTP(c *circonusCheck, l interfaceList) error { //nolint:un
 		c.Config[config.To] = v.(string)
 	}
 
+	checkTLSConfigToAPI(c, smtpConfig)
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckSMTP_basic(t *testing.T) {
@@ -74,3 +75,65 @@ resource "circonus_check" "smtp" {
   target = "127.0.0.1"
 }
 `
+
+func TestCheckSMTPConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkSMTPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkSMTPToAttr):                 "postmaster@example.org",
+				string(checkSMTPEhloAttr):               "monitor.example.org",
+				string(checkSMTPStartTLSAttr):           true,
+				string(checkSMTPSaslAuthenticationAttr): "plain",
+				string(checkSMTPSaslUserAttr):           "monitor",
+				string(checkSMTPSaslPasswordAttr):       "hunter2",
+				string(checkSMTPCodeRegexpAttr):         "^250",
+			},
+		},
+	})
+
+	smtpConfig := d.Get(string(checkSMTPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPISMTP(&c, smtpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		config.Code:               "^250",
+		config.EHLO:               "monitor.example.org",
+		config.StartTLS:           "true",
+		config.SASLAuthentication: "plain",
+		config.SASLUser:           "monitor",
+		config.SASLPassword:       "hunter2",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+
+	if err := checkAPIToStateSMTP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkSMTPAttr)).(*schema.Set).List()
+	if hashCheckSMTP(state[0]) != hashCheckSMTP(smtpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	for name, smtp := range map[string]map[string]interface{}{
+		"auth without a password": {
+			string(checkSMTPSaslAuthenticationAttr): "login",
+			string(checkSMTPSaslUserAttr):           "monitor",
+		},
+		"credentials without auth": {
+			string(checkSMTPSaslUserAttr):     "monitor",
+			string(checkSMTPSaslPasswordAttr): "hunter2",
+		},
+	} {
+		c := newCheck()
+		if err := checkConfigToAPISMTP(&c, interfaceList{smtp}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
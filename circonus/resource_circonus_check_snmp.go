@@ -30,13 +30,18 @@ const (
 	checkSNMPSecurityName      = "security_name"
 	checkSNMPSeparateQueries   = "separate_queries"
 	checkSNMPVersion           = "version"
+	checkSNMPWalk              = "walk"
 )
 
+// apiSNMPWalkPrefix is the prefix of the dynamic config keys of OID subtree
+// walks: `walk_(.+)`.
+const apiSNMPWalkPrefix = config.Key("walk_")
+
 var checkSNMPDescriptions = attrDescrs{
 
 	checkSNMPAuthPassphrase:    "The authentication passphrase to use. Only applicaable to SNMP Version 3.",
 	checkSNMPAuthProtocol:      "The authentication protocol to use. Only applicaable to SNMP Version 3.",
-	checkSNMPCommunity:         "The SNMP community string providing read access.",
+	checkSNMPCommunity:         "The SNMP community string providing read access. Required by SNMP Versions 1 and 2c.",
 	checkSNMPContextEngine:     "The context engine hex value to use. Only applicaable to SNMP Version 3.",
 	checkSNMPContextName:       "The context name to use. Only applicaable to SNMP Version 3.",
 	checkSNMPOID:               "Defines a metric to query.",
@@ -48,6 +53,7 @@ var checkSNMPDescriptions = attrDescrs{
 	checkSNMPSecurityName:      "The security name (or user name) to use. Only applicaable to SNMP Version 3.",
 	checkSNMPSeparateQueries:   "Whether or not to query each OID separately.",
 	checkSNMPVersion:           "The SNMP version used for queries.",
+	checkSNMPWalk:              "Defines an OID subtree to walk, producing a metric for each OID found.",
 }
 
 var checkSNMPOIDDescriptions = attrDescrs{
@@ -56,6 +62,12 @@ var checkSNMPOIDDescriptions = attrDescrs{
 	checkSNMPOIDType: "The metric type of this OID. The value can be either one of the single letter codes in the metric_type_t enum or the following string variants: guess, int32, uint32, int64, uint64, double, string.",
 }
 
+var checkSNMPWalkDescriptions = attrDescrs{
+	checkSNMPOIDName: "Prefix of the names of the metrics produced by this walk, each metric is named <name>`<index> after the OID's index below the subtree.",
+	checkSNMPOIDPath: "The decimal notation or MIB name of the subtree to walk, e.g. a table column.",
+	checkSNMPOIDType: "The metric type of the OIDs found. Accepts the same values as the type of an oid.",
+}
+
 var schemaCheckSNMP = &schema.Schema{
 	Type:     schema.TypeList,
 	Optional: true,
@@ -66,6 +78,7 @@ var schemaCheckSNMP = &schema.Schema{
 			checkSNMPAuthPassphrase: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkSNMPAuthPassphrase, `.+`),
 			},
 			checkSNMPAuthProtocol: {
@@ -76,7 +89,7 @@ var schemaCheckSNMP = &schema.Schema{
 			},
 			checkSNMPCommunity: {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				ValidateFunc: validateRegexp(checkSNMPCommunity, `.+`),
 			},
 			checkSNMPContextEngine: {
@@ -97,6 +110,7 @@ var schemaCheckSNMP = &schema.Schema{
 			checkSNMPPrivacyPassphrase: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Sensitive:    true,
 				ValidateFunc: validateRegexp(checkSNMPPrivacyPassphrase, `.+`),
 			},
 			checkSNMPPrivacyProtocol: {
@@ -133,8 +147,7 @@ var schemaCheckSNMP = &schema.Schema{
 			},
 			checkSNMPOID: {
 				Type:     schema.TypeList,
-				Required: true,
-				MinItems: 1,
+				Optional: true,
 				Elem: &schema.Resource{
 					Schema: convertToHelperSchema(checkSNMPOIDDescriptions, map[schemaAttr]*schema.Schema{
 						checkSNMPOIDName: {
@@ -155,6 +168,29 @@ var schemaCheckSNMP = &schema.Schema{
 					}),
 				},
 			},
+			checkSNMPWalk: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkSNMPWalkDescriptions, map[schemaAttr]*schema.Schema{
+						checkSNMPOIDName: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDName, `^.+$`),
+						},
+						checkSNMPOIDPath: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDPath, `^.+$`),
+						},
+						checkSNMPOIDType: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(checkSNMPOIDType, `^.+$`),
+						},
+					}),
+				},
+			},
 		}),
 	},
 }
@@ -247,6 +283,32 @@ func checkAPIToStateSNMP(c *circonusCheck, d *schema.ResourceData) error {
 		}
 	}
 
+	walkList := make([]interface{}, 0)
+	for k, v := range c.Config {
+		key := string(k)
+		if strings.HasPrefix(key, string(apiSNMPWalkPrefix)) {
+			walkProps := make(map[string]interface{})
+			name := strings.TrimPrefix(key, string(apiSNMPWalkPrefix))
+			walkProps[string(checkSNMPOIDName)] = name
+			walkProps[string(checkSNMPOIDPath)] = v
+
+			t := string(config.TypePrefix) + name
+			if tv, ok := c.Config[config.Key(t)]; ok {
+				walkProps[string(checkSNMPOIDType)] = tv
+				delete(swamp, config.Key(t))
+			}
+			delete(swamp, k)
+			walkList = append(walkList, walkProps)
+		}
+	}
+
+	sort.Slice(walkList, func(i, j int) bool {
+		y := walkList[i].(map[string]interface{})
+		z := walkList[j].(map[string]interface{})
+		return y[string(checkSNMPOIDName)].(string) < z[string(checkSNMPOIDName)].(string)
+	})
+	snmpConfig[string(checkSNMPWalk)] = walkList
+
 	sort.Slice(oidList, func(i, j int) bool {
 		if oidList[i] != nil && oidList[j] != nil {
 			y := oidList[i].(map[string]interface{})
@@ -287,6 +349,10 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error { //nolint:un
 	for _, mapRaw := range l {
 		snmpConfig := newInterfaceMap(mapRaw)
 
+		if err := checkSNMPValidate(snmpConfig); err != nil {
+			return err
+		}
+
 		if v, found := snmpConfig[checkSNMPAuthPassphrase]; found {
 			c.Config[config.AuthPassphrase] = v.(string)
 		}
@@ -295,7 +361,7 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error { //nolint:un
 			c.Config[config.AuthProtocol] = v.(string)
 		}
 
-		if v, found := snmpConfig[checkSNMPCommunity]; found {
+		if v, found := snmpConfig[checkSNMPCommunity]; found && v.(string) != "" {
 			c.Config[config.Community] = v.(string)
 		}
 
@@ -344,6 +410,98 @@ func checkConfigToAPISNMP(c *circonusCheck, l interfaceList) error { //nolint:un
 				c.Config[config.Key(fmt.Sprintf("type_%s", n[string(checkSNMPOIDName)].(string)))] = n[string(checkSNMPOIDType)].(string)
 			}
 		}
+
+		if v, found := snmpConfig[checkSNMPWalk]; found {
+			for _, ll := range v.([]interface{}) {
+				n, ok := ll.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name := n[string(checkSNMPOIDName)].(string)
+				c.Config[apiSNMPWalkPrefix+config.Key(name)] = n[string(checkSNMPOIDPath)].(string)
+				if t, _ := n[string(checkSNMPOIDType)].(string); t != "" {
+					c.Config[config.TypePrefix+config.Key(name)] = t
+				}
+			}
+		}
 	}
 	return nil
 }
+
+// checkSNMPValidate verifies the attributes of an snmp block are consistent.
+// OIDs and walks share the metric type keys so their names must be unique.
+// SNMP Versions 1 and 2c authenticate with a community while SNMP Version 3
+// uses the User-based Security Model, whose passphrases are required by the
+// chosen security level.
+func checkSNMPValidate(snmpConfig interfaceMap) error {
+	isSet := func(attrName schemaAttr) bool {
+		v, found := snmpConfig[string(attrName)]
+		return found && v.(string) != ""
+	}
+
+	v3Attrs := []schemaAttr{
+		checkSNMPAuthPassphrase,
+		checkSNMPContextEngine,
+		checkSNMPContextName,
+		checkSNMPPrivacyPassphrase,
+		checkSNMPSecurityEngine,
+		checkSNMPSecurityName,
+	}
+
+	names := make(map[string]struct{})
+	for _, attrName := range []schemaAttr{checkSNMPOID, checkSNMPWalk} {
+		l, _ := snmpConfig[string(attrName)].([]interface{})
+		for _, ll := range l {
+			n, ok := ll.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _ := n[string(checkSNMPOIDName)].(string)
+			if _, found := names[name]; found {
+				return fmt.Errorf("%s %q is used by more than one %s or %s", checkSNMPOIDName, name, checkSNMPOID, checkSNMPWalk)
+			}
+			names[name] = struct{}{}
+		}
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("at least one %s or %s is required", checkSNMPOID, checkSNMPWalk)
+	}
+
+	version, _ := snmpConfig[checkSNMPVersion].(string)
+	if version != "3" {
+		if !isSet(checkSNMPCommunity) {
+			return fmt.Errorf("%s is required by SNMP version %s", checkSNMPCommunity, version)
+		}
+
+		for _, attrName := range v3Attrs {
+			if isSet(attrName) {
+				return fmt.Errorf("%s is only applicable to SNMP version 3, not %s", attrName, version)
+			}
+		}
+
+		return nil
+	}
+
+	if !isSet(checkSNMPSecurityName) {
+		return fmt.Errorf("%s is required by SNMP version 3", checkSNMPSecurityName)
+	}
+
+	securityLevel, _ := snmpConfig[checkSNMPSecurityLevel].(string)
+	auth := securityLevel == "authNoPriv" || securityLevel == "authPriv"
+	priv := securityLevel == "authPriv"
+
+	switch {
+	case auth && !isSet(checkSNMPAuthPassphrase):
+		return fmt.Errorf("%s is required by %s %q", checkSNMPAuthPassphrase, checkSNMPSecurityLevel, securityLevel)
+	case !auth && isSet(checkSNMPAuthPassphrase):
+		return fmt.Errorf("%s requires %s \"authNoPriv\" or \"authPriv\", not %q", checkSNMPAuthPassphrase, checkSNMPSecurityLevel, securityLevel)
+	case priv && !isSet(checkSNMPPrivacyPassphrase):
+		return fmt.Errorf("%s is required by %s %q", checkSNMPPrivacyPassphrase, checkSNMPSecurityLevel, securityLevel)
+	case !priv && isSet(checkSNMPPrivacyPassphrase):
+		return fmt.Errorf("%s requires %s \"authPriv\", not %q", checkSNMPPrivacyPassphrase, checkSNMPSecurityLevel, securityLevel)
+	}
+
+	return nil
+}
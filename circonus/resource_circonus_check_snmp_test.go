@@ -45,6 +45,101 @@ func TestAccCirconusCheckSNMP_basic(t *testing.T) {
 	})
 }
 
+func TestCheckSNMPValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		ok     bool
+	}{
+		{"v2c", map[string]interface{}{"version": "2c", "community": "public"}, true},
+		{"v2c without community", map[string]interface{}{"version": "2c"}, false},
+		{"v2c with v3 attributes", map[string]interface{}{"version": "2c", "community": "public", "security_name": "admin"}, false},
+		{"v3 noAuthNoPriv", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "noAuthNoPriv"}, true},
+		{"v3 without security_name", map[string]interface{}{"version": "3", "security_level": "noAuthNoPriv"}, false},
+		{"v3 noAuthNoPriv with auth_passphrase", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "noAuthNoPriv", "auth_passphrase": "secret"}, false},
+		{"v3 authNoPriv", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "authNoPriv", "auth_passphrase": "secret", "context_name": "vlan-1"}, true},
+		{"v3 authNoPriv without auth_passphrase", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "authNoPriv"}, false},
+		{"v3 authNoPriv with privacy_passphrase", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "authNoPriv", "auth_passphrase": "secret", "privacy_passphrase": "secret"}, false},
+		{"v3 authPriv", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "authPriv", "auth_passphrase": "secret", "privacy_passphrase": "secret"}, true},
+		{"v3 authPriv without privacy_passphrase", map[string]interface{}{"version": "3", "security_name": "admin", "security_level": "authPriv", "auth_passphrase": "secret"}, false},
+	}
+
+	for _, test := range tests {
+		test.config["oid"] = []interface{}{
+			map[string]interface{}{"name": "sysUpTime", "path": ".1.3.6.1.2.1.1.3.0"},
+		}
+		err := checkSNMPValidate(newInterfaceMap(test.config))
+		if test.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestCheckSNMPWalk(t *testing.T) {
+	walk := func(name, path, typ string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "path": path, "type": typ}
+	}
+
+	snmpConfig := map[string]interface{}{
+		"version":   "2c",
+		"community": "public",
+		"walk": []interface{}{
+			walk("ifHCInOctets", ".1.3.6.1.2.1.31.1.1.1.6", "uint64"),
+			walk("ifOperStatus", ".1.3.6.1.2.1.2.2.1.8", ""),
+		},
+	}
+
+	c := newCheck()
+	if err := checkConfigToAPISNMP(&c, interfaceList{snmpConfig}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		"walk_ifHCInOctets": ".1.3.6.1.2.1.31.1.1.1.6",
+		"type_ifHCInOctets": "uint64",
+		"walk_ifOperStatus": ".1.3.6.1.2.1.2.2.1.8",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+	if _, found := c.Config["type_ifOperStatus"]; found {
+		t.Errorf("expected no type for a walk without one")
+	}
+
+	d := resourceCheck().TestResourceData()
+	if err := checkAPIToStateSNMP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for k, v := range map[string]string{
+		"snmp.0.walk.#":      "2",
+		"snmp.0.walk.0.name": "ifHCInOctets",
+		"snmp.0.walk.0.type": "uint64",
+		"snmp.0.walk.1.name": "ifOperStatus",
+		"snmp.0.walk.1.path": ".1.3.6.1.2.1.2.2.1.8",
+	} {
+		if got := fmt.Sprint(d.Get(k)); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+
+	snmpConfig["oid"] = []interface{}{walk("ifOperStatus", ".1.3.6.1.2.1.2.2.1.8.1", "")}
+	if err := checkSNMPValidate(newInterfaceMap(snmpConfig)); err == nil {
+		t.Errorf("expected an error for an oid and a walk sharing a name")
+	}
+
+	delete(snmpConfig, "oid")
+	delete(snmpConfig, "walk")
+	if err := checkSNMPValidate(newInterfaceMap(snmpConfig)); err == nil {
+		t.Errorf("expected an error without an oid or walk")
+	}
+}
+
 const testAccCirconusCheckSNMPConfigFmt = `
 variable "test_tags" {
   type = list(string)
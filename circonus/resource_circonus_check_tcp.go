@@ -21,7 +21,24 @@ const (
 	checkTCPHostAttr         = "host"
 	checkTCPKeyFileAttr      = "key_file"
 	checkTCPPortAttr         = "port"
+	checkTCPStepAttr         = "step"
 	checkTCPTLSAttr          = "tls"
+
+	// circonus_check.tcp.step.* resource attribute names.
+	checkTCPStepExpectAttr = "expect"
+	checkTCPStepSendAttr   = "send"
+
+	// circonus_check.tcp.tls_config.* resource attribute names only supported
+	// by the tcp check.
+	checkTCPTLSServerNameAttr = "server_name"
+)
+
+const (
+	// checkTCPStepSendKeyFmt and checkTCPStepExpectKeyFmt are the formats of
+	// the config keys of the steps, numbered from 1 in the order they run.
+	// They are not known to the API client.
+	checkTCPStepSendKeyFmt   = "send_%d"
+	checkTCPStepExpectKeyFmt = "expect_%d"
 )
 
 var checkTCPDescriptions = attrDescrs{
@@ -32,7 +49,18 @@ var checkTCPDescriptions = attrDescrs{
 	checkTCPHostAttr:         "Specifies the host name or IP address to connect to for this TCP check",
 	checkTCPKeyFileAttr:      "A path to a file containing key to be used in conjunction with the cilent certificate (for TLS checks)",
 	checkTCPPortAttr:         "Specifies the port on which the management interface can be reached.",
+	checkTCPStepAttr:         "An ordered list of strings to send and regular expressions the responses must match once connected",
 	checkTCPTLSAttr:          "Upgrade TCP connection to use TLS.",
+	checkTLSConfigAttr:       checkTLSConfigDescription,
+}
+
+var checkTCPStepDescriptions = attrDescrs{
+	checkTCPStepExpectAttr: "A regular expression the response read after the send of the step, if any, must match",
+	checkTCPStepSendAttr:   "A string sent to the target",
+}
+
+var checkTCPTLSDescriptions = attrDescrs{
+	checkTCPTLSServerNameAttr: "The server name sent with SNI, overriding the host",
 }
 
 var schemaCheckTCP = &schema.Schema{
@@ -51,16 +79,19 @@ var schemaCheckTCP = &schema.Schema{
 			checkTCPCAChainAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkTCPCAChainAttr, `.+`),
 			},
 			checkTCPCertFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkTCPCertFileAttr, `.+`),
 			},
 			checkTCPCiphersAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkTCPCiphersAttr, `.+`),
 			},
 			checkTCPHostAttr: {
@@ -71,6 +102,7 @@ var schemaCheckTCP = &schema.Schema{
 			checkTCPKeyFileAttr: {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Deprecated:   checkTLSDeprecation,
 				ValidateFunc: validateRegexp(checkTCPKeyFileAttr, `.+`),
 			},
 			checkTCPPortAttr: {
@@ -81,11 +113,34 @@ var schemaCheckTCP = &schema.Schema{
 					validateIntMax(checkTCPPortAttr, 65535),
 				),
 			},
+			checkTCPStepAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(checkTCPStepDescriptions, map[schemaAttr]*schema.Schema{
+						checkTCPStepExpectAttr: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						checkTCPStepSendAttr: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					}),
+				},
+			},
 			checkTCPTLSAttr: {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  false,
 			},
+			checkTLSConfigAttr: newSchemaCheckTLS(checkTCPTLSDescriptions, map[schemaAttr]*schema.Schema{
+				checkTCPTLSServerNameAttr: {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateRegexp(checkTCPTLSServerNameAttr, `^[^\s:/]+$`),
+				},
+			}),
 		}),
 	},
 }
@@ -139,12 +194,25 @@ func checkAPIToStateTCP(c *circonusCheck, d *schema.ResourceData) error {
 	}
 
 	saveStringConfigToState(config.BannerMatch, checkTCPBannerRegexpAttr)
-	saveStringConfigToState(config.CAChain, checkTCPCAChainAttr)
-	saveStringConfigToState(config.CertFile, checkTCPCertFileAttr)
-	saveStringConfigToState(config.Ciphers, checkTCPCiphersAttr)
+	if checkTLSLegacyInUse(d, checkTCPAttr) {
+		saveStringConfigToState(config.CAChain, checkTCPCAChainAttr)
+		saveStringConfigToState(config.CertFile, checkTCPCertFileAttr)
+		saveStringConfigToState(config.Ciphers, checkTCPCiphersAttr)
+		saveStringConfigToState(config.KeyFile, checkTCPKeyFileAttr)
+	} else {
+		tlsState := checkTLSAPIToState(c, swamp)
+		if v, ok := c.Config[apiHTTPTLSServerName]; ok && v != "" {
+			if len(tlsState) == 0 {
+				tlsState = []interface{}{map[string]interface{}{}}
+			}
+			tlsState[0].(map[string]interface{})[string(checkTCPTLSServerNameAttr)] = v
+		}
+		delete(swamp, apiHTTPTLSServerName)
+		tcpConfig[string(checkTLSConfigAttr)] = tlsState
+	}
 	tcpConfig[string(checkTCPHostAttr)] = c.Target
-	saveStringConfigToState(config.KeyFile, checkTCPKeyFileAttr)
 	saveIntConfigToState(config.Port, checkTCPPortAttr)
+	tcpConfig[string(checkTCPStepAttr)] = checkTCPStepsToState(c, swamp)
 	saveBoolConfigToState(config.UseSSL, checkTCPTLSAttr)
 
 	whitelistedConfigKeys := map[config.Key]struct{}{
@@ -202,19 +270,39 @@ func hashCheckTCP(v interface{}) int {
 	writeString(checkTCPHostAttr)
 	writeString(checkTCPKeyFileAttr)
 	writeInt(checkTCPPortAttr)
+	if l, ok := m[string(checkTCPStepAttr)].([]interface{}); ok {
+		for i, stepRaw := range l {
+			step := newInterfaceMap(stepRaw)
+			fmt.Fprint(b, checkTCPStepAttr, i)
+			for _, attrName := range []schemaAttr{checkTCPStepExpectAttr, checkTCPStepSendAttr} {
+				if v, ok := step[string(attrName)].(string); ok && v != "" {
+					fmt.Fprint(b, attrName, v)
+				}
+			}
+		}
+	}
 	writeBool(checkTCPTLSAttr)
+	writeCheckTLSHash(b, m)
+	for _, tlsConfig := range checkTLSConfigList(m) {
+		if v, ok := tlsConfig[string(checkTCPTLSServerNameAttr)].(string); ok && v != "" {
+			fmt.Fprint(b, checkTCPTLSServerNameAttr, strings.TrimSpace(v))
+		}
+	}
 
 	s := b.String()
 	return hashcode.String(s)
 }
 
-func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error { //nolint:unparam
+func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error {
 	c.Type = string(apiCheckTypeTCP)
 
 	// Iterate over all `tcp` attributes, even though we have a max of 1 in the
 	// schema.
 	for _, mapRaw := range l {
 		tcpConfig := newInterfaceMap(mapRaw)
+		if err := checkTLSConflict(checkTCPAttr, tcpConfig); err != nil {
+			return err
+		}
 
 		if v, found := tcpConfig[checkTCPBannerRegexpAttr]; found {
 			c.Config[config.BannerMatch] = v.(string)
@@ -240,10 +328,25 @@ func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error { //nolint:unp
 			c.Config[config.KeyFile] = v.(string)
 		}
 
+		checkTLSConfigToAPI(c, tcpConfig)
+		for _, tlsConfig := range checkTLSConfigList(tcpConfig) {
+			// The tcp module reads the server name from the same key as the
+			// http module.
+			if v, ok := tlsConfig[string(checkTCPTLSServerNameAttr)].(string); ok && v != "" {
+				c.Config[apiHTTPTLSServerName] = v
+			}
+		}
+
 		if v, found := tcpConfig[checkTCPPortAttr]; found {
 			c.Config[config.Port] = fmt.Sprintf("%d", v.(int))
 		}
 
+		if v, found := tcpConfig[checkTCPStepAttr]; found {
+			if err := checkTCPStepsToAPI(c, v.([]interface{})); err != nil {
+				return err
+			}
+		}
+
 		if v, found := tcpConfig[checkTCPTLSAttr]; found {
 			c.Config[config.UseSSL] = fmt.Sprintf("%t", v.(bool))
 		}
@@ -251,3 +354,47 @@ func checkConfigToAPITCP(c *circonusCheck, l interfaceList) error { //nolint:unp
 
 	return nil
 }
+
+// checkTCPStepsToAPI numbers the steps of the tcp block into the check's
+// config in the order they run.
+func checkTCPStepsToAPI(c *circonusCheck, l []interface{}) error {
+	for i, stepRaw := range l {
+		step := newInterfaceMap(stepRaw)
+		send, _ := step[string(checkTCPStepSendAttr)].(string)
+		expect, _ := step[string(checkTCPStepExpectAttr)].(string)
+		if send == "" && expect == "" {
+			return fmt.Errorf("%s.%s.%d: one of %s or %s must be specified", checkTCPAttr, checkTCPStepAttr, i, checkTCPStepSendAttr, checkTCPStepExpectAttr)
+		}
+
+		if send != "" {
+			c.Config[config.Key(fmt.Sprintf(checkTCPStepSendKeyFmt, i+1))] = send
+		}
+		if expect != "" {
+			c.Config[config.Key(fmt.Sprintf(checkTCPStepExpectKeyFmt, i+1))] = expect
+		}
+	}
+
+	return nil
+}
+
+// checkTCPStepsToState returns the steps found in the check's config, removing
+// them from swamp.  The steps are read until the first number without one.
+func checkTCPStepsToState(c *circonusCheck, swamp map[config.Key]string) []interface{} {
+	steps := make([]interface{}, 0)
+	for i := 1; ; i++ {
+		sendKey := config.Key(fmt.Sprintf(checkTCPStepSendKeyFmt, i))
+		expectKey := config.Key(fmt.Sprintf(checkTCPStepExpectKeyFmt, i))
+		send, sendFound := c.Config[sendKey]
+		expect, expectFound := c.Config[expectKey]
+		if !sendFound && !expectFound {
+			return steps
+		}
+
+		steps = append(steps, map[string]interface{}{
+			string(checkTCPStepSendAttr):   send,
+			string(checkTCPStepExpectAttr): expect,
+		})
+		delete(swamp, sendKey)
+		delete(swamp, expectKey)
+	}
+}
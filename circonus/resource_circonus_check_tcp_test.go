@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestAccCirconusCheckTCP_basic(t *testing.T) {
@@ -148,3 +150,68 @@ resource "circonus_check" "tls_cert" {
   tags = "${var.tcp_check_tags}"
 }
 `
+
+func TestCheckTCPSteps(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCheck().Schema, map[string]interface{}{
+		string(checkTCPAttr): []interface{}{
+			map[string]interface{}{
+				string(checkTCPHostAttr):         "mq.example.org",
+				string(checkTCPPortAttr):         5671,
+				string(checkTCPBannerRegexpAttr): "^AMQP",
+				string(checkTCPStepAttr): []interface{}{
+					map[string]interface{}{string(checkTCPStepSendAttr): "AMQP\x00\x00\x09\x01"},
+					map[string]interface{}{string(checkTCPStepExpectAttr): "^\\x01"},
+					map[string]interface{}{string(checkTCPStepSendAttr): "PING\r\n", string(checkTCPStepExpectAttr): "^PONG"},
+				},
+				string(checkTLSConfigAttr): []interface{}{
+					map[string]interface{}{
+						string(checkTLSCertFileAttr):      "/opt/certs/client.crt",
+						string(checkTLSKeyFileAttr):       "/opt/certs/client.key",
+						string(checkTCPTLSServerNameAttr): "mq.internal.example.org",
+					},
+				},
+			},
+		},
+	})
+
+	tcpConfig := d.Get(string(checkTCPAttr)).(*schema.Set).List()
+
+	c := newCheck()
+	if err := checkConfigToAPITCP(&c, tcpConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[config.Key]string{
+		"send_1":             "AMQP\x00\x00\x09\x01",
+		"expect_2":           "^\\x01",
+		"send_3":             "PING\r\n",
+		"expect_3":           "^PONG",
+		apiHTTPTLSServerName: "mq.internal.example.org",
+		config.CertFile:      "/opt/certs/client.crt",
+	}
+	for k, v := range expected {
+		if c.Config[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, c.Config[k])
+		}
+	}
+	for _, k := range []config.Key{"expect_1", "send_2"} {
+		if _, found := c.Config[k]; found {
+			t.Errorf("expected no %s", k)
+		}
+	}
+
+	if err := checkAPIToStateTCP(&c, d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := d.Get(string(checkTCPAttr)).(*schema.Set).List()
+	if hashCheckTCP(state[0]) != hashCheckTCP(tcpConfig[0]) {
+		t.Errorf("expected the hash of the state to match the hash of the config, got %#v", state[0])
+	}
+
+	c = newCheck()
+	err := checkTCPStepsToAPI(&c, []interface{}{map[string]interface{}{}})
+	if err == nil {
+		t.Errorf("expected an empty step to be rejected")
+	}
+}
@@ -2,25 +2,34 @@ package circonus
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
 	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
 	"github.com/circonus-labs/terraform-provider-circonus/internal/hashcode"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/timeutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_contact attributes.
 	contactAggregationWindowAttr = "aggregation_window"
+	contactAllowEmptyAttr        = "allow_empty"
 	contactAlwaysSendClearAttr   = "always_send_clear"
+	contactAuthoritativeAttr     = "authoritative"
 	contactGroupTypeAttr         = "group_type"
 	contactAlertOptionAttr       = "alert_option"
 	contactEmailAttr             = "email"
+	contactFloodControlAttr      = "flood_control"
 	contactHTTPAttr              = "http"
 	contactLongMessageAttr       = "long_message"
 	contactLongSubjectAttr       = "long_subject"
@@ -48,6 +57,7 @@ const (
 	// circonus_contact.http attributes.
 	contactHTTPFormatAttr             = "format"
 	contactHTTPMethodAttr             = "method"
+	contactHTTPParamsAttr             = "params"
 	contactHTTPAddressAttr schemaAttr = "address"
 
 	// circonus_contact.pager_duty attributes
@@ -58,10 +68,11 @@ const (
 
 	// circonus_contact.slack attributes
 	// contactContactGroupFallbackAttr.
-	contactSlackButtonsAttr  = "buttons"
-	contactSlackChannelAttr  = "channel"
-	contactSlackTeamAttr     = "team"
-	contactSlackUsernameAttr = "username"
+	contactSlackButtonsAttr   = "buttons"
+	contactSlackChannelAttr   = "channel"
+	contactSlackChannelIDAttr = "channel_id"
+	contactSlackTeamAttr      = "team"
+	contactSlackUsernameAttr  = "username"
 
 	// circonus_contact.sms attributes.
 	contactSMSAddressAttr = "address"
@@ -80,14 +91,24 @@ const (
 	contactXMPPAddressAttr = "address"
 
 	// circonus_contact read-only attributes.
-	contactLastModifiedAttr   = "last_modified"
-	contactLastModifiedByAttr = "last_modified_by"
+	contactConfigHashAttr         = "config_hash"
+	contactCreatedAttr            = "created"
+	contactCreatedByAttr          = "created_by"
+	contactEffectiveGroupTypeAttr = "effective_group_type"
+	contactLastModifiedAttr       = "last_modified"
+	contactLastModifiedByAttr     = "last_modified_by"
+	contactManagedContactsAttr    = "managed_contacts"
 
 	// circonus_contact.* shared attributes.
 	contactContactGroupFallbackAttr = "contact_group_fallback"
 	contactUserCIDAttr              = "user"
+	contactUserEmailAttr            = "user_email"
 )
 
+// contactImportNamePrefix is the prefix of an import ID that identifies a
+// contact group by name rather than by CID (e.g. `name=Ops-Primary`).
+const contactImportNamePrefix = "name="
+
 const (
 	// Contact methods from Circonus.
 	circonusMethodEmail     = "email"
@@ -99,6 +120,10 @@ const (
 	circonusMethodXMPP      = "xmpp"
 )
 
+// contactHTTPFormatParams is the http format whose parameters are set with
+// the params attribute.
+const contactHTTPFormatParams = "params"
+
 type contactHTTPInfo struct {
 	Address string `json:"url"`
 	Format  string `json:"params"`
@@ -114,6 +139,7 @@ type contactPagerDutyInfo struct {
 
 type contactSlackInfo struct {
 	Channel          string `json:"channel"`
+	ChannelID        string `json:"channel_id,omitempty"`
 	Team             string `json:"team"`
 	Username         string `json:"username"`
 	Buttons          int    `json:"buttons,string"`
@@ -131,14 +157,22 @@ type contactVictorOpsInfo struct {
 
 var contactGroupDescriptions = attrDescrs{
 	contactAggregationWindowAttr:    "",
+	contactAllowEmptyAttr:           "Accept a contact group without any contact method, which notifies nobody",
 	contactAlwaysSendClearAttr:      "",
-	contactGroupTypeAttr:            "",
+	contactAuthoritativeAttr:        "Manage all of the contacts of the group, set to false to only manage the contacts Terraform added and leave the others alone",
+	contactGroupTypeAttr:            "The type of contact group (e.g. normal or on_call)",
 	contactAlertOptionAttr:          "",
 	contactContactGroupFallbackAttr: "",
+	contactConfigHashAttr:           "Checksum of the normalized contact group as stored by the Circonus API",
+	contactCreatedAttr:              "When the contact group was created, in epoch seconds, when reported by the API",
+	contactCreatedByAttr:            "The CID of the user who created the contact group, when reported by the API",
+	contactEffectiveGroupTypeAttr:   "The contact group type as stored by the Circonus API",
 	contactEmailAttr:                "",
+	contactFloodControlAttr:         "A flood control preset (off, low, medium or high) that sets the aggregation window used to batch alert notifications",
 	contactHTTPAttr:                 "",
-	contactLastModifiedAttr:         "",
-	contactLastModifiedByAttr:       "",
+	contactLastModifiedAttr:         "When the contact group was last modified, in epoch seconds",
+	contactLastModifiedByAttr:       "The CID of the user who last modified the contact group",
+	contactManagedContactsAttr:      "The contacts Terraform added to the group when authoritative is false",
 	contactLongMessageAttr:          "",
 	contactLongSubjectAttr:          "",
 	contactLongSummaryAttr:          "",
@@ -163,12 +197,14 @@ var contactAlertDescriptions = attrDescrs{
 var contactEmailDescriptions = attrDescrs{
 	contactEmailAddressAttr: "",
 	contactUserCIDAttr:      "",
+	contactUserEmailAttr:    contactUserEmailDescription,
 }
 
 var contactHTTPDescriptions = attrDescrs{
 	contactHTTPAddressAttr: "",
 	contactHTTPFormatAttr:  "",
 	contactHTTPMethodAttr:  "",
+	contactHTTPParamsAttr:  "Parameters sent with the request when format is params, encoded by the provider",
 }
 
 var contactPagerDutyDescriptions = attrDescrs{
@@ -181,14 +217,16 @@ var contactPagerDutyDescriptions = attrDescrs{
 var contactSlackDescriptions = attrDescrs{
 	contactContactGroupFallbackAttr: "",
 	contactSlackButtonsAttr:         "",
-	contactSlackChannelAttr:         "",
+	contactSlackChannelAttr:         "The name of the Slack channel, e.g. #ops",
+	contactSlackChannelIDAttr:       "The ID of the Slack channel, e.g. C024BE91L",
 	contactSlackTeamAttr:            "",
 	contactSlackUsernameAttr:        "Username Slackbot uses in Slack to deliver a notification",
 }
 
 var contactSMSDescriptions = attrDescrs{
-	contactSMSAddressAttr: "",
+	contactSMSAddressAttr: "The phone number to send a short notification to, in international (E.164) format, e.g. +15551234567",
 	contactUserCIDAttr:    "",
+	contactUserEmailAttr:  contactUserEmailDescription,
 }
 
 var contactVictorOpsDescriptions = attrDescrs{
@@ -202,9 +240,12 @@ var contactVictorOpsDescriptions = attrDescrs{
 
 var contactXMPPDescriptions = attrDescrs{
 	contactUserCIDAttr:     "",
+	contactUserEmailAttr:   contactUserEmailDescription,
 	contactXMPPAddressAttr: "",
 }
 
+const contactUserEmailDescription = "The email address of the user to notify, resolved to the user's CID when applied"
+
 func resourceContactGroup() *schema.Resource {
 	return &schema.Resource{
 		Create: contactGroupCreate,
@@ -213,27 +254,46 @@ func resourceContactGroup() *schema.Resource {
 		Delete: contactGroupDelete,
 		Exists: contactGroupExists,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: contactGroupImportState,
 		},
+		CustomizeDiff: contactGroupCustomizeDiff,
 
 		Schema: convertToHelperSchema(contactGroupDescriptions, map[schemaAttr]*schema.Schema{
 			contactAggregationWindowAttr: {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Default:          defaultCirconusAggregationWindow,
-				DiffSuppressFunc: suppressEquivalentTimeDurations,
-				StateFunc:        normalizeTimeDurationStringToSeconds,
+				DiffSuppressFunc: suppressContactAggregationWindow,
+				StateFunc:        timeutil.NormalizeSeconds,
 				ValidateFunc: validateFuncs(
 					validateDurationMin(contactAggregationWindowAttr, "0s"),
 				),
 			},
+			contactFloodControlAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringIn(contactFloodControlAttr, validContactFloodControls),
+			},
+			contactAllowEmptyAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			contactAlwaysSendClearAttr: {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
-			contactGroupTypeAttr: {
-				Type:     schema.TypeString,
+			contactAuthoritativeAttr: {
+				Type:     schema.TypeBool,
 				Optional: true,
+				Default:  true,
+			},
+			contactGroupTypeAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				StateFunc:    normalizeContactGroupType,
+				ValidateFunc: validateContactGroupType,
 			},
 			contactAlertOptionAttr: {
 				Type:     schema.TypeSet,
@@ -245,7 +305,7 @@ func resourceContactGroup() *schema.Resource {
 							Type:             schema.TypeString,
 							Optional:         true,
 							DiffSuppressFunc: suppressEquivalentTimeDurations,
-							StateFunc:        normalizeTimeDurationStringToSeconds,
+							StateFunc:        timeutil.NormalizeSeconds,
 							ValidateFunc: validateFuncs(
 								validateDurationMin(contactEscalateAfterAttr, defaultCirconusAlertMinEscalateAfter),
 							),
@@ -259,7 +319,7 @@ func resourceContactGroup() *schema.Resource {
 							Type:             schema.TypeString,
 							Optional:         true,
 							DiffSuppressFunc: suppressEquivalentTimeDurations,
-							StateFunc:        normalizeTimeDurationStringToSeconds,
+							StateFunc:        timeutil.NormalizeSeconds,
 							ValidateFunc: validateFuncs(
 								validateDurationMin(contactReminderAttr, "0s"),
 							),
@@ -284,13 +344,20 @@ func resourceContactGroup() *schema.Resource {
 						contactEmailAddressAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
-							ConflictsWith: []string{contactEmailAttr + "." + contactUserCIDAttr},
+							ConflictsWith: []string{contactEmailAttr + "." + contactUserCIDAttr, contactEmailAttr + "." + contactUserEmailAttr},
 						},
 						contactUserCIDAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
+							Computed:      true,
 							ValidateFunc:  validateUserCID(contactUserCIDAttr),
-							ConflictsWith: []string{contactEmailAttr + "." + contactEmailAddressAttr},
+							ConflictsWith: []string{contactEmailAttr + "." + contactEmailAddressAttr, contactEmailAttr + "." + contactUserEmailAttr},
+						},
+						contactUserEmailAttr: {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateRegexp(contactUserEmailAttr, `^[^@\s]+@[^@\s]+$`),
+							ConflictsWith: []string{contactEmailAttr + "." + contactEmailAddressAttr, contactEmailAttr + "." + contactUserCIDAttr},
 						},
 					}),
 				},
@@ -317,6 +384,11 @@ func resourceContactGroup() *schema.Resource {
 							Default:      defaultCirconusHTTPMethod,
 							ValidateFunc: validateStringIn(contactHTTPMethodAttr, validContactHTTPMethods),
 						},
+						contactHTTPParamsAttr: {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
 					}),
 				},
 			},
@@ -393,11 +465,14 @@ func resourceContactGroup() *schema.Resource {
 							Default:  true,
 						},
 						contactSlackChannelAttr: {
-							Type:     schema.TypeString,
-							Required: true,
-							ValidateFunc: validateFuncs(
-								validateRegexp(contactSlackChannelAttr, `^#[\S]+$`),
-							),
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateSlackChannel,
+						},
+						contactSlackChannelIDAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(contactSlackChannelIDAttr, slackChannelIDRegex),
 						},
 						contactSlackTeamAttr: {
 							Type:     schema.TypeString,
@@ -423,13 +498,22 @@ func resourceContactGroup() *schema.Resource {
 						contactSMSAddressAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
-							ConflictsWith: []string{contactSMSAttr + "." + contactUserCIDAttr},
+							StateFunc:     normalizeSMSAddress,
+							ValidateFunc:  validateSMSAddress,
+							ConflictsWith: []string{contactSMSAttr + "." + contactUserCIDAttr, contactSMSAttr + "." + contactUserEmailAttr},
 						},
 						contactUserCIDAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
+							Computed:      true,
 							ValidateFunc:  validateUserCID(contactUserCIDAttr),
-							ConflictsWith: []string{contactSMSAttr + "." + contactSMSAddressAttr},
+							ConflictsWith: []string{contactSMSAttr + "." + contactSMSAddressAttr, contactSMSAttr + "." + contactUserEmailAttr},
+						},
+						contactUserEmailAttr: {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateRegexp(contactUserEmailAttr, `^[^@\s]+@[^@\s]+$`),
+							ConflictsWith: []string{contactSMSAttr + "." + contactSMSAddressAttr, contactSMSAttr + "." + contactUserCIDAttr},
 						},
 					}),
 				},
@@ -490,19 +574,42 @@ func resourceContactGroup() *schema.Resource {
 						contactXMPPAddressAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
-							ConflictsWith: []string{contactXMPPAttr + "." + contactUserCIDAttr},
+							ConflictsWith: []string{contactXMPPAttr + "." + contactUserCIDAttr, contactXMPPAttr + "." + contactUserEmailAttr},
 						},
 						contactUserCIDAttr: {
 							Type:          schema.TypeString,
 							Optional:      true,
+							Computed:      true,
 							ValidateFunc:  validateUserCID(contactUserCIDAttr),
-							ConflictsWith: []string{contactXMPPAttr + "." + contactXMPPAddressAttr},
+							ConflictsWith: []string{contactXMPPAttr + "." + contactXMPPAddressAttr, contactXMPPAttr + "." + contactUserEmailAttr},
+						},
+						contactUserEmailAttr: {
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateRegexp(contactUserEmailAttr, `^[^@\s]+@[^@\s]+$`),
+							ConflictsWith: []string{contactXMPPAttr + "." + contactXMPPAddressAttr, contactXMPPAttr + "." + contactUserCIDAttr},
 						},
 					}),
 				},
 			},
 
 			// OUT parameters
+			contactConfigHashAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			contactCreatedAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			contactCreatedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			contactEffectiveGroupTypeAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			contactLastModifiedAttr: {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -511,6 +618,11 @@ func resourceContactGroup() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			contactManagedContactsAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		}),
 	}
 }
@@ -518,7 +630,7 @@ func resourceContactGroup() *schema.Resource {
 func contactGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	ctxt := meta.(*providerContext)
 
-	in, err := getContactGroupInput(d)
+	in, err := getContactGroupInput(ctxt, d)
 	if err != nil {
 		return err
 	}
@@ -529,10 +641,197 @@ func contactGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.SetId(cg.CID)
+	contactGroupSetManagedContacts(d, in)
 
 	return contactGroupRead(d, meta)
 }
 
+// contactGroupImportState imports a contact group by its CID or, when the ID is
+// of the form name=<name>, resolves the name to a CID via the search API.
+func contactGroupImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if !strings.HasPrefix(d.Id(), contactImportNamePrefix) {
+		return schema.ImportStatePassthroughContext(ctx, d, meta)
+	}
+
+	c := meta.(*providerContext)
+
+	name := strings.TrimPrefix(d.Id(), contactImportNamePrefix)
+	if name == "" {
+		return nil, fmt.Errorf("contact group name is required when importing with %q", contactImportNamePrefix)
+	}
+
+	cid, err := c.contactGroupCIDByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w, import by CID instead", err)
+	}
+	d.SetId(cid)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// contactGroupCIDByName resolves the name of a contact group to its CID via the
+// search API.  The name must match exactly one contact group.  Resolved names
+// are cached for the life of the provider so each name is searched for once.
+func (c *providerContext) contactGroupCIDByName(name string) (string, error) {
+	cache := c.lookupCaches()
+	cache.contactGroupCIDsMu.Lock()
+	defer cache.contactGroupCIDsMu.Unlock()
+
+	if cid, found := cache.contactGroupCIDs[name]; found {
+		return cid, nil
+	}
+
+	groups, err := c.client.SearchContactGroups(nil, &api.SearchFilterType{
+		"f_name": []string{name},
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to search for contact group %q: %w", name, err)
+	}
+
+	cids := make([]string, 0, 1)
+	for _, cg := range *groups {
+		if cg.Name == name {
+			cids = append(cids, cg.CID)
+		}
+	}
+
+	switch len(cids) {
+	case 0:
+		return "", fmt.Errorf("no contact group named %q found", name)
+	case 1:
+	default:
+		return "", fmt.Errorf("contact group name %q is ambiguous: %s", name, strings.Join(cids, ", "))
+	}
+
+	if cache.contactGroupCIDs == nil {
+		cache.contactGroupCIDs = make(map[string]string)
+	}
+	cache.contactGroupCIDs[name] = cids[0]
+
+	if cache.contactGroupNames == nil {
+		cache.contactGroupNames = make(map[string]string)
+	}
+	cache.contactGroupNames[cids[0]] = name
+
+	return cids[0], nil
+}
+
+// contactGroupNameByCID returns the name of the contact group cid.  Names are
+// cached for the life of the provider so each contact group is fetched once.
+func (c *providerContext) contactGroupNameByCID(cid string) (string, error) {
+	cache := c.lookupCaches()
+	cache.contactGroupCIDsMu.Lock()
+	defer cache.contactGroupCIDsMu.Unlock()
+
+	if name, found := cache.contactGroupNames[cid]; found {
+		return name, nil
+	}
+
+	cg, err := c.client.FetchContactGroup(api.CIDType(&cid))
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch contact group %q: %w", cid, err)
+	}
+
+	if cache.contactGroupNames == nil {
+		cache.contactGroupNames = make(map[string]string)
+	}
+	cache.contactGroupNames[cid] = cg.Name
+
+	return cg.Name, nil
+}
+
+// userCIDByEmail resolves the email address of a user to the user's CID via
+// the user API.  Addresses are compared case-insensitively and must match
+// exactly one user.  Resolved addresses are cached for the life of the
+// provider.
+func (c *providerContext) userCIDByEmail(email string) (string, error) {
+	cache := c.lookupCaches()
+	cache.userCIDsMu.Lock()
+	defer cache.userCIDsMu.Unlock()
+
+	key := strings.ToLower(email)
+	if cid, found := cache.userCIDs[key]; found {
+		return cid, nil
+	}
+
+	users, err := c.client.SearchUsers(&api.SearchFilterType{
+		"f_email": []string{email},
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to search for user %q: %w", email, err)
+	}
+
+	cids := make([]string, 0, 1)
+	for _, u := range *users {
+		if strings.EqualFold(u.Email, email) {
+			cids = append(cids, u.CID)
+		}
+	}
+
+	switch len(cids) {
+	case 0:
+		return "", fmt.Errorf("no user with email %q found", email)
+	case 1:
+	default:
+		return "", fmt.Errorf("user email %q is ambiguous: %s", email, strings.Join(cids, ", "))
+	}
+
+	if cache.userCIDs == nil {
+		cache.userCIDs = make(map[string]string)
+	}
+	cache.userCIDs[key] = cids[0]
+
+	return cids[0], nil
+}
+
+// contactGroupUserCID returns the CID of the user an email, sms or xmpp block
+// notifies.  A user_email takes precedence over the user attribute, which
+// holds the CID it was last resolved to.
+func contactGroupUserCID(ctxt *providerContext, m map[string]interface{}) (string, error) {
+	if email, ok := m[contactUserEmailAttr].(string); ok && email != "" {
+		return ctxt.userCIDByEmail(email)
+	}
+
+	cid, _ := m[contactUserCIDAttr].(string)
+	return cid, nil
+}
+
+// contactGroupKeepUserEmails carries the user_email of the email, sms or xmpp
+// blocks in d over to state, the blocks read back from the API, as long as the
+// user they were resolved to is still the one notified.  The user in d is
+// stale right after user_email changed, so the CID the address was just
+// resolved to, when known, is what the API is compared with.
+func contactGroupKeepUserEmails(ctxt *providerContext, d *schema.ResourceData, attr schemaAttr, state []interface{}) []interface{} {
+	cache := ctxt.lookupCaches()
+	prior, _ := d.Get(string(attr)).([]interface{})
+
+	for i, raw := range state {
+		if i >= len(prior) || prior[i] == nil {
+			break
+		}
+
+		priorMap := prior[i].(map[string]interface{})
+		email, _ := priorMap[contactUserEmailAttr].(string)
+		if email == "" {
+			continue
+		}
+
+		m := raw.(map[string]interface{})
+		cid, _ := priorMap[contactUserCIDAttr].(string)
+		cache.userCIDsMu.Lock()
+		if resolved, found := cache.userCIDs[strings.ToLower(email)]; found {
+			cid = resolved
+		}
+		cache.userCIDsMu.Unlock()
+
+		if cid == "" || cid == m[contactUserCIDAttr] {
+			m[contactUserEmailAttr] = email
+		}
+	}
+
+	return state
+}
+
 func contactGroupExists(d *schema.ResourceData, meta interface{}) (bool, error) {
 	c := meta.(*providerContext)
 
@@ -553,12 +852,162 @@ func contactGroupExists(d *schema.ResourceData, meta interface{}) (bool, error)
 	return true, nil
 }
 
+// contactGroupConfigHash returns a checksum of the contact group as stored by
+// the API.  Fields maintained by the API (CID, last modification and the
+// resolved contact info of users) are ignored and lists whose order is not
+// meaningful are sorted, so the checksum only changes when the contact group
+// itself does.
+func contactGroupConfigHash(cg *api.ContactGroup) (string, error) {
+	n := *cg
+	n.CID = ""
+	n.LastModified = 0
+	n.LastModifiedBy = ""
+
+	n.Contacts.External = append([]api.ContactGroupContactsExternal(nil), cg.Contacts.External...)
+	sort.Slice(n.Contacts.External, func(i, j int) bool {
+		a, b := n.Contacts.External[i], n.Contacts.External[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Info < b.Info
+	})
+
+	n.Contacts.Users = make([]api.ContactGroupContactsUser, 0, len(cg.Contacts.Users))
+	for _, u := range cg.Contacts.Users {
+		u.Info = ""
+		n.Contacts.Users = append(n.Contacts.Users, u)
+	}
+	sort.Slice(n.Contacts.Users, func(i, j int) bool {
+		a, b := n.Contacts.Users[i], n.Contacts.Users[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.UserCID < b.UserCID
+	})
+
+	n.Tags = append([]string(nil), cg.Tags...)
+	sort.Strings(n.Tags)
+
+	buf, err := json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode contact group %s: %w", cg.CID, err)
+	}
+
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// contactGroupContactMarker returns the marker of a contact, recorded in the
+// managed_contacts of non-authoritative contact groups.  User contacts are
+// identified by their method and user CID, the API fills in their contact
+// info.  JSON contact info (e.g. of http contacts) is compacted with sorted
+// keys, as the API does not preserve its formatting.
+func contactGroupContactMarker(method, userCID, info string) string {
+	if userCID != "" {
+		return method + ":" + userCID
+	}
+
+	if strings.HasPrefix(info, "{") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(info), &v); err == nil {
+			if b, err := json.Marshal(v); err == nil {
+				info = string(b)
+			}
+		}
+	}
+
+	return method + ":" + info
+}
+
+// contactGroupSetManagedContacts records the contacts of in as the contacts
+// Terraform manages, when d is not authoritative.
+func contactGroupSetManagedContacts(d *schema.ResourceData, in *api.ContactGroup) {
+	if d.Get(contactAuthoritativeAttr).(bool) {
+		_ = d.Set(contactManagedContactsAttr, []string{})
+		return
+	}
+
+	markers := make([]string, 0, len(in.Contacts.Users)+len(in.Contacts.External))
+	for _, u := range in.Contacts.Users {
+		markers = append(markers, contactGroupContactMarker(u.Method, u.UserCID, ""))
+	}
+	for _, e := range in.Contacts.External {
+		markers = append(markers, contactGroupContactMarker(e.Method, "", e.Info))
+	}
+	sort.Strings(markers)
+
+	_ = d.Set(contactManagedContactsAttr, markers)
+}
+
+// contactGroupFilterContacts returns the contacts of contacts Terraform
+// manages.
+func contactGroupFilterContacts(contacts api.ContactGroupContacts, managed []string) api.ContactGroupContacts {
+	isManaged := make(map[string]bool, len(managed))
+	for _, marker := range managed {
+		isManaged[marker] = true
+	}
+
+	filtered := api.ContactGroupContacts{
+		External: make([]api.ContactGroupContactsExternal, 0, len(contacts.External)),
+		Users:    make([]api.ContactGroupContactsUser, 0, len(contacts.Users)),
+	}
+	for _, u := range contacts.Users {
+		if isManaged[contactGroupContactMarker(u.Method, u.UserCID, "")] {
+			filtered.Users = append(filtered.Users, u)
+		}
+	}
+	for _, e := range contacts.External {
+		if isManaged[contactGroupContactMarker(e.Method, "", e.Info)] {
+			filtered.External = append(filtered.External, e)
+		}
+	}
+
+	return filtered
+}
+
+// contactGroupMergeContacts returns the contacts of the configuration merged
+// with the contacts of the current contact group that Terraform did not add,
+// i.e. are not in managed.  Contacts Terraform added that are no longer
+// configured are dropped.
+func contactGroupMergeContacts(configured, current api.ContactGroupContacts, managed []string) api.ContactGroupContacts {
+	seen := make(map[string]bool, len(managed))
+	for _, marker := range managed {
+		seen[marker] = true
+	}
+
+	merged := api.ContactGroupContacts{
+		External: append([]api.ContactGroupContactsExternal(nil), configured.External...),
+		Users:    append([]api.ContactGroupContactsUser(nil), configured.Users...),
+	}
+	for _, u := range configured.Users {
+		seen[contactGroupContactMarker(u.Method, u.UserCID, "")] = true
+	}
+	for _, e := range configured.External {
+		seen[contactGroupContactMarker(e.Method, "", e.Info)] = true
+	}
+
+	for _, u := range current.Users {
+		if marker := contactGroupContactMarker(u.Method, u.UserCID, ""); !seen[marker] {
+			seen[marker] = true
+			u.Info = ""
+			merged.Users = append(merged.Users, u)
+		}
+	}
+	for _, e := range current.External {
+		if marker := contactGroupContactMarker(e.Method, "", e.Info); !seen[marker] {
+			seen[marker] = true
+			merged.External = append(merged.External, e)
+		}
+	}
+
+	return merged
+}
+
 func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*providerContext)
 
-	cid := d.Id()
-
-	cg, err := c.client.FetchContactGroup(api.CIDType(&cid))
+	cg := &api.ContactGroup{}
+	audit, err := fetchObject(c, config.ContactGroupPrefix, d.Id(), cg)
 	if err != nil {
 		return err
 	}
@@ -569,6 +1018,19 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.SetId(cg.CID)
 
+	// The config hash covers every contact, so changes made outside of
+	// Terraform show up in it even when they are not managed.
+	configHash, err := contactGroupConfigHash(cg)
+	if err != nil {
+		return err
+	}
+
+	if !d.Get(contactAuthoritativeAttr).(bool) {
+		filtered := *cg
+		filtered.Contacts = contactGroupFilterContacts(cg.Contacts, interfaceList(d.Get(contactManagedContactsAttr).([]interface{})).List())
+		cg = &filtered
+	}
+
 	httpState, err := contactGroupHTTPToState(cg)
 	if err != nil {
 		return err
@@ -600,6 +1062,12 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	_ = d.Set(contactAggregationWindowAttr, fmt.Sprintf("%ds", cg.AggregationWindow))
+	if preset, ok := d.GetOk(contactFloodControlAttr); ok {
+		// Drop a preset that no longer matches the API so the drift is planned.
+		if window, _ := time.ParseDuration(contactFloodControlWindows[preset.(string)]); uint(window.Seconds()) != cg.AggregationWindow {
+			_ = d.Set(contactFloodControlAttr, "")
+		}
+	}
 	_ = d.Set(contactAlwaysSendClearAttr, cg.AlwaysSendClear)
 	_ = d.Set(contactGroupTypeAttr, cg.GroupType)
 
@@ -607,7 +1075,7 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactAlertOptionAttr, err)
 	}
 
-	if err := d.Set(contactEmailAttr, contactGroupEmailToState(cg)); err != nil {
+	if err := d.Set(contactEmailAttr, contactGroupKeepUserEmails(c, d, contactEmailAttr, contactGroupEmailToState(cg))); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactEmailAttr, err)
 	}
 
@@ -631,7 +1099,7 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSlackAttr, err)
 	}
 
-	if err := d.Set(contactSMSAttr, smsState); err != nil {
+	if err := d.Set(contactSMSAttr, contactGroupKeepUserEmails(c, d, contactSMSAttr, smsState)); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactSMSAttr, err)
 	}
 
@@ -643,13 +1111,17 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactVictorOpsAttr, err)
 	}
 
-	if err := d.Set(contactXMPPAttr, xmppState); err != nil {
+	if err := d.Set(contactXMPPAttr, contactGroupKeepUserEmails(c, d, contactXMPPAttr, xmppState)); err != nil {
 		return fmt.Errorf("Unable to store contact %q attribute: %w", contactXMPPAttr, err)
 	}
 
 	// Out parameters
-	_ = d.Set(contactLastModifiedAttr, cg.LastModified)
-	_ = d.Set(contactLastModifiedByAttr, cg.LastModifiedBy)
+	_ = d.Set(contactConfigHashAttr, configHash)
+	_ = d.Set(contactCreatedAttr, audit.Created)
+	_ = d.Set(contactCreatedByAttr, audit.CreatedBy)
+	_ = d.Set(contactEffectiveGroupTypeAttr, cg.GroupType)
+	_ = d.Set(contactLastModifiedAttr, audit.LastModified)
+	_ = d.Set(contactLastModifiedByAttr, audit.LastModifiedBy)
 
 	return nil
 }
@@ -657,17 +1129,30 @@ func contactGroupRead(d *schema.ResourceData, meta interface{}) error {
 func contactGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*providerContext)
 
-	in, err := getContactGroupInput(d)
+	in, err := getContactGroupInput(c, d)
 	if err != nil {
 		return err
 	}
 
 	in.CID = d.Id()
 
+	managed := in.Contacts
+	if !d.Get(contactAuthoritativeAttr).(bool) {
+		cid := d.Id()
+		current, err := c.client.FetchContactGroup(api.CIDType(&cid))
+		if err != nil {
+			return fmt.Errorf("unable to fetch the contacts of contact group %q: %w", d.Id(), err)
+		}
+		prior, _ := d.GetChange(contactManagedContactsAttr)
+		in.Contacts = contactGroupMergeContacts(in.Contacts, current.Contacts, interfaceList(prior.([]interface{})).List())
+	}
+
 	if _, err := c.client.UpdateContactGroup(in); err != nil {
 		return fmt.Errorf("unable to update contact group %q: %w", d.Id(), err)
 	}
 
+	contactGroupSetManagedContacts(d, &api.ContactGroup{Contacts: managed})
+
 	return contactGroupRead(d, meta)
 }
 
@@ -684,6 +1169,199 @@ func contactGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// contactGroupCustomizeDiff verifies that every user and contact group
+// referenced by the contact group exists when the provider has been configured
+// with validate_references.
+func contactGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := contactGroupValidateFloodControl(d.Get(contactFloodControlAttr).(string), contactGroupConfiguredAggregationWindow(d)); err != nil {
+		return err
+	}
+
+	if err := contactGroupValidateNotEmpty(d); err != nil {
+		return err
+	}
+
+	// An update changes the stored contact group and with it the checksum.
+	if d.Id() != "" && len(d.GetChangedKeysPrefix("")) > 0 {
+		if err := d.SetNewComputed(contactConfigHashAttr); err != nil {
+			return err
+		}
+		if !d.Get(contactAuthoritativeAttr).(bool) {
+			if err := d.SetNewComputed(contactManagedContactsAttr); err != nil {
+				return err
+			}
+		}
+	}
+
+	c, ok := meta.(*providerContext)
+	if !ok || c == nil || !c.validateRefs {
+		return nil
+	}
+
+	userCIDs := make([]string, 0)
+	for _, attr := range []schemaAttr{contactEmailAttr, contactSMSAttr, contactXMPPAttr} {
+		l, _ := d.Get(string(attr)).([]interface{})
+		userCIDs = append(userCIDs, interfaceList(l).CollectList(contactUserCIDAttr)...)
+	}
+
+	groupCIDs := make([]string, 0)
+	if v, ok := d.Get(contactAlertOptionAttr).(*schema.Set); ok {
+		groupCIDs = append(groupCIDs, interfaceList(v.List()).CollectList(contactEscalateToAttr)...)
+	}
+	for _, attr := range []schemaAttr{contactPagerDutyAttr, contactSlackAttr, contactVictorOpsAttr} {
+		if v, ok := d.Get(string(attr)).(*schema.Set); ok {
+			groupCIDs = append(groupCIDs, interfaceList(v.List()).CollectList(contactContactGroupFallbackAttr)...)
+		}
+	}
+
+	unknownUsers, err := contactGroupUnknownCIDs(userCIDs, func(cid string) error {
+		_, err := c.client.FetchUser(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to verify %s references: %w", contactUserCIDAttr, err)
+	}
+
+	unknownGroups, err := contactGroupUnknownCIDs(groupCIDs, func(cid string) error {
+		_, err := c.client.FetchContactGroup(api.CIDType(&cid))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to verify contact group references: %w", err)
+	}
+
+	var problems []string
+	if len(unknownUsers) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown users: %s", strings.Join(unknownUsers, ", ")))
+	}
+	if len(unknownGroups) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown contact groups: %s", strings.Join(unknownGroups, ", ")))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("contact group %q references objects that do not exist in Circonus (%s)", d.Get(contactNameAttr).(string), strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// contactGroupMethodAttrs are the contact methods of a contact group.
+var contactGroupMethodAttrs = []schemaAttr{
+	contactEmailAttr,
+	contactHTTPAttr,
+	contactPagerDutyAttr,
+	contactSlackAttr,
+	contactSMSAttr,
+	contactVictorOpsAttr,
+	contactXMPPAttr,
+}
+
+// contactGroupValidateNotEmpty rejects a contact group without any contact
+// method unless allow_empty is set: the API accepts it, but alerts routed to it
+// notify nobody.  A group that is not authoritative gets its other contacts
+// from outside of Terraform, and methods not known until apply may be set, so
+// neither is rejected.
+func contactGroupValidateNotEmpty(d *schema.ResourceDiff) error {
+	if d.Get(contactAllowEmptyAttr).(bool) || !d.Get(contactAuthoritativeAttr).(bool) {
+		return nil
+	}
+
+	for _, attr := range contactGroupMethodAttrs {
+		if !d.NewValueKnown(string(attr)) {
+			return nil
+		}
+
+		switch v := d.Get(string(attr)).(type) {
+		case []interface{}:
+			if len(v) > 0 {
+				return nil
+			}
+		case *schema.Set:
+			if v.Len() > 0 {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("contact group %q has no contact methods and would notify nobody, add one or set %s = true", d.Get(contactNameAttr).(string), contactAllowEmptyAttr)
+}
+
+// contactGroupConfiguredAggregationWindow returns the aggregation_window set
+// in the config, or "" when it is left to its default.  The raw config is used
+// as the default can not be told apart from the same window set explicitly.
+func contactGroupConfiguredAggregationWindow(d *schema.ResourceDiff) string {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return ""
+	}
+
+	v := raw.GetAttr(contactAggregationWindowAttr)
+	if v.IsNull() || !v.IsKnown() {
+		return ""
+	}
+
+	return v.AsString()
+}
+
+// contactGroupValidateFloodControl rejects an aggregation_window set in the
+// config, "" when it is not, that contradicts the window of the selected
+// flood_control preset.
+func contactGroupValidateFloodControl(preset, aggregationWindow string) error {
+	if preset == "" || aggregationWindow == "" {
+		return nil
+	}
+
+	window, err := time.ParseDuration(aggregationWindow)
+	if err != nil {
+		return nil
+	}
+
+	presetWindow, _ := time.ParseDuration(contactFloodControlWindows[preset])
+	if window != presetWindow {
+		return fmt.Errorf("%s %q sets an aggregation window of %s which conflicts with %s %q, remove one of them", contactFloodControlAttr, preset, presetWindow, contactAggregationWindowAttr, aggregationWindow)
+	}
+
+	return nil
+}
+
+// suppressContactAggregationWindow ignores the aggregation_window while a
+// flood_control preset is in charge of it.
+func suppressContactAggregationWindow(k, old, update string, d *schema.ResourceData) bool {
+	if v, ok := d.GetOk(contactFloodControlAttr); ok && v.(string) != "" {
+		return true
+	}
+
+	return suppressEquivalentTimeDurations(k, old, update, d)
+}
+
+// contactGroupUnknownCIDs returns the de-duplicated subset of cids for which
+// fetch returned a 404 from the API.  Empty values (e.g. references that are
+// not yet known during plan) are skipped.
+func contactGroupUnknownCIDs(cids []string, fetch func(cid string) error) ([]string, error) {
+	seen := make(map[string]struct{}, len(cids))
+	unknown := make([]string, 0)
+
+	for _, cid := range cids {
+		if cid == "" {
+			continue
+		}
+		if _, found := seen[cid]; found {
+			continue
+		}
+		seen[cid] = struct{}{}
+
+		if err := fetch(cid); err != nil {
+			if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+				unknown = append(unknown, cid)
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return unknown, nil
+}
+
 func contactGroupAlertOptionsToState(cg *api.ContactGroup) []interface{} {
 	if config.NumSeverityLevels != len(cg.Reminders) {
 		log.Printf("[FATAL] PROVIDER BUG: Need to update constants in contactGroupAlertOptionsToState re: reminders")
@@ -763,10 +1441,16 @@ func contactGroupHTTPToState(cg *api.ContactGroup) ([]interface{}, error) {
 				return nil, fmt.Errorf("unable to decode external %s JSON (%q): %w", contactHTTPAttr, ext.Info, err)
 			}
 
+			format, params, err := contactHTTPParamsToState(url.Format)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode external %s %s (%q): %w", contactHTTPAttr, contactHTTPParamsAttr, url.Format, err)
+			}
+
 			httpContacts = append(httpContacts, map[string]interface{}{
 				string(contactHTTPAddressAttr): url.Address,
-				string(contactHTTPFormatAttr):  url.Format,
+				string(contactHTTPFormatAttr):  format,
 				string(contactHTTPMethodAttr):  url.Method,
+				string(contactHTTPParamsAttr):  params,
 			})
 		}
 	}
@@ -774,19 +1458,61 @@ func contactGroupHTTPToState(cg *api.ContactGroup) ([]interface{}, error) {
 	return httpContacts, nil
 }
 
-func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
+// contactHTTPParamsToAPI encodes params the way the API expects them in the
+// params field of an http contact: a URL query string, e.g. "a=1&b=2", with
+// the keys sorted so the encoding is stable.
+func contactHTTPParamsToAPI(params map[string]interface{}) string {
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v.(string))
+	}
+
+	return values.Encode()
+}
+
+// contactHTTPParamsToState returns the format and params of the params field
+// of an http contact.  The field holds either the name of a format or, for the
+// params format, the encoded parameters.
+func contactHTTPParamsToState(apiParams string) (string, map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if apiParams == "" {
+		return apiParams, params, nil
+	}
+	for _, format := range validContactHTTPFormats {
+		if apiParams == string(format) {
+			return apiParams, params, nil
+		}
+	}
+
+	values, err := url.ParseQuery(apiParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for k, v := range values {
+		params[k] = strings.Join(v, ",")
+	}
+
+	return contactHTTPFormatParams, params, nil
+}
+
+func getContactGroupInput(ctxt *providerContext, d *schema.ResourceData) (*api.ContactGroup, error) {
 	slack := false
 	cg := api.NewContactGroup()
 	if v, ok := d.GetOk(contactAggregationWindowAttr); ok {
 		aggWindow, _ := time.ParseDuration(v.(string))
 		cg.AggregationWindow = uint(aggWindow.Seconds())
 	}
+	if v, ok := d.GetOk(contactFloodControlAttr); ok {
+		aggWindow, _ := time.ParseDuration(contactFloodControlWindows[v.(string)])
+		cg.AggregationWindow = uint(aggWindow.Seconds())
+	}
 	if v, ok := d.GetOk(contactAlwaysSendClearAttr); ok {
 		cg.AlwaysSendClear = v.(bool)
 	}
 	if v, ok := d.GetOk(contactGroupTypeAttr); ok {
 		if v.(string) != "" {
-			cg.GroupType = v.(string)
+			cg.GroupType = normalizeContactGroupType(v)
 		}
 	}
 
@@ -839,7 +1565,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 	}
 
 	if v, ok := d.GetOk(contactEmailAttr); ok {
-		emailListRaw := v.(*schema.Set).List()
+		emailListRaw := v.([]interface{})
 		for _, emailMapRaw := range emailListRaw {
 			emailMap := emailMapRaw.(map[string]interface{})
 
@@ -852,18 +1578,22 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				})
 			}
 
-			if v, ok := emailMap[contactUserCIDAttr]; ok && v.(string) != "" {
+			userCID, err := contactGroupUserCID(ctxt, emailMap)
+			if err != nil {
+				return nil, fmt.Errorf("In type %s: %w", contactEmailAttr, err)
+			}
+			if userCID != "" {
 				requiredAttrFound = true
 				cg.Contacts.Users = append(cg.Contacts.Users, api.ContactGroupContactsUser{
 					Method:  circonusMethodEmail,
-					UserCID: v.(string),
+					UserCID: userCID,
 				})
 			}
 
 			// Can't mark two attributes that are conflicting as required so we do our
 			// own validation check here.
 			if !requiredAttrFound {
-				return nil, fmt.Errorf("In type %s, either %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr)
+				return nil, fmt.Errorf("In type %s, one of %s, %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr, contactUserEmailAttr)
 			}
 		}
 	}
@@ -887,6 +1617,13 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				httpInfo.Method = v.(string)
 			}
 
+			if v, ok := httpMap[string(contactHTTPParamsAttr)]; ok && len(v.(map[string]interface{})) > 0 {
+				if httpInfo.Format != contactHTTPFormatParams {
+					return nil, fmt.Errorf("In type %s, %s requires %s to be %q, got %q", contactHTTPAttr, contactHTTPParamsAttr, contactHTTPFormatAttr, contactHTTPFormatParams, httpInfo.Format)
+				}
+				httpInfo.Format = contactHTTPParamsToAPI(v.(map[string]interface{}))
+			}
+
 			js, err := json.Marshal(httpInfo)
 			if err != nil {
 				return nil, fmt.Errorf("error marshaling %s JSON config string: %w", contactHTTPAttr, err)
@@ -959,6 +1696,20 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				slackInfo.Channel = v.(string)
 			}
 
+			if v, ok := slackMap[contactSlackChannelIDAttr]; ok && v.(string) != "" {
+				if slackInfo.Channel != "" {
+					return nil, fmt.Errorf("only one of %s.%s or %s.%s may be set", contactSlackAttr, contactSlackChannelAttr, contactSlackAttr, contactSlackChannelIDAttr)
+				}
+
+				// Integrations that predate channel IDs only read channel.
+				slackInfo.Channel = v.(string)
+				slackInfo.ChannelID = v.(string)
+			}
+
+			if slackInfo.Channel == "" {
+				return nil, fmt.Errorf("one of %s.%s or %s.%s is required", contactSlackAttr, contactSlackChannelAttr, contactSlackAttr, contactSlackChannelIDAttr)
+			}
+
 			if v, ok := slackMap[contactContactGroupFallbackAttr]; ok && v.(string) != "" {
 				cid := v.(string)
 				contactGroupID, err := failoverGroupCIDToID(api.CIDType(&cid))
@@ -989,7 +1740,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 	}
 
 	if v, ok := d.GetOk(contactSMSAttr); ok {
-		smsListRaw := v.(*schema.Set).List()
+		smsListRaw := v.([]interface{})
 		for _, smsMapRaw := range smsListRaw {
 			smsMap := smsMapRaw.(map[string]interface{})
 
@@ -997,23 +1748,27 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 			if v, ok := smsMap[contactSMSAddressAttr]; ok && v.(string) != "" {
 				requiredAttrFound = true
 				cg.Contacts.External = append(cg.Contacts.External, api.ContactGroupContactsExternal{
-					Info:   v.(string),
+					Info:   normalizeSMSAddress(v),
 					Method: circonusMethodSMS,
 				})
 			}
 
-			if v, ok := smsMap[contactUserCIDAttr]; ok && v.(string) != "" {
+			userCID, err := contactGroupUserCID(ctxt, smsMap)
+			if err != nil {
+				return nil, fmt.Errorf("In type %s: %w", contactSMSAttr, err)
+			}
+			if userCID != "" {
 				requiredAttrFound = true
 				cg.Contacts.Users = append(cg.Contacts.Users, api.ContactGroupContactsUser{
 					Method:  circonusMethodSMS,
-					UserCID: v.(string),
+					UserCID: userCID,
 				})
 			}
 
 			// Can't mark two attributes that are conflicting as required so we do our
 			// own validation check here.
 			if !requiredAttrFound {
-				return nil, fmt.Errorf("In type %s, either %s or %s must be specified", contactEmailAttr, contactEmailAddressAttr, contactUserCIDAttr)
+				return nil, fmt.Errorf("In type %s, one of %s, %s or %s must be specified", contactSMSAttr, contactSMSAddressAttr, contactUserCIDAttr, contactUserEmailAttr)
 			}
 		}
 	}
@@ -1067,7 +1822,7 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 	}
 
 	if v, ok := d.GetOk(contactXMPPAttr); ok {
-		xmppListRaw := v.(*schema.Set).List()
+		xmppListRaw := v.([]interface{})
 		for _, xmppMapRaw := range xmppListRaw {
 			xmppMap := xmppMapRaw.(map[string]interface{})
 
@@ -1078,10 +1833,14 @@ func getContactGroupInput(d *schema.ResourceData) (*api.ContactGroup, error) {
 				})
 			}
 
-			if v, ok := xmppMap[contactUserCIDAttr]; ok && v.(string) != "" {
+			userCID, err := contactGroupUserCID(ctxt, xmppMap)
+			if err != nil {
+				return nil, fmt.Errorf("In type %s: %w", contactXMPPAttr, err)
+			}
+			if userCID != "" {
 				cg.Contacts.Users = append(cg.Contacts.Users, api.ContactGroupContactsUser{
 					Method:  circonusMethodXMPP,
-					UserCID: v.(string),
+					UserCID: userCID,
 				})
 			}
 		}
@@ -1175,10 +1934,13 @@ func contactGroupSlackToState(cg *api.ContactGroup) ([]interface{}, error) {
 				return nil, fmt.Errorf("unable to decode external %s JSON (%q): %w", contactSlackAttr, ext.Info, err)
 			}
 
+			channel, channelID := slackChannelToState(slackInfo)
+
 			slackContacts = append(slackContacts, map[string]interface{}{
 				contactContactGroupFallbackAttr: failoverGroupIDToCID(slackInfo.FallbackGroupCID),
 				contactSlackButtonsAttr:         slackInfo.Buttons == int(1),
-				contactSlackChannelAttr:         slackInfo.Channel,
+				contactSlackChannelAttr:         channel,
+				contactSlackChannelIDAttr:       channelID,
 				contactSlackTeamAttr:            slackInfo.Team,
 				contactSlackUsernameAttr:        slackInfo.Username,
 			})
@@ -1188,6 +1950,19 @@ func contactGroupSlackToState(cg *api.ContactGroup) ([]interface{}, error) {
 	return slackContacts, nil
 }
 
+// slackChannelToState returns the channel name and channel ID of a Slack
+// contact, whichever of the two the API stored.
+func slackChannelToState(slackInfo contactSlackInfo) (channel, channelID string) {
+	switch {
+	case slackInfo.ChannelID != "":
+		return "", slackInfo.ChannelID
+	case slackChannelIDRE.MatchString(slackInfo.Channel):
+		return "", slackInfo.Channel
+	default:
+		return slackInfo.Channel, ""
+	}
+}
+
 func contactGroupSMSToState(cg *api.ContactGroup) ([]interface{}, error) { //nolint:unparam
 	smsContacts := make([]interface{}, 0, len(cg.Contacts.Users)+len(cg.Contacts.External))
 
@@ -1256,14 +2031,31 @@ func contactGroupXMPPToState(cg *api.ContactGroup) ([]interface{}, error) { //no
 	return xmppContacts, nil
 }
 
+// normalizeContactGroupType folds the accepted spellings of a group type (e.g.
+// "On-Call") into the form used by the API.
+func normalizeContactGroupType(v interface{}) string {
+	s := strings.ToLower(strings.TrimSpace(v.(string)))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(s)
+}
+
+// smsAddressSeparators are the characters commonly used to group the digits
+// of a phone number, dropped when the number is sent to the API.
+var smsAddressSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// normalizeSMSAddress removes the separators from a phone number, e.g.
+// "+1 (555) 123-4567" becomes "+15551234567".
+func normalizeSMSAddress(v interface{}) string {
+	return smsAddressSeparators.Replace(strings.TrimSpace(v.(string)))
+}
+
 // contactGroupAlertOptionsChecksum creates a stable hash of the normalized values.
 func contactGroupAlertOptionsChecksum(v interface{}) int {
 	m := v.(map[string]interface{})
 	b := &bytes.Buffer{}
 	b.Grow(defaultHashBufSize)
 	fmt.Fprintf(b, "%x", m[contactSeverityAttr].(int))
-	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(m[contactEscalateAfterAttr]))
+	fmt.Fprint(b, timeutil.NormalizeSeconds(m[contactEscalateAfterAttr]))
 	fmt.Fprint(b, m[contactEscalateToAttr])
-	fmt.Fprint(b, normalizeTimeDurationStringToSeconds(m[contactReminderAttr]))
+	fmt.Fprint(b, timeutil.NormalizeSeconds(m[contactReminderAttr]))
 	return hashcode.String(b.String())
 }
@@ -1,12 +1,18 @@
 package circonus
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -93,6 +99,222 @@ func TestAccCirconusContactGroup_basic(t *testing.T) {
 	})
 }
 
+func TestContactGroupUnknownCIDs(t *testing.T) {
+	known := map[string]bool{
+		"/user/1234":          true,
+		"/contact_group/4661": true,
+	}
+
+	fetch := func(cid string) error {
+		if !known[cid] {
+			return fmt.Errorf("%s %s not found", defaultCirconus404ErrorString, cid)
+		}
+		return nil
+	}
+
+	unknown, err := contactGroupUnknownCIDs([]string{"/user/1234", "", "/user/5678", "/user/5678", "/contact_group/4661"}, fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(unknown) != 1 || unknown[0] != "/user/5678" {
+		t.Fatalf("expected [/user/5678], got %v", unknown)
+	}
+
+	if _, err := contactGroupUnknownCIDs([]string{"/user/1"}, func(string) error { return fmt.Errorf("API response code 500:") }); err == nil {
+		t.Fatal("expected non-404 error to be returned")
+	}
+}
+
+func TestValidateContactGroupType(t *testing.T) {
+	for _, v := range []string{"normal", "On-Call", " on_call "} {
+		if _, errs := validateContactGroupType(v, contactGroupTypeAttr); len(errs) != 0 {
+			t.Fatalf("expected %q to be valid: %v", v, errs)
+		}
+	}
+
+	if _, errs := validateContactGroupType("pager", contactGroupTypeAttr); len(errs) == 0 {
+		t.Fatal("expected unknown group type to be rejected")
+	}
+
+	if got := normalizeContactGroupType("On Call"); got != "on_call" {
+		t.Fatalf("expected on_call, got %q", got)
+	}
+}
+
+func TestContactGroupValidateFloodControl(t *testing.T) {
+	tests := []struct {
+		preset string
+		window string
+		ok     bool
+	}{
+		{"", "42s", true},
+		{"high", "900s", true},
+		{"high", "15m", true},
+		{"high", "", true},
+		{"medium", defaultCirconusAggregationWindow, true},
+		{"off", "0s", true},
+		{"high", defaultCirconusAggregationWindow, false},
+		{"high", "60s", false},
+		{"low", "10m", false},
+	}
+
+	for _, test := range tests {
+		err := contactGroupValidateFloodControl(test.preset, test.window)
+		if test.ok && err != nil {
+			t.Fatalf("expected %s/%s to be valid: %v", test.preset, test.window, err)
+		}
+		if !test.ok && err == nil {
+			t.Fatalf("expected %s/%s to be rejected", test.preset, test.window)
+		}
+	}
+
+	for _, preset := range validContactFloodControls {
+		if _, ok := contactFloodControlWindows[string(preset)]; !ok {
+			t.Fatalf("flood_control preset %q has no aggregation window", preset)
+		}
+	}
+}
+
+func TestContactGroupValidateNotEmpty(t *testing.T) {
+	// unknown is how the SDK marks values that are not known until apply.
+	const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		err    bool
+	}{
+		{"empty", map[string]interface{}{}, true},
+		{"email", map[string]interface{}{contactEmailAttr: []interface{}{map[string]interface{}{contactEmailAddressAttr: "ops@example.com"}}}, false},
+		{"slack", map[string]interface{}{contactSlackAttr: []interface{}{map[string]interface{}{contactSlackChannelAttr: "#ops", contactSlackTeamAttr: "T123UT98F"}}}, false},
+		{"allow empty", map[string]interface{}{contactAllowEmptyAttr: true}, false},
+		{"not authoritative", map[string]interface{}{contactAuthoritativeAttr: false}, false},
+		{"unknown", map[string]interface{}{contactEmailAttr: unknown}, false},
+	}
+
+	for _, test := range tests {
+		test.config[contactNameAttr] = "ops"
+		_, err := resourceContactGroup().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(test.config), nil)
+		switch {
+		case test.err && (err == nil || !strings.Contains(err.Error(), contactAllowEmptyAttr+" = true")):
+			t.Errorf("%s: expected an error suggesting %s, got %v", test.name, contactAllowEmptyAttr, err)
+		case !test.err && err != nil:
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+}
+
+func TestSlackChannelToState(t *testing.T) {
+	tests := []struct {
+		info      contactSlackInfo
+		channel   string
+		channelID string
+	}{
+		{contactSlackInfo{Channel: "#ops"}, "#ops", ""},
+		{contactSlackInfo{Channel: "C024BE91L"}, "", "C024BE91L"},
+		{contactSlackInfo{Channel: "C024BE91L", ChannelID: "C024BE91L"}, "", "C024BE91L"},
+		{contactSlackInfo{ChannelID: "G024BE91L"}, "", "G024BE91L"},
+	}
+
+	for _, test := range tests {
+		channel, channelID := slackChannelToState(test.info)
+		if channel != test.channel || channelID != test.channelID {
+			t.Fatalf("%+v: expected (%q, %q), got (%q, %q)", test.info, test.channel, test.channelID, channel, channelID)
+		}
+	}
+}
+
+func TestValidateSlackChannel(t *testing.T) {
+	if warns, errs := validateSlackChannel("#ops", contactSlackChannelAttr); len(warns) != 0 || len(errs) != 0 {
+		t.Fatalf("expected #ops to be valid: %v %v", warns, errs)
+	}
+
+	if warns, errs := validateSlackChannel("ops", contactSlackChannelAttr); len(warns) != 1 || len(errs) != 0 {
+		t.Fatalf("expected a warning for ops: %v %v", warns, errs)
+	}
+
+	for _, v := range []string{"", "#my ops", "C024BE91L"} {
+		if _, errs := validateSlackChannel(v, contactSlackChannelAttr); len(errs) == 0 {
+			t.Fatalf("expected %q to be rejected", v)
+		}
+	}
+}
+
+func TestValidateSMSAddress(t *testing.T) {
+	for _, v := range []string{"+15551234567", "+1 (555) 123-4567", "+44 20 7946 0958", "+49.30.901820"} {
+		if warns, errs := validateSMSAddress(v, contactSMSAddressAttr); len(warns) != 0 || len(errs) != 0 {
+			t.Fatalf("expected %q to be valid: %v %v", v, warns, errs)
+		}
+	}
+
+	for _, v := range []string{"", "5551234567", "+0551234567", "+1555", "+1234567890123456", "+1 555 CALL NOW"} {
+		if _, errs := validateSMSAddress(v, contactSMSAddressAttr); len(errs) == 0 {
+			t.Fatalf("expected %q to be rejected", v)
+		}
+	}
+
+	if n := normalizeSMSAddress(" +1 (555) 123-4567 "); n != "+15551234567" {
+		t.Fatalf("expected the separators to be removed, got %q", n)
+	}
+}
+
+func TestContactGroupConfigHash(t *testing.T) {
+	newGroup := func() *api.ContactGroup {
+		return &api.ContactGroup{
+			CID:            "/contact_group/1",
+			LastModified:   1500000000,
+			LastModifiedBy: "/user/1",
+			Name:           "ops",
+			Contacts: api.ContactGroupContacts{
+				External: []api.ContactGroupContactsExternal{
+					{Info: "b@example.com", Method: "email"},
+					{Info: "a@example.com", Method: "email"},
+				},
+				Users: []api.ContactGroupContactsUser{
+					{Info: "user2@example.com", Method: "email", UserCID: "/user/2"},
+					{Info: "user1@example.com", Method: "email", UserCID: "/user/1"},
+				},
+			},
+			Tags: []string{"team:ops", "env:prod"},
+		}
+	}
+
+	expected, err := contactGroupConfigHash(newGroup())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cg := newGroup()
+	cg.LastModified = 1600000000
+	cg.LastModifiedBy = "/user/2"
+	cg.Contacts.External[0], cg.Contacts.External[1] = cg.Contacts.External[1], cg.Contacts.External[0]
+	cg.Contacts.Users[0], cg.Contacts.Users[1] = cg.Contacts.Users[1], cg.Contacts.Users[0]
+	cg.Contacts.Users[0].Info = "renamed@example.com"
+	cg.Tags = []string{"env:prod", "team:ops"}
+	if h, _ := contactGroupConfigHash(cg); h != expected {
+		t.Errorf("expected API maintained fields and ordering to be ignored, got %s, expected %s", h, expected)
+	}
+
+	cg = newGroup()
+	cg.Contacts.External[0].Info = "c@example.com"
+	if h, _ := contactGroupConfigHash(cg); h == expected {
+		t.Errorf("expected a changed contact to change the hash")
+	}
+
+	cg = newGroup()
+	cg.AggregationWindow = 900
+	if h, _ := contactGroupConfigHash(cg); h == expected {
+		t.Errorf("expected a changed aggregation window to change the hash")
+	}
+
+	cg = newGroup()
+	_, _ = contactGroupConfigHash(cg)
+	if cg.Contacts.External[0].Info != "b@example.com" || cg.Contacts.Users[0].Info != "user2@example.com" || cg.Tags[0] != "team:ops" {
+		t.Fatalf("expected the contact group to be left unmodified: %#v", cg)
+	}
+}
+
 func testAccCheckDestroyCirconusContactGroup(s *terraform.State) error {
 	c := testAccProvider.Meta().(*providerContext)
 
@@ -136,6 +358,7 @@ func checkContactGroupExists(c *providerContext, contactGroupCID api.CIDType) (b
 const testAccCirconusContactGroupConfig = `
 resource "circonus_contact_group" "staging-sev3" {
   name = "ops-staging-sev3"
+  allow_empty = true
 
   // these can't really be tested without actually creating users on the account
 
@@ -252,3 +475,289 @@ resource "circonus_contact_group" "staging-sev3" {
   group_type = "normal"
 }
 `
+
+func TestContactGroupUserEmail(t *testing.T) {
+	users := []api.User{
+		{CID: "/user/1", Email: "oncall@example.org"},
+		{CID: "/user/2", Email: "twin@example.org"},
+		{CID: "/user/3", Email: "Twin@example.org"},
+	}
+
+	var searches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		email := r.URL.Query().Get("f_email")
+		results := make([]api.User, 0)
+		for _, u := range users {
+			if strings.EqualFold(u.Email, email) {
+				results = append(results, u)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	d := schema.TestResourceDataRaw(t, resourceContactGroup().Schema, map[string]interface{}{
+		contactNameAttr: "on-call",
+		contactEmailAttr: []interface{}{
+			map[string]interface{}{
+				contactUserEmailAttr: "OnCall@example.org",
+			},
+		},
+		contactSMSAttr: []interface{}{
+			map[string]interface{}{
+				contactUserCIDAttr: "/user/4",
+			},
+		},
+	})
+
+	cg, err := getContactGroupInput(ctxt, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []api.ContactGroupContactsUser{
+		{Method: circonusMethodEmail, UserCID: "/user/1"},
+		{Method: circonusMethodSMS, UserCID: "/user/4"},
+	}
+	if len(cg.Contacts.Users) != len(expected) {
+		t.Fatalf("expected users %v, got %v", expected, cg.Contacts.Users)
+	}
+	for i, u := range expected {
+		if cg.Contacts.Users[i].Method != u.Method || cg.Contacts.Users[i].UserCID != u.UserCID {
+			t.Errorf("expected user %v, got %v", u, cg.Contacts.Users[i])
+		}
+	}
+
+	if _, err := ctxt.userCIDByEmail("oncall@example.org"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if searches != 1 {
+		t.Errorf("expected the email to be searched for once, got %d searches", searches)
+	}
+
+	state := contactGroupKeepUserEmails(ctxt, d, contactEmailAttr, contactGroupEmailToState(cg))
+	if v := state[0].(map[string]interface{})[contactUserEmailAttr]; v != "OnCall@example.org" {
+		t.Errorf("expected %s to be kept, got %v", contactUserEmailAttr, v)
+	}
+
+	// Someone else is notified now, user_email no longer describes the block.
+	cg.Contacts.Users[0].UserCID = "/user/5"
+	state = contactGroupKeepUserEmails(ctxt, d, contactEmailAttr, contactGroupEmailToState(cg))
+	if v, found := state[0].(map[string]interface{})[contactUserEmailAttr]; found {
+		t.Errorf("expected %s to be dropped, got %v", contactUserEmailAttr, v)
+	}
+
+	for _, email := range []string{"twin@example.org", "nobody@example.org"} {
+		if _, err := ctxt.userCIDByEmail(email); err == nil {
+			t.Errorf("%q: expected an error", email)
+		}
+	}
+}
+
+func TestContactGroupHTTPParams(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceContactGroup().Schema, map[string]interface{}{
+		contactNameAttr: "webhooks",
+		contactHTTPAttr: []interface{}{
+			map[string]interface{}{
+				string(contactHTTPAddressAttr): "https://hooks.example.org/alert",
+				string(contactHTTPFormatAttr):  contactHTTPFormatParams,
+				string(contactHTTPParamsAttr): map[string]interface{}{
+					"team":  "ops",
+					"route": "a b&c",
+				},
+			},
+		},
+	})
+
+	cg, err := getContactGroupInput(&providerContext{}, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cg.Contacts.External) != 1 {
+		t.Fatalf("expected 1 external contact, got %d", len(cg.Contacts.External))
+	}
+
+	info := contactHTTPInfo{}
+	if err := json.Unmarshal([]byte(cg.Contacts.External[0].Info), &info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "route=a+b%26c&team=ops"; info.Format != expected {
+		t.Errorf("expected params %q, got %q", expected, info.Format)
+	}
+
+	state, err := contactGroupHTTPToState(cg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	httpState := state[0].(map[string]interface{})
+	if httpState[contactHTTPFormatAttr] != contactHTTPFormatParams {
+		t.Errorf("expected format %q, got %q", contactHTTPFormatParams, httpState[contactHTTPFormatAttr])
+	}
+	expected := map[string]interface{}{"team": "ops", "route": "a b&c"}
+	if !reflect.DeepEqual(httpState[contactHTTPParamsAttr], expected) {
+		t.Errorf("expected params %v, got %v", expected, httpState[contactHTTPParamsAttr])
+	}
+
+	for _, format := range []string{"", "json", "params"} {
+		got, params, err := contactHTTPParamsToState(format)
+		if err != nil || got != format || len(params) != 0 {
+			t.Errorf("%q: expected the format alone, got %q %v %v", format, got, params, err)
+		}
+	}
+
+	// params can not be sent in the json format.
+	d = schema.TestResourceDataRaw(t, resourceContactGroup().Schema, map[string]interface{}{
+		contactNameAttr: "webhooks",
+		contactHTTPAttr: []interface{}{
+			map[string]interface{}{
+				string(contactHTTPAddressAttr): "https://hooks.example.org/alert",
+				string(contactHTTPParamsAttr):  map[string]interface{}{"team": "ops"},
+			},
+		},
+	})
+	if _, err := getContactGroupInput(&providerContext{}, d); err == nil {
+		t.Errorf("expected params with the json format to be rejected")
+	}
+}
+
+func TestContactGroupMergeContacts(t *testing.T) {
+	current := api.ContactGroupContacts{
+		External: []api.ContactGroupContactsExternal{
+			{Method: circonusMethodEmail, Info: "old@example.org"},
+			{Method: circonusMethodEmail, Info: "ui@example.org"},
+			{Method: circonusMethodHTTP, Info: `{"url":"https://hooks.example.org","method":"POST","params":"json"}`},
+		},
+		Users: []api.ContactGroupContactsUser{
+			{Method: circonusMethodEmail, UserCID: "/user/1", Info: "oncall@example.org"},
+			{Method: circonusMethodSMS, UserCID: "/user/9", Info: "+15555550100"},
+		},
+	}
+	configured := api.ContactGroupContacts{
+		External: []api.ContactGroupContactsExternal{
+			{Method: circonusMethodEmail, Info: "new@example.org"},
+			{Method: circonusMethodHTTP, Info: `{"method":"POST","params":"json","url":"https://hooks.example.org"}`},
+		},
+		Users: []api.ContactGroupContactsUser{
+			{Method: circonusMethodEmail, UserCID: "/user/1"},
+		},
+	}
+	managed := []string{
+		"email:/user/1",
+		"email:old@example.org",
+		`http:{"method":"POST","params":"json","url":"https://hooks.example.org"}`,
+	}
+
+	merged := contactGroupMergeContacts(configured, current, managed)
+
+	external := make([]string, 0)
+	for _, e := range merged.External {
+		external = append(external, contactGroupContactMarker(e.Method, "", e.Info))
+	}
+	expectedExternal := []string{
+		"email:new@example.org",
+		`http:{"method":"POST","params":"json","url":"https://hooks.example.org"}`,
+		"email:ui@example.org",
+	}
+	if !reflect.DeepEqual(external, expectedExternal) {
+		t.Errorf("expected external contacts %v, got %v", expectedExternal, external)
+	}
+
+	users := make([]string, 0)
+	for _, u := range merged.Users {
+		users = append(users, contactGroupContactMarker(u.Method, u.UserCID, u.Info))
+	}
+	expectedUsers := []string{"email:/user/1", "sms:/user/9"}
+	if !reflect.DeepEqual(users, expectedUsers) {
+		t.Errorf("expected user contacts %v, got %v", expectedUsers, users)
+	}
+
+	filtered := contactGroupFilterContacts(current, managed)
+	if len(filtered.Users) != 1 || filtered.Users[0].UserCID != "/user/1" {
+		t.Errorf("expected only the managed user contact, got %v", filtered.Users)
+	}
+	if len(filtered.External) != 2 || filtered.External[0].Info != "old@example.org" || filtered.External[1].Method != circonusMethodHTTP {
+		t.Errorf("expected only the managed external contacts, got %v", filtered.External)
+	}
+}
+
+func TestContactGroupImportState(t *testing.T) {
+	groups := []api.ContactGroup{
+		{CID: "/contact_group/1", Name: "On-call"},
+		{CID: "/contact_group/2", Name: "On-call secondary"},
+		{CID: "/contact_group/3", Name: "Operations"},
+		{CID: "/contact_group/4", Name: "Operations"},
+	}
+
+	var searches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		if r.URL.Path != "/contact_group" {
+			http.Error(w, `{"code":404,"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		// The search API matches on substrings, the import only on names.
+		name := r.URL.Query().Get("f_name")
+		results := make([]api.ContactGroup, 0)
+		for _, cg := range groups {
+			if strings.Contains(cg.Name, name) {
+				results = append(results, cg)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{
+		URL:        srv.URL,
+		TokenKey:   "test",
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	for id, expected := range map[string]string{
+		"/contact_group/2": "/contact_group/2",
+		"name=On-call":     "/contact_group/1",
+	} {
+		d := resourceContactGroup().Data(nil)
+		d.SetId(id)
+
+		imported, err := contactGroupImportState(context.Background(), d, ctxt)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+		if len(imported) != 1 || imported[0].Id() != expected {
+			t.Fatalf("%s: expected %s to be imported, got %v", id, expected, imported)
+		}
+	}
+	if searches != 1 {
+		t.Errorf("expected only the name to be searched for, got %d searches", searches)
+	}
+
+	for id, expected := range map[string]string{
+		"name=Missing":    `no contact group named "Missing" found`,
+		"name=Operations": `contact group name "Operations" is ambiguous: /contact_group/3, /contact_group/4`,
+		"name=":           "contact group name is required",
+	} {
+		d := resourceContactGroup().Data(nil)
+		d.SetId(id)
+
+		_, err := contactGroupImportState(context.Background(), d, ctxt)
+		if err == nil {
+			t.Errorf("%s: expected an error", id)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected error %q, got %q", id, expected, err)
+		}
+	}
+}
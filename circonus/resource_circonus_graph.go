@@ -1,6 +1,9 @@
 package circonus
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -9,11 +12,15 @@ import (
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 const (
 	// circonus_graph.* resource attribute names.
+	graphAccessKeyAttr     = "access_key"
+	graphColorPaletteAttr  = "color_palette"
+	graphCompositeAttr     = "composite"
 	graphDescriptionAttr   = "description"
 	graphLeftAttr          = "left"
 	graphLineStyleAttr     = "line_style"
@@ -26,6 +33,13 @@ const (
 	graphTagsAttr          = "tags"
 	graphGuidesAttr        = "guide"
 
+	// Out parameters for circonus_graph.
+	graphOutCreatedAttr        = "created"
+	graphOutCreatedByAttr      = "created_by"
+	graphOutLastModifiedAttr   = "last_modified"
+	graphOutLastModifiedByAttr = "last_modified_by"
+	graphOutUUIDAttr           = "uuid"
+
 	// circonus_graph.metric.* resource attribute names.
 	graphMetricActiveAttr        = "active"
 	graphMetricAlphaAttr         = "alpha"
@@ -41,14 +55,41 @@ const (
 	graphMetricMetricTypeAttr    = "metric_type"
 	graphMetricNameAttr          = "metric_name"
 	graphMetricStackAttr         = "stack"
+	graphMetricStreamCountAttr   = "stream_count"
 
 	// circonus_graph.metric_cluster.* resource attribute names.
-	graphMetricClusterActiveAttr    = "active"
-	graphMetricClusterAggregateAttr = "aggregate"
-	graphMetricClusterAxisAttr      = "axis"
-	graphMetricClusterColorAttr     = "color"
-	graphMetricClusterQueryAttr     = "query"
-	graphMetricClusterHumanNameAttr = "name"
+	graphMetricClusterActiveAttr      = "active"
+	graphMetricClusterAggregateAttr   = "aggregate"
+	graphMetricClusterAxisAttr        = "axis"
+	graphMetricClusterColorAttr       = "color"
+	graphMetricClusterQueryAttr       = "query"
+	graphMetricClusterHumanNameAttr   = "name"
+	graphMetricClusterStreamCountAttr = "stream_count"
+
+	// circonus_graph.access_key.* resource attribute names.
+	graphAccessKeyHeightAttr         = "height"
+	graphAccessKeyKeyAttr            = "key"
+	graphAccessKeyLegendAttr         = "legend"
+	graphAccessKeyLockDateAttr       = "lock_date"
+	graphAccessKeyLockModeAttr       = "lock_mode"
+	graphAccessKeyLockRangeEndAttr   = "lock_range_end"
+	graphAccessKeyLockRangeStartAttr = "lock_range_start"
+	graphAccessKeyLockShowTimesAttr  = "lock_show_times"
+	graphAccessKeyLockZoomAttr       = "lock_zoom"
+	graphAccessKeyNicknameAttr       = "nickname"
+	graphAccessKeyTitleAttr          = "title"
+	graphAccessKeyWidthAttr          = "width"
+	graphAccessKeyXLabelsAttr        = "x_labels"
+	graphAccessKeyYLabelsAttr        = "y_labels"
+
+	// circonus_graph.composite.* resource attribute names.
+	graphCompositeActiveAttr        = "active"
+	graphCompositeAxisAttr          = "axis"
+	graphCompositeColorAttr         = "color"
+	graphCompositeFormulaAttr       = "formula"
+	graphCompositeFormulaLegendAttr = "legend_formula"
+	graphCompositeHumanNameAttr     = "name"
+	graphCompositeStackAttr         = "stack"
 
 	// circonus_graph.{left,right}.* resource attribute names.
 	graphAxisLogarithmicAttr = "logarithmic"
@@ -69,17 +110,25 @@ const (
 
 var graphDescriptions = attrDescrs{
 	// circonus_graph.* resource attribute names
-	graphDescriptionAttr:   "",
-	graphLeftAttr:          "",
-	graphLineStyleAttr:     "How the line should change between point. A string containing either 'stepped', 'interpolated' or null.",
-	graphNameAttr:          "",
-	graphNotesAttr:         "",
-	graphRightAttr:         "",
-	graphMetricAttr:        "",
-	graphMetricClusterAttr: "",
-	graphStyleAttr:         "",
-	graphTagsAttr:          "",
-	graphGuidesAttr:        "",
+	graphAccessKeyAttr:         "An access key sharing the graph, e.g. to embed it in a status page",
+	graphColorPaletteAttr:      "The palette colors are assigned from to datapoints without a color.  Kept in the statefile only",
+	graphCompositeAttr:         "A series computed by a formula from the metric datapoints of the graph",
+	graphDescriptionAttr:       "",
+	graphLeftAttr:              "",
+	graphLineStyleAttr:         "How the line should change between point. A string containing either 'stepped', 'interpolated' or null.",
+	graphNameAttr:              "",
+	graphNotesAttr:             "",
+	graphRightAttr:             "",
+	graphMetricAttr:            "",
+	graphMetricClusterAttr:     "",
+	graphStyleAttr:             "",
+	graphTagsAttr:              "",
+	graphGuidesAttr:            "",
+	graphOutCreatedAttr:        "When the graph was created, in epoch seconds, when reported by the API",
+	graphOutCreatedByAttr:      "The CID of the user who created the graph, when reported by the API",
+	graphOutLastModifiedAttr:   "When the graph was last modified, in epoch seconds, when reported by the API",
+	graphOutLastModifiedByAttr: "The CID of the user who last modified the graph, when reported by the API",
+	graphOutUUIDAttr:           "The UUID of the graph, as referenced by dashboard widgets",
 }
 
 var graphMetricDescriptions = attrDescrs{
@@ -98,6 +147,7 @@ var graphMetricDescriptions = attrDescrs{
 	graphMetricHumanNameAttr:     "",
 	graphMetricNameAttr:          "",
 	graphMetricStackAttr:         "",
+	graphMetricStreamCountAttr:   "The number of metric streams the search matched as of the last refresh, only set for search datapoints",
 }
 
 var graphGuidesDescriptions = attrDescrs{
@@ -111,12 +161,42 @@ var graphGuidesDescriptions = attrDescrs{
 
 var graphMetricClusterDescriptions = attrDescrs{
 	// circonus_graph.metric_cluster.* resource attribute names
-	graphMetricClusterActiveAttr:    "",
-	graphMetricClusterAggregateAttr: "",
-	graphMetricClusterAxisAttr:      "",
-	graphMetricClusterColorAttr:     "",
-	graphMetricClusterQueryAttr:     "",
-	graphMetricClusterHumanNameAttr: "",
+	graphMetricClusterActiveAttr:      "",
+	graphMetricClusterAggregateAttr:   "",
+	graphMetricClusterAxisAttr:        "",
+	graphMetricClusterColorAttr:       "",
+	graphMetricClusterQueryAttr:       "",
+	graphMetricClusterHumanNameAttr:   "",
+	graphMetricClusterStreamCountAttr: "The number of metric streams the metric cluster matched as of the last refresh",
+}
+
+var graphAccessKeyDescriptions = attrDescrs{
+	// circonus_graph.access_key.* resource attribute names
+	graphAccessKeyHeightAttr:         "The height of the shared graph, in pixels",
+	graphAccessKeyKeyAttr:            "The access key, generated by the API",
+	graphAccessKeyLegendAttr:         "Show the legend",
+	graphAccessKeyLockDateAttr:       "Lock the date range of the shared graph",
+	graphAccessKeyLockModeAttr:       "How the date range is locked",
+	graphAccessKeyLockRangeEndAttr:   "The end of the locked date range, in epoch seconds",
+	graphAccessKeyLockRangeStartAttr: "The start of the locked date range, in epoch seconds",
+	graphAccessKeyLockShowTimesAttr:  "Show the locked date range",
+	graphAccessKeyLockZoomAttr:       "The zoom level the shared graph is locked to",
+	graphAccessKeyNicknameAttr:       "A name identifying the access key",
+	graphAccessKeyTitleAttr:          "Show the title",
+	graphAccessKeyWidthAttr:          "The width of the shared graph, in pixels",
+	graphAccessKeyXLabelsAttr:        "Show the x axis labels",
+	graphAccessKeyYLabelsAttr:        "Show the y axis labels",
+}
+
+var graphCompositeDescriptions = attrDescrs{
+	// circonus_graph.composite.* resource attribute names
+	graphCompositeActiveAttr:        "",
+	graphCompositeAxisAttr:          "",
+	graphCompositeColorAttr:         "",
+	graphCompositeFormulaAttr:       "The formula computing the series, referencing metric datapoints by letter (A is the first)",
+	graphCompositeFormulaLegendAttr: "",
+	graphCompositeHumanNameAttr:     "",
+	graphCompositeStackAttr:         "",
 }
 
 // NOTE(sean@): There is no way to set a description on map inputs, but if that
@@ -139,11 +219,11 @@ func resourceGraph() *schema.Resource {
 	// }
 
 	return &schema.Resource{
-		Create: graphCreate,
-		Read:   graphRead,
-		Update: graphUpdate,
-		Delete: graphDelete,
-		Exists: graphExists,
+		CreateContext: graphCreate,
+		Read:          graphRead,
+		UpdateContext: graphUpdate,
+		Delete:        graphDelete,
+		Exists:        graphExists,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -169,11 +249,18 @@ func resourceGraph() *schema.Resource {
 			graphNameAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
+				StateFunc:    suppressWhitespace,
 				ValidateFunc: validateRegexp(graphNameAttr, `.+`),
 			},
 			graphNotesAttr: {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:      schema.TypeString,
+				Optional:  true,
+				StateFunc: suppressWhitespace,
+			},
+			graphColorPaletteAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringIn(graphColorPaletteAttr, validGraphColorPalettes),
 			},
 			graphRightAttr: {
 				Type:         schema.TypeMap,
@@ -199,16 +286,17 @@ func resourceGraph() *schema.Resource {
 						graphGuideFormulaAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateRegexp(graphGuideFormulaAttr, `^.+$`),
+							ValidateFunc: validateGraphFormula(graphGuideFormulaAttr),
 						},
 						graphGuideFormulaLegendAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateRegexp(graphGuideFormulaLegendAttr, `^.+$`),
+							ValidateFunc: validateGraphFormula(graphGuideFormulaLegendAttr),
 						},
 						graphGuideHumanNameAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
+							StateFunc:    suppressWhitespace,
 							ValidateFunc: validateRegexp(graphGuideHumanNameAttr, `.+`),
 						},
 					}),
@@ -266,17 +354,18 @@ func resourceGraph() *schema.Resource {
 						graphMetricColorAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
+							Computed:     true,
 							ValidateFunc: validateRegexp(graphMetricColorAttr, `^#[0-9a-fA-F]{6}$`),
 						},
 						graphMetricFormulaAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateRegexp(graphMetricFormulaAttr, `^.+$`),
+							ValidateFunc: validateGraphFormula(graphMetricFormulaAttr),
 						},
 						graphMetricFormulaLegendAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateRegexp(graphMetricFormulaLegendAttr, `^.+$`),
+							ValidateFunc: validateGraphFormula(graphMetricFormulaLegendAttr),
 						},
 						graphMetricFunctionAttr: {
 							Type:         schema.TypeString,
@@ -292,6 +381,7 @@ func resourceGraph() *schema.Resource {
 						graphMetricHumanNameAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
+							StateFunc:    suppressWhitespace,
 							ValidateFunc: validateRegexp(graphMetricHumanNameAttr, `.+`),
 						},
 						graphMetricStackAttr: {
@@ -299,6 +389,10 @@ func resourceGraph() *schema.Resource {
 							Optional:     true,
 							ValidateFunc: validateRegexp(graphMetricStackAttr, `^[\d]*$`),
 						},
+						graphMetricStreamCountAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
 					}),
 				},
 			},
@@ -328,6 +422,7 @@ func resourceGraph() *schema.Resource {
 						graphMetricClusterColorAttr: {
 							Type:         schema.TypeString,
 							Optional:     true,
+							Computed:     true,
 							ValidateFunc: validateRegexp(graphMetricClusterColorAttr, `^#[0-9a-fA-F]{6}$`),
 						},
 						graphMetricClusterQueryAttr: {
@@ -338,8 +433,133 @@ func resourceGraph() *schema.Resource {
 						graphMetricClusterHumanNameAttr: {
 							Type:         schema.TypeString,
 							Required:     true,
+							StateFunc:    suppressWhitespace,
 							ValidateFunc: validateRegexp(graphMetricHumanNameAttr, `.+`),
 						},
+						graphMetricClusterStreamCountAttr: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					}),
+				},
+			},
+			graphAccessKeyAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(graphAccessKeyDescriptions, map[schemaAttr]*schema.Schema{
+						graphAccessKeyHeightAttr: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntMin(graphAccessKeyHeightAttr, 0),
+						},
+						graphAccessKeyKeyAttr: {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+						graphAccessKeyLegendAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						graphAccessKeyLockDateAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						graphAccessKeyLockModeAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphAccessKeyLockModeAttr, `.+`),
+						},
+						graphAccessKeyLockRangeEndAttr: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntMin(graphAccessKeyLockRangeEndAttr, 0),
+						},
+						graphAccessKeyLockRangeStartAttr: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntMin(graphAccessKeyLockRangeStartAttr, 0),
+						},
+						graphAccessKeyLockShowTimesAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						graphAccessKeyLockZoomAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphAccessKeyLockZoomAttr, `.+`),
+						},
+						graphAccessKeyNicknameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateRegexp(graphAccessKeyNicknameAttr, `.+`),
+						},
+						graphAccessKeyTitleAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						graphAccessKeyWidthAttr: {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntMin(graphAccessKeyWidthAttr, 0),
+						},
+						graphAccessKeyXLabelsAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						graphAccessKeyYLabelsAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+					}),
+				},
+			},
+			graphCompositeAttr: {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: convertToHelperSchema(graphCompositeDescriptions, map[schemaAttr]*schema.Schema{
+						graphCompositeActiveAttr: {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						graphCompositeAxisAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "left",
+							ValidateFunc: validateStringIn(graphCompositeAxisAttr, validAxisAttrs),
+						},
+						graphCompositeColorAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateRegexp(graphCompositeColorAttr, `^#[0-9a-fA-F]{6}$`),
+						},
+						graphCompositeFormulaAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateGraphFormula(graphCompositeFormulaAttr),
+						},
+						graphCompositeFormulaLegendAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateGraphFormula(graphCompositeFormulaLegendAttr),
+						},
+						graphCompositeHumanNameAttr: {
+							Type:         schema.TypeString,
+							Required:     true,
+							StateFunc:    suppressWhitespace,
+							ValidateFunc: validateRegexp(graphCompositeHumanNameAttr, `.+`),
+						},
+						graphCompositeStackAttr: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateRegexp(graphCompositeStackAttr, `^[\d]*$`),
+						},
 					}),
 				},
 			},
@@ -350,24 +570,54 @@ func resourceGraph() *schema.Resource {
 				ValidateFunc: validateStringIn(graphStyleAttr, validGraphStyles),
 			},
 			graphTagsAttr: tagMakeConfigSchema(graphTagsAttr),
+			graphOutCreatedAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			graphOutCreatedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			graphOutLastModifiedAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			graphOutLastModifiedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			graphOutUUIDAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		}),
 	}
 }
 
-func graphCreate(d *schema.ResourceData, meta interface{}) error {
+func graphCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ctxt := meta.(*providerContext)
 	g := newGraph()
 	if err := g.ParseConfig(d); err != nil {
-		return fmt.Errorf("error parsing graph schema during create: %w", err)
+		return diag.FromErr(fmt.Errorf("error parsing graph schema during create: %w", err))
 	}
 
-	if err := g.Create(ctxt); err != nil {
-		return fmt.Errorf("error creating graph: %w", err)
+	if err := ctxt.graphCreates.Do(ctx, func() error { return g.Create(ctxt) }); err != nil {
+		return diag.FromErr(fmt.Errorf("error creating graph: %w", err))
 	}
 
-	d.SetId(g.CID)
+	// The graph exists at this point, failing the create would taint it.
+	var diags diag.Diagnostics
+	if err := g.StreamCounts(ctxt); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to count the metric streams of the graph",
+			Detail:   fmt.Sprintf("%s, they are counted on the next refresh.", err),
+		})
+	}
 
-	return graphRead(d, meta)
+	// The API answers the create with the graph as stored, there is no need
+	// to read it back.
+	return append(diags, diag.FromErr(graphToState(d, &g))...)
 }
 
 func graphExists(d *schema.ResourceData, meta interface{}) (bool, error) {
@@ -401,7 +651,49 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	graphCountStreams(ctxt, d, &g)
+
+	return graphToState(d, &g)
+}
+
+// graphCountStreams counts the metric streams of g for graphRead and
+// graphUpdateTags.  Counting is best effort, a search or metric cluster that
+// can not be looked up keeps the count it has in the statefile so one failed
+// lookup neither fails the refresh nor changes stream_count.
+func graphCountStreams(ctxt *providerContext, d *schema.ResourceData, g *circonusGraph) {
+	g.searchStreams = make(map[string]int)
+	for _, datapointRaw := range d.Get(graphMetricAttr).([]interface{}) {
+		datapointAttrs := newInterfaceMap(datapointRaw)
+		if search, ok := datapointAttrs[graphMetricSearchAttr].(string); ok && search != "" {
+			if n, ok := datapointAttrs[graphMetricStreamCountAttr].(int); ok {
+				g.searchStreams[search] = n
+			}
+		}
+	}
+
+	g.clusterStreams = make(map[string]int)
+	for _, metricClusterRaw := range d.Get(graphMetricClusterAttr).([]interface{}) {
+		metricClusterAttrs := newInterfaceMap(metricClusterRaw)
+		if cid, ok := metricClusterAttrs[graphMetricClusterQueryAttr].(string); ok && cid != "" {
+			if n, ok := metricClusterAttrs[graphMetricClusterStreamCountAttr].(int); ok {
+				g.clusterStreams[cid] = n
+			}
+		}
+	}
+
+	if err := g.StreamCounts(ctxt); err != nil {
+		log.Printf("[WARN] %v, keeping the stream counts of the last refresh", err)
+	}
+}
+
+// graphToState stores the contents of a Graph object in the statefile.
+func graphToState(d *schema.ResourceData, g *circonusGraph) error {
 	d.SetId(g.CID)
+	_ = d.Set(graphOutUUIDAttr, g.UUID())
+	_ = d.Set(graphOutCreatedAttr, g.audit.Created)
+	_ = d.Set(graphOutCreatedByAttr, g.audit.CreatedBy)
+	_ = d.Set(graphOutLastModifiedAttr, g.audit.LastModified)
+	_ = d.Set(graphOutLastModifiedByAttr, g.audit.LastModifiedBy)
 
 	metrics := make([]interface{}, 0, len(g.Datapoints))
 	for _, datapoint := range g.Datapoints {
@@ -465,13 +757,19 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		}
 
 		if datapoint.Name != "" {
-			dataPointAttrs[string(graphMetricHumanNameAttr)] = datapoint.Name
+			dataPointAttrs[string(graphMetricHumanNameAttr)] = suppressWhitespace(datapoint.Name)
 		}
 
 		if datapoint.Stack != nil {
 			dataPointAttrs[string(graphMetricStackAttr)] = fmt.Sprintf("%d", *datapoint.Stack)
 		}
 
+		if datapoint.Search != nil {
+			if n, found := g.searchStreams[*datapoint.Search]; found {
+				dataPointAttrs[string(graphMetricStreamCountAttr)] = n
+			}
+		}
+
 		metrics = append(metrics, dataPointAttrs)
 	}
 
@@ -511,13 +809,17 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		}
 
 		if metricCluster.Name != "" {
-			metricClusterAttrs[string(graphMetricHumanNameAttr)] = metricCluster.Name
+			metricClusterAttrs[string(graphMetricHumanNameAttr)] = suppressWhitespace(metricCluster.Name)
 		}
 
 		if metricCluster.Stack != nil {
 			metricClusterAttrs[string(graphMetricStackAttr)] = fmt.Sprintf("%d", *metricCluster.Stack)
 		}
 
+		if n, found := g.clusterStreams[metricCluster.MetricCluster]; found {
+			metricClusterAttrs[string(graphMetricClusterStreamCountAttr)] = n
+		}
+
 		metricClusters = append(metricClusters, metricClusterAttrs)
 	}
 
@@ -547,15 +849,19 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		rightAxisMap[string(graphAxisMinAttr)] = strconv.FormatFloat(*g.MinRightY, 'f', -1, 64)
 	}
 
-	_ = d.Set(graphDescriptionAttr, g.Description)
+	_ = d.Set(graphDescriptionAttr, suppressWhitespace(g.Description))
 
 	if err := d.Set(graphLeftAttr, leftAxisMap); err != nil {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphLeftAttr, err)
 	}
 
 	_ = d.Set(graphLineStyleAttr, g.LineStyle)
-	_ = d.Set(graphNameAttr, g.Title)
-	_ = d.Set(graphNotesAttr, indirect(g.Notes))
+	_ = d.Set(graphNameAttr, suppressWhitespace(g.Title))
+	if g.Notes != nil {
+		_ = d.Set(graphNotesAttr, suppressWhitespace(*g.Notes))
+	} else {
+		_ = d.Set(graphNotesAttr, nil)
+	}
 
 	if err := d.Set(graphRightAttr, rightAxisMap); err != nil {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphRightAttr, err)
@@ -569,6 +875,72 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Unable to store graph %q attribute: %w", graphMetricClusterAttr, err)
 	}
 
+	accessKeys := make([]interface{}, 0, len(g.AccessKeys))
+	for _, accessKey := range g.AccessKeys {
+		accessKeys = append(accessKeys, map[string]interface{}{
+			string(graphAccessKeyHeightAttr):         int(accessKey.Height),
+			string(graphAccessKeyKeyAttr):            accessKey.Key,
+			string(graphAccessKeyLegendAttr):         accessKey.Legend,
+			string(graphAccessKeyLockDateAttr):       accessKey.LockDate,
+			string(graphAccessKeyLockModeAttr):       accessKey.LockMode,
+			string(graphAccessKeyLockRangeEndAttr):   int(accessKey.LockRangeEnd),
+			string(graphAccessKeyLockRangeStartAttr): int(accessKey.LockRangeStart),
+			string(graphAccessKeyLockShowTimesAttr):  accessKey.LockShowTimes,
+			string(graphAccessKeyLockZoomAttr):       accessKey.LockZoom,
+			string(graphAccessKeyNicknameAttr):       accessKey.Nickname,
+			string(graphAccessKeyTitleAttr):          accessKey.Title,
+			string(graphAccessKeyWidthAttr):          int(accessKey.Width),
+			string(graphAccessKeyXLabelsAttr):        accessKey.XLabels,
+			string(graphAccessKeyYLabelsAttr):        accessKey.YLabels,
+		})
+	}
+
+	if err := d.Set(graphAccessKeyAttr, accessKeys); err != nil {
+		return fmt.Errorf("Unable to store graph %q attribute: %w", graphAccessKeyAttr, err)
+	}
+
+	composites := make([]interface{}, 0, len(g.Composites))
+	for _, composite := range g.Composites {
+		compositeAttrs := make(map[string]interface{}, 7) // 7 == len(members in api.GraphComposite)
+
+		compositeAttrs[string(graphCompositeActiveAttr)] = !composite.Hidden
+
+		switch composite.Axis {
+		case "l", "":
+			compositeAttrs[string(graphCompositeAxisAttr)] = "left"
+		case "r":
+			compositeAttrs[string(graphCompositeAxisAttr)] = "right"
+		default:
+			return fmt.Errorf("PROVIDER BUG: Unsupported axis type %q", composite.Axis)
+		}
+
+		if composite.Color != "" {
+			compositeAttrs[string(graphCompositeColorAttr)] = composite.Color
+		}
+
+		if composite.DataFormula != nil {
+			compositeAttrs[string(graphCompositeFormulaAttr)] = *composite.DataFormula
+		}
+
+		if composite.LegendFormula != nil {
+			compositeAttrs[string(graphCompositeFormulaLegendAttr)] = *composite.LegendFormula
+		}
+
+		if composite.Name != "" {
+			compositeAttrs[string(graphCompositeHumanNameAttr)] = suppressWhitespace(composite.Name)
+		}
+
+		if composite.Stack != nil {
+			compositeAttrs[string(graphCompositeStackAttr)] = fmt.Sprintf("%d", *composite.Stack)
+		}
+
+		composites = append(composites, compositeAttrs)
+	}
+
+	if err := d.Set(graphCompositeAttr, composites); err != nil {
+		return fmt.Errorf("Unable to store graph %q attribute: %w", graphCompositeAttr, err)
+	}
+
 	_ = d.Set(graphStyleAttr, g.Style)
 
 	if err := d.Set(graphTagsAttr, tagsToState(apiToTags(g.Tags))); err != nil {
@@ -592,7 +964,7 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 		}
 
 		if guide.Name != "" {
-			guideAttrs[string(graphGuideHumanNameAttr)] = guide.Name
+			guideAttrs[string(graphGuideHumanNameAttr)] = suppressWhitespace(guide.Name)
 		}
 
 		guides = append(guides, guideAttrs)
@@ -602,8 +974,174 @@ func graphRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func graphUpdate(d *schema.ResourceData, meta interface{}) error {
+func graphUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ctxt := meta.(*providerContext)
+
+	// Tag policy rollouts touch nothing but the tags, avoid re-fetching the
+	// graph and the dashboards referencing it in that case.
+	if !d.HasChangesExcept(graphTagsAttr) {
+		return diag.FromErr(graphUpdateTags(d, meta))
+	}
+
+	g := newGraph()
+	if err := g.ParseConfig(d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	g.CID = d.Id()
+
+	// The plugin SDK has no way to warn while planning, the check runs right
+	// before the graph is changed instead.
+	diags := graphRemovedDatapointsDiags(ctxt, g.CID, graphRemovedDatapoints(d))
+
+	if err := g.Update(ctxt); err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf("unable to update graph %q: %w", d.Id(), err))...)
+	}
+
+	return append(diags, diag.FromErr(graphRead(d, meta))...)
+}
+
+// graphRemovedDatapoints describes each metric, metric cluster and composite
+// datapoint of the graph in state that the change in d removes.
+func graphRemovedDatapoints(d *schema.ResourceData) []string {
+	removed := make([]string, 0)
+
+	for _, attr := range []schemaAttr{graphMetricAttr, graphMetricClusterAttr, graphCompositeAttr} {
+		o, n := d.GetChange(string(attr))
+		oldList, _ := o.([]interface{})
+		newList, _ := n.([]interface{})
+		removed = append(removed, graphDatapointsRemoved(attr, oldList, newList)...)
+	}
+
+	return removed
+}
+
+// graphDatapointsRemoved returns the keys of the attr datapoints in oldList
+// missing from newList.  Reordering or restyling a datapoint does not remove
+// it.
+func graphDatapointsRemoved(attr schemaAttr, oldList, newList []interface{}) []string {
+	removed := make([]string, 0)
+
+	kept := make(map[string]int, len(newList))
+	for _, raw := range newList {
+		kept[graphDatapointKey(attr, raw)]++
+	}
+
+	for _, raw := range oldList {
+		key := graphDatapointKey(attr, raw)
+		if kept[key] > 0 {
+			kept[key]--
+			continue
+		}
+		removed = append(removed, key)
+	}
+
+	return removed
+}
+
+// graphDatapointKey identifies a metric, metric cluster or composite datapoint
+// by what it draws, ignoring how it is drawn.
+func graphDatapointKey(attr schemaAttr, raw interface{}) string {
+	m, _ := raw.(map[string]interface{})
+	get := func(attrName schemaAttr) string {
+		v, _ := m[string(attrName)].(string)
+		return v
+	}
+
+	switch attr {
+	case graphMetricClusterAttr:
+		return fmt.Sprintf("%s %s", graphMetricClusterAttr, get(graphMetricClusterQueryAttr))
+	case graphCompositeAttr:
+		return fmt.Sprintf("%s %s", graphCompositeAttr, get(graphCompositeFormulaAttr))
+	}
+
+	switch {
+	case get(graphMetricCAQLAttr) != "":
+		return fmt.Sprintf("%s %s %s", graphMetricAttr, graphMetricCAQLAttr, get(graphMetricCAQLAttr))
+	case get(graphMetricSearchAttr) != "":
+		return fmt.Sprintf("%s %s %s", graphMetricAttr, graphMetricSearchAttr, get(graphMetricSearchAttr))
+	case get(graphMetricNameAttr) != "":
+		return fmt.Sprintf("%s %s`%s", graphMetricAttr, get(graphMetricCheckAttr), get(graphMetricNameAttr))
+	default:
+		return fmt.Sprintf("%s %s %s", graphMetricAttr, graphMetricFormulaAttr, get(graphMetricFormulaAttr))
+	}
+}
+
+// graphRemovedDatapointsDiags warns about removing datapoints from a graph
+// shown on dashboards or worksheets.  The API has no reverse lookup, every
+// dashboard and worksheet is searched for references to the graph.
+func graphRemovedDatapointsDiags(ctxt *providerContext, cid string, removed []string) diag.Diagnostics {
+	if len(removed) == 0 {
+		return nil
+	}
+
+	refs, err := graphReferences(ctxt, cid)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Unable to check graph references",
+			Detail:   fmt.Sprintf("Datapoints are removed from graph %s but the dashboards and worksheets using it could not be searched: %v", cid, err),
+		}}
+	}
+
+	if len(refs) == 0 {
+		return nil
+	}
+
+	log.Printf("[WARN] removing datapoints %q from graph %s referenced by %q", removed, cid, refs)
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Datapoints removed from a shared graph",
+		Detail: fmt.Sprintf("Graph %s is used by %s.  They no longer show the removed datapoints: %s.",
+			cid, strings.Join(refs, ", "), strings.Join(removed, ", ")),
+	}}
+}
+
+// graphReferences lists the dashboards, whose widgets reference graphs by
+// UUID, and the worksheets, which reference graphs by CID, showing the graph.
+func graphReferences(ctxt *providerContext, cid string) ([]string, error) {
+	uuid, _ := cidID(cid)
+	refs := make([]string, 0)
+
+	dashboards, err := ctxt.client.SearchDashboards(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search dashboards: %w", err)
+	}
+
+	for _, dash := range *dashboards {
+		for _, w := range dash.Widgets {
+			if w.Settings.GraphUUID != "" && w.Settings.GraphUUID == uuid {
+				refs = append(refs, fmt.Sprintf("dashboard %q (%s)", dash.Title, dash.CID))
+				break
+			}
+		}
+	}
+
+	worksheets, err := ctxt.client.SearchWorksheets(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search worksheets: %w", err)
+	}
+
+	for _, ws := range *worksheets {
+		for _, wg := range ws.Graphs {
+			if wg.GraphCID == cid {
+				refs = append(refs, fmt.Sprintf("worksheet %q (%s)", ws.Title, ws.CID))
+				break
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// graphUpdateTags updates a graph whose tags are the only change.  The API
+// replaces the whole graph on PUT, a payload of only the tags would clear the
+// datapoints, so the full graph is sent.  The graph returned by the PUT is
+// stored in the statefile rather than fetched again.
+func graphUpdateTags(d *schema.ResourceData, meta interface{}) error {
 	ctxt := meta.(*providerContext)
+
 	g := newGraph()
 	if err := g.ParseConfig(d); err != nil {
 		return err
@@ -614,7 +1152,9 @@ func graphUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("unable to update graph %q: %w", d.Id(), err)
 	}
 
-	return graphRead(d, meta)
+	graphCountStreams(ctxt, d, &g)
+
+	return graphToState(d, &g)
 }
 
 func graphDelete(d *schema.ResourceData, meta interface{}) error {
@@ -632,6 +1172,15 @@ func graphDelete(d *schema.ResourceData, meta interface{}) error {
 
 type circonusGraph struct {
 	api.Graph
+
+	// The number of metric streams matched by each search and metric cluster
+	// of the graph, as counted by StreamCounts.
+	searchStreams  map[string]int
+	clusterStreams map[string]int
+
+	// audit is who created and last modified the graph, and when, as of the
+	// last response of the API.
+	audit apiAuditMetadata
 }
 
 func newGraph() circonusGraph {
@@ -642,14 +1191,21 @@ func newGraph() circonusGraph {
 	return g
 }
 
+// loadGraph fetches the whole graph.  The graph API has no field selection
+// (e.g. a fields query parameter), it always returns the complete object, so a
+// refresh can not be limited to the attributes kept in the statefile.
 func loadGraph(ctxt *providerContext, cid api.CIDType) (circonusGraph, error) {
 	var g circonusGraph
-	ng, err := ctxt.client.FetchGraph(cid)
+	if cid == nil {
+		return circonusGraph{}, fmt.Errorf("invalid graph CID (none)")
+	}
+
+	audit, err := fetchObject(ctxt, config.GraphPrefix, *cid, &g.Graph)
 	if err != nil {
 		return circonusGraph{}, err
 	}
-	g.Graph = *ng
-	log.Printf("[loadGraph] %#v\n", *ng)
+	g.audit = audit
+	log.Printf("[loadGraph] %#v\n", g.Graph)
 
 	return g, nil
 }
@@ -708,7 +1264,7 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 	}
 
 	if v, found := d.GetOk(graphDescriptionAttr); found {
-		g.Description = v.(string)
+		g.Description = suppressWhitespace(v)
 	}
 
 	if v, found := d.GetOk(graphLineStyleAttr); found {
@@ -724,11 +1280,11 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 	}
 
 	if v, found := d.GetOk(graphNameAttr); found {
-		g.Title = v.(string)
+		g.Title = suppressWhitespace(v)
 	}
 
 	if v, found := d.GetOk(graphNotesAttr); found {
-		s := v.(string)
+		s := suppressWhitespace(v)
 		g.Notes = &s
 	}
 
@@ -973,7 +1529,7 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 			if v, found := metricClusterAttrs[graphMetricHumanNameAttr]; found {
 				s := v.(string)
 				if s != "" {
-					metricCluster.Name = s
+					metricCluster.Name = suppressWhitespace(s)
 				}
 			}
 
@@ -1001,6 +1557,99 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
+	// Access keys are matched to the keys the API generated by position, an
+	// access key keeps its key as long as it keeps its place in the list.
+	// Removing an access key revokes it.
+	if listRaw, found := d.GetOk(graphAccessKeyAttr); found {
+		for _, accessKeyListRaw := range listRaw.([]interface{}) {
+			accessKeyAttrs := newInterfaceMap(accessKeyListRaw.(map[string]interface{}))
+			getBool := func(attrName schemaAttr) bool {
+				b, _ := accessKeyAttrs[string(attrName)].(bool)
+				return b
+			}
+			getUint := func(attrName schemaAttr) uint {
+				i, _ := accessKeyAttrs[string(attrName)].(int)
+				return uint(i)
+			}
+			getString := func(attrName schemaAttr) string {
+				s, _ := accessKeyAttrs[string(attrName)].(string)
+				return s
+			}
+
+			g.AccessKeys = append(g.AccessKeys, api.GraphAccessKey{
+				Active:         true,
+				Height:         getUint(graphAccessKeyHeightAttr),
+				Key:            getString(graphAccessKeyKeyAttr),
+				Legend:         getBool(graphAccessKeyLegendAttr),
+				LockDate:       getBool(graphAccessKeyLockDateAttr),
+				LockMode:       getString(graphAccessKeyLockModeAttr),
+				LockRangeEnd:   getUint(graphAccessKeyLockRangeEndAttr),
+				LockRangeStart: getUint(graphAccessKeyLockRangeStartAttr),
+				LockShowTimes:  getBool(graphAccessKeyLockShowTimesAttr),
+				LockZoom:       getString(graphAccessKeyLockZoomAttr),
+				Nickname:       getString(graphAccessKeyNicknameAttr),
+				Title:          getBool(graphAccessKeyTitleAttr),
+				Width:          getUint(graphAccessKeyWidthAttr),
+				XLabels:        getBool(graphAccessKeyXLabelsAttr),
+				YLabels:        getBool(graphAccessKeyYLabelsAttr),
+			})
+		}
+	}
+
+	if listRaw, found := d.GetOk(graphCompositeAttr); found {
+		for _, compositeListRaw := range listRaw.([]interface{}) {
+			compositeAttrs := newInterfaceMap(compositeListRaw.(map[string]interface{}))
+
+			composite := api.GraphComposite{}
+
+			if v, found := compositeAttrs[graphCompositeActiveAttr]; found {
+				composite.Hidden = !(v.(bool))
+			}
+
+			if v, found := compositeAttrs[graphCompositeAxisAttr]; found {
+				switch v.(string) {
+				case "left", "":
+					composite.Axis = "l"
+				case "right":
+					composite.Axis = "r"
+				default:
+					return fmt.Errorf("PROVIDER BUG: Unsupported axis attribute %q: %q", graphCompositeAxisAttr, v.(string))
+				}
+			}
+
+			if v, found := compositeAttrs[graphCompositeColorAttr]; found {
+				composite.Color = v.(string)
+			}
+
+			if v, found := compositeAttrs[graphCompositeFormulaAttr]; found {
+				s := v.(string)
+				composite.DataFormula = &s
+			}
+
+			if v, found := compositeAttrs[graphCompositeFormulaLegendAttr]; found {
+				s := v.(string)
+				if s != "" {
+					composite.LegendFormula = &s
+				}
+			}
+
+			if v, found := compositeAttrs[graphCompositeHumanNameAttr]; found {
+				composite.Name = suppressWhitespace(v.(string))
+			}
+
+			if v, found := compositeAttrs[graphCompositeStackAttr]; found {
+				s := v.(string)
+				if s != "" {
+					u64, _ := strconv.ParseUint(s, 10, 64)
+					u := uint(u64)
+					composite.Stack = &u
+				}
+			}
+
+			g.Composites = append(g.Composites, composite)
+		}
+	}
+
 	if v, found := d.GetOk(graphStyleAttr); found {
 		switch v := v.(type) {
 		case string:
@@ -1056,7 +1705,7 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 			if v, found := guideAttrs[graphGuideHumanNameAttr]; found {
 				s := v.(string)
 				if s != "" {
-					guide.Name = s
+					guide.Name = suppressWhitespace(s)
 				}
 			}
 
@@ -1064,6 +1713,10 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 		}
 	}
 
+	if v, found := d.GetOk(graphColorPaletteAttr); found {
+		g.assignPaletteColors(graphColorPalettes[v.(string)])
+	}
+
 	log.Printf("[ParseConfig] %#v\n", g.Graph)
 
 	if err := g.Validate(); err != nil {
@@ -1074,22 +1727,116 @@ func (g *circonusGraph) ParseConfig(d *schema.ResourceData) error {
 }
 
 func (g *circonusGraph) Create(ctxt *providerContext) error {
-	ng, err := ctxt.client.CreateGraph(&g.Graph)
+	payload, err := json.Marshal(&g.Graph)
 	if err != nil {
 		return err
 	}
 
-	g.CID = ng.CID
+	result, err := ctxt.client.Post(config.GraphPrefix, payload)
+	if err != nil {
+		return fmt.Errorf("creating graph: %w", err)
+	}
+
+	var ng api.Graph
+	audit, err := parseObject(result, &ng)
+	if err != nil {
+		return fmt.Errorf("parsing graph: %w", err)
+	}
+	g.Graph, g.audit = ng, audit
 
 	return nil
 }
 
+// StreamCounts counts the metric streams matched by the search datapoints and
+// the metric clusters of the graph, so a search that silently stopped matching
+// anything, e.g. after the metrics were renamed, shows up in the statefile.
+// Each search and metric cluster is looked up once.  Lookups that fail keep
+// the count g already has, if any, and are reported together once every other
+// lookup was made.
+func (g *circonusGraph) StreamCounts(ctxt *providerContext) error {
+	var errs []string
+
+	searchStreams := make(map[string]int)
+	searched := make(map[string]bool)
+	for _, datapoint := range g.Datapoints {
+		if datapoint.Search == nil || *datapoint.Search == "" {
+			continue
+		}
+
+		search := *datapoint.Search
+		if searched[search] {
+			continue
+		}
+		searched[search] = true
+
+		query := api.SearchQueryType(search)
+		metrics, err := ctxt.client.SearchMetrics(&query, nil)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Unable to search for the metrics of graph %s matching %q: %v", g.CID, search, err))
+			if n, found := g.searchStreams[search]; found {
+				searchStreams[search] = n
+			}
+			continue
+		}
+		searchStreams[search] = len(*metrics)
+	}
+	g.searchStreams = searchStreams
+
+	clusterStreams := make(map[string]int)
+	fetched := make(map[string]bool)
+	for _, metricCluster := range g.MetricClusters {
+		cid := metricCluster.MetricCluster
+		if cid == "" || fetched[cid] {
+			continue
+		}
+		fetched[cid] = true
+
+		cluster, err := ctxt.client.FetchMetricCluster(api.CIDType(&cid), "metrics")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Unable to fetch metric cluster %s of graph %s: %v", cid, g.CID, err))
+			if n, found := g.clusterStreams[cid]; found {
+				clusterStreams[cid] = n
+			}
+			continue
+		}
+		clusterStreams[cid] = len(cluster.MatchingMetrics)
+	}
+	g.clusterStreams = clusterStreams
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// UUID returns the graph's UUID, the last element of its CID.  Dashboard
+// widgets reference graphs by UUID rather than by CID.
+func (g *circonusGraph) UUID() string {
+	id, _ := cidID(g.CID)
+	return id
+}
+
+// Update replaces the graph with g.  g is refreshed with the graph returned by
+// the API.
 func (g *circonusGraph) Update(ctxt *providerContext) error {
-	_, err := ctxt.client.UpdateGraph(&g.Graph)
+	payload, err := json.Marshal(&g.Graph)
+	if err != nil {
+		return err
+	}
+
+	result, err := ctxt.client.Put(g.CID, payload)
 	if err != nil {
 		return fmt.Errorf("Unable to update graph %s: %w", g.CID, err)
 	}
 
+	var ng api.Graph
+	audit, err := parseObject(result, &ng)
+	if err != nil {
+		return fmt.Errorf("Unable to parse graph %s: %w", g.CID, err)
+	}
+	g.Graph, g.audit = ng, audit
+
 	return nil
 }
 
@@ -1125,6 +1872,16 @@ func (g *circonusGraph) Validate() error {
 		}
 	}
 
+	for i, composite := range g.Composites {
+		if composite.DataFormula == nil {
+			continue
+		}
+
+		if err := g.validateCompositeFormula(*composite.DataFormula); err != nil {
+			return fmt.Errorf("Error with %s[%d] name=%q: %w", graphCompositeAttr, i, composite.Name, err)
+		}
+	}
+
 	for i, mc := range g.MetricClusters {
 		if mc.AggregateFunc != "" && (mc.Color == nil || *mc.Color == "") {
 			return fmt.Errorf("Error with %s[%d] name=%q: %s is a required attribute for graphs with %s set", graphMetricClusterAttr, i, mc.Name, graphMetricClusterColorAttr, graphMetricClusterAggregateAttr)
@@ -1133,3 +1890,89 @@ func (g *circonusGraph) Validate() error {
 
 	return nil
 }
+
+// validateCompositeFormula checks a composite formula references at least one
+// metric datapoint of the graph, and only datapoints the graph has.
+func (g *circonusGraph) validateCompositeFormula(formula string) error {
+	refs, err := graphFormulaDatapointRefs(formula)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", graphCompositeFormulaAttr, formula, err)
+	}
+
+	if len(refs) == 0 {
+		return fmt.Errorf("%s %q must reference %s datapoints (A, B, ...)", graphCompositeFormulaAttr, formula, graphMetricAttr)
+	}
+
+	for _, ref := range refs {
+		if graphFormulaDatapointIndex(ref) >= len(g.Datapoints) {
+			return fmt.Errorf("%s %q references datapoint %s, the graph has %d %s datapoints", graphCompositeFormulaAttr, formula, ref, len(g.Datapoints), graphMetricAttr)
+		}
+	}
+
+	return nil
+}
+
+// graphColorPalettes are the colors of each color_palette, in the order they
+// are assigned.
+var graphColorPalettes = map[string][]string{
+	"okabe_ito": {"#e69f00", "#56b4e9", "#009e73", "#f0e442", "#0072b2", "#d55e00", "#cc79a7", "#000000"},
+	"tableau10": {"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"},
+}
+
+// assignPaletteColors gives the metric, metric cluster and composite
+// datapoints without a color the next palette color not already used by
+// another datapoint.  Colors are reused once the palette runs out.  Colors
+// the API backfilled are kept in the statefile, so datapoints keep their color
+// when datapoints are added.
+func (g *circonusGraph) assignPaletteColors(palette []string) {
+	if len(palette) == 0 {
+		return
+	}
+
+	used := make(map[string]bool, len(palette))
+	colors := make([]*string, 0, len(g.Datapoints)+len(g.MetricClusters)+len(g.Composites))
+	for i := range g.Datapoints {
+		if g.Datapoints[i].Color == nil {
+			g.Datapoints[i].Color = new(string)
+		}
+		colors = append(colors, g.Datapoints[i].Color)
+	}
+	for i := range g.MetricClusters {
+		if g.MetricClusters[i].Color == nil {
+			g.MetricClusters[i].Color = new(string)
+		}
+		colors = append(colors, g.MetricClusters[i].Color)
+	}
+	for i := range g.Composites {
+		colors = append(colors, &g.Composites[i].Color)
+	}
+
+	for _, color := range colors {
+		if *color != "" {
+			used[strings.ToLower(*color)] = true
+		}
+	}
+
+	next := 0
+	for _, color := range colors {
+		if *color != "" {
+			continue
+		}
+
+		// Prefer a color no datapoint uses, fall back to cycling through
+		// the palette once every color is taken.
+		c := palette[next%len(palette)]
+		for i := 0; i < len(palette); i++ {
+			candidate := palette[(next+i)%len(palette)]
+			if !used[candidate] {
+				c = candidate
+				next += i
+				break
+			}
+		}
+		next++
+
+		*color = c
+		used[c] = true
+	}
+}
@@ -1,13 +1,18 @@
 package circonus
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -28,6 +33,7 @@ func TestAccCirconusGraph_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "name", graphName),
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "description", "Terraform Test: mixed graph"),
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "notes", "test notes"),
+					resource.TestMatchResourceAttr("circonus_graph.mixed-points", "uuid", regexp.MustCompile(`^[0-9a-f-]{36}$`)),
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "graph_style", "line"),
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "left.%", "1"),
 					resource.TestCheckResourceAttr("circonus_graph.mixed-points", "left.max", "11"),
@@ -130,6 +136,398 @@ func TestAccCirconusGraph_basic(t *testing.T) {
 	})
 }
 
+func TestGraphFreeTextWhitespace(t *testing.T) {
+	graphSchema := resourceGraph().Schema
+
+	for _, attr := range []schemaAttr{graphDescriptionAttr, graphNameAttr, graphNotesAttr} {
+		if graphSchema[string(attr)].StateFunc == nil {
+			t.Errorf("%s: expected a StateFunc to normalize whitespace", attr)
+		}
+	}
+
+	nested := map[schemaAttr]schemaAttr{
+		graphGuidesAttr:        graphGuideHumanNameAttr,
+		graphMetricAttr:        graphMetricHumanNameAttr,
+		graphMetricClusterAttr: graphMetricClusterHumanNameAttr,
+	}
+	for block, attr := range nested {
+		elem := graphSchema[string(block)].Elem.(*schema.Resource)
+		if elem.Schema[string(attr)].StateFunc == nil {
+			t.Errorf("%s.%s: expected a StateFunc to normalize whitespace", block, attr)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, graphSchema, map[string]interface{}{
+		string(graphDescriptionAttr): "  A description\n",
+		string(graphNameAttr):        "A graph\n",
+		string(graphNotesAttr):       "Line one\nLine two\n\n",
+	})
+
+	g := newGraph()
+	if err := g.ParseConfig(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if g.Description != "A description" {
+		t.Errorf("description: expected %q, got %q", "A description", g.Description)
+	}
+	if g.Title != "A graph" {
+		t.Errorf("title: expected %q, got %q", "A graph", g.Title)
+	}
+	if g.Notes == nil || *g.Notes != "Line one\nLine two" {
+		t.Errorf("notes: expected %q, got %v", "Line one\nLine two", g.Notes)
+	}
+
+	notes := "Line one\nLine two\n"
+	g.Description = "A description\n"
+	g.Notes = &notes
+	g.Title = " A graph"
+
+	d = resourceGraph().TestResourceData()
+	if err := graphToState(d, &g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for attr, expected := range map[schemaAttr]string{
+		graphDescriptionAttr: "A description",
+		graphNameAttr:        "A graph",
+		graphNotesAttr:       "Line one\nLine two",
+	} {
+		if v := d.Get(string(attr)).(string); v != expected {
+			t.Errorf("%s: expected %q, got %q", attr, expected, v)
+		}
+	}
+}
+
+func TestGraphComposite(t *testing.T) {
+	metric := func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			string(graphMetricActiveAttr):     false,
+			string(graphMetricAxisAttr):       "left",
+			string(graphMetricCheckAttr):      "/check/1",
+			string(graphMetricNameAttr):       name,
+			string(graphMetricMetricTypeAttr): "numeric",
+		}
+	}
+
+	tests := []struct {
+		formula string
+		err     string
+	}{
+		{formula: "=A/B*100"},
+		{formula: "=max(A, B)"},
+		{formula: "=VAL*100", err: "must reference metric datapoints"},
+		{formula: "=A/C", err: "references datapoint C, the graph has 2"},
+		{formula: "=A/AA", err: "references datapoint AA"},
+	}
+
+	for _, test := range tests {
+		d := schema.TestResourceDataRaw(t, resourceGraph().Schema, map[string]interface{}{
+			string(graphNameAttr):   "Error rate",
+			string(graphMetricAttr): []interface{}{metric("errors"), metric("requests")},
+			string(graphCompositeAttr): []interface{}{
+				map[string]interface{}{
+					string(graphCompositeHumanNameAttr): "Error rate (%)",
+					string(graphCompositeFormulaAttr):   test.formula,
+					string(graphCompositeAxisAttr):      "right",
+					string(graphCompositeStackAttr):     "1",
+				},
+			},
+		})
+
+		g := newGraph()
+		err := g.ParseConfig(d)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%q: expected an error containing %q, got %v", test.formula, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.formula, err)
+		}
+
+		if len(g.Composites) != 1 {
+			t.Fatalf("%q: expected 1 composite, got %d", test.formula, len(g.Composites))
+		}
+		composite := g.Composites[0]
+		if composite.DataFormula == nil || *composite.DataFormula != test.formula || composite.Axis != "r" || composite.Hidden ||
+			composite.Stack == nil || *composite.Stack != 1 || composite.Name != "Error rate (%)" {
+			t.Fatalf("%q: unexpected composite %+v", test.formula, composite)
+		}
+
+		d = resourceGraph().TestResourceData()
+		if err := graphToState(d, &g); err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.formula, err)
+		}
+		prefix := string(graphCompositeAttr) + ".0."
+		for attr, expected := range map[schemaAttr]interface{}{
+			graphCompositeActiveAttr:    true,
+			graphCompositeAxisAttr:      "right",
+			graphCompositeFormulaAttr:   test.formula,
+			graphCompositeHumanNameAttr: "Error rate (%)",
+			graphCompositeStackAttr:     "1",
+		} {
+			if v := d.Get(prefix + string(attr)); v != expected {
+				t.Errorf("%q: %s: expected %v, got %v", test.formula, attr, expected, v)
+			}
+		}
+	}
+}
+
+func TestGraphAccessKeys(t *testing.T) {
+	elem := resourceGraph().Schema[string(graphAccessKeyAttr)].Elem.(*schema.Resource)
+	if k := elem.Schema[string(graphAccessKeyKeyAttr)]; !k.Sensitive || !k.Computed {
+		t.Fatalf("expected %s.%s to be sensitive and computed", graphAccessKeyAttr, graphAccessKeyKeyAttr)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceGraph().Schema, map[string]interface{}{
+		string(graphNameAttr): "Status page",
+		string(graphAccessKeyAttr): []interface{}{
+			map[string]interface{}{
+				string(graphAccessKeyNicknameAttr): "status page",
+				string(graphAccessKeyHeightAttr):   300,
+				string(graphAccessKeyWidthAttr):    600,
+				string(graphAccessKeyLegendAttr):   true,
+				string(graphAccessKeyLockZoomAttr): "1d",
+			},
+		},
+	})
+
+	g := newGraph()
+	if err := g.ParseConfig(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := api.GraphAccessKey{Active: true, Nickname: "status page", Height: 300, Width: 600, Legend: true, LockZoom: "1d"}
+	if len(g.AccessKeys) != 1 || g.AccessKeys[0] != expected {
+		t.Fatalf("expected %+v, got %+v", []api.GraphAccessKey{expected}, g.AccessKeys)
+	}
+
+	// The key generated by the API is kept in the statefile and sent back on
+	// updates so the shared graph keeps its URL.
+	g.AccessKeys[0].Key = "0123456789abcdef"
+	d = resourceGraph().TestResourceData()
+	if err := graphToState(d, &g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := d.Get(string(graphAccessKeyAttr) + ".0." + string(graphAccessKeyKeyAttr)); v != "0123456789abcdef" {
+		t.Fatalf("expected the key in the statefile, got %q", v)
+	}
+
+	g = newGraph()
+	if err := g.ParseConfig(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected.Key = "0123456789abcdef"
+	if len(g.AccessKeys) != 1 || g.AccessKeys[0] != expected {
+		t.Fatalf("expected %+v, got %+v", []api.GraphAccessKey{expected}, g.AccessKeys)
+	}
+}
+
+func TestGraphColorPalette(t *testing.T) {
+	metric := func(name, color string) map[string]interface{} {
+		return map[string]interface{}{
+			string(graphMetricActiveAttr):     true,
+			string(graphMetricAxisAttr):       "left",
+			string(graphMetricCheckAttr):      "/check/1",
+			string(graphMetricColorAttr):      color,
+			string(graphMetricNameAttr):       name,
+			string(graphMetricMetricTypeAttr): "numeric",
+		}
+	}
+	config := func(palette string) map[string]interface{} {
+		return map[string]interface{}{
+			string(graphNameAttr):         "Palette",
+			string(graphColorPaletteAttr): palette,
+			string(graphMetricAttr): []interface{}{
+				metric("average", ""),
+				metric("maximum", "#4E79A7"),
+				metric("minimum", ""),
+			},
+			string(graphMetricClusterAttr): []interface{}{
+				map[string]interface{}{
+					string(graphMetricClusterHumanNameAttr): "cluster",
+					string(graphMetricClusterQueryAttr):     "/metric_cluster/1",
+				},
+			},
+			string(graphCompositeAttr): []interface{}{
+				map[string]interface{}{
+					string(graphCompositeHumanNameAttr): "spread",
+					string(graphCompositeFormulaAttr):   "=B-C",
+				},
+			},
+		}
+	}
+
+	g := newGraph()
+	if err := g.ParseConfig(schema.TestResourceDataRaw(t, resourceGraph().Schema, config("tableau10"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first palette color is taken by the explicit color, any case.
+	got := []string{*g.Datapoints[0].Color, *g.Datapoints[1].Color, *g.Datapoints[2].Color, *g.MetricClusters[0].Color, g.Composites[0].Color}
+	expected := []string{"#f28e2b", "#4E79A7", "#e15759", "#76b7b2", "#59a14f"}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// Without a palette the metric cluster, which aggregates, needs a color.
+	g = newGraph()
+	err := g.ParseConfig(schema.TestResourceDataRaw(t, resourceGraph().Schema, config("")))
+	if err == nil || !strings.Contains(err.Error(), "color is a required attribute") {
+		t.Fatalf("expected the metric cluster to require a color, got %v", err)
+	}
+
+	noClusters := config("")
+	delete(noClusters, string(graphMetricClusterAttr))
+	g = newGraph()
+	if err := g.ParseConfig(schema.TestResourceDataRaw(t, resourceGraph().Schema, noClusters)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *g.Datapoints[0].Color != "" || g.Composites[0].Color != "" {
+		t.Errorf("expected no colors to be assigned without a palette, got %q and %q", *g.Datapoints[0].Color, g.Composites[0].Color)
+	}
+
+	// Colors backfilled by the API are kept rather than diffed against an
+	// unset color.
+	graphSchema := resourceGraph().Schema
+	for block, attr := range map[schemaAttr]schemaAttr{
+		graphMetricAttr:        graphMetricColorAttr,
+		graphMetricClusterAttr: graphMetricClusterColorAttr,
+		graphCompositeAttr:     graphCompositeColorAttr,
+	} {
+		if !graphSchema[string(block)].Elem.(*schema.Resource).Schema[string(attr)].Computed {
+			t.Errorf("%s.%s: expected the color to be computed", block, attr)
+		}
+	}
+}
+
+func TestGraphUpdateTags(t *testing.T) {
+	var methods []string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		if r.Method != http.MethodPut || r.URL.Path != "/graph/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("unable to decode the request body: %v", err)
+		}
+
+		// The API answers with the graph as stored.
+		graph := map[string]interface{}{
+			"_cid":              "/graph/abc",
+			"_last_modified":    1600000100,
+			"_last_modified_by": "/user/2",
+		}
+		for k, v := range body {
+			graph[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(graph)
+	}))
+	defer srv.Close()
+
+	client, err := api.NewAPI(&api.Config{URL: srv.URL, TokenKey: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceGraph().Schema, map[string]interface{}{
+		string(graphNameAttr): "Latency",
+		string(graphMetricAttr): []interface{}{
+			map[string]interface{}{
+				string(graphMetricActiveAttr):     true,
+				string(graphMetricAxisAttr):       "left",
+				string(graphMetricCheckAttr):      "/check/1",
+				string(graphMetricNameAttr):       "duration",
+				string(graphMetricMetricTypeAttr): "numeric",
+			},
+		},
+		string(graphTagsAttr): []interface{}{"team:ops"},
+	})
+	d.SetId("/graph/abc")
+
+	if err := graphUpdateTags(d, &providerContext{client: client}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(methods, ",") != "PUT /graph/abc" {
+		t.Fatalf("expected a single PUT and no other request, got %q", methods)
+	}
+
+	// A PUT replaces the whole graph, the datapoints must be sent along with
+	// the tags.
+	if body["title"] != "Latency" {
+		t.Errorf("expected the title to be sent, got %v", body["title"])
+	}
+	if datapoints, _ := body["datapoints"].([]interface{}); len(datapoints) != 1 {
+		t.Errorf("expected the datapoint to be sent, got %v", body["datapoints"])
+	}
+	if tags, _ := body["tags"].([]interface{}); len(tags) != 1 || tags[0] != "team:ops" {
+		t.Errorf("expected the tags to be sent, got %v", body["tags"])
+	}
+
+	if v := d.Get(string(graphMetricAttr) + ".0." + string(graphMetricNameAttr)); v != "duration" {
+		t.Errorf("expected the datapoint in the statefile, got %q", v)
+	}
+	if tags := d.Get(string(graphTagsAttr)).(*schema.Set); tags.Len() != 1 || !tags.Contains("team:ops") {
+		t.Errorf("expected the tags in the statefile, got %v", tags.List())
+	}
+	if v := d.Get(string(graphOutLastModifiedByAttr)); v != "/user/2" {
+		t.Errorf("expected the graph returned by the PUT in the statefile, got %s=%q", graphOutLastModifiedByAttr, v)
+	}
+}
+
+func TestValidateGraphFormula(t *testing.T) {
+	validate := validateGraphFormula(graphMetricFormulaAttr)
+
+	tests := []struct {
+		formula  string
+		warnings int
+		fail     bool
+	}{
+		{formula: "=VAL"},
+		{formula: "=VAL*8"},
+		{formula: "VAL / 1000"},
+		{formula: "=round(VAL,2)"},
+		{formula: "=round(VAL / 1024 ^ 2, 1)"},
+		{formula: "=-(A + B) % 3"},
+		{formula: "=max(A, B, 0)"},
+		{formula: "=1.5e3"},
+		{formula: "=.5*VAL"},
+		{formula: "=median(VAL)", warnings: 1},
+		{formula: "=", fail: true},
+		{formula: "=VAL*", fail: true},
+		{formula: "=(VAL", fail: true},
+		{formula: "=VAL)", fail: true},
+		{formula: "=round(VAL,2", fail: true},
+		{formula: "=round(VAL,2,3)", fail: true},
+		{formula: "=pow(VAL)", fail: true},
+		{formula: "=max()", fail: true},
+		{formula: "=val*8", fail: true},
+		{formula: "=VAL VAL", fail: true},
+		{formula: "=VAL,2", fail: true},
+		{formula: "=1e", fail: true},
+		{formula: "=VAL & 1", fail: true},
+	}
+
+	for _, test := range tests {
+		warnings, errs := validate(test.formula, string(graphMetricFormulaAttr))
+		if test.fail && len(errs) == 0 {
+			t.Errorf("%q: expected an error", test.formula)
+		}
+		if !test.fail && len(errs) > 0 {
+			t.Errorf("%q: unexpected error: %v", test.formula, errs)
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("%q: expected %d warning(s), got %v", test.formula, test.warnings, warnings)
+		}
+	}
+}
+
 func testAccCheckDestroyCirconusGraph(s *terraform.State) error {
 	ctxt := testAccProvider.Meta().(*providerContext)
 
@@ -260,3 +658,181 @@ EOF
   tags = "${var.test_tags}"
 }
 `
+
+func TestGraphDatapointsRemoved(t *testing.T) {
+	metric := func(check, name, color string) interface{} {
+		return map[string]interface{}{
+			string(graphMetricCheckAttr): check,
+			string(graphMetricNameAttr):  name,
+			string(graphMetricColorAttr): color,
+		}
+	}
+
+	oldList := []interface{}{
+		metric("/check/1", "average", "#4a00e3"),
+		metric("/check/1", "maximum", "#4a00e3"),
+		metric("/check/2", "average", "#ff0000"),
+		map[string]interface{}{string(graphMetricCAQLAttr): "find('cpu') | stats:mean()"},
+	}
+	newList := []interface{}{
+		metric("/check/2", "average", "#00ff00"),
+		metric("/check/1", "average", "#4a00e3"),
+	}
+
+	got := graphDatapointsRemoved(graphMetricAttr, oldList, newList)
+	expected := []string{"metric /check/1`maximum", "metric caql find('cpu') | stats:mean()"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if got := graphDatapointsRemoved(graphMetricAttr, newList, oldList); len(got) != 0 {
+		t.Errorf("expected no datapoints to be removed, got %q", got)
+	}
+
+	composite := func(formula string) interface{} {
+		return map[string]interface{}{string(graphCompositeFormulaAttr): formula}
+	}
+	got = graphDatapointsRemoved(graphCompositeAttr, []interface{}{composite("=A/B"), composite("=A+B")}, []interface{}{composite("=A+B")})
+	if len(got) != 1 || got[0] != "composite =A/B" {
+		t.Errorf("expected %q, got %q", []string{"composite =A/B"}, got)
+	}
+}
+
+func TestGraphReferences(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/dashboard"):
+			dash := api.Dashboard{CID: "/dashboard/10", Title: "NOC"}
+			dash.Widgets = []api.DashboardWidget{
+				{Type: "graph", Settings: api.DashboardWidgetSettings{GraphUUID: "abc"}},
+			}
+			other := api.Dashboard{CID: "/dashboard/11", Title: "Other"}
+			other.Widgets = []api.DashboardWidget{{Type: "html"}}
+			_ = json.NewEncoder(w).Encode([]api.Dashboard{dash, other})
+		case strings.HasPrefix(r.URL.Path, "/worksheet"):
+			_ = json.NewEncoder(w).Encode([]api.Worksheet{
+				{CID: "/worksheet/20", Title: "Capacity", Graphs: []api.WorksheetGraph{{GraphCID: "/graph/abc"}}},
+				{CID: "/worksheet/21", Title: "Empty", Graphs: []api.WorksheetGraph{}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	refs, err := graphReferences(ctxt, "/graph/abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{`dashboard "NOC" (/dashboard/10)`, `worksheet "Capacity" (/worksheet/20)`}
+	if strings.Join(refs, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, refs)
+	}
+
+	if diags := graphRemovedDatapointsDiags(ctxt, "/graph/abc", nil); len(diags) != 0 {
+		t.Errorf("expected no warning without removed datapoints, got %v", diags)
+	}
+
+	diags := graphRemovedDatapointsDiags(ctxt, "/graph/abc", []string{"metric /check/1`maximum"})
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail, "/worksheet/20") {
+		t.Errorf("expected a warning listing the references, got %v", diags)
+	}
+
+	if diags := graphRemovedDatapointsDiags(ctxt, "/graph/def", []string{"metric /check/1`maximum"}); len(diags) != 0 {
+		t.Errorf("expected no warning for an unreferenced graph, got %v", diags)
+	}
+}
+
+func TestGraphStreamCounts(t *testing.T) {
+	renamed := "service:api`requests"
+	current := "service:api`latency"
+	searches := make(map[string]int)
+	var failLookups bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failLookups && r.URL.Path != "/graph/abc" {
+			http.Error(w, `{"code":400,"message":"search failed"}`, http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/graph/abc":
+			_ = json.NewEncoder(w).Encode(api.Graph{
+				CID:   "/graph/abc",
+				Title: "API",
+				Datapoints: []api.GraphDatapoint{
+					{Axis: "l", Derive: "gauge", MetricType: "numeric", Search: &current},
+					{Axis: "l", Derive: "gauge", MetricType: "numeric", Search: &renamed},
+					{Axis: "r", Derive: "gauge", MetricType: "numeric", Search: &current},
+					{Axis: "l", Derive: "gauge", MetricType: "numeric", CheckID: 1, MetricName: "duration"},
+				},
+				MetricClusters: []api.GraphMetricCluster{
+					{Axis: "l", MetricCluster: "/metric_cluster/5", Name: "latencies"},
+				},
+			})
+		case "/metric":
+			search := r.URL.Query().Get("search")
+			searches[search]++
+			metrics := make([]api.Metric, 0)
+			if search == current {
+				metrics = append(metrics, api.Metric{MetricName: "latency"}, api.Metric{MetricName: "latency"})
+			}
+			_ = json.NewEncoder(w).Encode(metrics)
+		case "/metric_cluster/5":
+			if r.URL.Query().Get("extra") != "_matching_metrics" {
+				http.Error(w, "expected the matching metrics extra", http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(api.MetricCluster{CID: "/metric_cluster/5", MatchingMetrics: []string{"a", "b", "c"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d := resourceGraph().TestResourceData()
+	d.SetId("/graph/abc")
+	if err := graphRead(d, &providerContext{client: client}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{
+		"metric.0.stream_count":         2,
+		"metric.1.stream_count":         0,
+		"metric.2.stream_count":         2,
+		"metric.3.stream_count":         0,
+		"metric_cluster.0.stream_count": 3,
+	}
+	for attr, n := range expected {
+		if v := d.Get(attr).(int); v != n {
+			t.Errorf("%s: expected %d, got %d", attr, n, v)
+		}
+	}
+
+	// Searches shared by several datapoints are only looked up once.
+	if searches[current] != 1 || searches[renamed] != 1 {
+		t.Errorf("expected each search to be looked up once, got %v", searches)
+	}
+
+	// Failed lookups neither fail the refresh nor change the counts.
+	failLookups = true
+	if err := graphRead(d, &providerContext{client: client}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for attr, n := range expected {
+		if v := d.Get(attr).(int); v != n {
+			t.Errorf("%s: expected %d to be kept, got %d", attr, n, v)
+		}
+	}
+}
@@ -17,7 +17,9 @@ const (
 	metricActiveAttr = "active"
 	metricIDAttr     = "id"
 	metricNameAttr   = "name"
+	metricTagsAttr   = "tags"
 	metricTypeAttr   = "type"
+	metricUnitAttr   = "unit"
 
 	// CheckBundle.Metric.Status can be one of these values.
 	metricStatusActive    = "active"
@@ -27,7 +29,9 @@ const (
 var metricDescriptions = attrDescrs{
 	metricActiveAttr: "Enables or disables the metric",
 	metricNameAttr:   "Name of the metric",
+	metricTagsAttr:   "Tags of the metric, in addition to the tags of its check",
 	metricTypeAttr:   "Type of metric (e.g. numeric, histogram, text)",
+	metricUnitAttr:   "The unit of the metric's values (e.g. seconds, bytes), used as the axis unit of graphs",
 }
 
 func resourceMetric() *schema.Resource {
@@ -52,11 +56,17 @@ func resourceMetric() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validateRegexp(metricNameAttr, `[\S]+`),
 			},
+			metricTagsAttr: tagMakeConfigSchema(metricTagsAttr),
 			metricTypeAttr: {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validateStringIn(metricTypeAttr, validMetricTypes),
 			},
+			metricUnitAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp(metricUnitAttr, `.+`),
+			},
 		}),
 	}
 }
@@ -5,12 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/timeutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -18,8 +23,11 @@ import (
 const (
 	// circonus_rule_set.* resource attribute names.
 	ruleSetCheckAttr         = "check"
+	ruleSetChunkAttr         = "chunk"
+	ruleSetDuplicatesAttr    = "duplicates"
 	ruleSetNameAttr          = "name"
 	ruleSetIfAttr            = "if"
+	ruleSetIgnoreEmptyNotify = "ignore_empty_notify"
 	ruleSetLinkAttr          = "link"
 	ruleSetMetricTypeAttr    = "metric_type"
 	ruleSetNotesAttr         = "notes"
@@ -58,9 +66,41 @@ const (
 	ruleSetAtLeastAttr = "atleast"
 
 	// out attributes.
-	ruleSetIDAttr = "rule_set_id"
+	ruleSetIDAttr             = "rule_set_id"
+	ruleSetChunkIDsAttr       = "chunk_ids"
+	ruleSetCheckIDAttr        = "check_id"
+	ruleSetCheckUUIDAttr      = "check_uuid"
+	ruleSetCreatedAttr        = "created"
+	ruleSetCreatedByAttr      = "created_by"
+	ruleSetHostAttr           = "host"
+	ruleSetLookupKeyAttr      = "lookup_key"
+	ruleSetLastModifiedAttr   = "last_modified"
+	ruleSetLastModifiedByAttr = "last_modified_by"
+	ruleSetRoutingAttr        = "routing"
 )
 
+// ruleSetSearchCheckFilter is the API filter selecting the rule sets of a
+// check.
+const ruleSetSearchCheckFilter = "f_check"
+
+// The duplicates modes of a rule set.
+const (
+	ruleSetDuplicatesAllow = "allow"
+	ruleSetDuplicatesWarn  = "warn"
+	ruleSetDuplicatesError = "error"
+)
+
+var validRuleSetDuplicates = validStringValues{ruleSetDuplicatesAllow, ruleSetDuplicatesWarn, ruleSetDuplicatesError}
+
+// apiRuleSetMaxRules is the number of rules the API accepts in a single rule
+// set.  Larger lists of rules must be split across several rule sets, which
+// the chunk attribute does.
+const apiRuleSetMaxRules = 64
+
+// ruleSetNotifyNamePrefix is the prefix of a notify entry that references a
+// contact group by name rather than by CID (e.g. `name:Platform OnCall`).
+const ruleSetNotifyNamePrefix = "name:"
+
 const (
 	// Different criteria that an api.RuleSetRule can return.
 	apiRuleSetAbsent      = "on absence"       // ruleSetAbsentAttr
@@ -77,19 +117,32 @@ const (
 
 var ruleSetDescriptions = attrDescrs{
 	// circonus_rule_set.* resource attribute names
-	ruleSetCheckAttr:         "The CID of the check that contains the metric for this rule set",
-	ruleSetNameAttr:          "The name of this ruleset, if omitted will default to the metric_name (or pattern) and filter",
-	ruleSetIfAttr:            "A rule to execute for this rule set",
-	ruleSetLinkAttr:          "URL to show users when this rule set is active (e.g. wiki)",
-	ruleSetMetricTypeAttr:    "The type of data flowing through the specified metric stream",
-	ruleSetNotesAttr:         "Notes describing this rule set",
-	ruleSetUserJSONAttr:      "Opaque data that can be supplied with the result and appears in webhooks when alerts go off",
-	ruleSetParentAttr:        "Parent CID that must be healthy for this rule set to be active",
-	ruleSetMetricNameAttr:    "The name of the metric stream within a check to register the rule set with",
-	ruleSetMetricPatternAttr: "The pattern match (regex) of the metric stream within a check to register the rule set with",
-	ruleSetMetricFilterAttr:  "The tag filter a pattern match ruleset will user",
-	ruleSetTagsAttr:          "Tags associated with this rule set",
-	ruleSetIDAttr:            "out",
+	ruleSetCheckAttr:          "The CID of the check that contains the metric for this rule set",
+	ruleSetChunkAttr:          "Split rules beyond the number the API accepts in a rule set into additional rule sets",
+	ruleSetChunkIDsAttr:       "The CIDs of the additional rule sets holding the rules beyond the first chunk",
+	ruleSetDuplicatesAttr:     "What to do about other rule sets registered with the same check and metric: allow them, warn about them or fail the plan (allow, warn or error)",
+	ruleSetNameAttr:           "The name of this ruleset, if omitted will default to the metric_name (or pattern) and filter",
+	ruleSetIfAttr:             "A rule to execute for this rule set",
+	ruleSetIgnoreEmptyNotify:  "Do not warn about rules with a nonzero severity that notify no contact groups",
+	ruleSetLinkAttr:           "URL to show users when this rule set is active (e.g. wiki)",
+	ruleSetMetricTypeAttr:     "The type of data flowing through the specified metric stream",
+	ruleSetNotesAttr:          "Notes describing this rule set",
+	ruleSetUserJSONAttr:       "Opaque data that can be supplied with the result and appears in webhooks when alerts go off",
+	ruleSetParentAttr:         "Parent CID that must be healthy for this rule set to be active",
+	ruleSetMetricNameAttr:     "The name of the metric stream within a check to register the rule set with",
+	ruleSetMetricPatternAttr:  "The pattern match (regex) of the metric stream within a check to register the rule set with",
+	ruleSetMetricFilterAttr:   "The tag filter a pattern match ruleset will user",
+	ruleSetTagsAttr:           "Tags associated with this rule set",
+	ruleSetIDAttr:             "out",
+	ruleSetCheckIDAttr:        "The numeric ID of the check the rule set is registered with",
+	ruleSetCheckUUIDAttr:      "The UUID of the check the rule set is registered with",
+	ruleSetCreatedAttr:        "When the rule set was created, in epoch seconds, when reported by the API",
+	ruleSetCreatedByAttr:      "The CID of the user who created the rule set, when reported by the API",
+	ruleSetHostAttr:           "The host (check target) the API associates with the rule set",
+	ruleSetLookupKeyAttr:      "The lookup key the API associates with the rule set",
+	ruleSetLastModifiedAttr:   "When the rule set was last modified, in epoch seconds, when reported by the API",
+	ruleSetLastModifiedByAttr: "The CID of the user who last modified the rule set, when reported by the API",
+	ruleSetRoutingAttr:        "The names of the contact groups notified at each severity the rules use",
 }
 
 var ruleSetIfDescriptions = attrDescrs{
@@ -147,12 +200,60 @@ func resourceRuleSet() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: importStatePassthroughUnescape,
 		},
+		CustomizeDiff: ruleSetCustomizeDiff,
 		Schema: convertToHelperSchema(ruleSetDescriptions, map[schemaAttr]*schema.Schema{
 			// _cid
 			ruleSetIDAttr: {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			ruleSetChunkAttr: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			ruleSetChunkIDsAttr: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			ruleSetCheckIDAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetCheckUUIDAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetCreatedAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			ruleSetCreatedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetHostAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetLookupKeyAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetLastModifiedAttr: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			ruleSetLastModifiedByAttr: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			ruleSetRoutingAttr: {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			// check
 			ruleSetCheckAttr: {
 				Type:         schema.TypeString,
@@ -179,10 +280,11 @@ func resourceRuleSet() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: convertToHelperSchema(ruleSetIfThenDescriptions, map[schemaAttr]*schema.Schema{
 									ruleSetAfterAttr: {
-										Type:         schema.TypeString,
-										Optional:     true,
-										Default:      "0",
-										ValidateFunc: validateRegexp(ruleSetAfterAttr, "^[0-9]+$"),
+										Type:             schema.TypeString,
+										Optional:         true,
+										Default:          "0",
+										DiffSuppressFunc: suppressEquivalentMinutes,
+										ValidateFunc:     validateRegexp(ruleSetAfterAttr, "^[0-9]+$"),
 									},
 									ruleSetNotifyAttr: {
 										Type:     schema.TypeSet,
@@ -190,7 +292,7 @@ func resourceRuleSet() *schema.Resource {
 										MinItems: 0,
 										Elem: &schema.Schema{
 											Type:         schema.TypeString,
-											ValidateFunc: validateContactGroupCID(ruleSetNotifyAttr),
+											ValidateFunc: validateContactGroupRef(ruleSetNotifyAttr, ruleSetNotifyNamePrefix),
 										},
 									},
 									ruleSetSeverityAttr: {
@@ -212,9 +314,10 @@ func resourceRuleSet() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: convertToHelperSchema(ruleSetIfValueDescriptions, map[schemaAttr]*schema.Schema{
 									ruleSetAbsentAttr: {
-										Type:         schema.TypeString, // Applies to text or numeric metrics
-										Optional:     true,
-										ValidateFunc: validateRegexp(ruleSetAbsentAttr, "^[0-9]+$"),
+										Type:             schema.TypeString, // Applies to text or numeric metrics
+										Optional:         true,
+										DiffSuppressFunc: suppressEquivalentNumbers,
+										ValidateFunc:     validateRegexp(ruleSetAbsentAttr, "^[0-9]+$"),
 									},
 									ruleSetChangedAttr: {
 										Type:     schema.TypeString, // Applies to text or numeric metrics
@@ -236,9 +339,10 @@ func resourceRuleSet() *schema.Resource {
 										ValidateFunc: validateRegexp(ruleSetNotMatchAttr, `.+`),
 									},
 									ruleSetMinValueAttr: {
-										Type:         schema.TypeString, // Applies to numeric metrics only
-										Optional:     true,
-										ValidateFunc: validateRegexp(ruleSetMinValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
+										Type:             schema.TypeString, // Applies to numeric metrics only
+										Optional:         true,
+										DiffSuppressFunc: suppressEquivalentNumbers,
+										ValidateFunc:     validateRegexp(ruleSetMinValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
 									},
 									ruleSetNotContainAttr: {
 										Type:         schema.TypeString, // Applies to text metrics only
@@ -246,19 +350,22 @@ func resourceRuleSet() *schema.Resource {
 										ValidateFunc: validateRegexp(ruleSetNotContainAttr, `.+`),
 									},
 									ruleSetMaxValueAttr: {
-										Type:         schema.TypeString, // Applies to numeric metrics only
-										Optional:     true,
-										ValidateFunc: validateRegexp(ruleSetMaxValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
+										Type:             schema.TypeString, // Applies to numeric metrics only
+										Optional:         true,
+										DiffSuppressFunc: suppressEquivalentNumbers,
+										ValidateFunc:     validateRegexp(ruleSetMaxValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
 									},
 									ruleSetEqValueAttr: {
-										Type:         schema.TypeString, // Applies to numeric metrics only
-										Optional:     true,
-										ValidateFunc: validateRegexp(ruleSetEqValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
+										Type:             schema.TypeString, // Applies to numeric metrics only
+										Optional:         true,
+										DiffSuppressFunc: suppressEquivalentNumbers,
+										ValidateFunc:     validateRegexp(ruleSetEqValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
 									},
 									ruleSetNotEqValueAttr: {
-										Type:         schema.TypeString, // Applies to numeric metrics only
-										Optional:     true,
-										ValidateFunc: validateRegexp(ruleSetNotEqValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
+										Type:             schema.TypeString, // Applies to numeric metrics only
+										Optional:         true,
+										DiffSuppressFunc: suppressEquivalentNumbers,
+										ValidateFunc:     validateRegexp(ruleSetNotEqValueAttr, `.+`), // TODO(sean): improve this regexp to match int and float
 									},
 									// windowing
 									ruleSetOverAttr: {
@@ -294,6 +401,17 @@ func resourceRuleSet() *schema.Resource {
 					}),
 				},
 			},
+			ruleSetDuplicatesAttr: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      ruleSetDuplicatesAllow,
+				ValidateFunc: validateStringIn(ruleSetDuplicatesAttr, validRuleSetDuplicates),
+			},
+			ruleSetIgnoreEmptyNotify: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			// link
 			ruleSetLinkAttr: {
 				Type:         schema.TypeString,
@@ -402,11 +520,32 @@ func ruleSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}
 		return diag.FromErr(err)
 	}
 
-	if err := rs.Create(ctxt); err != nil {
+	if err := rs.ResolveContactGroups(ctxt); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(rs.CID)
+	// The link template is only applied at create time, the rendered link is
+	// stored and left alone afterwards.
+	if rs.Link == nil && ctxt.linkTemplate != "" {
+		link, err := rs.RenderLink(ctxt)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		rs.Link = &link
+	}
+
+	chunks := rs.Chunks(ruleSetChunkSize(d))
+	if err := chunks[0].Create(ctxt); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(chunks[0].CID)
+
+	chunkIDs, err := ruleSetSyncChunks(ctxt, chunks[1:], nil)
+	_ = d.Set(ruleSetChunkIDsAttr, chunkIDs)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	return ruleSetRead(ctx, d, meta)
 }
@@ -430,16 +569,27 @@ func ruleSetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}
 // ruleSetRead pulls data out of the RuleSet object and stores it into the
 // appropriate place in the statefile.
 func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(*providerContext).client
+	ctxt := meta.(*providerContext)
+	client := ctxt.client
 	var diags diag.Diagnostics
 
+	// Contact groups that were referenced by name are written back by name.
+	notifyRefs := make(map[string]string)
+	for _, ref := range ruleSetNotifyNames(d.Get(ruleSetIfAttr)) {
+		cid, err := ctxt.contactGroupCIDByName(strings.TrimPrefix(ref, ruleSetNotifyNamePrefix))
+		if err != nil {
+			log.Printf("[WARN] unable to resolve %s %q: %v", ruleSetNotifyAttr, ref, err)
+			continue
+		}
+		notifyRefs[cid] = ref
+	}
+
 	cid := d.Id()
 	var rs circonusRuleSet
-	crs, err := client.FetchRuleSet(api.CIDType(&cid))
+	audit, err := fetchObject(ctxt, config.RuleSetPrefix, cid, &rs.RuleSet)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	rs.RuleSet = *crs
 
 	if rs.CID == "" {
 		d.SetId("")
@@ -455,6 +605,25 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	if err = d.Set(ruleSetIDAttr, rs.CID); err != nil {
 		return diag.FromErr(err)
 	}
+
+	// The rules of the additional chunks follow those of the rule set, chunks
+	// removed outside of Terraform are dropped and recreated by the next apply.
+	chunkIDs := make([]string, 0)
+	for _, chunkCID := range interfaceList(d.Get(ruleSetChunkIDsAttr).([]interface{})).List() {
+		chunkCID := chunkCID
+		chunk, err := client.FetchRuleSet(api.CIDType(&chunkCID))
+		if err != nil {
+			if strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+				continue
+			}
+			return diag.FromErr(err)
+		}
+		rs.Rules = append(rs.Rules, chunk.Rules...)
+		chunkIDs = append(chunkIDs, chunkCID)
+	}
+	if err = d.Set(ruleSetChunkIDsAttr, chunkIDs); err != nil {
+		return diag.FromErr(err)
+	}
 	if err = d.Set(ruleSetNameAttr, rs.Name); err != nil {
 		return diag.FromErr(err)
 	}
@@ -468,15 +637,7 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 		switch rule.Criteria {
 		case apiRuleSetAbsent:
-			switch v := rule.Value.(type) {
-			case string:
-				valueAttrs[string(ruleSetAbsentAttr)] = v
-			case float64:
-				d, _ := time.ParseDuration(fmt.Sprintf("%fs", v))
-				valueAttrs[string(ruleSetAbsentAttr)] = fmt.Sprintf("%d", int(d.Seconds()))
-			default:
-				valueAttrs[string(ruleSetAbsentAttr)] = fmt.Sprintf("%v", v)
-			}
+			valueAttrs[string(ruleSetAbsentAttr)] = ruleSetAbsentToState(rule.Value)
 		case apiRuleSetChanged:
 			valueAttrs[string(ruleSetChangedAttr)] = "true"
 		case apiRuleSetContains:
@@ -484,13 +645,13 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 		case apiRuleSetMatch:
 			valueAttrs[string(ruleSetMatchAttr)] = rule.Value
 		case apiRuleSetMaxValue:
-			valueAttrs[string(ruleSetMaxValueAttr)] = rule.Value
+			valueAttrs[string(ruleSetMaxValueAttr)] = formatNumber(rule.Value)
 		case apiRuleSetMinValue:
-			valueAttrs[string(ruleSetMinValueAttr)] = rule.Value
+			valueAttrs[string(ruleSetMinValueAttr)] = formatNumber(rule.Value)
 		case apiRuleSetEqValue:
-			valueAttrs[string(ruleSetEqValueAttr)] = rule.Value
+			valueAttrs[string(ruleSetEqValueAttr)] = formatNumber(rule.Value)
 		case apiRuleSetNotEqValue:
-			valueAttrs[string(ruleSetNotEqValueAttr)] = rule.Value
+			valueAttrs[string(ruleSetNotEqValueAttr)] = formatNumber(rule.Value)
 		case apiRuleSetNotContains:
 			valueAttrs[string(ruleSetNotContainAttr)] = rule.Value
 		case apiRuleSetNotMatch:
@@ -504,12 +665,19 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 			return diags
 		}
 
-		thenAttrs[string(ruleSetAfterAttr)] = fmt.Sprintf("%d", 60*rule.Wait)
+		thenAttrs[string(ruleSetAfterAttr)] = timeutil.FormatSeconds(time.Duration(rule.Wait) * time.Minute)
 		thenAttrs[string(ruleSetSeverityAttr)] = int(rule.Severity)
 		if int(rule.Severity) > 0 {
 			if contactGroups, ok := rs.ContactGroups[uint8(rule.Severity)]; ok {
-				sort.Strings(contactGroups)
-				thenAttrs[string(ruleSetNotifyAttr)] = contactGroups
+				notify := make([]string, 0, len(contactGroups))
+				for _, cid := range contactGroups {
+					if ref, found := notifyRefs[cid]; found {
+						cid = ref
+					}
+					notify = append(notify, cid)
+				}
+				sort.Strings(notify)
+				thenAttrs[string(ruleSetNotifyAttr)] = notify
 			} else {
 				thenAttrs[string(ruleSetNotifyAttr)] = make([]string, 0)
 			}
@@ -574,6 +742,53 @@ func ruleSetRead(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 	_ = d.Set(ruleSetParentAttr, indirect(rs.Parent))
 
+	_ = d.Set(ruleSetCreatedAttr, audit.Created)
+	_ = d.Set(ruleSetCreatedByAttr, audit.CreatedBy)
+	_ = d.Set(ruleSetHostAttr, rs.Host)
+	_ = d.Set(ruleSetLookupKeyAttr, indirect(rs.LookupKey))
+	_ = d.Set(ruleSetLastModifiedAttr, audit.LastModified)
+	_ = d.Set(ruleSetLastModifiedByAttr, audit.LastModifiedBy)
+	if checkID, err := cidID(rs.CheckCID); err == nil {
+		_ = d.Set(ruleSetCheckIDAttr, checkID)
+	}
+
+	// The check UUID is not part of the rule set, it only needs to be looked up
+	// once since changing the check replaces the rule set.
+	if d.Get(ruleSetCheckUUIDAttr).(string) == "" && rs.CheckCID != "" {
+		checkCID := rs.CheckCID
+		if chk, err := client.FetchCheck(api.CIDType(&checkCID)); err != nil {
+			log.Printf("[WARN] unable to look up the UUID of check %s for rule set %s: %v", rs.CheckCID, rs.CID, err)
+		} else {
+			_ = d.Set(ruleSetCheckUUIDAttr, chk.CheckUUID)
+		}
+	}
+
+	if err = d.Set(ruleSetRoutingAttr, rs.Routing(ctxt)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !d.Get(ruleSetIgnoreEmptyNotify).(bool) {
+		diags = append(diags, rs.EmptyNotifyDiags()...)
+	}
+
+	if d.Get(ruleSetDuplicatesAttr).(string) != ruleSetDuplicatesAllow {
+		dups, err := ruleSetFindDuplicates(ctxt, &rs.RuleSet, ruleSetOwnCIDs(d.Id(), chunkIDs))
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to search for duplicate rule sets",
+				Detail:   err.Error(),
+			})
+		} else if len(dups) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Duplicate rule sets",
+				Detail: fmt.Sprintf("Rule set %s is not the only rule set registered with check %s and metric %q: %s.  Each of them alerts, doubling the pages.",
+					rs.CID, rs.CheckCID, ruleSetMetric(&rs.RuleSet), strings.Join(dups, ", ")),
+			})
+		}
+	}
+
 	// if err := d.Set(ruleSetTagsAttr, tagsToState(apiToTags(rs.Tags))); err != nil {
 	// 	return fmt.Errorf("Unable to store rule set %q attribute: %w", ruleSetTagsAttr, err)
 	// }
@@ -589,10 +804,21 @@ func ruleSetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}
 		return diag.FromErr(err)
 	}
 
-	rs.CID = d.Id()
+	if err := rs.ResolveContactGroups(ctxt); err != nil {
+		return diag.FromErr(err)
+	}
+
+	chunks := rs.Chunks(ruleSetChunkSize(d))
+	chunks[0].CID = d.Id()
+	if err := chunks[0].Update(ctxt); err != nil {
+		return diag.FromErr(err)
+	}
 
-	if err := rs.Update(ctxt); err != nil {
-		diag.FromErr(err)
+	old, _ := d.GetChange(ruleSetChunkIDsAttr)
+	chunkIDs, err := ruleSetSyncChunks(ctxt, chunks[1:], interfaceList(old.([]interface{})).List())
+	_ = d.Set(ruleSetChunkIDsAttr, chunkIDs)
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
 	return ruleSetRead(ctx, d, meta)
@@ -602,6 +828,10 @@ func ruleSetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}
 	ctxt := meta.(*providerContext)
 	var diags diag.Diagnostics
 
+	if _, err := ruleSetSyncChunks(ctxt, nil, interfaceList(d.Get(ruleSetChunkIDsAttr).([]interface{})).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	cid := d.Id()
 	if _, err := ctxt.client.DeleteRuleSetByCID(api.CIDType(&cid)); err != nil {
 		return diag.FromErr(err)
@@ -617,6 +847,23 @@ type circonusRuleSet struct {
 	api.RuleSet
 }
 
+// ruleSetLinkTemplateVars are the placeholders available to the provider's
+// link_template, e.g. https://wiki.example.org/runbooks/{check_name}/{metric}.
+var ruleSetLinkTemplateVars = []string{"check_id", "check_name", "metric", "name"}
+
+var ruleSetLinkTemplatePlaceholderRE = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// renderLinkTemplate replaces each {placeholder} in tmpl with its path escaped
+// value from vars.  Unknown placeholders are left untouched.
+func renderLinkTemplate(tmpl string, vars map[string]string) string {
+	return ruleSetLinkTemplatePlaceholderRE.ReplaceAllStringFunc(tmpl, func(m string) string {
+		if v, ok := vars[m[1:len(m)-1]]; ok {
+			return url.PathEscape(v)
+		}
+		return m
+	})
+}
+
 func newRuleSet() circonusRuleSet {
 	rs := circonusRuleSet{
 		RuleSet: *api.NewRuleSet(),
@@ -709,11 +956,11 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 					if v, found := thenAttrs[ruleSetAfterAttr]; found {
 						s := v.(string)
 						if s != "" {
-							d, err := time.ParseDuration(v.(string) + "s")
+							d, err := timeutil.ParseDuration(v.(string))
 							if err != nil {
 								return fmt.Errorf("unable to parse %q duration %q: %w", ruleSetAfterAttr, v.(string), err)
 							}
-							rule.Wait = uint(d.Minutes())
+							rule.Wait = timeutil.Minutes(d)
 						}
 					}
 
@@ -755,7 +1002,7 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 					if v, found := valueAttrs[ruleSetAbsentAttr]; found && v.(string) != "" {
 						s := v.(string)
 						if s != "" {
-							d, _ := time.ParseDuration(s + "s")
+							d, _ := timeutil.ParseDuration(s)
 							rule.Criteria = apiRuleSetAbsent
 							rule.Value = d.Seconds()
 						}
@@ -793,7 +1040,7 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 					if v, found := valueAttrs[ruleSetAbsentAttr]; found && v.(string) != "" {
 						s := v.(string)
 						if s != "" {
-							d, _ := time.ParseDuration(s + "s")
+							d, _ := timeutil.ParseDuration(s)
 							rule.Criteria = apiRuleSetAbsent
 							rule.Value = d.Seconds()
 						}
@@ -886,6 +1133,187 @@ func (rs *circonusRuleSet) ParseConfig(d *schema.ResourceData) error {
 	return nil
 }
 
+// ResolveContactGroups replaces the contact groups referenced by name with
+// their CIDs.
+func (rs *circonusRuleSet) ResolveContactGroups(ctxt *providerContext) error {
+	for sev, contactGroups := range rs.ContactGroups {
+		resolved := make([]string, 0, len(contactGroups))
+		for _, contactGroup := range contactGroups {
+			if strings.HasPrefix(contactGroup, ruleSetNotifyNamePrefix) {
+				cid, err := ctxt.contactGroupCIDByName(strings.TrimPrefix(contactGroup, ruleSetNotifyNamePrefix))
+				if err != nil {
+					return fmt.Errorf("unable to resolve %s %q: %w", ruleSetNotifyAttr, contactGroup, err)
+				}
+				contactGroup = cid
+			}
+
+			if !stringInSlice(contactGroup, resolved) {
+				resolved = append(resolved, contactGroup)
+			}
+		}
+		rs.ContactGroups[sev] = resolved
+	}
+
+	return nil
+}
+
+// ruleSetNotifyNames returns the notify entries of an `if` list that reference
+// a contact group by name.
+func ruleSetNotifyNames(ifListRaw interface{}) []string {
+	names := make([]string, 0)
+
+	ifList, ok := ifListRaw.([]interface{})
+	if !ok {
+		return names
+	}
+
+	for _, ifRaw := range ifList {
+		ifAttrs, ok := ifRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		thenList, ok := ifAttrs[string(ruleSetThenAttr)].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, thenRaw := range thenList {
+			thenAttrs, ok := thenRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			notify, ok := thenAttrs[string(ruleSetNotifyAttr)].(*schema.Set)
+			if !ok {
+				continue
+			}
+
+			for _, v := range notify.List() {
+				if s, ok := v.(string); ok && strings.HasPrefix(s, ruleSetNotifyNamePrefix) && !stringInSlice(s, names) {
+					names = append(names, s)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// ruleSetCustomizeDiff verifies during plan that the contact groups referenced
+// by name exist.
+func ruleSetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := ruleSetCustomizeDiffChunks(d); err != nil {
+		return err
+	}
+
+	ctxt, ok := meta.(*providerContext)
+	if !ok || ctxt == nil {
+		return nil
+	}
+
+	for _, ref := range ruleSetNotifyNames(d.Get(ruleSetIfAttr)) {
+		if _, err := ctxt.contactGroupCIDByName(strings.TrimPrefix(ref, ruleSetNotifyNamePrefix)); err != nil {
+			return fmt.Errorf("unable to resolve %s %q: %w", ruleSetNotifyAttr, ref, err)
+		}
+	}
+
+	return ruleSetCustomizeDiffDuplicates(d, ctxt)
+}
+
+// ruleSetCustomizeDiffDuplicates fails the plan of a rule set in error
+// duplicates mode when other rule sets are registered with the same check and
+// metric.  Only new rule sets and changes of the check, metric or mode are
+// searched for, duplicates created later are warned about on refresh.
+func ruleSetCustomizeDiffDuplicates(d *schema.ResourceDiff, ctxt *providerContext) error {
+	if d.Get(ruleSetDuplicatesAttr).(string) != ruleSetDuplicatesError {
+		return nil
+	}
+
+	attrs := []string{ruleSetCheckAttr, ruleSetMetricNameAttr, ruleSetMetricPatternAttr, ruleSetMetricFilterAttr}
+	changed := d.Id() == "" || d.HasChange(ruleSetDuplicatesAttr)
+	for _, attr := range attrs {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+		changed = changed || d.HasChange(attr)
+	}
+	if !changed {
+		return nil
+	}
+
+	rs := &api.RuleSet{
+		CheckCID:      d.Get(ruleSetCheckAttr).(string),
+		MetricName:    d.Get(ruleSetMetricNameAttr).(string),
+		MetricPattern: d.Get(ruleSetMetricPatternAttr).(string),
+		Filter:        d.Get(ruleSetMetricFilterAttr).(string),
+	}
+
+	// The chunk_ids may be planned as unknown, the chunks of the rule set are
+	// those of the prior state.
+	chunkIDs, _ := d.GetChange(ruleSetChunkIDsAttr)
+	dups, err := ruleSetFindDuplicates(ctxt, rs, ruleSetOwnCIDs(d.Id(), interfaceList(chunkIDs.([]interface{})).List()))
+	if err != nil {
+		return fmt.Errorf("unable to search for duplicate rule sets: %w", err)
+	}
+
+	if len(dups) > 0 {
+		return fmt.Errorf("rule sets registered with check %s and metric %q already exist: %s; each of them alerts, doubling the pages.  Import or remove them, or set %s = %q",
+			rs.CheckCID, ruleSetMetric(rs), strings.Join(dups, ", "), ruleSetDuplicatesAttr, ruleSetDuplicatesWarn)
+	}
+
+	return nil
+}
+
+// ruleSetMetric returns the metric name or pattern a rule set is registered
+// with.
+func ruleSetMetric(rs *api.RuleSet) string {
+	if rs.MetricName != "" {
+		return rs.MetricName
+	}
+
+	return rs.MetricPattern
+}
+
+// ruleSetOwnCIDs returns the CIDs of a rule set and its chunks.
+func ruleSetOwnCIDs(cid string, chunkIDs []string) map[string]bool {
+	own := make(map[string]bool, len(chunkIDs)+1)
+	if cid != "" {
+		own[cid] = true
+	}
+	for _, chunkCID := range chunkIDs {
+		own[chunkCID] = true
+	}
+
+	return own
+}
+
+// ruleSetFindDuplicates returns the sorted CIDs of the rule sets, other than
+// those in own, registered with the check, metric name or pattern and filter
+// of rs.  The API filters on the check, the rest is matched exactly.
+func ruleSetFindDuplicates(ctxt *providerContext, rs *api.RuleSet, own map[string]bool) ([]string, error) {
+	ruleSets, err := ctxt.client.SearchRuleSets(nil, &api.SearchFilterType{
+		ruleSetSearchCheckFilter: []string{rs.CheckCID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dups := make([]string, 0)
+	for _, other := range *ruleSets {
+		if own[other.CID] || other.CheckCID != rs.CheckCID {
+			continue
+		}
+		if other.MetricName != rs.MetricName || other.MetricPattern != rs.MetricPattern || other.Filter != rs.Filter {
+			continue
+		}
+		dups = append(dups, other.CID)
+	}
+	sort.Strings(dups)
+
+	return dups, nil
+}
+
 func (rs *circonusRuleSet) Create(ctxt *providerContext) error {
 	crs, err := ctxt.client.CreateRuleSet(&rs.RuleSet)
 	if err != nil {
@@ -897,6 +1325,215 @@ func (rs *circonusRuleSet) Create(ctxt *providerContext) error {
 	return nil
 }
 
+// RenderLink renders the provider's link_template for the rule set.  The check
+// bundle is only fetched when the template references {check_name}.
+func (rs *circonusRuleSet) RenderLink(ctxt *providerContext) (string, error) {
+	metric := rs.MetricName
+	if metric == "" {
+		metric = rs.MetricPattern
+	}
+
+	checkID, err := cidID(rs.CheckCID)
+	if err != nil {
+		return "", err
+	}
+
+	vars := map[string]string{
+		"check_id": checkID,
+		"metric":   metric,
+		"name":     rs.Name,
+	}
+
+	if strings.Contains(ctxt.linkTemplate, "{check_name}") {
+		checkCID := rs.CheckCID
+		chk, err := ctxt.client.FetchCheck(api.CIDType(&checkCID))
+		if err != nil {
+			return "", fmt.Errorf("unable to fetch check %s to render %s: %w", rs.CheckCID, providerLinkTemplateAttr, err)
+		}
+
+		bundleCID := chk.CheckBundleCID
+		cb, err := ctxt.client.FetchCheckBundle(api.CIDType(&bundleCID))
+		if err != nil {
+			return "", fmt.Errorf("unable to fetch check bundle %s to render %s: %w", chk.CheckBundleCID, providerLinkTemplateAttr, err)
+		}
+
+		vars["check_name"] = cb.DisplayName
+	}
+
+	return renderLinkTemplate(ctxt.linkTemplate, vars), nil
+}
+
+// Routing returns the names of the contact groups notified at each nonzero
+// severity of the rule set's rules, sorted and comma separated, keyed by the
+// severity.  A severity notifying no contact groups maps to an empty string.
+// Contact groups whose name cannot be looked up are listed by CID.
+func (rs *circonusRuleSet) Routing(ctxt *providerContext) map[string]interface{} {
+	routing := make(map[string]interface{})
+	for _, rule := range rs.Rules {
+		key := strconv.FormatUint(uint64(rule.Severity), 10)
+		if _, found := routing[key]; found || rule.Severity == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(rs.ContactGroups[uint8(rule.Severity)]))
+		for _, cid := range rs.ContactGroups[uint8(rule.Severity)] {
+			name, err := ctxt.contactGroupNameByCID(cid)
+			if err != nil {
+				log.Printf("[WARN] unable to look up the name of contact group %s for rule set %s: %v", cid, rs.CID, err)
+				name = cid
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		routing[key] = strings.Join(names, ", ")
+	}
+
+	return routing
+}
+
+// EmptyNotifySeverities returns the nonzero severities of the rule set's
+// rules that notify no contact groups.
+func (rs *circonusRuleSet) EmptyNotifySeverities() []uint {
+	severities := make([]uint, 0)
+	for _, rule := range rs.Rules {
+		if rule.Severity == 0 || len(rs.ContactGroups[uint8(rule.Severity)]) > 0 {
+			continue
+		}
+
+		if !uintInSlice(rule.Severity, severities) {
+			severities = append(severities, rule.Severity)
+		}
+	}
+
+	sort.Slice(severities, func(i, j int) bool { return severities[i] < severities[j] })
+
+	return severities
+}
+
+// EmptyNotifyDiags warns about rules with a nonzero severity that notify no
+// contact groups.  The API has no account level default contact groups to
+// fall back on, such alerts go unnoticed.
+func (rs *circonusRuleSet) EmptyNotifyDiags() diag.Diagnostics {
+	severities := rs.EmptyNotifySeverities()
+	if len(severities) == 0 {
+		return nil
+	}
+
+	levels := make([]string, 0, len(severities))
+	for _, sev := range severities {
+		levels = append(levels, strconv.FormatUint(uint64(sev), 10))
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Rule set alerts notify no one",
+		Detail: fmt.Sprintf("Rule set %s has rules with severity %s that notify no contact groups, these alerts go unnoticed.  Add contact groups to %s or set %s to silence this warning.",
+			rs.CID, strings.Join(levels, ", "), ruleSetNotifyAttr, ruleSetIgnoreEmptyNotify),
+	}}
+}
+
+// ruleSetCustomizeDiffChunks rejects more rules than the API accepts in a rule
+// set unless chunk is set, and marks the chunk_ids as changing when the rules
+// may be spread over a different number of rule sets.
+func ruleSetCustomizeDiffChunks(d *schema.ResourceDiff) error {
+	ifList, _ := d.Get(ruleSetIfAttr).([]interface{})
+	chunk := d.Get(ruleSetChunkAttr).(bool)
+	if len(ifList) > apiRuleSetMaxRules && !chunk {
+		return fmt.Errorf("%s has %d rules, the API accepts at most %d in a rule set: remove rules or set %s = true to split them across several rule sets",
+			ruleSetIfAttr, len(ifList), apiRuleSetMaxRules, ruleSetChunkAttr)
+	}
+
+	if d.Id() == "" || !(d.HasChange(ruleSetIfAttr) || d.HasChange(ruleSetChunkAttr)) {
+		return nil
+	}
+
+	chunkIDs, _ := d.Get(ruleSetChunkIDsAttr).([]interface{})
+	if (chunk && len(ifList) > apiRuleSetMaxRules) || len(chunkIDs) > 0 {
+		return d.SetNewComputed(ruleSetChunkIDsAttr)
+	}
+
+	return nil
+}
+
+// ruleSetChunkSize returns the number of rules kept in each rule set of d,
+// zero when the rules are not split.
+func ruleSetChunkSize(d *schema.ResourceData) int {
+	if !d.Get(ruleSetChunkAttr).(bool) {
+		return 0
+	}
+
+	return apiRuleSetMaxRules
+}
+
+// Chunks splits the rules of rs into rule sets of at most size rules each.
+// The first chunk is rs itself, the others are copies of rs named after it
+// with their position appended, e.g. "cpu (2)", so their names are stable for
+// as long as the number of rules before them is.  A size of zero, or rules
+// that fit a single rule set, return rs alone.
+func (rs *circonusRuleSet) Chunks(size int) []*circonusRuleSet {
+	if size <= 0 || len(rs.Rules) <= size {
+		return []*circonusRuleSet{rs}
+	}
+
+	base := rs.Name
+	if base == "" {
+		base = rs.MetricName
+	}
+	if base == "" {
+		base = rs.MetricPattern
+	}
+
+	rules := rs.Rules
+	chunks := make([]*circonusRuleSet, 0, (len(rules)+size-1)/size)
+	for i := 0; i < len(rules); i += size {
+		end := i + size
+		if end > len(rules) {
+			end = len(rules)
+		}
+
+		chunk := rs
+		if i > 0 {
+			chunk = &circonusRuleSet{RuleSet: rs.RuleSet}
+			chunk.CID = ""
+			chunk.Name = fmt.Sprintf("%s (%d)", base, len(chunks)+1)
+		}
+		chunk.Rules = rules[i:end]
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// ruleSetSyncChunks makes the rule sets with CIDs existing hold chunks, in
+// order: existing rule sets are updated, missing ones created and extra ones
+// deleted.  The CIDs of the rule sets holding chunks are returned, including
+// when an error stops the sync part of the way, so that they are kept in the
+// statefile.
+func ruleSetSyncChunks(ctxt *providerContext, chunks []*circonusRuleSet, existing []string) ([]string, error) {
+	chunkIDs := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		if i < len(existing) {
+			chunk.CID = existing[i]
+			if err := chunk.Update(ctxt); err != nil {
+				return append(chunkIDs, existing[i:]...), err
+			}
+		} else if err := chunk.Create(ctxt); err != nil {
+			return chunkIDs, err
+		}
+		chunkIDs = append(chunkIDs, chunk.CID)
+	}
+
+	for i := len(chunks); i < len(existing); i++ {
+		cid := existing[i]
+		if _, err := ctxt.client.DeleteRuleSetByCID(api.CIDType(&cid)); err != nil && !strings.Contains(err.Error(), defaultCirconus404ErrorString) {
+			return append(chunkIDs, existing[i:]...), fmt.Errorf("unable to delete rule set %s: %w", cid, err)
+		}
+	}
+
+	return chunkIDs, nil
+}
+
 func (rs *circonusRuleSet) Update(ctxt *providerContext) error {
 	_, err := ctxt.client.UpdateRuleSet(&rs.RuleSet)
 	if err != nil {
@@ -906,6 +1543,15 @@ func (rs *circonusRuleSet) Update(ctxt *providerContext) error {
 	return nil
 }
 
+func uintInSlice(a uint, list []uint) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -954,3 +1600,16 @@ func (rs *circonusRuleSet) Validate() error {
 
 	return nil
 }
+
+// ruleSetAbsentToState renders the absence window the API returned, in
+// seconds as a float64 or a string, as the whole number of seconds it was
+// configured with.
+func ruleSetAbsentToState(v interface{}) string {
+	s := formatNumber(v)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+
+	return strconv.FormatInt(int64(math.Round(f)), 10)
+}
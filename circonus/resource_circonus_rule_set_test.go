@@ -1,13 +1,21 @@
 package circonus
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	api "github.com/circonus-labs/go-apiclient"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -23,6 +31,9 @@ func TestAccCirconusRuleSet_basic(t *testing.T) {
 				Config: fmt.Sprintf(testAccCirconusRuleSetConfigFmt, rulesetCheckName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet("circonus_rule_set.icmp-latency-alarm", "check"),
+					resource.TestCheckResourceAttrSet("circonus_rule_set.icmp-latency-alarm", "check_id"),
+					resource.TestCheckResourceAttrSet("circonus_rule_set.icmp-latency-alarm", "check_uuid"),
+					resource.TestCheckResourceAttrSet("circonus_rule_set.icmp-latency-alarm", "host"),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "metric_name", "maximum"),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "metric_type", "numeric"),
 					resource.TestCheckResourceAttr("circonus_rule_set.icmp-latency-alarm", "notes", "Simple check to create notifications based on ICMP performance."),
@@ -124,6 +135,219 @@ func TestAccCirconusRuleSet_basic(t *testing.T) {
 	})
 }
 
+func TestRenderLinkTemplate(t *testing.T) {
+	vars := map[string]string{
+		"check_id":   "1234",
+		"check_name": "web lb",
+		"metric":     "www`FRONTEND`scur",
+	}
+
+	got := renderLinkTemplate("https://wiki.example.org/{check_name}/{metric}?id={check_id}&x={unknown}", vars)
+	expected := "https://wiki.example.org/web%20lb/www%60FRONTEND%60scur?id=1234&x={unknown}"
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestValidateLinkTemplate(t *testing.T) {
+	for _, v := range []string{"", "https://wiki.example.org/{check_name}/{metric}", "http://runbooks/{name}?check={check_id}"} {
+		if _, errs := validateLinkTemplate(v, providerLinkTemplateAttr); len(errs) != 0 {
+			t.Fatalf("expected %q to be valid: %v", v, errs)
+		}
+	}
+
+	for _, v := range []string{"https://wiki.example.org/{check}", "/runbooks/{metric}"} {
+		if _, errs := validateLinkTemplate(v, providerLinkTemplateAttr); len(errs) == 0 {
+			t.Fatalf("expected %q to be rejected", v)
+		}
+	}
+}
+
+func TestValidateContactGroupRef(t *testing.T) {
+	validate := validateContactGroupRef(ruleSetNotifyAttr, ruleSetNotifyNamePrefix)
+
+	tests := []struct {
+		v  string
+		ok bool
+	}{
+		{"/contact_group/1234", true},
+		{"name:Platform OnCall", true},
+		{"name:", false},
+		{"name:  ", false},
+		{"Platform OnCall", false},
+	}
+
+	for _, test := range tests {
+		_, errs := validate(test.v, string(ruleSetNotifyAttr))
+		if test.ok && len(errs) > 0 {
+			t.Errorf("%q: unexpected errors: %v", test.v, errs)
+		}
+		if !test.ok && len(errs) == 0 {
+			t.Errorf("%q: expected an error", test.v)
+		}
+	}
+}
+
+func TestRuleSetResolveContactGroups(t *testing.T) {
+	groups := []api.ContactGroup{
+		{CID: "/contact_group/1", Name: "Platform OnCall"},
+		{CID: "/contact_group/2", Name: "Platform OnCall Backup"},
+		{CID: "/contact_group/3", Name: "Twins"},
+		{CID: "/contact_group/4", Name: "Twins"},
+	}
+
+	var searches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		name := r.URL.Query().Get("f_name")
+		results := make([]api.ContactGroup, 0)
+		for _, cg := range groups {
+			if strings.HasPrefix(cg.Name, name) {
+				results = append(results, cg)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	rs := newRuleSet()
+	rs.ContactGroups = map[uint8][]string{
+		1: {"name:Platform OnCall", "/contact_group/1", "/contact_group/9"},
+		2: {"name:Platform OnCall"},
+	}
+	if err := rs.ResolveContactGroups(ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[uint8][]string{
+		1: {"/contact_group/1", "/contact_group/9"},
+		2: {"/contact_group/1"},
+	}
+	if !reflect.DeepEqual(rs.ContactGroups, expected) {
+		t.Fatalf("expected %v, got %v", expected, rs.ContactGroups)
+	}
+
+	if searches != 1 {
+		t.Errorf("expected the name to be searched for once, got %d searches", searches)
+	}
+
+	for _, name := range []string{"name:Twins", "name:Nobody"} {
+		rs.ContactGroups = map[uint8][]string{1: {name}}
+		if err := rs.ResolveContactGroups(ctxt); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestRuleSetNotifyNames(t *testing.T) {
+	then := func(notify ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			string(ruleSetThenAttr): []interface{}{
+				map[string]interface{}{
+					string(ruleSetNotifyAttr): schema.NewSet(schema.HashString, notify),
+				},
+			},
+		}
+	}
+
+	ifList := []interface{}{
+		then("name:Platform OnCall", "/contact_group/1"),
+		then("name:Platform OnCall", "name:DBAs"),
+		map[string]interface{}{},
+	}
+
+	names := ruleSetNotifyNames(ifList)
+	sort.Strings(names)
+	if expected := []string{"name:DBAs", "name:Platform OnCall"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestRuleSetEmptyNotifyDiags(t *testing.T) {
+	rs := newRuleSet()
+	rs.CID = "/rule_set/1_cpu"
+	rs.Rules = []api.RuleSetRule{
+		{Criteria: apiRuleSetMaxValue, Severity: 1},
+		{Criteria: apiRuleSetMaxValue, Severity: 2},
+		{Criteria: apiRuleSetMaxValue, Severity: 3},
+		{Criteria: apiRuleSetMaxValue, Severity: 3},
+		{Criteria: apiRuleSetMaxValue, Severity: 0},
+	}
+	rs.ContactGroups[2] = []string{"/contact_group/1"}
+
+	if sevs := rs.EmptyNotifySeverities(); !reflect.DeepEqual(sevs, []uint{1, 3}) {
+		t.Fatalf("expected severities 1 and 3, got %v", sevs)
+	}
+
+	diags := rs.EmptyNotifyDiags()
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "severity 1, 3") {
+		t.Fatalf("expected a warning about severities 1 and 3, got %#v", diags)
+	}
+
+	rs.ContactGroups[1] = []string{"/contact_group/1"}
+	rs.ContactGroups[3] = []string{"/contact_group/1"}
+	if diags := rs.EmptyNotifyDiags(); len(diags) != 0 {
+		t.Fatalf("expected no warning, got %#v", diags)
+	}
+}
+
+func TestRuleSetRouting(t *testing.T) {
+	var fetches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		switch r.URL.Path {
+		case "/contact_group/1":
+			_ = json.NewEncoder(w).Encode(api.ContactGroup{CID: "/contact_group/1", Name: "Platform OnCall"})
+		case "/contact_group/2":
+			_ = json.NewEncoder(w).Encode(api.ContactGroup{CID: "/contact_group/2", Name: "DBAs"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	rs := newRuleSet()
+	rs.CID = "/rule_set/1_cpu"
+	rs.Rules = []api.RuleSetRule{
+		{Criteria: apiRuleSetMaxValue, Severity: 1},
+		{Criteria: apiRuleSetMaxValue, Severity: 2},
+		{Criteria: apiRuleSetMaxValue, Severity: 2},
+		{Criteria: apiRuleSetMaxValue, Severity: 3},
+		{Criteria: apiRuleSetMaxValue, Severity: 0},
+	}
+	rs.ContactGroups[1] = []string{"/contact_group/1", "/contact_group/2"}
+	rs.ContactGroups[2] = []string{"/contact_group/9", "/contact_group/1"}
+	rs.ContactGroups[4] = []string{"/contact_group/2"}
+
+	expected := map[string]interface{}{
+		"1": "DBAs, Platform OnCall",
+		"2": "/contact_group/9, Platform OnCall",
+		"3": "",
+	}
+	if routing := rs.Routing(ctxt); !reflect.DeepEqual(routing, expected) {
+		t.Fatalf("expected %v, got %v", expected, routing)
+	}
+
+	// Names are cached, only the unknown contact group is fetched again.
+	fetches = 0
+	rs.Routing(ctxt)
+	if fetches != 1 {
+		t.Errorf("expected 1 fetch, got %d", fetches)
+	}
+}
+
 func testAccCheckDestroyCirconusRuleSet(s *terraform.State) error {
 	ctxt := testAccProvider.Meta().(*providerContext)
 
@@ -461,3 +685,185 @@ EOF
   }
 }
 `
+
+func TestRuleSetValueRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria string
+		value    interface{}
+		expected string
+	}{
+		{"absent float", apiRuleSetAbsent, float64(300), "300"},
+		{"absent float rounding", apiRuleSetAbsent, 299.9999999, "300"},
+		{"absent formatted string", apiRuleSetAbsent, "300.000000", "300"},
+		{"absent string", apiRuleSetAbsent, "300", "300"},
+		{"max value float", apiRuleSetMaxValue, float64(90), "90"},
+		{"max value fraction", apiRuleSetMaxValue, 0.25, "0.25"},
+		{"min value formatted string", apiRuleSetMinValue, "10.500000", "10.5"},
+		{"equals negative", apiRuleSetEqValue, "-1.0", "-1"},
+		{"does not equal int", apiRuleSetNotEqValue, 0, "0"},
+		{"not a number", apiRuleSetMaxValue, "NaN-ish", "NaN-ish"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			if test.criteria == apiRuleSetAbsent {
+				got = ruleSetAbsentToState(test.value)
+			} else {
+				got = formatNumber(test.value)
+			}
+
+			if got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentNumbers(t *testing.T) {
+	tests := []struct {
+		old, update string
+		suppress    bool
+	}{
+		{"300", "300.000000", true},
+		{"300", "300", true},
+		{"0.5", ".5", true},
+		{"300", "301", false},
+		{"", "300", false},
+		{"300", "5m", false},
+	}
+
+	for _, test := range tests {
+		if got := suppressEquivalentNumbers("", test.old, test.update, nil); got != test.suppress {
+			t.Errorf("%q vs %q: expected %t, got %t", test.old, test.update, test.suppress, got)
+		}
+	}
+}
+
+func TestRuleSetChunks(t *testing.T) {
+	rs := newRuleSet()
+	rs.Name = "queue depth"
+	rs.CheckCID = "/check/1234"
+	rs.MetricName = "depth"
+	for i := 0; i < 2*apiRuleSetMaxRules+1; i++ {
+		rs.Rules = append(rs.Rules, api.RuleSetRule{Criteria: apiRuleSetMaxValue, Severity: 1, Value: fmt.Sprintf("%d", i)})
+	}
+
+	if chunks := rs.Chunks(0); len(chunks) != 1 || len(chunks[0].Rules) != len(rs.Rules) {
+		t.Fatalf("expected the rules not to be split without a chunk size, got %d rule sets", len(chunks))
+	}
+
+	chunks := rs.Chunks(apiRuleSetMaxRules)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 rule sets, got %d", len(chunks))
+	}
+
+	expected := []struct {
+		name  string
+		rules int
+		first string
+	}{
+		{"queue depth", apiRuleSetMaxRules, "0"},
+		{"queue depth (2)", apiRuleSetMaxRules, fmt.Sprintf("%d", apiRuleSetMaxRules)},
+		{"queue depth (3)", 1, fmt.Sprintf("%d", 2*apiRuleSetMaxRules)},
+	}
+	for i, e := range expected {
+		chunk := chunks[i]
+		if chunk.Name != e.name {
+			t.Errorf("chunk %d: expected name %q, got %q", i, e.name, chunk.Name)
+		}
+		if len(chunk.Rules) != e.rules {
+			t.Errorf("chunk %d: expected %d rules, got %d", i, e.rules, len(chunk.Rules))
+		}
+		if chunk.Rules[0].Value != e.first {
+			t.Errorf("chunk %d: expected the first rule to be %q, got %v", i, e.first, chunk.Rules[0].Value)
+		}
+		if chunk.CheckCID != rs.CheckCID || chunk.MetricName != rs.MetricName {
+			t.Errorf("chunk %d: expected the check and metric of the rule set, got %q %q", i, chunk.CheckCID, chunk.MetricName)
+		}
+	}
+	if chunks[0] != &rs {
+		t.Errorf("expected the first chunk to be the rule set itself")
+	}
+}
+
+func TestRuleSetCustomizeDiffChunks(t *testing.T) {
+	ifList := make([]interface{}, apiRuleSetMaxRules+1)
+	for i := range ifList {
+		ifList[i] = map[string]interface{}{
+			ruleSetValueAttr: []interface{}{map[string]interface{}{ruleSetMaxValueAttr: "90"}},
+		}
+	}
+
+	for _, chunk := range []bool{false, true} {
+		cfg := terraform.NewResourceConfigRaw(map[string]interface{}{
+			ruleSetCheckAttr:      "/check/1234",
+			ruleSetMetricNameAttr: "depth",
+			ruleSetIfAttr:         ifList,
+			ruleSetChunkAttr:      chunk,
+		})
+
+		_, err := resourceRuleSet().Diff(context.Background(), nil, cfg, nil)
+		if chunk && err != nil {
+			t.Errorf("expected %d rules to be accepted with %s, got %v", len(ifList), ruleSetChunkAttr, err)
+		}
+		if !chunk && (err == nil || !strings.Contains(err.Error(), ruleSetChunkAttr+" = true")) {
+			t.Errorf("expected %d rules to be rejected without %s, got %v", len(ifList), ruleSetChunkAttr, err)
+		}
+	}
+}
+
+func TestRuleSetCustomizeDiffDuplicates(t *testing.T) {
+	var searches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rule_set" || r.URL.Query().Get(ruleSetSearchCheckFilter) != "/check/1234" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		searches++
+		_ = json.NewEncoder(w).Encode([]api.RuleSet{
+			{CID: "/rule_set/1234_depth", CheckCID: "/check/1234", MetricName: "depth"},
+			{CID: "/rule_set/1234_depth_2", CheckCID: "/check/1234", MetricName: "depth"},
+			{CID: "/rule_set/1234_latency", CheckCID: "/check/1234", MetricName: "latency"},
+			{CID: "/rule_set/1234_filtered", CheckCID: "/check/1234", MetricPattern: "^depth", Filter: "and(env:prod)"},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := api.New(&api.Config{URL: srv.URL, TokenKey: "test", MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt := &providerContext{client: client}
+
+	cfg := func(mode, metric string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			ruleSetCheckAttr:      "/check/1234",
+			ruleSetDuplicatesAttr: mode,
+			ruleSetMetricNameAttr: metric,
+			ruleSetIfAttr: []interface{}{map[string]interface{}{
+				ruleSetValueAttr: []interface{}{map[string]interface{}{ruleSetMaxValueAttr: "90"}},
+			}},
+		})
+	}
+
+	r := resourceRuleSet()
+	if _, err := r.Diff(context.Background(), nil, cfg(ruleSetDuplicatesWarn, "depth"), ctxt); err != nil || searches != 0 {
+		t.Fatalf("expected the plan to be left alone in %s mode, got %d searches: %v", ruleSetDuplicatesWarn, searches, err)
+	}
+
+	_, err = r.Diff(context.Background(), nil, cfg(ruleSetDuplicatesError, "depth"), ctxt)
+	if err == nil || !strings.Contains(err.Error(), "/rule_set/1234_depth, /rule_set/1234_depth_2") {
+		t.Fatalf("expected the duplicates to be reported, got %v", err)
+	}
+
+	if _, err := r.Diff(context.Background(), nil, cfg(ruleSetDuplicatesError, "queue"), ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dups, err := ruleSetFindDuplicates(ctxt, &api.RuleSet{CheckCID: "/check/1234", MetricName: "depth"}, ruleSetOwnCIDs("/rule_set/1234_depth", []string{"/rule_set/1234_depth_2"}))
+	if err != nil || len(dups) != 0 {
+		t.Fatalf("expected the rule set and its chunks to be ignored, got %v: %v", dups, err)
+	}
+}
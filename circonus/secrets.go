@@ -0,0 +1,191 @@
+package circonus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config values that are secrets, such as DSNs and API keys, can be given to
+// checks as references (e.g. vault:secret/data/pg1#dsn) that the provider
+// resolves when the check is applied.  The resolved values are sent to the API
+// but never written to the statefile: the API echoes the config of a check
+// back, so the keys set from secrets are removed from it before it is read.
+
+const (
+	secretSchemeEnv   = "env"
+	secretSchemeFile  = "file"
+	secretSchemeVault = "vault"
+)
+
+// secretVaultTimeout bounds each request made to Vault.
+const secretVaultTimeout = 10 * time.Second
+
+// secretResolver resolves the path of a secret reference, the part following
+// its scheme, to the value of the secret.
+type secretResolver interface {
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+// secretResolvers maps the schemes of secret references to their resolver.
+type secretResolvers map[string]secretResolver
+
+// newSecretResolvers returns the resolvers of the provider.  The vault scheme
+// is only available when the address of Vault is set.
+func newSecretResolvers(vaultAddress, vaultToken string) secretResolvers {
+	r := secretResolvers{
+		secretSchemeEnv:  envSecretResolver{},
+		secretSchemeFile: fileSecretResolver{},
+	}
+
+	if vaultAddress != "" {
+		r[secretSchemeVault] = &vaultSecretResolver{
+			address: strings.TrimSuffix(vaultAddress, "/"),
+			token:   vaultToken,
+			client:  &http.Client{Timeout: secretVaultTimeout},
+		}
+	}
+
+	return r
+}
+
+// splitSecretRef splits ref, of the form <scheme>:<path>, into its parts.
+func splitSecretRef(ref string) (string, string, error) {
+	parts := strings.SplitN(ref, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid secret reference %q, expected <scheme>:<path> (e.g. %s:MY_SECRET)", ref, secretSchemeEnv)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// Resolve returns the value of the secret ref refers to.
+func (r secretResolvers) Resolve(ctx context.Context, ref string) (string, error) {
+	scheme, path, err := splitSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	resolver, found := r[scheme]
+	if !found {
+		if scheme == secretSchemeVault {
+			return "", fmt.Errorf("unable to resolve secret %q: the provider's %s is not set", ref, providerVaultAddressAttr)
+		}
+		return "", fmt.Errorf("unable to resolve secret %q: unsupported scheme %q", ref, scheme)
+	}
+
+	v, err := resolver.Resolve(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret %q: %w", ref, err)
+	}
+
+	if v == "" {
+		return "", fmt.Errorf("secret %q is empty", ref)
+	}
+
+	return v, nil
+}
+
+// envSecretResolver resolves env:<name> to the value of an environment
+// variable of the provider.
+type envSecretResolver struct{}
+
+func (envSecretResolver) Resolve(_ context.Context, name string) (string, error) {
+	v, found := os.LookupEnv(name)
+	if !found {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return v, nil
+}
+
+// fileSecretResolver resolves file:<path> to the contents of a file, without
+// its trailing newline.
+type fileSecretResolver struct{}
+
+func (fileSecretResolver) Resolve(_ context.Context, path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// vaultSecretResolver resolves vault:<path>#<field> to a field of the secret
+// read from path of the Vault HTTP API, e.g. vault:secret/data/pg1#dsn.  The
+// field may be omitted when the secret has a single field.
+type vaultSecretResolver struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// vaultSecret is the body of the responses of Vault.  The fields of a KV
+// version 2 secret are nested in a second data object next to its metadata.
+type vaultSecret struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func (v *vaultSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field := ref, ""
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		path, field = ref[:i], ref[i+1:]
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.address+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	setRequestAnnotationHeaders(ctx, req.Header)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded %s", resp.Status)
+	}
+
+	var secret vaultSecret
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("unable to decode vault response: %w", err)
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	if field == "" {
+		if len(data) != 1 {
+			fields := make([]string, 0, len(data))
+			for k := range data {
+				fields = append(fields, k)
+			}
+			sort.Strings(fields)
+			return "", fmt.Errorf("secret has %d fields (%s), select one with #<field>", len(data), strings.Join(fields, ", "))
+		}
+		for k := range data {
+			field = k
+		}
+	}
+
+	s, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string field %q", field)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,89 @@
+package circonus
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretResolvers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/pg1":
+			_, _ = w.Write([]byte(`{"data":{"data":{"dsn":"host=db1 password=hunter2","user":"monitor"},"metadata":{"version":3}}}`))
+		case "/v1/kv/api":
+			_, _ = w.Write([]byte(`{"data":{"key":"abc123"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	secretFile := filepath.Join(dir, "dsn")
+	if err := ioutil.WriteFile(secretFile, []byte("host=db2\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	os.Setenv("CIRCONUS_TEST_SECRET", "from-env")
+	defer os.Unsetenv("CIRCONUS_TEST_SECRET")
+
+	r := newSecretResolvers(srv.URL+"/", "s.test")
+
+	tests := []struct {
+		ref      string
+		expected string
+		err      string
+	}{
+		{ref: "env:CIRCONUS_TEST_SECRET", expected: "from-env"},
+		{ref: "env:CIRCONUS_TEST_SECRET_UNSET", err: "is not set"},
+		{ref: "file:" + secretFile, expected: "host=db2"},
+		{ref: "file:" + filepath.Join(dir, "missing"), err: "no such file"},
+		{ref: "vault:secret/data/pg1#dsn", expected: "host=db1 password=hunter2"},
+		{ref: "vault:secret/data/pg1", err: "select one with #<field>"},
+		{ref: "vault:secret/data/pg1#password", err: `no string field "password"`},
+		{ref: "vault:kv/api", expected: "abc123"},
+		{ref: "vault:kv/missing", err: "404"},
+		{ref: "aws:pg1", err: `unsupported scheme "aws"`},
+		{ref: "pg1", err: "invalid secret reference"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ref, func(t *testing.T) {
+			v, err := r.Resolve(context.Background(), test.ref)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("expected an error containing %q, got %q, %v", test.err, v, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, v)
+			}
+		})
+	}
+
+	// vault references require the provider's vault_address.
+	_, err = newSecretResolvers("", "").Resolve(context.Background(), "vault:kv/api")
+	if err == nil || !strings.Contains(err.Error(), providerVaultAddressAttr) {
+		t.Errorf("expected an error naming %s, got %v", providerVaultAddressAttr, err)
+	}
+}
@@ -8,10 +8,10 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	api "github.com/circonus-labs/go-apiclient"
 	"github.com/circonus-labs/go-apiclient/config"
+	"github.com/circonus-labs/terraform-provider-circonus/internal/timeutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -114,20 +114,6 @@ func stringListToSet(stringList []string, keyName schemaAttr) []interface{} {
 	return m
 }
 
-func normalizeTimeDurationStringToSeconds(v interface{}) string {
-	switch v := v.(type) {
-	case string:
-		d, err := time.ParseDuration(v)
-		if err != nil {
-			return fmt.Sprintf("<unable to normalize time duration %s: %v>", v, err)
-		}
-
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	default:
-		return fmt.Sprintf("<unable to normalize duration on %#v>", v)
-	}
-}
-
 func indirect(v interface{}) interface{} {
 	switch v := v.(type) {
 	case string:
@@ -143,17 +129,51 @@ func indirect(v interface{}) interface{} {
 }
 
 func suppressEquivalentTimeDurations(k, old, update string, d *schema.ResourceData) bool {
-	d1, err := time.ParseDuration(old)
+	return timeutil.Equivalent(old, update)
+}
+
+// suppressEquivalentMinutes ignores changes to a duration in seconds that the
+// API keeps in whole minutes, e.g. 90 and 60 are both stored as one minute.
+func suppressEquivalentMinutes(k, old, update string, d *schema.ResourceData) bool {
+	return timeutil.EquivalentMinutes(old, update)
+}
+
+func suppressEquivalentNumbers(k, old, update string, d *schema.ResourceData) bool {
+	f1, err := strconv.ParseFloat(strings.TrimSpace(old), 64)
 	if err != nil {
 		return false
 	}
 
-	d2, err := time.ParseDuration(update)
+	f2, err := strconv.ParseFloat(strings.TrimSpace(update), 64)
 	if err != nil {
 		return false
 	}
 
-	return d1 == d2
+	return f1 == f2
+}
+
+// formatNumber renders a number the API returned, as a float64 or as a string
+// with a varying number of trailing zeros, in its shortest form.  Values that
+// are not numbers are returned as they are.
+func formatNumber(v interface{}) string {
+	var s string
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(n)
+	case string:
+		s = n
+	default:
+		s = fmt.Sprintf("%v", n)
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return s
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
 }
 
 func suppressWhitespace(v interface{}) string {
@@ -23,11 +23,11 @@ func init() {
 	checkTypes := []circonusCheckType{
 		"caql", "cim", "circonuswindowsagent", "circonuswindowsagent,nad",
 		"collectd", "composite", "dcm", "dhcp", "dns", "elasticsearch",
-		"external", "ganglia", "googleanalytics", "haproxy", "http",
-		"http,apache", "httptrap", "imap", "jmx", "json", "json,couchdb",
+		"external", "ganglia", "googleanalytics", "haproxy", "histogram_ingest", "http",
+		"http,apache", "http_sequence", "httptrap", "imap", "jmx", "json", "json,couchdb",
 		"json,mongodb", "json,nad", "json,riak", "ldap", "memcached",
 		"munin", "mysql", "newrelic_rpm", "nginx", "nrpe", "ntp",
-		"oracle", "ping_icmp", "pop3", "postgres", "redis", "resmon",
+		"oracle", "otlphttp", "ping_icmp", "pop3", "postgres", "redis", "resmon",
 		"smtp", "snmp", "snmp,momentum", "sqlserver", "ssh2", "statsd",
 		"tcp", "varnish", "keynote", "keynote_pulse", "cloudwatch",
 		"ec_console", "mongodb",
@@ -104,6 +104,77 @@ func validateContactGroup(cg *api.ContactGroup) error {
 	return nil
 }
 
+func validateContactGroupType(v interface{}, key string) (warnings []string, errors []error) {
+	return validateStringIn(contactGroupTypeAttr, validContactGroupTypes)(normalizeContactGroupType(v), key)
+}
+
+// smsAddressRE matches a phone number in international (E.164) format: a plus
+// sign, a country code and at most 15 digits in all.
+var smsAddressRE = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// validateSMSAddress verifies an sms address is a phone number in
+// international format once separators are removed.  The API accepts any
+// string and messages to malformed numbers are silently never delivered.
+func validateSMSAddress(v interface{}, key string) (warnings []string, errors []error) {
+	if !smsAddressRE.MatchString(normalizeSMSAddress(v)) {
+		errors = append(errors, fmt.Errorf("invalid %s %q: expected a phone number in international format, a + followed by the country code and number (e.g. +15551234567)", key, v.(string)))
+	}
+
+	return warnings, errors
+}
+
+// validateLinkTemplate verifies a rule set link template only references known
+// placeholders and renders into an absolute URL.
+func validateLinkTemplate(v interface{}, key string) (warnings []string, errors []error) {
+	s := v.(string)
+	if s == "" {
+		return warnings, errors
+	}
+
+	vars := make(map[string]string, len(ruleSetLinkTemplateVars))
+	for _, name := range ruleSetLinkTemplateVars {
+		vars[name] = name
+	}
+
+	for _, m := range ruleSetLinkTemplatePlaceholderRE.FindAllStringSubmatch(s, -1) {
+		if _, ok := vars[m[1]]; !ok {
+			errors = append(errors, fmt.Errorf("Unknown placeholder %q in %s, supported placeholders: {%s}", m[0], providerLinkTemplateAttr, strings.Join(ruleSetLinkTemplateVars, "}, {")))
+		}
+	}
+
+	u, err := url.Parse(renderLinkTemplate(s, vars))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("Invalid %s %q: %w", providerLinkTemplateAttr, s, err))
+	} else if !u.IsAbs() || u.Host == "" {
+		errors = append(errors, fmt.Errorf("%s %q must render into an absolute URL", providerLinkTemplateAttr, s))
+	}
+
+	return warnings, errors
+}
+
+// slackChannelIDRegex matches Slack channel IDs: public (C), private (G) and
+// direct message (D) channels.
+const slackChannelIDRegex = `^[CGD][A-Z0-9]{8,}$`
+
+var slackChannelIDRE = regexp.MustCompile(slackChannelIDRegex)
+
+// validateSlackChannel accepts any channel name without whitespace.  Names are
+// expected to start with '#', a channel ID must be set as channel_id.
+func validateSlackChannel(v interface{}, key string) (warnings []string, errors []error) {
+	s := v.(string)
+
+	switch {
+	case s == "" || strings.ContainsAny(s, " \t\n"):
+		errors = append(errors, fmt.Errorf("Invalid %s specified (%q)", contactSlackChannelAttr, s))
+	case slackChannelIDRE.MatchString(s):
+		errors = append(errors, fmt.Errorf("%s %q looks like a Slack channel ID, set it as %s instead", contactSlackChannelAttr, s, contactSlackChannelIDAttr))
+	case !strings.HasPrefix(s, "#"):
+		warnings = append(warnings, fmt.Sprintf("%s %q does not start with '#', Slack channel names are usually written as #%s", contactSlackChannelAttr, s, s))
+	}
+
+	return warnings, errors
+}
+
 func validateContactGroupCID(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
 	return func(v interface{}, key string) (warnings []string, errors []error) {
 		validContactGroupCID := regexp.MustCompile(config.ContactGroupCIDRegex)
@@ -116,6 +187,24 @@ func validateContactGroupCID(attrName schemaAttr) func(v interface{}, key string
 	}
 }
 
+// validateContactGroupRef validates a contact group CID or a contact group
+// name following namePrefix.
+func validateContactGroupRef(attrName schemaAttr, namePrefix string) func(v interface{}, key string) (warnings []string, errors []error) {
+	validateCID := validateContactGroupCID(attrName)
+
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		if s := v.(string); strings.HasPrefix(s, namePrefix) {
+			if strings.TrimSpace(strings.TrimPrefix(s, namePrefix)) == "" {
+				errors = append(errors, fmt.Errorf("Invalid %s specified (%q): contact group name is empty", attrName, s))
+			}
+
+			return warnings, errors
+		}
+
+		return validateCID(v, key)
+	}
+}
+
 func validateDurationMin(attrName schemaAttr, minDuration string) func(v interface{}, key string) (warnings []string, errors []error) {
 	var min time.Duration
 	{
@@ -222,6 +311,18 @@ func validateHTTPHeaders(v interface{}, key string) (warnings []string, errors [
 	return warnings, errors
 }
 
+func validateHTTPTrapMetricTypes(v interface{}, key string) (warnings []string, errors []error) {
+	for name, vRaw := range v.(map[string]interface{}) {
+		switch metricType := vRaw.(string); metricType {
+		case "histogram", "numeric", "text":
+		default:
+			errors = append(errors, fmt.Errorf("Invalid %s for metric %q specified: %q, must be one of histogram, numeric or text", checkHTTPTrapMetricTypesAttr, name, metricType))
+		}
+	}
+
+	return warnings, errors
+}
+
 func validateGraphAxisOptions(v interface{}, key string) (warnings []string, errors []error) {
 	axisOptionsMap := v.(map[string]interface{})
 	validOpts := map[schemaAttr]struct{}{
@@ -240,6 +341,22 @@ func validateGraphAxisOptions(v interface{}, key string) (warnings []string, err
 	return warnings, errors
 }
 
+func validateGraphFormula(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		formula := v.(string)
+
+		w, err := parseGraphFormula(formula)
+		for _, s := range w {
+			warnings = append(warnings, fmt.Sprintf("%s %q: %s", attrName, formula, s))
+		}
+		if err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): %w", attrName, formula, err))
+		}
+
+		return warnings, errors
+	}
+}
+
 func validateIntMin(attrName schemaAttr, min int) func(v interface{}, key string) (warnings []string, errors []error) {
 	return func(v interface{}, key string) (warnings []string, errors []error) {
 		if v.(int) < min {
@@ -283,6 +400,17 @@ func validateRegexp(attrName schemaAttr, reString string) func(v interface{}, ke
 	}
 }
 
+// validateRegexpSyntax accepts any regular expression that compiles.
+func validateRegexpSyntax(attrName schemaAttr) func(v interface{}, key string) (warnings []string, errors []error) {
+	return func(v interface{}, key string) (warnings []string, errors []error) {
+		if _, err := regexp.Compile(v.(string)); err != nil {
+			errors = append(errors, fmt.Errorf("Invalid %s specified (%q): %v", attrName, v.(string), err))
+		}
+
+		return warnings, errors
+	}
+}
+
 func validateTag(v interface{}, key string) (warnings []string, errors []error) {
 	tag := v.(string)
 	if !strings.ContainsRune(tag, ':') {
@@ -374,3 +502,30 @@ func validateStringIn(attrName schemaAttr, valid validStringValues) func(v inter
 		return warnings, errors
 	}
 }
+
+// validateCheckMetricsUnique returns an error naming the index of the first
+// metric block that declares the same name and type as an earlier one.  Nil
+// entries and metrics without a name or type are skipped.
+func validateCheckMetricsUnique(metrics []interface{}) error {
+	seen := make(map[string]int, len(metrics))
+	for i, metricRaw := range metrics {
+		metricAttrs, ok := metricRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := metricAttrs[string(metricNameAttr)].(string)
+		metricType, _ := metricAttrs[string(metricTypeAttr)].(string)
+		if name == "" || metricType == "" {
+			continue
+		}
+
+		key := metricType + "`" + name
+		if first, found := seen[key]; found {
+			return fmt.Errorf("%s.%d duplicates %s.%d: %s %q of %s %q is declared more than once", checkMetricAttr, i, checkMetricAttr, first, metricNameAttr, name, metricTypeAttr, metricType)
+		}
+		seen[key] = i
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Package timeutil holds the duration helpers shared by the resources of the
+// provider, so every attribute that takes a duration parses, normalizes and
+// compares it the same way.
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses s as a Go duration (e.g. "90s" or "1m30s"), or as a
+// whole number of seconds when s has no unit (e.g. "90").
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseUint(s, 10, 63); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+
+	return time.ParseDuration(s)
+}
+
+// NormalizeSeconds renders v, a duration string, as the whole number of
+// seconds it spans followed by an "s" (e.g. "2m" becomes "120s"), the form
+// durations are kept in the statefile.  It is meant to be used as a StateFunc,
+// values that can not be parsed are rendered as a marker that never matches a
+// valid value.
+func NormalizeSeconds(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		d, err := ParseDuration(v)
+		if err != nil {
+			return fmt.Sprintf("<unable to normalize time duration %s: %v>", v, err)
+		}
+
+		return fmt.Sprintf("%ds", int64(d.Seconds()))
+	default:
+		return fmt.Sprintf("<unable to normalize duration on %#v>", v)
+	}
+}
+
+// Equivalent reports whether a and b parse to the same duration.  Values that
+// can not be parsed are never equivalent.
+func Equivalent(a, b string) bool {
+	d1, err := ParseDuration(a)
+	if err != nil {
+		return false
+	}
+
+	d2, err := ParseDuration(b)
+	if err != nil {
+		return false
+	}
+
+	return d1 == d2
+}
+
+// Minutes returns the whole number of minutes in d, dropping any remainder,
+// for API fields that are kept in minutes.
+func Minutes(d time.Duration) uint {
+	if d < 0 {
+		return 0
+	}
+
+	return uint(d / time.Minute)
+}
+
+// EquivalentMinutes reports whether a and b parse to durations that span the
+// same whole number of minutes, i.e. whether they are the same once stored in
+// a field kept in minutes.  Values that can not be parsed are never
+// equivalent.
+func EquivalentMinutes(a, b string) bool {
+	d1, err := ParseDuration(a)
+	if err != nil {
+		return false
+	}
+
+	d2, err := ParseDuration(b)
+	if err != nil {
+		return false
+	}
+
+	return Minutes(d1) == Minutes(d2)
+}
+
+// FormatSeconds renders d as a whole number of seconds without a unit, the
+// form attributes documented in seconds are kept in.
+func FormatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
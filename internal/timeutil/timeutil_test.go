@@ -0,0 +1,181 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{in: "0", expected: 0},
+		{in: "90", expected: 90 * time.Second},
+		{in: " 90 ", expected: 90 * time.Second},
+		{in: "90s", expected: 90 * time.Second},
+		{in: "1m30s", expected: 90 * time.Second},
+		{in: "5m", expected: 5 * time.Minute},
+		{in: "1h", expected: time.Hour},
+		{in: "250ms", expected: 250 * time.Millisecond},
+		{in: "0s", expected: 0},
+		{in: "-5s", expected: -5 * time.Second},
+		{in: "", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "1.5", wantErr: true},
+		{in: "5 minutes", wantErr: true},
+		{in: "s", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			d, err := ParseDuration(test.in)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %s", d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, d)
+			}
+		})
+	}
+}
+
+func TestNormalizeSeconds(t *testing.T) {
+	tests := []struct {
+		in       interface{}
+		expected string
+	}{
+		{in: "0s", expected: "0s"},
+		{in: "60", expected: "60s"},
+		{in: "60s", expected: "60s"},
+		{in: "1m", expected: "60s"},
+		{in: "2m30s", expected: "150s"},
+		{in: "1h", expected: "3600s"},
+		{in: "1500ms", expected: "1s"},
+		{in: "10m", expected: "600s"},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeSeconds(test.in); got != test.expected {
+			t.Errorf("%#v: expected %q, got %q", test.in, test.expected, got)
+		}
+	}
+
+	// Values that can not be normalized must never equal a normalized value.
+	for _, in := range []interface{}{"ten minutes", "", 60, nil} {
+		got := NormalizeSeconds(in)
+		if _, err := time.ParseDuration(got); err == nil {
+			t.Errorf("%#v: expected a marker, got %q", in, got)
+		}
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"60s", "1m", true},
+		{"60", "1m", true},
+		{"60", "60s", true},
+		{"1h", "3600s", true},
+		{"90s", "1m30s", true},
+		{"0", "0s", true},
+		{"60s", "61s", false},
+		{"1m", "1h", false},
+		{"", "", false},
+		{"60s", "", false},
+		{"invalid", "invalid", false},
+	}
+
+	for _, test := range tests {
+		if got := Equivalent(test.a, test.b); got != test.expected {
+			t.Errorf("Equivalent(%q, %q): expected %t, got %t", test.a, test.b, test.expected, got)
+		}
+		if got := Equivalent(test.b, test.a); got != test.expected {
+			t.Errorf("Equivalent(%q, %q): expected %t, got %t", test.b, test.a, test.expected, got)
+		}
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	tests := []struct {
+		in       time.Duration
+		expected uint
+	}{
+		{0, 0},
+		{59 * time.Second, 0},
+		{time.Minute, 1},
+		{90 * time.Second, 1},
+		{119 * time.Second, 1},
+		{2 * time.Minute, 2},
+		{time.Hour, 60},
+		{-time.Minute, 0},
+	}
+
+	for _, test := range tests {
+		if got := Minutes(test.in); got != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.in, test.expected, got)
+		}
+	}
+}
+
+func TestEquivalentMinutes(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"60", "60", true},
+		{"90", "60", true},
+		{"119", "60", true},
+		{"120", "60", false},
+		{"0", "59", true},
+		{"0", "60", false},
+		{"300", "5m", true},
+		{"", "0", false},
+		{"invalid", "0", false},
+	}
+
+	for _, test := range tests {
+		if got := EquivalentMinutes(test.a, test.b); got != test.expected {
+			t.Errorf("EquivalentMinutes(%q, %q): expected %t, got %t", test.a, test.b, test.expected, got)
+		}
+		if got := EquivalentMinutes(test.b, test.a); got != test.expected {
+			t.Errorf("EquivalentMinutes(%q, %q): expected %t, got %t", test.b, test.a, test.expected, got)
+		}
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	tests := []struct {
+		in       time.Duration
+		expected string
+	}{
+		{0, "0"},
+		{time.Second, "1"},
+		{1500 * time.Millisecond, "1"},
+		{5 * time.Minute, "300"},
+		{time.Hour, "3600"},
+	}
+
+	for _, test := range tests {
+		if got := FormatSeconds(test.in); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.in, test.expected, got)
+		}
+	}
+
+	// Seconds rendered by FormatSeconds parse back to the same duration.
+	for _, d := range []time.Duration{0, time.Second, time.Minute, 42 * time.Minute} {
+		got, err := ParseDuration(FormatSeconds(d))
+		if err != nil || got != d {
+			t.Errorf("%s: round trip returned %s, %v", d, got, err)
+		}
+	}
+}